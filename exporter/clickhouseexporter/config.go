@@ -8,6 +8,7 @@ import (
 	"errors"
 	"fmt"
 	"net/url"
+	"strings"
 	"time"
 
 	"github.com/ClickHouse/clickhouse-go/v2"
@@ -42,14 +43,55 @@ type Config struct {
 	TTLDays uint `mapstructure:"ttl_days"`
 	// TTL is The data time-to-live example 30m, 48h. 0 means no ttl.
 	TTL time.Duration `mapstructure:"ttl"`
+	// ClusterName if set will append `ON CLUSTER` with the provided name when creating the database, tables and views.
+	ClusterName string `mapstructure:"cluster_name"`
+	// TableEngine is the engine of the created tables. default is `MergeTree()`.
+	TableEngine TableEngine `mapstructure:"table_engine"`
+	// MigrationsTableName is the table name recording the applied schema migrations. default is `otel_schema_migrations`.
+	MigrationsTableName string `mapstructure:"migrations_table_name"`
+	// MigrationsTableEngine is the engine of the migrations table. default is the `table_engine` name without
+	// parameters, so that replicated engines use the default replication path of the server.
+	MigrationsTableEngine TableEngine `mapstructure:"migrations_table_engine"`
+	// MaterializedViews configures the optional materialized views created for traces.
+	MaterializedViews MaterializedViewsConfig `mapstructure:"materialized_views"`
 }
 
-const defaultDatabase = "default"
+// TableEngine defines the ENGINE string value when creating the tables.
+type TableEngine struct {
+	// Name is the name of a supported MergeTree family engine: `MergeTree`, `ReplicatedMergeTree` or `SharedMergeTree`.
+	Name string `mapstructure:"name"`
+	// Params are the engine parameters, for example `'/clickhouse/tables/{shard}/{database}/{table}', '{replica}'`.
+	Params string `mapstructure:"params"`
+}
+
+// MaterializedViewsConfig defines the materialized views created for traces.
+type MaterializedViewsConfig struct {
+	// TraceIDTimestamp creates the `<traces_table_name>_trace_id_ts` table holding the time range of every trace. default is true.
+	TraceIDTimestamp bool `mapstructure:"trace_id_timestamp"`
+	// ServiceDependencies creates the `<traces_table_name>_service_dependencies` view counting the calls between services. default is false.
+	ServiceDependencies bool `mapstructure:"service_dependencies"`
+}
+
+const (
+	defaultDatabase    = "default"
+	defaultTableEngine = "MergeTree"
+)
+
+// tableEngines are the supported engine families. The engines of the aggregating tables
+// are derived by inserting a variant before `MergeTree`, e.g. `ReplicatedAggregatingMergeTree`.
+var tableEngines = map[string]bool{
+	"MergeTree":           true,
+	"ReplicatedMergeTree": true,
+	"SharedMergeTree":     true,
+}
 
 var (
-	errConfigNoEndpoint      = errors.New("endpoint must be specified")
-	errConfigInvalidEndpoint = errors.New("endpoint must be url format")
-	errConfigTTL             = errors.New("both 'ttl_days' and 'ttl' can not be provided. 'ttl_days' is deprecated, use 'ttl' instead")
+	errConfigNoEndpoint            = errors.New("endpoint must be specified")
+	errConfigInvalidEndpoint       = errors.New("endpoint must be url format")
+	errConfigTTL                   = errors.New("both 'ttl_days' and 'ttl' can not be provided. 'ttl_days' is deprecated, use 'ttl' instead")
+	errConfigTableEngine           = errors.New("table_engine name must be one of MergeTree, ReplicatedMergeTree or SharedMergeTree")
+	errConfigMigrationsTableEngine = errors.New("migrations_table_engine name must be one of MergeTree, ReplicatedMergeTree or SharedMergeTree")
+	errConfigMigrationsTable       = errors.New("migrations_table_name must be specified")
 )
 
 // Validate the clickhouse server configuration.
@@ -66,6 +108,18 @@ func (cfg *Config) Validate() (err error) {
 		err = errors.Join(err, errConfigTTL)
 	}
 
+	if !tableEngines[cfg.TableEngine.Name] {
+		err = errors.Join(err, errConfigTableEngine)
+	}
+
+	if cfg.MigrationsTableEngine.Name != "" && !tableEngines[cfg.MigrationsTableEngine.Name] {
+		err = errors.Join(err, errConfigMigrationsTableEngine)
+	}
+
+	if cfg.MigrationsTableName == "" {
+		err = errors.Join(err, errConfigMigrationsTable)
+	}
+
 	// Validate DSN with clickhouse driver.
 	// Last chance to catch invalid config.
 	if _, e := clickhouse.ParseDSN(dsn); e != nil {
@@ -135,3 +189,27 @@ func (cfg *Config) buildDB(database string) (*sql.DB, error) {
 	return conn, nil
 
 }
+
+// clusterString renders the `ON CLUSTER` clause of the DDL statements.
+func (cfg *Config) clusterString() string {
+	if cfg.ClusterName == "" {
+		return ""
+	}
+	return fmt.Sprintf("ON CLUSTER %s", cfg.ClusterName)
+}
+
+// tableEngineString renders the engine of the created tables. variant selects another
+// engine of the same family, e.g. `Summing` turns `ReplicatedMergeTree` into `ReplicatedSummingMergeTree`.
+func (cfg *Config) tableEngineString(variant string) string {
+	name := strings.TrimSuffix(cfg.TableEngine.Name, defaultTableEngine) + variant + defaultTableEngine
+	return fmt.Sprintf("%s(%s)", name, cfg.TableEngine.Params)
+}
+
+// migrationsTableEngineString renders the engine of the migrations table. The parameters of
+// `table_engine` are not used, as the replication path of the data tables must not be shared.
+func (cfg *Config) migrationsTableEngineString() string {
+	if cfg.MigrationsTableEngine.Name == "" {
+		return fmt.Sprintf("%s()", cfg.TableEngine.Name)
+	}
+	return fmt.Sprintf("%s(%s)", cfg.MigrationsTableEngine.Name, cfg.MigrationsTableEngine.Params)
+}
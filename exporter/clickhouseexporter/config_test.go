@@ -53,6 +53,19 @@ func TestLoadConfig(t *testing.T) {
 				LogsTableName:    "otel_logs",
 				TracesTableName:  "otel_traces",
 				MetricsTableName: "otel_metrics",
+				ClusterName:      "my_cluster",
+				TableEngine: TableEngine{
+					Name:   "ReplicatedMergeTree",
+					Params: "'/clickhouse/tables/{shard}/{database}/{table}', '{replica}'",
+				},
+				MigrationsTableName: "otel_migrations",
+				MigrationsTableEngine: TableEngine{
+					Name:   "ReplicatedMergeTree",
+					Params: "'/clickhouse/tables/{shard}/{database}/otel_migrations', '{replica}'",
+				},
+				MaterializedViews: MaterializedViewsConfig{
+					ServiceDependencies: true,
+				},
 				TimeoutSettings: exporterhelper.TimeoutSettings{
 					Timeout: 5 * time.Second,
 				},
@@ -272,3 +285,79 @@ func TestConfig_buildDSN(t *testing.T) {
 		})
 	}
 }
+
+func TestConfig_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     *Config
+		wantErr error
+	}{
+		{
+			name: "valid replicated engine",
+			cfg: withDefaultConfig(func(cfg *Config) {
+				cfg.Endpoint = defaultEndpoint
+				cfg.TableEngine.Name = "ReplicatedMergeTree"
+			}),
+		},
+		{
+			name: "invalid table engine",
+			cfg: withDefaultConfig(func(cfg *Config) {
+				cfg.Endpoint = defaultEndpoint
+				cfg.TableEngine.Name = "Log"
+			}),
+			wantErr: errConfigTableEngine,
+		},
+		{
+			name: "unsupported table engine variant",
+			cfg: withDefaultConfig(func(cfg *Config) {
+				cfg.Endpoint = defaultEndpoint
+				cfg.TableEngine.Name = "ReplacingMergeTree"
+			}),
+			wantErr: errConfigTableEngine,
+		},
+		{
+			name: "invalid migrations table engine",
+			cfg: withDefaultConfig(func(cfg *Config) {
+				cfg.Endpoint = defaultEndpoint
+				cfg.MigrationsTableEngine.Name = "Log"
+			}),
+			wantErr: errConfigMigrationsTableEngine,
+		},
+		{
+			name: "no migrations table",
+			cfg: withDefaultConfig(func(cfg *Config) {
+				cfg.Endpoint = defaultEndpoint
+				cfg.MigrationsTableName = ""
+			}),
+			wantErr: errConfigMigrationsTable,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.cfg.Validate()
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestConfig_tableEngineString(t *testing.T) {
+	cfg := withDefaultConfig()
+	assert.Equal(t, "MergeTree()", cfg.tableEngineString(""))
+	assert.Equal(t, "AggregatingMergeTree()", cfg.tableEngineString("Aggregating"))
+	assert.Equal(t, "MergeTree()", cfg.migrationsTableEngineString())
+	assert.Equal(t, "", cfg.clusterString())
+
+	cfg.ClusterName = "my_cluster"
+	cfg.TableEngine = TableEngine{Name: "ReplicatedMergeTree", Params: "'/tables/{table}', '{replica}'"}
+	assert.Equal(t, "ReplicatedMergeTree('/tables/{table}', '{replica}')", cfg.tableEngineString(""))
+	assert.Equal(t, "ReplicatedAggregatingMergeTree('/tables/{table}', '{replica}')", cfg.tableEngineString("Aggregating"))
+	assert.Equal(t, "ReplicatedMergeTree()", cfg.migrationsTableEngineString())
+	assert.Equal(t, "ON CLUSTER my_cluster", cfg.clusterString())
+
+	cfg.MigrationsTableEngine = TableEngine{Name: "ReplicatedMergeTree", Params: "'/tables/migrations', '{replica}'"}
+	assert.Equal(t, "ReplicatedMergeTree('/tables/migrations', '{replica}')", cfg.migrationsTableEngineString())
+}
@@ -43,8 +43,7 @@ func (e *metricsExporter) start(ctx context.Context, _ component.Host) error {
 
 	internal.SetLogger(e.logger)
 
-	ttlExpr := generateTTLExpr(e.cfg.TTLDays, e.cfg.TTL)
-	return internal.NewMetricsTable(ctx, e.cfg.MetricsTableName, ttlExpr, e.client)
+	return migrate(ctx, e.cfg, e.client, e.cfg.MetricsTableName, metricsMigrations)
 }
 
 // shutdown will shut down the exporter.
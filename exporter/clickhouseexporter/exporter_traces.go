@@ -158,7 +158,7 @@ func convertLinks(links ptrace.SpanLinkSlice) ([]string, []string, []string, []m
 const (
 	// language=ClickHouse SQL
 	createTracesTableSQL = `
-CREATE TABLE IF NOT EXISTS %s (
+CREATE TABLE IF NOT EXISTS %s %s (
      Timestamp DateTime64(9) CODEC(Delta, ZSTD(1)),
      TraceId String CODEC(ZSTD(1)),
      SpanId String CODEC(ZSTD(1)),
@@ -191,7 +191,7 @@ CREATE TABLE IF NOT EXISTS %s (
      INDEX idx_span_attr_key mapKeys(SpanAttributes) TYPE bloom_filter(0.01) GRANULARITY 1,
      INDEX idx_span_attr_value mapValues(SpanAttributes) TYPE bloom_filter(0.01) GRANULARITY 1,
      INDEX idx_duration Duration TYPE minmax GRANULARITY 1
-) ENGINE MergeTree()
+) ENGINE %s
 %s
 PARTITION BY toDate(Timestamp)
 ORDER BY (ServiceName, SpanName, toUnixTimestamp(Timestamp), TraceId)
@@ -248,19 +248,21 @@ SETTINGS index_granularity=8192, ttl_only_drop_parts = 1;
 )
 
 const (
+	// language=ClickHouse SQL
 	createTraceIDTsTableSQL = `
-create table IF NOT EXISTS %s_trace_id_ts (
+create table IF NOT EXISTS %s_trace_id_ts %s (
      TraceId String CODEC(ZSTD(1)),
      Start DateTime64(9) CODEC(Delta, ZSTD(1)),
      End DateTime64(9) CODEC(Delta, ZSTD(1)),
      INDEX idx_trace_id TraceId TYPE bloom_filter(0.01) GRANULARITY 1
-) ENGINE MergeTree()
+) ENGINE %s
 %s
 ORDER BY (TraceId, toUnixTimestamp(Start))
 SETTINGS index_granularity=8192;
 `
+	// language=ClickHouse SQL
 	createTraceIDTsMaterializedViewSQL = `
-CREATE MATERIALIZED VIEW IF NOT EXISTS %s_trace_id_ts_mv
+CREATE MATERIALIZED VIEW IF NOT EXISTS %s_trace_id_ts_mv %s
 TO %s.%s_trace_id_ts
 AS SELECT
 TraceId,
@@ -270,18 +272,69 @@ FROM
 %s.%s
 WHERE TraceId!=''
 GROUP BY TraceId;
+`
+	// language=ClickHouse SQL
+	createServiceDependenciesSpansTableSQL = `
+CREATE TABLE IF NOT EXISTS %s_service_dependencies_spans %s (
+     TraceId String CODEC(ZSTD(1)),
+     Timestamp SimpleAggregateFunction(min, DateTime64(9)) CODEC(Delta, ZSTD(1)),
+     Spans AggregateFunction(groupArray, Tuple(String, String, LowCardinality(String))) CODEC(ZSTD(1))
+) ENGINE %s
+%s
+ORDER BY TraceId
+SETTINGS index_granularity=8192;
+`
+	// language=ClickHouse SQL
+	createServiceDependenciesSpansMaterializedViewSQL = `
+CREATE MATERIALIZED VIEW IF NOT EXISTS %s_service_dependencies_spans_mv %s
+TO %s.%s_service_dependencies_spans
+AS SELECT
+TraceId,
+min(Timestamp) as Timestamp,
+groupArrayState((SpanId, ParentSpanId, ServiceName)) as Spans
+FROM
+%s.%s
+WHERE TraceId!=''
+GROUP BY TraceId;
+`
+	// language=ClickHouse SQL
+	createServiceDependenciesViewSQL = `
+CREATE VIEW IF NOT EXISTS %s_service_dependencies %s
+AS SELECT
+toStartOfMinute(Start) as Timestamp,
+ServiceNames[indexOf(SpanIds, Span.2)] as Parent,
+Span.3 as Child,
+count() as CallCount
+FROM (
+    SELECT
+    min(Timestamp) as Start,
+    groupArrayMerge(Spans) as TraceSpans,
+    arrayMap(s -> s.1, TraceSpans) as SpanIds,
+    arrayMap(s -> s.3, TraceSpans) as ServiceNames
+    FROM %s.%s_service_dependencies_spans
+    GROUP BY TraceId
+)
+ARRAY JOIN TraceSpans AS Span
+WHERE Span.2!='' AND indexOf(SpanIds, Span.2)>0 AND Parent!=Child
+GROUP BY Timestamp, Parent, Child;
 `
 )
 
 func createTracesTable(ctx context.Context, cfg *Config, db *sql.DB) error {
-	if _, err := db.ExecContext(ctx, renderCreateTracesTableSQL(cfg)); err != nil {
-		return fmt.Errorf("exec create traces table sql: %w", err)
+	if err := migrate(ctx, cfg, db, cfg.TracesTableName, tracesMigrations); err != nil {
+		return err
 	}
-	if _, err := db.ExecContext(ctx, renderCreateTraceIDTsTableSQL(cfg)); err != nil {
-		return fmt.Errorf("exec create traceIDTs table sql: %w", err)
+	// The optional tables and views are versioned apart from the traces table,
+	// so that enabling them later applies their migrations from the start.
+	if cfg.MaterializedViews.TraceIDTimestamp {
+		if err := migrate(ctx, cfg, db, cfg.TracesTableName+"_trace_id_ts", traceIDTsMigrations); err != nil {
+			return err
+		}
 	}
-	if _, err := db.ExecContext(ctx, renderTraceIDTsMaterializedViewSQL(cfg)); err != nil {
-		return fmt.Errorf("exec create traceIDTs view sql: %w", err)
+	if cfg.MaterializedViews.ServiceDependencies {
+		if err := migrate(ctx, cfg, db, cfg.TracesTableName+"_service_dependencies", serviceDependenciesMigrations); err != nil {
+			return err
+		}
 	}
 	return nil
 }
@@ -292,15 +345,29 @@ func renderInsertTracesSQL(cfg *Config) string {
 
 func renderCreateTracesTableSQL(cfg *Config) string {
 	ttlExpr := generateTTLExpr(cfg.TTLDays, cfg.TTL)
-	return fmt.Sprintf(createTracesTableSQL, cfg.TracesTableName, ttlExpr)
+	return fmt.Sprintf(createTracesTableSQL, cfg.TracesTableName, cfg.clusterString(), cfg.tableEngineString(""), ttlExpr)
 }
 
 func renderCreateTraceIDTsTableSQL(cfg *Config) string {
 	ttlExpr := generateTTLExpr(cfg.TTLDays, cfg.TTL)
-	return fmt.Sprintf(createTraceIDTsTableSQL, cfg.TracesTableName, ttlExpr)
+	return fmt.Sprintf(createTraceIDTsTableSQL, cfg.TracesTableName, cfg.clusterString(), cfg.tableEngineString(""), ttlExpr)
 }
 
 func renderTraceIDTsMaterializedViewSQL(cfg *Config) string {
-	return fmt.Sprintf(createTraceIDTsMaterializedViewSQL, cfg.TracesTableName,
+	return fmt.Sprintf(createTraceIDTsMaterializedViewSQL, cfg.TracesTableName, cfg.clusterString(),
 		cfg.Database, cfg.TracesTableName, cfg.Database, cfg.TracesTableName)
 }
+
+func renderCreateServiceDependenciesSpansTableSQL(cfg *Config) string {
+	ttlExpr := generateTTLExpr(cfg.TTLDays, cfg.TTL)
+	return fmt.Sprintf(createServiceDependenciesSpansTableSQL, cfg.TracesTableName, cfg.clusterString(), cfg.tableEngineString("Aggregating"), ttlExpr)
+}
+
+func renderServiceDependenciesSpansMaterializedViewSQL(cfg *Config) string {
+	return fmt.Sprintf(createServiceDependenciesSpansMaterializedViewSQL, cfg.TracesTableName, cfg.clusterString(),
+		cfg.Database, cfg.TracesTableName, cfg.Database, cfg.TracesTableName)
+}
+
+func renderServiceDependenciesViewSQL(cfg *Config) string {
+	return fmt.Sprintf(createServiceDependenciesViewSQL, cfg.TracesTableName, cfg.clusterString(), cfg.Database, cfg.TracesTableName)
+}
@@ -22,7 +22,7 @@ func TestExporter_pushTracesData(t *testing.T) {
 		var items int
 		initClickhouseTestServer(t, func(query string, values []driver.Value) error {
 			t.Logf("%d, values:%+v", items, values)
-			if strings.HasPrefix(query, "INSERT") {
+			if strings.HasPrefix(query, "INSERT INTO otel_traces") {
 				items++
 			}
 			return nil
@@ -36,7 +36,7 @@ func TestExporter_pushTracesData(t *testing.T) {
 	})
 	t.Run("check insert scopeName and ScopeVersion", func(t *testing.T) {
 		initClickhouseTestServer(t, func(query string, values []driver.Value) error {
-			if strings.HasPrefix(query, "INSERT") {
+			if strings.HasPrefix(query, "INSERT INTO otel_traces") {
 				require.Equal(t, "io.opentelemetry.contrib.clickhouse", values[9])
 				require.Equal(t, "1.0.0", values[10])
 			}
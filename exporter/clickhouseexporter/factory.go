@@ -42,6 +42,13 @@ func createDefaultConfig() component.Config {
 		TracesTableName:  "otel_traces",
 		MetricsTableName: "otel_metrics",
 		TTL:              0,
+		TableEngine: TableEngine{
+			Name: defaultTableEngine,
+		},
+		MigrationsTableName: "otel_schema_migrations",
+		MaterializedViews: MaterializedViewsConfig{
+			TraceIDTimestamp: true,
+		},
 	}
 }
 
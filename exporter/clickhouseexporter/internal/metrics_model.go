@@ -18,12 +18,12 @@ import (
 	"go.uber.org/zap"
 )
 
-var supportedMetricTypes = map[string]struct{}{
-	createGaugeTableSQL:        {},
-	createSumTableSQL:          {},
-	createHistogramTableSQL:    {},
-	createExpHistogramTableSQL: {},
-	createSummaryTableSQL:      {},
+var supportedMetricTypes = []string{
+	createGaugeTableSQL,
+	createSumTableSQL,
+	createHistogramTableSQL,
+	createExpHistogramTableSQL,
+	createSummaryTableSQL,
 }
 
 var logger *zap.Logger
@@ -50,15 +50,14 @@ func SetLogger(l *zap.Logger) {
 	logger = l
 }
 
-// NewMetricsTable create metric tables with an expiry time to storage metric telemetry data
-func NewMetricsTable(ctx context.Context, tableName string, ttlExpr string, db *sql.DB) error {
-	for table := range supportedMetricTypes {
-		query := fmt.Sprintf(table, tableName, ttlExpr)
-		if _, err := db.ExecContext(ctx, query); err != nil {
-			return fmt.Errorf("exec create metrics table sql: %w", err)
-		}
+// RenderCreateMetricsTablesSQL renders the statements creating the metric tables with an expiry time to storage metric telemetry data.
+// cluster is the `ON CLUSTER` clause and engine the table engine of the tables.
+func RenderCreateMetricsTablesSQL(tableName string, cluster string, engine string, ttlExpr string) []string {
+	queries := make([]string, 0, len(supportedMetricTypes))
+	for _, table := range supportedMetricTypes {
+		queries = append(queries, fmt.Sprintf(table, tableName, cluster, engine, ttlExpr))
 	}
-	return nil
+	return queries
 }
 
 // NewMetricsModel create a model for contain different metric data
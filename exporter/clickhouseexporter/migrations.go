@@ -0,0 +1,144 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package clickhouseexporter // import "github.com/open-telemetry/opentelemetry-collector-contrib/exporter/clickhouseexporter"
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/exporter/clickhouseexporter/internal"
+)
+
+// migration is a versioned change of the schema of a table.
+// Migrations are never edited once released, schema changes are appended as new versions.
+type migration struct {
+	version     uint32
+	description string
+	statements  func(cfg *Config) []string
+}
+
+var logsMigrations = []migration{
+	{
+		version:     1,
+		description: "create logs table",
+		statements: func(cfg *Config) []string {
+			return []string{renderCreateLogsTableSQL(cfg)}
+		},
+	},
+}
+
+var tracesMigrations = []migration{
+	{
+		version:     1,
+		description: "create traces table",
+		statements: func(cfg *Config) []string {
+			return []string{renderCreateTracesTableSQL(cfg)}
+		},
+	},
+}
+
+// traceIDTsMigrations create the optional table and materialized view of the trace_id_timestamp setting.
+var traceIDTsMigrations = []migration{
+	{
+		version:     1,
+		description: "create trace id timestamp table and materialized view",
+		statements: func(cfg *Config) []string {
+			return []string{renderCreateTraceIDTsTableSQL(cfg), renderTraceIDTsMaterializedViewSQL(cfg)}
+		},
+	},
+}
+
+// serviceDependenciesMigrations create the optional tables and views of the service_dependencies setting.
+var serviceDependenciesMigrations = []migration{
+	{
+		version:     1,
+		description: "create service dependencies tables and views",
+		statements: func(cfg *Config) []string {
+			return []string{
+				renderCreateServiceDependenciesSpansTableSQL(cfg),
+				renderServiceDependenciesSpansMaterializedViewSQL(cfg),
+				renderServiceDependenciesViewSQL(cfg),
+			}
+		},
+	},
+}
+
+var metricsMigrations = []migration{
+	{
+		version:     1,
+		description: "create metrics tables",
+		statements: func(cfg *Config) []string {
+			ttlExpr := generateTTLExpr(cfg.TTLDays, cfg.TTL)
+			return internal.RenderCreateMetricsTablesSQL(cfg.MetricsTableName, cfg.clusterString(), cfg.tableEngineString(""), ttlExpr)
+		},
+	},
+}
+
+const (
+	// language=ClickHouse SQL
+	createMigrationsTableSQL = `
+CREATE TABLE IF NOT EXISTS %s %s (
+     TableName LowCardinality(String) CODEC(ZSTD(1)),
+     Version UInt32 CODEC(ZSTD(1)),
+     Description String CODEC(ZSTD(1)),
+     AppliedAt DateTime64(3) DEFAULT now64(3) CODEC(Delta, ZSTD(1))
+) ENGINE %s
+ORDER BY (TableName, Version)
+SETTINGS index_granularity=8192;
+`
+	// language=ClickHouse SQL
+	selectMigrationVersionSQL = `SELECT max(Version) FROM %s WHERE TableName = ?`
+	// language=ClickHouse SQL
+	insertMigrationSQL = `INSERT INTO %s (TableName, Version, Description) VALUES (?, ?, ?)`
+)
+
+// migrationLocks holds a *sync.Mutex per database and table, so that the exporters of the
+// collector writing to the same table do not apply its migrations concurrently.
+var migrationLocks sync.Map
+
+// lockMigrations locks the migrations of the table of the database, it returns the unlock function.
+func lockMigrations(database, table string) func() {
+	mu, _ := migrationLocks.LoadOrStore(database+"."+table, &sync.Mutex{})
+	mu.(*sync.Mutex).Lock()
+	return mu.(*sync.Mutex).Unlock
+}
+
+// migrate applies the migrations of table newer than the version recorded in the
+// migrations table, in order, and records every applied version.
+func migrate(ctx context.Context, cfg *Config, db *sql.DB, table string, migrations []migration) error {
+	defer lockMigrations(cfg.Database, table)()
+
+	if _, err := db.ExecContext(ctx, renderCreateMigrationsTableSQL(cfg)); err != nil {
+		return fmt.Errorf("exec create migrations table sql: %w", err)
+	}
+
+	var current uint32
+	err := db.QueryRowContext(ctx, fmt.Sprintf(selectMigrationVersionSQL, cfg.MigrationsTableName), table).Scan(&current)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return fmt.Errorf("query schema version of %s: %w", table, err)
+	}
+
+	insertSQL := fmt.Sprintf(insertMigrationSQL, cfg.MigrationsTableName)
+	for _, m := range migrations {
+		if m.version <= current {
+			continue
+		}
+		for _, statement := range m.statements(cfg) {
+			if _, err := db.ExecContext(ctx, statement); err != nil {
+				return fmt.Errorf("exec migration %d of %s (%s): %w", m.version, table, m.description, err)
+			}
+		}
+		if _, err := db.ExecContext(ctx, insertSQL, table, m.version, m.description); err != nil {
+			return fmt.Errorf("record migration %d of %s: %w", m.version, table, err)
+		}
+	}
+	return nil
+}
+
+func renderCreateMigrationsTableSQL(cfg *Config) string {
+	return fmt.Sprintf(createMigrationsTableSQL, cfg.MigrationsTableName, cfg.clusterString(), cfg.migrationsTableEngineString())
+}
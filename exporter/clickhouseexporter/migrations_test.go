@@ -0,0 +1,206 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package clickhouseexporter
+
+import (
+	"context"
+	"database/sql/driver"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMigrate(t *testing.T) {
+	migrations := []migration{
+		{version: 1, description: "create", statements: func(cfg *Config) []string {
+			return []string{fmt.Sprintf("CREATE TABLE %s", cfg.LogsTableName)}
+		}},
+		{version: 2, description: "add column", statements: func(cfg *Config) []string {
+			return []string{fmt.Sprintf("ALTER TABLE %s ADD COLUMN a String", cfg.LogsTableName), fmt.Sprintf("ALTER TABLE %s ADD COLUMN b String", cfg.LogsTableName)}
+		}},
+		{version: 3, description: "drop column", statements: func(cfg *Config) []string {
+			return []string{fmt.Sprintf("ALTER TABLE %s DROP COLUMN a", cfg.LogsTableName)}
+		}},
+	}
+
+	t.Run("apply all migrations", func(t *testing.T) {
+		var queries []string
+		var versions []driver.Value
+		initClickhouseTestServer(t, func(query string, values []driver.Value) error {
+			queries = append(queries, strings.TrimSpace(query))
+			if strings.HasPrefix(query, "INSERT INTO otel_schema_migrations") {
+				require.Equal(t, "otel_logs", values[0])
+				versions = append(versions, values[1])
+			}
+			return nil
+		})
+
+		cfg := withTestExporterConfig()(defaultEndpoint)
+		db, err := newClickhouseClient(cfg)
+		require.NoError(t, err)
+		defer func() { _ = db.Close() }()
+
+		require.NoError(t, migrate(context.TODO(), cfg, db, cfg.LogsTableName, migrations))
+		require.True(t, strings.HasPrefix(queries[0], "CREATE TABLE IF NOT EXISTS otel_schema_migrations"))
+		require.Equal(t, "SELECT max(Version) FROM otel_schema_migrations WHERE TableName = ?", queries[1])
+		require.Equal(t, []string{
+			"CREATE TABLE otel_logs",
+			"ALTER TABLE otel_logs ADD COLUMN a String",
+			"ALTER TABLE otel_logs ADD COLUMN b String",
+			"ALTER TABLE otel_logs DROP COLUMN a",
+		}, filterQueries(queries, "INSERT", "SELECT", "CREATE TABLE IF NOT EXISTS"))
+		require.Equal(t, []driver.Value{uint32(1), uint32(2), uint32(3)}, versions)
+	})
+
+	t.Run("skip applied migrations", func(t *testing.T) {
+		var queries []string
+		initClickhouseTestServerWithRows(t, func(query string, _ []driver.Value) error {
+			queries = append(queries, strings.TrimSpace(query))
+			return nil
+		}, func(string) [][]driver.Value {
+			return [][]driver.Value{{int64(2)}}
+		})
+
+		cfg := withTestExporterConfig()(defaultEndpoint)
+		db, err := newClickhouseClient(cfg)
+		require.NoError(t, err)
+		defer func() { _ = db.Close() }()
+
+		require.NoError(t, migrate(context.TODO(), cfg, db, cfg.LogsTableName, migrations))
+		require.Equal(t, []string{
+			"ALTER TABLE otel_logs DROP COLUMN a",
+		}, filterQueries(queries, "INSERT", "SELECT", "CREATE TABLE IF NOT EXISTS"))
+	})
+
+	t.Run("stop at failed migration", func(t *testing.T) {
+		var inserts int
+		initClickhouseTestServer(t, func(query string, _ []driver.Value) error {
+			if strings.HasPrefix(query, "ALTER TABLE otel_logs ADD COLUMN b") {
+				return fmt.Errorf("mock alter error")
+			}
+			if strings.HasPrefix(query, "INSERT") {
+				inserts++
+			}
+			return nil
+		})
+
+		cfg := withTestExporterConfig()(defaultEndpoint)
+		db, err := newClickhouseClient(cfg)
+		require.NoError(t, err)
+		defer func() { _ = db.Close() }()
+
+		err = migrate(context.TODO(), cfg, db, cfg.LogsTableName, migrations)
+		require.ErrorContains(t, err, "exec migration 2 of otel_logs (add column): mock alter error")
+		require.Equal(t, 1, inserts)
+	})
+}
+
+func TestCreateTables_ClusterAndEngine(t *testing.T) {
+	var queries []string
+	initClickhouseTestServer(t, func(query string, _ []driver.Value) error {
+		queries = append(queries, query)
+		return nil
+	})
+
+	newTestTracesExporter(t, defaultEndpoint, func(cfg *Config) {
+		cfg.ClusterName = "my_cluster"
+		cfg.TableEngine = TableEngine{Name: "ReplicatedMergeTree", Params: "'/tables/{table}', '{replica}'"}
+		cfg.MaterializedViews.ServiceDependencies = true
+	})
+
+	require.Contains(t, queries[0], "CREATE TABLE IF NOT EXISTS otel_schema_migrations ON CLUSTER my_cluster (")
+	require.Contains(t, queries[0], "ENGINE ReplicatedMergeTree()")
+
+	ddl := filterQueries(queries, "INSERT", "SELECT", "CREATE TABLE IF NOT EXISTS otel_schema_migrations")
+	require.Len(t, ddl, 6)
+	for _, query := range ddl {
+		require.Contains(t, query, "ON CLUSTER my_cluster")
+	}
+	require.Contains(t, ddl[0], "CREATE TABLE IF NOT EXISTS otel_traces ON CLUSTER my_cluster (")
+	require.Contains(t, ddl[0], "ENGINE ReplicatedMergeTree('/tables/{table}', '{replica}')")
+	require.Contains(t, ddl[1], "otel_traces_trace_id_ts ON CLUSTER my_cluster (")
+	require.Contains(t, ddl[2], "CREATE MATERIALIZED VIEW IF NOT EXISTS otel_traces_trace_id_ts_mv ON CLUSTER my_cluster")
+	require.Contains(t, ddl[3], "CREATE TABLE IF NOT EXISTS otel_traces_service_dependencies_spans ON CLUSTER my_cluster (")
+	require.Contains(t, ddl[3], "ENGINE ReplicatedAggregatingMergeTree('/tables/{table}', '{replica}')")
+	require.Contains(t, ddl[4], "CREATE MATERIALIZED VIEW IF NOT EXISTS otel_traces_service_dependencies_spans_mv ON CLUSTER my_cluster")
+	require.Contains(t, ddl[5], "CREATE VIEW IF NOT EXISTS otel_traces_service_dependencies ON CLUSTER my_cluster")
+	for _, query := range ddl {
+		require.NotContains(t, query, "JOIN otel_traces")
+	}
+}
+
+func TestCreateTables_MaterializedViewsMigrations(t *testing.T) {
+	var tables []driver.Value
+	initClickhouseTestServer(t, func(query string, values []driver.Value) error {
+		if strings.HasPrefix(query, "INSERT INTO otel_schema_migrations") {
+			tables = append(tables, values[0])
+		}
+		return nil
+	})
+
+	newTestTracesExporter(t, defaultEndpoint, func(cfg *Config) {
+		cfg.MaterializedViews.ServiceDependencies = true
+	})
+
+	require.Equal(t, []driver.Value{"otel_traces", "otel_traces_trace_id_ts", "otel_traces_service_dependencies"}, tables)
+}
+
+func TestLockMigrations(t *testing.T) {
+	unlock := lockMigrations("db", "otel_logs")
+
+	// The migrations of other tables are not blocked
+	lockMigrations("db", "otel_traces")()
+	lockMigrations("other_db", "otel_logs")()
+
+	locked := make(chan struct{})
+	go func() {
+		lockMigrations("db", "otel_logs")()
+		close(locked)
+	}()
+	select {
+	case <-locked:
+		t.Fatal("the migrations of the same table were not serialized")
+	case <-time.After(50 * time.Millisecond):
+	}
+	unlock()
+	<-locked
+}
+
+func TestCreateTables_WithoutMaterializedViews(t *testing.T) {
+	var queries []string
+	initClickhouseTestServer(t, func(query string, _ []driver.Value) error {
+		queries = append(queries, query)
+		return nil
+	})
+
+	newTestTracesExporter(t, defaultEndpoint, func(cfg *Config) {
+		cfg.MaterializedViews.TraceIDTimestamp = false
+	})
+
+	for _, query := range queries {
+		require.NotContains(t, query, "MATERIALIZED VIEW")
+	}
+}
+
+// filterQueries returns the queries not starting with any of the prefixes, trimmed.
+func filterQueries(queries []string, prefixes ...string) []string {
+	var filtered []string
+	for _, query := range queries {
+		query = strings.TrimSpace(query)
+		skip := false
+		for _, prefix := range prefixes {
+			if strings.HasPrefix(query, prefix) {
+				skip = true
+				break
+			}
+		}
+		if !skip {
+			filtered = append(filtered, query)
+		}
+	}
+	return filtered
+}
@@ -49,6 +49,10 @@ type Config struct {
 	TracesIndex string `mapstructure:"traces_index"`
 	// fall back to pure TracesIndex, if 'elasticsearch.index.prefix' or 'elasticsearch.index.suffix' are not found in resource or attribute (prio: resource > attribute)
 	TracesDynamicIndex DynamicIndexSetting `mapstructure:"traces_dynamic_index"`
+	// This setting is required when metrics pipelines used.
+	MetricsIndex string `mapstructure:"metrics_index"`
+	// fall back to pure MetricsIndex, if 'elasticsearch.index.prefix' or 'elasticsearch.index.suffix' are not found in resource or attribute (prio: resource > attribute)
+	MetricsDynamicIndex DynamicIndexSetting `mapstructure:"metrics_dynamic_index"`
 
 	// Pipeline configures the ingest node pipeline name that should be used to process the
 	// events.
@@ -65,6 +69,29 @@ type Config struct {
 
 type DynamicIndexSetting struct {
 	Enabled bool `mapstructure:"enabled"`
+
+	// Mode configures how the index is derived from the attributes.
+	// Valid values are "prefix_suffix" (default) and "data_stream".
+	Mode string `mapstructure:"mode"`
+}
+
+// Dynamic index modes.
+const (
+	// DynamicIndexModePrefixSuffix prefixes and suffixes the configured index with the
+	// 'elasticsearch.index.prefix' and 'elasticsearch.index.suffix' attributes.
+	DynamicIndexModePrefixSuffix = "prefix_suffix"
+	// DynamicIndexModeDataStream routes events to the `{type}-{dataset}-{namespace}`
+	// data stream named by the 'data_stream.dataset' and 'data_stream.namespace' attributes.
+	DynamicIndexModeDataStream = "data_stream"
+)
+
+func (s DynamicIndexSetting) validate() error {
+	switch s.Mode {
+	case "", DynamicIndexModePrefixSuffix, DynamicIndexModeDataStream:
+		return nil
+	default:
+		return fmt.Errorf("unknown dynamic index mode %q", s.Mode)
+	}
 }
 
 type HTTPClientSettings struct {
@@ -168,6 +195,7 @@ type MappingMode int
 const (
 	MappingNone MappingMode = iota
 	MappingECS
+	MappingOTel
 )
 
 var (
@@ -181,6 +209,8 @@ func (m MappingMode) String() string {
 		return ""
 	case MappingECS:
 		return "ecs"
+	case MappingOTel:
+		return "otel"
 	default:
 		return ""
 	}
@@ -191,6 +221,7 @@ var mappingModes = func() map[string]MappingMode {
 	for _, m := range []MappingMode{
 		MappingNone,
 		MappingECS,
+		MappingOTel,
 	} {
 		table[strings.ToLower(m.String())] = m
 	}
@@ -198,6 +229,7 @@ var mappingModes = func() map[string]MappingMode {
 	// config aliases
 	table["no"] = MappingNone
 	table["none"] = MappingNone
+	table["raw"] = MappingNone
 
 	return table
 }()
@@ -218,9 +250,26 @@ func (cfg *Config) Validate() error {
 		}
 	}
 
-	if _, ok := mappingModes[cfg.Mapping.Mode]; !ok {
+	if _, ok := mappingModes[strings.ToLower(cfg.Mapping.Mode)]; !ok {
 		return fmt.Errorf("unknown mapping mode %v", cfg.Mapping.Mode)
 	}
 
+	if err := cfg.LogsDynamicIndex.validate(); err != nil {
+		return fmt.Errorf("logs_dynamic_index: %w", err)
+	}
+	if err := cfg.TracesDynamicIndex.validate(); err != nil {
+		return fmt.Errorf("traces_dynamic_index: %w", err)
+	}
+	if err := cfg.MetricsDynamicIndex.validate(); err != nil {
+		return fmt.Errorf("metrics_dynamic_index: %w", err)
+	}
+
 	return nil
 }
+
+// MappingMode returns the mapping.mode defined in the given cfg
+// object. This method must be called after cfg.Validate() has been
+// called without returning an error.
+func (cfg *Config) MappingMode() MappingMode {
+	return mappingModes[strings.ToLower(cfg.Mapping.Mode)]
+}
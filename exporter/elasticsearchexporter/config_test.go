@@ -33,12 +33,13 @@ func TestLoad_DeprecatedIndexConfigOption(t *testing.T) {
 			NumConsumers: exporterhelper.NewDefaultQueueSettings().NumConsumers,
 			QueueSize:    exporterhelper.NewDefaultQueueSettings().QueueSize,
 		},
-		Endpoints:   []string{"http://localhost:9200"},
-		CloudID:     "TRNMxjXlNJEt",
-		Index:       "my_log_index",
-		LogsIndex:   "logs-generic-default",
-		TracesIndex: "traces-generic-default",
-		Pipeline:    "mypipeline",
+		Endpoints:    []string{"http://localhost:9200"},
+		CloudID:      "TRNMxjXlNJEt",
+		Index:        "my_log_index",
+		LogsIndex:    "logs-generic-default",
+		TracesIndex:  "traces-generic-default",
+		MetricsIndex: "metrics-generic-default",
+		Pipeline:     "mypipeline",
 		HTTPClientSettings: HTTPClientSettings{
 			Authentication: AuthenticationSettings{
 				User:     "elastic",
@@ -95,12 +96,13 @@ func TestLoadConfig(t *testing.T) {
 					NumConsumers: exporterhelper.NewDefaultQueueSettings().NumConsumers,
 					QueueSize:    exporterhelper.NewDefaultQueueSettings().QueueSize,
 				},
-				Endpoints:   []string{"https://elastic.example.com:9200"},
-				CloudID:     "TRNMxjXlNJEt",
-				Index:       "",
-				LogsIndex:   "logs-generic-default",
-				TracesIndex: "trace_index",
-				Pipeline:    "mypipeline",
+				Endpoints:    []string{"https://elastic.example.com:9200"},
+				CloudID:      "TRNMxjXlNJEt",
+				Index:        "",
+				LogsIndex:    "logs-generic-default",
+				TracesIndex:  "trace_index",
+				MetricsIndex: "metrics-generic-default",
+				Pipeline:     "mypipeline",
 				HTTPClientSettings: HTTPClientSettings{
 					Authentication: AuthenticationSettings{
 						User:     "elastic",
@@ -140,12 +142,13 @@ func TestLoadConfig(t *testing.T) {
 					NumConsumers: exporterhelper.NewDefaultQueueSettings().NumConsumers,
 					QueueSize:    exporterhelper.NewDefaultQueueSettings().QueueSize,
 				},
-				Endpoints:   []string{"http://localhost:9200"},
-				CloudID:     "TRNMxjXlNJEt",
-				Index:       "",
-				LogsIndex:   "my_log_index",
-				TracesIndex: "traces-generic-default",
-				Pipeline:    "mypipeline",
+				Endpoints:    []string{"http://localhost:9200"},
+				CloudID:      "TRNMxjXlNJEt",
+				Index:        "",
+				LogsIndex:    "my_log_index",
+				TracesIndex:  "traces-generic-default",
+				MetricsIndex: "metrics-generic-default",
+				Pipeline:     "mypipeline",
 				HTTPClientSettings: HTTPClientSettings{
 					Authentication: AuthenticationSettings{
 						User:     "elastic",
@@ -0,0 +1,106 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package elasticsearchexporter // import "github.com/open-telemetry/opentelemetry-collector-contrib/exporter/elasticsearchexporter"
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/exporter/elasticsearchexporter/internal/objmodel"
+)
+
+// data stream attribute key constants
+const (
+	dataStreamType      = "data_stream.type"
+	dataStreamDataset   = "data_stream.dataset"
+	dataStreamNamespace = "data_stream.namespace"
+)
+
+// data stream types, see https://www.elastic.co/guide/en/fleet/current/data-streams.html#data-streams-naming-scheme
+const (
+	dataStreamTypeLogs    = "logs"
+	dataStreamTypeTraces  = "traces"
+	dataStreamTypeMetrics = "metrics"
+
+	defaultDataStreamDataset   = "generic"
+	defaultDataStreamNamespace = "default"
+
+	// maxDataStreamNameLength is the maximum length in bytes of the dataset and namespace.
+	maxDataStreamNameLength = 100
+)
+
+// dataStream identifies the data stream a record is routed to.
+type dataStream struct {
+	typ       string
+	dataset   string
+	namespace string
+}
+
+// index returns the name of the data stream, `{type}-{dataset}-{namespace}`.
+func (ds dataStream) index() string {
+	return fmt.Sprintf("%s-%s-%s", ds.typ, ds.dataset, ds.namespace)
+}
+
+// routeDataStream resolves the data stream of a record from the 'data_stream.dataset'
+// and 'data_stream.namespace' attributes (prio: record > scope > resource).
+func routeDataStream(typ string, resource, scope, record attrGetter) dataStream {
+	return dataStream{
+		typ:       typ,
+		dataset:   sanitizeDataStreamField(lookupAttribute(dataStreamDataset, record, scope, resource), defaultDataStreamDataset, "-"),
+		namespace: sanitizeDataStreamField(lookupAttribute(dataStreamNamespace, record, scope, resource), defaultDataStreamNamespace, ""),
+	}
+}
+
+// addDataStreamFields adds the 'data_stream.*' fields of the data stream the record is
+// routed to, as expected by the data stream mappings, if the data stream mode is enabled.
+func addDataStreamFields(document *objmodel.Document, setting DynamicIndexSetting, typ string, resource, scope, record attrGetter) {
+	if !setting.Enabled || setting.Mode != DynamicIndexModeDataStream {
+		return
+	}
+	ds := routeDataStream(typ, resource, scope, record)
+	document.AddString(dataStreamType, ds.typ)
+	document.AddString(dataStreamDataset, ds.dataset)
+	document.AddString(dataStreamNamespace, ds.namespace)
+}
+
+// lookupAttribute returns the value of the first getter holding the attribute.
+func lookupAttribute(name string, getters ...attrGetter) string {
+	for _, g := range getters {
+		if val, exist := g.Attributes().Get(name); exist {
+			return val.AsString()
+		}
+	}
+	return ""
+}
+
+// sanitizeDataStreamField lowercases the value and replaces the characters that
+// are not allowed in data stream names with underscores.
+func sanitizeDataStreamField(value, defaultValue, disallowed string) string {
+	if value == "" {
+		return defaultValue
+	}
+	value = strings.Map(func(r rune) rune {
+		if strings.ContainsRune(`\/*?"<>| ,#:`+disallowed, r) {
+			return '_'
+		}
+		return r
+	}, strings.ToLower(value))
+	if len(value) > maxDataStreamNameLength {
+		value = value[:maxDataStreamNameLength]
+	}
+	return value
+}
+
+// routeIndex returns the index a record is indexed in according to the dynamic index setting.
+func routeIndex(index string, setting DynamicIndexSetting, typ string, resource, scope, record attrGetter) string {
+	if !setting.Enabled {
+		return index
+	}
+	if setting.Mode == DynamicIndexModeDataStream {
+		return routeDataStream(typ, resource, scope, record).index()
+	}
+	prefix := getFromBothResourceAndAttribute(indexPrefix, resource, record)
+	suffix := getFromBothResourceAndAttribute(indexSuffix, resource, record)
+	return fmt.Sprintf("%s%s%s", prefix, index, suffix)
+}
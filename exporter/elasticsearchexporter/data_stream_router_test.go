@@ -0,0 +1,69 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package elasticsearchexporter
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/plog"
+)
+
+func TestRouteDataStream(t *testing.T) {
+	tests := []struct {
+		name     string
+		resource map[string]string
+		scope    map[string]string
+		record   map[string]string
+		expected string
+	}{
+		{
+			name:     "defaults",
+			expected: "logs-generic-default",
+		},
+		{
+			name:     "resource attributes",
+			resource: map[string]string{dataStreamDataset: "nginx.access", dataStreamNamespace: "prod"},
+			expected: "logs-nginx.access-prod",
+		},
+		{
+			name:     "record attributes take precedence over scope and resource",
+			resource: map[string]string{dataStreamDataset: "resource", dataStreamNamespace: "resource"},
+			scope:    map[string]string{dataStreamDataset: "scope", dataStreamNamespace: "scope"},
+			record:   map[string]string{dataStreamDataset: "record"},
+			expected: "logs-record-scope",
+		},
+		{
+			name:     "sanitize invalid characters",
+			record:   map[string]string{dataStreamDataset: "My-App/Access", dataStreamNamespace: "Team-A:prod"},
+			expected: "logs-my_app_access-team-a_prod",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resource := pcommon.NewResource()
+			fillResourceAttributeMap(resource.Attributes(), tt.resource)
+			scope := pcommon.NewInstrumentationScope()
+			fillResourceAttributeMap(scope.Attributes(), tt.scope)
+			record := plog.NewLogRecord()
+			fillResourceAttributeMap(record.Attributes(), tt.record)
+
+			assert.Equal(t, tt.expected, routeDataStream(dataStreamTypeLogs, resource, scope, record).index())
+		})
+	}
+}
+
+func TestRouteIndex(t *testing.T) {
+	resource := pcommon.NewResource()
+	resource.Attributes().PutStr(indexPrefix, "prefix-")
+	resource.Attributes().PutStr(dataStreamDataset, "app")
+	scope := pcommon.NewInstrumentationScope()
+	record := plog.NewLogRecord()
+	record.Attributes().PutStr(indexSuffix, "-suffix")
+
+	assert.Equal(t, "index", routeIndex("index", DynamicIndexSetting{}, dataStreamTypeLogs, resource, scope, record))
+	assert.Equal(t, "prefix-index-suffix", routeIndex("index", DynamicIndexSetting{Enabled: true}, dataStreamTypeLogs, resource, scope, record))
+	assert.Equal(t, "logs-app-default", routeIndex("index", DynamicIndexSetting{Enabled: true, Mode: DynamicIndexModeDataStream}, dataStreamTypeLogs, resource, scope, record))
+}
@@ -0,0 +1,178 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package elasticsearchexporter
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/plog"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+	semconv "go.opentelemetry.io/collector/semconv/v1.18.0"
+)
+
+// TestMappingModes_Golden compares the bulk request bodies sent in each mapping
+// mode with the files in testdata/golden/<mode>.
+func TestMappingModes_Golden(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("skipping test on Windows, see https://github.com/open-telemetry/opentelemetry-collector-contrib/issues/10178")
+	}
+
+	for _, mode := range []string{"none", "ecs", "otel"} {
+		mode := mode
+		withMode := func(cfg *Config) {
+			cfg.Mapping.Mode = mode
+		}
+
+		t.Run(mode+"/logs", func(t *testing.T) {
+			rec, url := newGoldenTestServer(t)
+			exporter := newTestLogsExporter(t, url, withMode)
+			require.NoError(t, exporter.pushLogsData(context.TODO(), newGoldenLogs()))
+			rec.WaitItems(2)
+			assertGoldenBulkBody(t, filepath.Join("testdata", "golden", mode, "logs.ndjson"), rec)
+		})
+
+		t.Run(mode+"/traces", func(t *testing.T) {
+			rec, url := newGoldenTestServer(t)
+			exporter := newTestTracesExporter(t, url, withMode)
+			require.NoError(t, exporter.pushTraceData(context.TODO(), newGoldenTraces()))
+			rec.WaitItems(1)
+			assertGoldenBulkBody(t, filepath.Join("testdata", "golden", mode, "traces.ndjson"), rec)
+		})
+
+		t.Run(mode+"/metrics", func(t *testing.T) {
+			rec, url := newGoldenTestServer(t)
+			exporter := newTestMetricsExporter(t, url, withMode)
+			require.NoError(t, exporter.pushMetricsData(context.TODO(), newGoldenMetrics()))
+			rec.WaitItems(2)
+			assertGoldenBulkBody(t, filepath.Join("testdata", "golden", mode, "metrics.ndjson"), rec)
+		})
+	}
+}
+
+func newGoldenTestServer(t *testing.T) (*bulkRecorder, string) {
+	rec := newBulkRecorder()
+	server := newESTestServer(t, func(docs []itemRequest) ([]itemResponse, error) {
+		rec.Record(docs)
+		return itemsAllOK(docs)
+	})
+	return rec, server.URL
+}
+
+func assertGoldenBulkBody(t *testing.T, file string, rec *bulkRecorder) {
+	var body bytes.Buffer
+	for _, item := range rec.Items() {
+		body.Write(item.Action)
+		body.WriteByte('\n')
+		body.Write(item.Document)
+		body.WriteByte('\n')
+	}
+
+	expected, err := os.ReadFile(file)
+	require.NoError(t, err)
+	assert.Equal(t, string(expected), body.String())
+}
+
+func fillGoldenResource(resource pcommon.Resource) {
+	attrs := resource.Attributes()
+	attrs.PutStr(semconv.AttributeServiceName, "checkout")
+	attrs.PutStr(semconv.AttributeServiceVersion, "1.2.3")
+	attrs.PutStr(semconv.AttributeServiceInstanceID, "checkout-1")
+	attrs.PutStr(semconv.AttributeDeploymentEnvironment, "production")
+	attrs.PutStr(semconv.AttributeHostName, "web-1")
+	attrs.PutStr(semconv.AttributeHostArch, "amd64")
+	attrs.PutStr(semconv.AttributeOSType, "linux")
+	attrs.PutStr(semconv.AttributeK8SPodName, "checkout-6d4b9c")
+	attrs.PutStr(semconv.AttributeK8SNamespaceName, "shop")
+	attrs.PutStr(semconv.AttributeTelemetrySDKName, "opentelemetry")
+	attrs.PutStr(semconv.AttributeTelemetrySDKLanguage, "go")
+}
+
+func fillGoldenScope(scope pcommon.InstrumentationScope) {
+	scope.SetName("checkout/handler")
+	scope.SetVersion("0.1.0")
+	scope.Attributes().PutStr("owner", "payments")
+}
+
+func newGoldenLogs() plog.Logs {
+	ts := time.Date(2023, 12, 1, 10, 0, 0, 0, time.UTC)
+
+	logs := plog.NewLogs()
+	rl := logs.ResourceLogs().AppendEmpty()
+	fillGoldenResource(rl.Resource())
+	sl := rl.ScopeLogs().AppendEmpty()
+	fillGoldenScope(sl.Scope())
+
+	record := sl.LogRecords().AppendEmpty()
+	record.SetTimestamp(pcommon.NewTimestampFromTime(ts))
+	record.SetObservedTimestamp(pcommon.NewTimestampFromTime(ts.Add(time.Second)))
+	record.SetSeverityText("ERROR")
+	record.SetSeverityNumber(plog.SeverityNumberError)
+	record.SetTraceID([16]byte{1, 2, 3, 4, 5, 6, 7, 8, 8, 7, 6, 5, 4, 3, 2, 1})
+	record.SetSpanID([8]byte{1, 2, 3, 4, 5, 6, 7, 8})
+	record.Body().SetStr("payment failed")
+	record.Attributes().PutStr(semconv.AttributeExceptionType, "PaymentError")
+	record.Attributes().PutStr(semconv.AttributeCodeFunction, "Charge")
+	record.Attributes().PutInt("order.items", 3)
+
+	structured := sl.LogRecords().AppendEmpty()
+	structured.SetObservedTimestamp(pcommon.NewTimestampFromTime(ts.Add(2 * time.Second)))
+	structured.SetSeverityText("INFO")
+	structured.SetSeverityNumber(plog.SeverityNumberInfo)
+	structured.Body().SetEmptyMap().PutStr("event", "order.created")
+
+	return logs
+}
+
+func newGoldenTraces() ptrace.Traces {
+	start := time.Date(2023, 12, 1, 10, 0, 0, 0, time.UTC)
+
+	traces := ptrace.NewTraces()
+	rs := traces.ResourceSpans().AppendEmpty()
+	fillGoldenResource(rs.Resource())
+	ss := rs.ScopeSpans().AppendEmpty()
+	fillGoldenScope(ss.Scope())
+
+	span := ss.Spans().AppendEmpty()
+	span.SetName("POST /checkout")
+	span.SetKind(ptrace.SpanKindServer)
+	span.SetTraceID([16]byte{1, 2, 3, 4, 5, 6, 7, 8, 8, 7, 6, 5, 4, 3, 2, 1})
+	span.SetSpanID([8]byte{1, 2, 3, 4, 5, 6, 7, 8})
+	span.SetParentSpanID([8]byte{8, 7, 6, 5, 4, 3, 2, 1})
+	span.SetStartTimestamp(pcommon.NewTimestampFromTime(start))
+	span.SetEndTimestamp(pcommon.NewTimestampFromTime(start.Add(250 * time.Millisecond)))
+	span.Status().SetCode(ptrace.StatusCodeError)
+	span.Status().SetMessage("payment failed")
+	span.Attributes().PutStr(semconv.AttributeHTTPMethod, "POST")
+	span.Attributes().PutInt(semconv.AttributeHTTPStatusCode, 502)
+	span.Attributes().PutStr(semconv.AttributeHTTPTarget, "/checkout")
+
+	event := span.Events().AppendEmpty()
+	event.SetName("retry")
+	event.SetTimestamp(pcommon.NewTimestampFromTime(start.Add(100 * time.Millisecond)))
+	event.Attributes().PutInt("attempt", 2)
+
+	link := span.Links().AppendEmpty()
+	link.SetTraceID([16]byte{1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1})
+	link.SetSpanID([8]byte{2, 2, 2, 2, 2, 2, 2, 2})
+
+	return traces
+}
+
+func newGoldenMetrics() pmetric.Metrics {
+	metrics := newTestMetrics()
+	rm := metrics.ResourceMetrics().At(0)
+	rm.Resource().Attributes().Clear()
+	fillGoldenResource(rm.Resource())
+	return metrics
+}
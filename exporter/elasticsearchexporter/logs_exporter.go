@@ -19,7 +19,7 @@ type elasticsearchLogsExporter struct {
 	logger *zap.Logger
 
 	index        string
-	dynamicIndex bool
+	dynamicIndex DynamicIndexSetting
 	maxAttempts  int
 
 	client      *esClientCurrent
@@ -51,7 +51,11 @@ func newLogsExporter(logger *zap.Logger, cfg *Config) (*elasticsearchLogsExporte
 		maxAttempts = cfg.Retry.MaxRequests
 	}
 
-	model := &encodeModel{dedup: cfg.Mapping.Dedup, dedot: cfg.Mapping.Dedot}
+	model := &encodeModel{
+		dedup: cfg.Mapping.Dedup,
+		dedot: cfg.Mapping.Dedot,
+		mode:  cfg.MappingMode(),
+	}
 
 	indexStr := cfg.LogsIndex
 	if cfg.Index != "" {
@@ -63,7 +67,7 @@ func newLogsExporter(logger *zap.Logger, cfg *Config) (*elasticsearchLogsExporte
 		bulkIndexer: bulkIndexer,
 
 		index:        indexStr,
-		dynamicIndex: cfg.LogsDynamicIndex.Enabled,
+		dynamicIndex: cfg.LogsDynamicIndex,
 		maxAttempts:  maxAttempts,
 		model:        model,
 	}
@@ -101,17 +105,13 @@ func (e *elasticsearchLogsExporter) pushLogsData(ctx context.Context, ld plog.Lo
 }
 
 func (e *elasticsearchLogsExporter) pushLogRecord(ctx context.Context, resource pcommon.Resource, record plog.LogRecord, scope pcommon.InstrumentationScope) error {
-	fIndex := e.index
-	if e.dynamicIndex {
-		prefix := getFromBothResourceAndAttribute(indexPrefix, resource, record)
-		suffix := getFromBothResourceAndAttribute(indexSuffix, resource, record)
-
-		fIndex = fmt.Sprintf("%s%s%s", prefix, fIndex, suffix)
-	}
+	fIndex := routeIndex(e.index, e.dynamicIndex, dataStreamTypeLogs, resource, scope, record)
 
-	document, err := e.model.encodeLog(resource, record, scope)
+	document := e.model.newLogDocument(resource, record, scope)
+	addDataStreamFields(&document, e.dynamicIndex, dataStreamTypeLogs, resource, scope, record)
+	encoded, err := e.model.encodeDocument(document)
 	if err != nil {
 		return fmt.Errorf("Failed to encode log event: %w", err)
 	}
-	return pushDocuments(ctx, e.logger, fIndex, document, e.bulkIndexer, e.maxAttempts)
+	return pushDocuments(ctx, e.logger, fIndex, encoded, e.bulkIndexer, e.maxAttempts)
 }
@@ -121,7 +121,7 @@ func TestExporter_New(t *testing.T) {
 				cfg.Mapping.Dedot = false
 				cfg.Mapping.Dedup = true
 			}),
-			want: successWithInternalModel(&encodeModel{dedot: false, dedup: true}),
+			want: successWithInternalModel(&encodeModel{dedot: false, dedup: true, mode: MappingECS}),
 		},
 	}
 
@@ -210,6 +210,35 @@ func TestExporter_PushEvent(t *testing.T) {
 		rec.WaitItems(1)
 	})
 
+	t.Run("publish to data stream", func(t *testing.T) {
+		rec := newBulkRecorder()
+		server := newESTestServer(t, func(docs []itemRequest) ([]itemResponse, error) {
+			rec.Record(docs)
+			return itemsAllOK(docs)
+		})
+
+		exporter := newTestLogsExporter(t, server.URL, func(cfg *Config) {
+			cfg.LogsDynamicIndex.Enabled = true
+			cfg.LogsDynamicIndex.Mode = DynamicIndexModeDataStream
+		})
+
+		mustSendLogsWithAttributes(t, exporter,
+			map[string]string{dataStreamDataset: "nginx.access"},
+			map[string]string{dataStreamNamespace: "prod"},
+		)
+
+		rec.WaitItems(1)
+		item := rec.Items()[0]
+		assert.Equal(t, "logs-nginx.access-prod", actionIndex(t, item))
+		var document map[string]any
+		require.NoError(t, json.Unmarshal(item.Document, &document))
+		assert.Equal(t, map[string]any{
+			"type":      "logs",
+			"dataset":   "nginx.access",
+			"namespace": "prod",
+		}, document["data_stream"])
+	})
+
 	t.Run("retry http request", func(t *testing.T) {
 		failures := 0
 		rec := newBulkRecorder()
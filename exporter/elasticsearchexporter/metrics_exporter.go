@@ -0,0 +1,185 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package elasticsearchexporter // import "github.com/open-telemetry/opentelemetry-collector-contrib/exporter/elasticsearchexporter"
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.uber.org/zap"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/exporter/elasticsearchexporter/internal/objmodel"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/pdatautil"
+)
+
+type elasticsearchMetricsExporter struct {
+	logger *zap.Logger
+
+	index        string
+	dynamicIndex DynamicIndexSetting
+	maxAttempts  int
+
+	client      *esClientCurrent
+	bulkIndexer esBulkIndexerCurrent
+	model       mappingModel
+}
+
+// metricDocumentKey identifies the document the data points sharing the same
+// index, resource, scope, attributes and timestamp are grouped into.
+type metricDocumentKey struct {
+	index        string
+	timestamp    pcommon.Timestamp
+	resource     [16]byte
+	scopeName    string
+	scopeVersion string
+	scope        [16]byte
+	attributes   [16]byte
+}
+
+func newMetricsExporter(logger *zap.Logger, cfg *Config) (*elasticsearchMetricsExporter, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+
+	client, err := newElasticsearchClient(logger, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	bulkIndexer, err := newBulkIndexer(logger, client, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	maxAttempts := 1
+	if cfg.Retry.Enabled {
+		maxAttempts = cfg.Retry.MaxRequests
+	}
+
+	model := &encodeModel{
+		dedup: cfg.Mapping.Dedup,
+		dedot: cfg.Mapping.Dedot,
+		mode:  cfg.MappingMode(),
+	}
+
+	return &elasticsearchMetricsExporter{
+		logger:      logger,
+		client:      client,
+		bulkIndexer: bulkIndexer,
+
+		index:        cfg.MetricsIndex,
+		dynamicIndex: cfg.MetricsDynamicIndex,
+		maxAttempts:  maxAttempts,
+		model:        model,
+	}, nil
+}
+
+func (e *elasticsearchMetricsExporter) Shutdown(ctx context.Context) error {
+	return e.bulkIndexer.Close(ctx)
+}
+
+func (e *elasticsearchMetricsExporter) pushMetricsData(ctx context.Context, md pmetric.Metrics) error {
+	var errs []error
+
+	keys := []metricDocumentKey{}
+	documents := map[metricDocumentKey]*objmodel.Document{}
+	var droppedExpHistograms, droppedSummaries int
+
+	rms := md.ResourceMetrics()
+	for i := 0; i < rms.Len(); i++ {
+		rm := rms.At(i)
+		resource := rm.Resource()
+		resourceHash := pdatautil.MapHash(resource.Attributes())
+		sms := rm.ScopeMetrics()
+		for j := 0; j < sms.Len(); j++ {
+			scope := sms.At(j).Scope()
+			scopeHash := pdatautil.MapHash(scope.Attributes())
+			metrics := sms.At(j).Metrics()
+			for k := 0; k < metrics.Len(); k++ {
+				metric := metrics.At(k)
+
+				upsert := func(dp dataPoint, value objmodel.Value) {
+					index := routeIndex(e.index, e.dynamicIndex, dataStreamTypeMetrics, resource, scope, dp)
+					key := metricDocumentKey{
+						index:        index,
+						timestamp:    dp.Timestamp(),
+						resource:     resourceHash,
+						scopeName:    scope.Name(),
+						scopeVersion: scope.Version(),
+						scope:        scopeHash,
+						attributes:   pdatautil.MapHash(dp.Attributes()),
+					}
+					document, ok := documents[key]
+					if !ok {
+						doc := e.model.newMetricDocument(resource, scope, dp.Attributes(), dp.Timestamp())
+						addDataStreamFields(&doc, e.dynamicIndex, dataStreamTypeMetrics, resource, scope, dp)
+						document = &doc
+						documents[key] = document
+						keys = append(keys, key)
+					}
+					e.model.addMetricValue(document, metric.Name(), value)
+				}
+
+				switch metric.Type() {
+				case pmetric.MetricTypeGauge:
+					dps := metric.Gauge().DataPoints()
+					for l := 0; l < dps.Len(); l++ {
+						if value, ok := numberDataPointValue(dps.At(l)); ok {
+							upsert(dps.At(l), value)
+						}
+					}
+				case pmetric.MetricTypeSum:
+					dps := metric.Sum().DataPoints()
+					for l := 0; l < dps.Len(); l++ {
+						if value, ok := numberDataPointValue(dps.At(l)); ok {
+							upsert(dps.At(l), value)
+						}
+					}
+				case pmetric.MetricTypeHistogram:
+					dps := metric.Histogram().DataPoints()
+					for l := 0; l < dps.Len(); l++ {
+						if value, ok := histogramDataPointValue(dps.At(l)); ok {
+							upsert(dps.At(l), value)
+						}
+					}
+				case pmetric.MetricTypeExponentialHistogram:
+					droppedExpHistograms += metric.ExponentialHistogram().DataPoints().Len()
+				case pmetric.MetricTypeSummary:
+					droppedSummaries += metric.Summary().DataPoints().Len()
+				}
+			}
+		}
+	}
+
+	if droppedExpHistograms > 0 || droppedSummaries > 0 {
+		e.logger.Warn("Dropped data points of unsupported metric types",
+			zap.Int("exponential_histogram", droppedExpHistograms),
+			zap.Int("summary", droppedSummaries))
+	}
+
+	for _, key := range keys {
+		document, err := e.model.encodeDocument(*documents[key])
+		if err != nil {
+			errs = append(errs, fmt.Errorf("Failed to encode metric document: %w", err))
+			continue
+		}
+		if err := pushDocuments(ctx, e.logger, key.index, document, e.bulkIndexer, e.maxAttempts); err != nil {
+			if cerr := ctx.Err(); cerr != nil {
+				return cerr
+			}
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// dataPoint is implemented by the data points of all supported metric types.
+type dataPoint interface {
+	Timestamp() pcommon.Timestamp
+	Attributes() pcommon.Map
+}
@@ -0,0 +1,183 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package elasticsearchexporter
+
+import (
+	"context"
+	"encoding/json"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestMetricsExporter_New(t *testing.T) {
+	t.Run("invalid dynamic index mode", func(t *testing.T) {
+		_, err := newMetricsExporter(zaptest.NewLogger(t), withDefaultConfig(func(cfg *Config) {
+			cfg.Endpoints = []string{"http://localhost:9200"}
+			cfg.MetricsDynamicIndex.Mode = "unknown"
+		}))
+		assert.EqualError(t, err, `metrics_dynamic_index: unknown dynamic index mode "unknown"`)
+	})
+}
+
+func TestMetricsExporter_PushMetricsData(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("skipping test on Windows, see https://github.com/open-telemetry/opentelemetry-collector-contrib/issues/10178")
+	}
+
+	t.Run("group data points", func(t *testing.T) {
+		rec := newBulkRecorder()
+		server := newESTestServer(t, func(docs []itemRequest) ([]itemResponse, error) {
+			rec.Record(docs)
+			return itemsAllOK(docs)
+		})
+
+		exporter := newTestMetricsExporter(t, server.URL, func(cfg *Config) {
+			cfg.Mapping.Mode = "none"
+		})
+		require.NoError(t, exporter.pushMetricsData(context.TODO(), newTestMetrics()))
+
+		rec.WaitItems(2)
+		items := rec.Items()
+		assert.Equal(t, "metrics-generic-default", actionIndex(t, items[0]))
+		assert.JSONEq(t, `{
+			"@timestamp": "2023-12-01T10:00:00.000000000Z",
+			"Attributes": {"cpu": "0"},
+			"Resource": {"host": {"name": "web-1"}},
+			"Scope": {"name": "scraper"},
+			"system": {"cpu": {"time": 1.5, "load": 2}}
+		}`, string(items[0].Document))
+		assert.Equal(t, "metrics-generic-default", actionIndex(t, items[1]))
+		assert.JSONEq(t, `{
+			"@timestamp": "2023-12-01T10:00:00.000000000Z",
+			"Attributes": {"cpu": "1"},
+			"Resource": {"host": {"name": "web-1"}},
+			"Scope": {"name": "scraper"},
+			"system": {"cpu": {"time": 3}},
+			"http": {"duration": {"values": [1, 2.5, 5], "counts": [1, 3, 2]}}
+		}`, string(items[1].Document))
+	})
+
+	t.Run("log dropped data points", func(t *testing.T) {
+		server := newESTestServer(t, func(docs []itemRequest) ([]itemResponse, error) {
+			return itemsAllOK(docs)
+		})
+
+		core, logs := observer.New(zap.WarnLevel)
+		exporter, err := newMetricsExporter(zap.New(core), withTestExporterConfig()(server.URL))
+		require.NoError(t, err)
+		t.Cleanup(func() {
+			require.NoError(t, exporter.Shutdown(context.TODO()))
+		})
+
+		require.NoError(t, exporter.pushMetricsData(context.TODO(), newTestMetrics()))
+		require.Equal(t, 1, logs.Len())
+		entry := logs.All()[0]
+		assert.Equal(t, "Dropped data points of unsupported metric types", entry.Message)
+		assert.Equal(t, map[string]any{"exponential_histogram": int64(0), "summary": int64(1)}, entry.ContextMap())
+	})
+
+	t.Run("route to data streams", func(t *testing.T) {
+		rec := newBulkRecorder()
+		server := newESTestServer(t, func(docs []itemRequest) ([]itemResponse, error) {
+			rec.Record(docs)
+			return itemsAllOK(docs)
+		})
+
+		exporter := newTestMetricsExporter(t, server.URL, func(cfg *Config) {
+			cfg.MetricsDynamicIndex.Enabled = true
+			cfg.MetricsDynamicIndex.Mode = DynamicIndexModeDataStream
+		})
+
+		metrics := newTestMetrics()
+		rm := metrics.ResourceMetrics().At(0)
+		rm.Resource().Attributes().PutStr(dataStreamDataset, "system.cpu")
+		rm.Resource().Attributes().PutStr(dataStreamNamespace, "prod")
+		rm.ScopeMetrics().At(0).Metrics().At(2).Histogram().DataPoints().At(0).Attributes().PutStr(dataStreamDataset, "http")
+		require.NoError(t, exporter.pushMetricsData(context.TODO(), metrics))
+
+		rec.WaitItems(3)
+		indices := map[string]int{}
+		for _, item := range rec.Items() {
+			indices[actionIndex(t, item)]++
+
+			var document map[string]any
+			require.NoError(t, json.Unmarshal(item.Document, &document))
+			assert.Equal(t, "metrics", document["data_stream"].(map[string]any)["type"])
+		}
+		assert.Equal(t, map[string]int{"metrics-system.cpu-prod": 2, "metrics-http-prod": 1}, indices)
+	})
+}
+
+func newTestMetricsExporter(t *testing.T, url string, fns ...func(*Config)) *elasticsearchMetricsExporter {
+	exporter, err := newMetricsExporter(zaptest.NewLogger(t), withTestExporterConfig(fns...)(url))
+	require.NoError(t, err)
+
+	t.Cleanup(func() {
+		require.NoError(t, exporter.Shutdown(context.TODO()))
+	})
+	return exporter
+}
+
+// newTestMetrics creates a gauge and a sum for two CPUs and a histogram for the
+// second CPU, all sharing the same timestamp.
+func newTestMetrics() pmetric.Metrics {
+	ts := pcommon.NewTimestampFromTime(time.Date(2023, 12, 1, 10, 0, 0, 0, time.UTC))
+
+	metrics := pmetric.NewMetrics()
+	rm := metrics.ResourceMetrics().AppendEmpty()
+	rm.Resource().Attributes().PutStr("host.name", "web-1")
+	sm := rm.ScopeMetrics().AppendEmpty()
+	sm.Scope().SetName("scraper")
+
+	sum := sm.Metrics().AppendEmpty()
+	sum.SetName("system.cpu.time")
+	sumDps := sum.SetEmptySum().DataPoints()
+	for i, v := range []float64{1.5, 3} {
+		dp := sumDps.AppendEmpty()
+		dp.SetTimestamp(ts)
+		dp.SetDoubleValue(v)
+		dp.Attributes().PutStr("cpu", []string{"0", "1"}[i])
+	}
+
+	gauge := sm.Metrics().AppendEmpty()
+	gauge.SetName("system.cpu.load")
+	dp := gauge.SetEmptyGauge().DataPoints().AppendEmpty()
+	dp.SetTimestamp(ts)
+	dp.SetIntValue(2)
+	dp.Attributes().PutStr("cpu", "0")
+
+	histogram := sm.Metrics().AppendEmpty()
+	histogram.SetName("http.duration")
+	hdp := histogram.SetEmptyHistogram().DataPoints().AppendEmpty()
+	hdp.SetTimestamp(ts)
+	hdp.Attributes().PutStr("cpu", "1")
+	hdp.ExplicitBounds().FromRaw([]float64{1, 4, 5})
+	hdp.BucketCounts().FromRaw([]uint64{1, 3, 0, 2})
+
+	summary := sm.Metrics().AppendEmpty()
+	summary.SetName("unsupported")
+	sdp := summary.SetEmptySummary().DataPoints().AppendEmpty()
+	sdp.SetTimestamp(ts)
+
+	return metrics
+}
+
+func actionIndex(t *testing.T, item itemRequest) string {
+	var action struct {
+		Create struct {
+			Index string `json:"_index"`
+		} `json:"create"`
+	}
+	require.NoError(t, json.Unmarshal(item.Action, &action))
+	return action.Create.Index
+}
@@ -6,23 +6,30 @@ package elasticsearchexporter // import "github.com/open-telemetry/opentelemetry
 import (
 	"bytes"
 	"encoding/json"
+	"strings"
 	"time"
 
 	"go.opentelemetry.io/collector/pdata/pcommon"
 	"go.opentelemetry.io/collector/pdata/plog"
+	"go.opentelemetry.io/collector/pdata/pmetric"
 	"go.opentelemetry.io/collector/pdata/ptrace"
+	semconv "go.opentelemetry.io/collector/semconv/v1.18.0"
 
 	"github.com/open-telemetry/opentelemetry-collector-contrib/exporter/elasticsearchexporter/internal/objmodel"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/coreinternal/traceutil"
 )
 
 type mappingModel interface {
-	encodeLog(pcommon.Resource, plog.LogRecord, pcommon.InstrumentationScope) ([]byte, error)
-	encodeSpan(pcommon.Resource, ptrace.Span, pcommon.InstrumentationScope) ([]byte, error)
+	newLogDocument(pcommon.Resource, plog.LogRecord, pcommon.InstrumentationScope) objmodel.Document
+	newSpanDocument(pcommon.Resource, ptrace.Span, pcommon.InstrumentationScope) objmodel.Document
+	newMetricDocument(pcommon.Resource, pcommon.InstrumentationScope, pcommon.Map, pcommon.Timestamp) objmodel.Document
+	addMetricValue(*objmodel.Document, string, objmodel.Value)
+	encodeDocument(objmodel.Document) ([]byte, error)
 }
 
 // encodeModel tries to keep the event as close to the original open telemetry semantics as is.
-// No fields will be mapped by default.
+// No fields will be mapped by default. The ECS mapping mode maps the fields to the
+// Elastic Common Schema instead, and the OTel mapping mode keeps the OTLP structure.
 //
 // Field deduplication and dedotting of attributes is supported by the encodeModel.
 //
@@ -30,6 +37,7 @@ type mappingModel interface {
 type encodeModel struct {
 	dedup bool
 	dedot bool
+	mode  MappingMode
 }
 
 const (
@@ -38,7 +46,18 @@ const (
 	attributeField = "attribute"
 )
 
-func (m *encodeModel) encodeLog(resource pcommon.Resource, record plog.LogRecord, scope pcommon.InstrumentationScope) ([]byte, error) {
+func (m *encodeModel) newLogDocument(resource pcommon.Resource, record plog.LogRecord, scope pcommon.InstrumentationScope) objmodel.Document {
+	switch m.mode {
+	case MappingECS:
+		return m.encodeLogECSMode(resource, record, scope)
+	case MappingOTel:
+		return m.encodeLogOTelMode(resource, record, scope)
+	default:
+		return m.encodeLogDefaultMode(resource, record, scope)
+	}
+}
+
+func (m *encodeModel) encodeLogDefaultMode(resource pcommon.Resource, record plog.LogRecord, scope pcommon.InstrumentationScope) objmodel.Document {
 	var document objmodel.Document
 	document.AddTimestamp("@timestamp", record.Timestamp()) // We use @timestamp in order to ensure that we can index if the default data stream logs template is used.
 	document.AddTraceID("TraceId", record.TraceID())
@@ -50,19 +69,72 @@ func (m *encodeModel) encodeLog(resource pcommon.Resource, record plog.LogRecord
 	document.AddAttributes("Attributes", record.Attributes())
 	document.AddAttributes("Resource", resource.Attributes())
 	document.AddAttributes("Scope", scopeToAttributes(scope))
+	return document
+}
 
-	if m.dedup {
-		document.Dedup()
-	} else if m.dedot {
-		document.Sort()
+// encodeLogECSMode maps the log record to the Elastic Common Schema (ECS).
+//
+// See: https://www.elastic.co/guide/en/ecs/current/ecs-field-reference.html
+func (m *encodeModel) encodeLogECSMode(resource pcommon.Resource, record plog.LogRecord, scope pcommon.InstrumentationScope) objmodel.Document {
+	var document objmodel.Document
+	document.AddAttributes("", ecsAttributes(resource.Attributes(), resourceAttrsConversionMap))
+	document.AddAttributes("", ecsAttributes(record.Attributes(), logAttrsConversionMap))
+
+	document.AddTimestamp("@timestamp", logTimestamp(record))
+	document.AddTraceID("trace.id", record.TraceID())
+	document.AddSpanID("span.id", record.SpanID())
+	document.AddString("log.level", record.SeverityText())
+	if record.SeverityNumber() != plog.SeverityNumberUnspecified {
+		document.AddInt("event.severity", int64(record.SeverityNumber()))
 	}
+	document.AddString("log.logger", scope.Name())
+	if record.Body().Type() != pcommon.ValueTypeEmpty {
+		document.AddString("message", record.Body().AsString())
+	}
+	return document
+}
 
-	var buf bytes.Buffer
-	err := document.Serialize(&buf, m.dedot)
-	return buf.Bytes(), err
+// encodeLogOTelMode keeps the structure of the OTLP log record, with the resource,
+// scope and record attributes in their own objects.
+func (m *encodeModel) encodeLogOTelMode(resource pcommon.Resource, record plog.LogRecord, scope pcommon.InstrumentationScope) objmodel.Document {
+	var document objmodel.Document
+	document.AddTimestamp("@timestamp", logTimestamp(record))
+	document.AddTimestamp("observed_timestamp", record.ObservedTimestamp())
+	document.AddTraceID("trace_id", record.TraceID())
+	document.AddSpanID("span_id", record.SpanID())
+	if record.Flags() != 0 {
+		document.AddInt("flags", int64(record.Flags()))
+	}
+	document.AddString("severity_text", record.SeverityText())
+	if record.SeverityNumber() != plog.SeverityNumberUnspecified {
+		document.AddInt("severity_number", int64(record.SeverityNumber()))
+	}
+	switch body := record.Body(); body.Type() {
+	case pcommon.ValueTypeEmpty:
+	case pcommon.ValueTypeMap:
+		document.AddAttributes("body.structured", body.Map())
+	default:
+		document.AddString("body.text", body.AsString())
+	}
+	document.AddAttributes("attributes", record.Attributes())
+	addCount(&document, "dropped_attributes_count", record.DroppedAttributesCount())
+	encodeResourceOTelMode(&document, resource)
+	encodeScopeOTelMode(&document, scope)
+	return document
 }
 
-func (m *encodeModel) encodeSpan(resource pcommon.Resource, span ptrace.Span, scope pcommon.InstrumentationScope) ([]byte, error) {
+func (m *encodeModel) newSpanDocument(resource pcommon.Resource, span ptrace.Span, scope pcommon.InstrumentationScope) objmodel.Document {
+	switch m.mode {
+	case MappingECS:
+		return m.encodeSpanECSMode(resource, span)
+	case MappingOTel:
+		return m.encodeSpanOTelMode(resource, span, scope)
+	default:
+		return m.encodeSpanDefaultMode(resource, span, scope)
+	}
+}
+
+func (m *encodeModel) encodeSpanDefaultMode(resource pcommon.Resource, span ptrace.Span, scope pcommon.InstrumentationScope) objmodel.Document {
 	var document objmodel.Document
 	document.AddTimestamp("@timestamp", span.StartTimestamp()) // We use @timestamp in order to ensure that we can index if the default data stream logs template is used.
 	document.AddTimestamp("EndTimestamp", span.EndTimestamp())
@@ -78,7 +150,124 @@ func (m *encodeModel) encodeSpan(resource pcommon.Resource, span ptrace.Span, sc
 	document.AddEvents("Events", span.Events())
 	document.AddInt("Duration", durationAsMicroseconds(span.StartTimestamp().AsTime(), span.EndTimestamp().AsTime())) // unit is microseconds
 	document.AddAttributes("Scope", scopeToAttributes(scope))
+	return document
+}
+
+// encodeSpanECSMode maps the span to the Elastic Common Schema (ECS).
+//
+// See: https://www.elastic.co/guide/en/ecs/current/ecs-field-reference.html
+func (m *encodeModel) encodeSpanECSMode(resource pcommon.Resource, span ptrace.Span) objmodel.Document {
+	var document objmodel.Document
+	document.AddAttributes("", ecsAttributes(resource.Attributes(), resourceAttrsConversionMap))
+	document.AddAttributes("", ecsAttributes(span.Attributes(), spanAttrsConversionMap))
+
+	document.AddTimestamp("@timestamp", span.StartTimestamp())
+	document.AddTraceID("trace.id", span.TraceID())
+	document.AddSpanID("span.id", span.SpanID())
+	document.AddSpanID("parent.id", span.ParentSpanID())
+	document.AddString("span.name", span.Name())
+	document.AddString("span.kind", strings.ToLower(span.Kind().String()))
+	document.AddInt("event.duration", int64(span.EndTimestamp()-span.StartTimestamp())) // unit is nanoseconds
+	switch span.Status().Code() {
+	case ptrace.StatusCodeOk:
+		document.AddString("event.outcome", "success")
+	case ptrace.StatusCodeError:
+		document.AddString("event.outcome", "failure")
+		document.AddString("error.message", span.Status().Message())
+	default:
+		document.AddString("event.outcome", "unknown")
+	}
+	if span.Links().Len() > 0 {
+		document.AddString("span.links", spanLinksToString(span.Links()))
+	}
+	document.AddEvents("span.events", span.Events())
+	return document
+}
 
+// encodeSpanOTelMode keeps the structure of the OTLP span, with the resource,
+// scope and span attributes in their own objects.
+func (m *encodeModel) encodeSpanOTelMode(resource pcommon.Resource, span ptrace.Span, scope pcommon.InstrumentationScope) objmodel.Document {
+	var document objmodel.Document
+	document.AddTimestamp("@timestamp", span.StartTimestamp())
+	document.AddTraceID("trace_id", span.TraceID())
+	document.AddSpanID("span_id", span.SpanID())
+	document.AddSpanID("parent_span_id", span.ParentSpanID())
+	document.AddString("trace_state", span.TraceState().AsRaw())
+	document.AddString("name", span.Name())
+	document.AddString("kind", span.Kind().String())
+	document.AddInt("duration", int64(span.EndTimestamp()-span.StartTimestamp())) // unit is nanoseconds
+	document.AddString("status.code", span.Status().Code().String())
+	document.AddString("status.message", span.Status().Message())
+	document.AddAttributes("attributes", span.Attributes())
+	addCount(&document, "dropped_attributes_count", span.DroppedAttributesCount())
+	addCount(&document, "dropped_events_count", span.DroppedEventsCount())
+	addCount(&document, "dropped_links_count", span.DroppedLinksCount())
+
+	links := make([]objmodel.Value, 0, span.Links().Len())
+	for i := 0; i < span.Links().Len(); i++ {
+		link := span.Links().At(i)
+		var linkDocument objmodel.Document
+		linkDocument.AddTraceID("trace_id", link.TraceID())
+		linkDocument.AddSpanID("span_id", link.SpanID())
+		linkDocument.AddString("trace_state", link.TraceState().AsRaw())
+		linkDocument.AddAttributes("attributes", link.Attributes())
+		linkDocument.Sort()
+		links = append(links, objmodel.ObjectValue(linkDocument))
+	}
+	document.Add("links", objmodel.ArrValue(links...))
+
+	events := make([]objmodel.Value, 0, span.Events().Len())
+	for i := 0; i < span.Events().Len(); i++ {
+		event := span.Events().At(i)
+		var eventDocument objmodel.Document
+		eventDocument.AddTimestamp("timestamp", event.Timestamp())
+		eventDocument.AddString("name", event.Name())
+		eventDocument.AddAttributes("attributes", event.Attributes())
+		eventDocument.Sort()
+		events = append(events, objmodel.ObjectValue(eventDocument))
+	}
+	document.Add("events", objmodel.ArrValue(events...))
+
+	encodeResourceOTelMode(&document, resource)
+	encodeScopeOTelMode(&document, scope)
+	return document
+}
+
+// newMetricDocument creates the document holding the values of all metric data points
+// sharing the resource, scope, attributes and timestamp.
+//
+// Attributes are added as strings, so that they are mapped as keyword dimensions
+// by time series data streams.
+func (m *encodeModel) newMetricDocument(resource pcommon.Resource, scope pcommon.InstrumentationScope, attributes pcommon.Map, timestamp pcommon.Timestamp) objmodel.Document {
+	var document objmodel.Document
+	document.AddTimestamp("@timestamp", timestamp)
+	switch m.mode {
+	case MappingECS:
+		addKeywordAttributes(&document, "", ecsAttributes(resource.Attributes(), resourceAttrsConversionMap))
+		addKeywordAttributes(&document, "", attributes)
+	case MappingOTel:
+		addKeywordAttributes(&document, "attributes", attributes)
+		addKeywordAttributes(&document, "resource.attributes", resource.Attributes())
+		document.AddString("scope.name", scope.Name())
+		document.AddString("scope.version", scope.Version())
+		addKeywordAttributes(&document, "scope.attributes", scope.Attributes())
+	default:
+		addKeywordAttributes(&document, "Attributes", attributes)
+		addKeywordAttributes(&document, "Resource", resource.Attributes())
+		addKeywordAttributes(&document, "Scope", scopeToAttributes(scope))
+	}
+	return document
+}
+
+// addMetricValue adds the value of a metric data point to its document.
+func (m *encodeModel) addMetricValue(document *objmodel.Document, name string, value objmodel.Value) {
+	if m.mode == MappingOTel {
+		name = "metrics." + name
+	}
+	document.Add(name, value)
+}
+
+func (m *encodeModel) encodeDocument(document objmodel.Document) ([]byte, error) {
 	if m.dedup {
 		document.Dedup()
 	} else if m.dedot {
@@ -90,6 +279,109 @@ func (m *encodeModel) encodeSpan(resource pcommon.Resource, span ptrace.Span, sc
 	return buf.Bytes(), err
 }
 
+// addKeywordAttributes adds the attributes to the document as string values.
+// Nested maps are flattened.
+func addKeywordAttributes(document *objmodel.Document, key string, attributes pcommon.Map) {
+	attributes.Range(func(k string, v pcommon.Value) bool {
+		if key != "" {
+			k = key + "." + k
+		}
+		switch v.Type() {
+		case pcommon.ValueTypeEmpty:
+		case pcommon.ValueTypeMap:
+			addKeywordAttributes(document, k, v.Map())
+		default:
+			document.AddString(k, v.AsString())
+		}
+		return true
+	})
+}
+
+func encodeResourceOTelMode(document *objmodel.Document, resource pcommon.Resource) {
+	document.AddAttributes("resource.attributes", resource.Attributes())
+	addCount(document, "resource.dropped_attributes_count", resource.DroppedAttributesCount())
+}
+
+func encodeScopeOTelMode(document *objmodel.Document, scope pcommon.InstrumentationScope) {
+	document.AddString("scope.name", scope.Name())
+	document.AddString("scope.version", scope.Version())
+	document.AddAttributes("scope.attributes", scope.Attributes())
+	addCount(document, "scope.dropped_attributes_count", scope.DroppedAttributesCount())
+}
+
+// addCount adds the count of dropped attributes, events or links, omitting zeros.
+func addCount(document *objmodel.Document, key string, count uint32) {
+	if count > 0 {
+		document.AddInt(key, int64(count))
+	}
+}
+
+// logTimestamp returns the time of the event, falling back to the time it was
+// observed by the collector.
+func logTimestamp(record plog.LogRecord) pcommon.Timestamp {
+	if record.Timestamp() != 0 {
+		return record.Timestamp()
+	}
+	return record.ObservedTimestamp()
+}
+
+// numberDataPointValue converts the value of a gauge or sum data point.
+func numberDataPointValue(dp pmetric.NumberDataPoint) (objmodel.Value, bool) {
+	switch dp.ValueType() {
+	case pmetric.NumberDataPointValueTypeInt:
+		return objmodel.IntValue(dp.IntValue()), true
+	case pmetric.NumberDataPointValueTypeDouble:
+		return objmodel.DoubleValue(dp.DoubleValue()), true
+	default:
+		return objmodel.Value{}, false
+	}
+}
+
+// histogramDataPointValue converts a histogram data point into the Elasticsearch
+// histogram field format, using the midpoints of the buckets as values.
+// Empty buckets are skipped, and so are data points without any counts.
+//
+// https://www.elastic.co/guide/en/elasticsearch/reference/current/histogram.html
+func histogramDataPointValue(dp pmetric.HistogramDataPoint) (objmodel.Value, bool) {
+	bounds := dp.ExplicitBounds()
+	bucketCounts := dp.BucketCounts()
+
+	var values, counts []objmodel.Value
+	for i := 0; i < bucketCounts.Len(); i++ {
+		count := bucketCounts.At(i)
+		if count == 0 {
+			continue
+		}
+
+		var value float64
+		switch {
+		case bounds.Len() == 0:
+			// A single bucket without bounds only carries the count.
+			value = 0
+		case i == 0:
+			// The first bucket has no lower bound, use its upper bound.
+			value = bounds.At(0)
+		case i >= bounds.Len():
+			// The last bucket has no upper bound, use its lower bound.
+			value = bounds.At(bounds.Len() - 1)
+		default:
+			value = bounds.At(i-1) + (bounds.At(i)-bounds.At(i-1))/2
+		}
+
+		values = append(values, objmodel.DoubleValue(value))
+		counts = append(counts, objmodel.IntValue(int64(count)))
+	}
+
+	if len(values) == 0 {
+		return objmodel.Value{}, false
+	}
+
+	var document objmodel.Document
+	document.Add("values", objmodel.ArrValue(values...))
+	document.Add("counts", objmodel.ArrValue(counts...))
+	return objmodel.ObjectValue(document), true
+}
+
 func spanLinksToString(spanLinkSlice ptrace.SpanLinkSlice) string {
 	linkArray := make([]map[string]any, 0, spanLinkSlice.Len())
 	for i := 0; i < spanLinkSlice.Len(); i++ {
@@ -119,3 +411,77 @@ func scopeToAttributes(scope pcommon.InstrumentationScope) pcommon.Map {
 	}
 	return attrs
 }
+
+// resourceAttrsConversionMap contains the resource attributes whose semantic
+// convention name differs from their ECS field name. Other attributes are kept
+// as is.
+var resourceAttrsConversionMap = map[string]string{
+	semconv.AttributeServiceInstanceID:     "service.node.name",
+	semconv.AttributeDeploymentEnvironment: "service.environment",
+	semconv.AttributeTelemetrySDKName:      "agent.name",
+	semconv.AttributeTelemetrySDKVersion:   "agent.version",
+	semconv.AttributeTelemetrySDKLanguage:  "service.language.name",
+	semconv.AttributeCloudPlatform:         "cloud.service.name",
+	semconv.AttributeContainerImageTag:     "container.image.tag",
+	semconv.AttributeHostName:              "host.hostname",
+	semconv.AttributeHostArch:              "host.architecture",
+	semconv.AttributeProcessExecutablePath: "process.executable",
+	semconv.AttributeProcessCommandLine:    "process.command_line",
+	semconv.AttributeProcessRuntimeName:    "service.runtime.name",
+	semconv.AttributeProcessRuntimeVersion: "service.runtime.version",
+	semconv.AttributeOSName:                "host.os.name",
+	semconv.AttributeOSType:                "host.os.platform",
+	semconv.AttributeOSDescription:         "host.os.full",
+	semconv.AttributeOSVersion:             "host.os.version",
+	semconv.AttributeK8SDeploymentName:     "kubernetes.deployment.name",
+	semconv.AttributeK8SNamespaceName:      "kubernetes.namespace",
+	semconv.AttributeK8SNodeName:           "kubernetes.node.name",
+	semconv.AttributeK8SPodName:            "kubernetes.pod.name",
+	semconv.AttributeK8SPodUID:             "kubernetes.pod.uid",
+	semconv.AttributeK8SStatefulSetName:    "kubernetes.statefulset.name",
+	semconv.AttributeK8SDaemonSetName:      "kubernetes.daemonset.name",
+	semconv.AttributeK8SReplicaSetName:     "kubernetes.replicaset.name",
+	semconv.AttributeK8SContainerName:      "kubernetes.container.name",
+	semconv.AttributeProcessOwner:          "user.name",
+	semconv.AttributeProcessParentPID:      "process.parent.pid",
+	semconv.AttributeProcessExecutableName: "process.name",
+}
+
+// logAttrsConversionMap contains the log record attributes whose semantic
+// convention name differs from their ECS field name.
+var logAttrsConversionMap = map[string]string{
+	semconv.AttributeExceptionType:       "error.type",
+	semconv.AttributeExceptionMessage:    "error.message",
+	semconv.AttributeExceptionStacktrace: "error.stack_trace",
+	semconv.AttributeCodeFunction:        "log.origin.function",
+	semconv.AttributeCodeFilepath:        "log.origin.file.name",
+	semconv.AttributeCodeLineNumber:      "log.origin.file.line",
+}
+
+// spanAttrsConversionMap contains the span attributes whose semantic
+// convention name differs from their ECS field name.
+var spanAttrsConversionMap = map[string]string{
+	semconv.AttributeHTTPMethod:     "http.request.method",
+	semconv.AttributeHTTPStatusCode: "http.response.status_code",
+	semconv.AttributeHTTPURL:        "url.full",
+	semconv.AttributeHTTPTarget:     "url.original",
+	semconv.AttributeHTTPScheme:     "url.scheme",
+	semconv.AttributeHTTPUserAgent:  "user_agent.original",
+	semconv.AttributeNetPeerName:    "destination.address",
+	semconv.AttributeNetPeerPort:    "destination.port",
+}
+
+// ecsAttributes renames the attributes found in the conversion map to their ECS
+// field names.
+func ecsAttributes(attributes pcommon.Map, conversionMap map[string]string) pcommon.Map {
+	converted := pcommon.NewMap()
+	converted.EnsureCapacity(attributes.Len())
+	attributes.Range(func(k string, v pcommon.Value) bool {
+		if ecsKey, exists := conversionMap[k]; exists {
+			k = ecsKey
+		}
+		v.CopyTo(converted.PutEmpty(k))
+		return true
+	})
+	return converted
+}
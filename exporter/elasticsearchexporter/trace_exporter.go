@@ -19,7 +19,7 @@ type elasticsearchTracesExporter struct {
 	logger *zap.Logger
 
 	index        string
-	dynamicIndex bool
+	dynamicIndex DynamicIndexSetting
 	maxAttempts  int
 
 	client      *esClientCurrent
@@ -47,7 +47,11 @@ func newTracesExporter(logger *zap.Logger, cfg *Config) (*elasticsearchTracesExp
 		maxAttempts = cfg.Retry.MaxRequests
 	}
 
-	model := &encodeModel{dedup: cfg.Mapping.Dedup, dedot: cfg.Mapping.Dedot}
+	model := &encodeModel{
+		dedup: cfg.Mapping.Dedup,
+		dedot: cfg.Mapping.Dedot,
+		mode:  cfg.MappingMode(),
+	}
 
 	return &elasticsearchTracesExporter{
 		logger:      logger,
@@ -55,7 +59,7 @@ func newTracesExporter(logger *zap.Logger, cfg *Config) (*elasticsearchTracesExp
 		bulkIndexer: bulkIndexer,
 
 		index:        cfg.TracesIndex,
-		dynamicIndex: cfg.TracesDynamicIndex.Enabled,
+		dynamicIndex: cfg.TracesDynamicIndex,
 		maxAttempts:  maxAttempts,
 		model:        model,
 	}, nil
@@ -93,17 +97,13 @@ func (e *elasticsearchTracesExporter) pushTraceData(
 }
 
 func (e *elasticsearchTracesExporter) pushTraceRecord(ctx context.Context, resource pcommon.Resource, span ptrace.Span, scope pcommon.InstrumentationScope) error {
-	fIndex := e.index
-	if e.dynamicIndex {
-		prefix := getFromBothResourceAndAttribute(indexPrefix, resource, span)
-		suffix := getFromBothResourceAndAttribute(indexSuffix, resource, span)
-
-		fIndex = fmt.Sprintf("%s%s%s", prefix, fIndex, suffix)
-	}
+	fIndex := routeIndex(e.index, e.dynamicIndex, dataStreamTypeTraces, resource, scope, span)
 
-	document, err := e.model.encodeSpan(resource, span, scope)
+	document := e.model.newSpanDocument(resource, span, scope)
+	addDataStreamFields(&document, e.dynamicIndex, dataStreamTypeTraces, resource, scope, span)
+	encoded, err := e.model.encodeDocument(document)
 	if err != nil {
 		return fmt.Errorf("Failed to encode trace record: %w", err)
 	}
-	return pushDocuments(ctx, e.logger, fIndex, document, e.bulkIndexer, e.maxAttempts)
+	return pushDocuments(ctx, e.logger, fIndex, encoded, e.bulkIndexer, e.maxAttempts)
 }
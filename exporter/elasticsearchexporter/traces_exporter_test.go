@@ -99,7 +99,7 @@ func TestTracesExporter_New(t *testing.T) {
 				cfg.Mapping.Dedot = false
 				cfg.Mapping.Dedup = true
 			}),
-			want: successWithInternalModel(&encodeModel{dedot: false, dedup: true}),
+			want: successWithInternalModel(&encodeModel{dedot: false, dedup: true, mode: MappingECS}),
 		},
 	}
 
@@ -200,6 +200,35 @@ func TestExporter_PushTraceRecord(t *testing.T) {
 		rec.WaitItems(1)
 	})
 
+	t.Run("publish to data stream", func(t *testing.T) {
+		rec := newBulkRecorder()
+		server := newESTestServer(t, func(docs []itemRequest) ([]itemResponse, error) {
+			rec.Record(docs)
+			return itemsAllOK(docs)
+		})
+
+		exporter := newTestTracesExporter(t, server.URL, func(cfg *Config) {
+			cfg.TracesDynamicIndex.Enabled = true
+			cfg.TracesDynamicIndex.Mode = DynamicIndexModeDataStream
+		})
+
+		mustSendTracesWithAttributes(t, exporter,
+			map[string]string{dataStreamDataset: "nginx.access"},
+			map[string]string{dataStreamNamespace: "prod"},
+		)
+
+		rec.WaitItems(1)
+		item := rec.Items()[0]
+		assert.Equal(t, "traces-nginx.access-prod", actionIndex(t, item))
+		var document map[string]any
+		require.NoError(t, json.Unmarshal(item.Document, &document))
+		assert.Equal(t, map[string]any{
+			"type":      "traces",
+			"dataset":   "nginx.access",
+			"namespace": "prod",
+		}, document["data_stream"])
+	})
+
 	t.Run("retry http request", func(t *testing.T) {
 		failures := 0
 		rec := newBulkRecorder()
@@ -38,6 +38,25 @@ type Config struct {
 	// Encoding of messages (default "otlp_proto")
 	Encoding string `mapstructure:"encoding"`
 
+	// PartitionTracesByID sets the message key of outgoing trace messages to
+	// the trace ID, splitting batches so each message holds a single trace.
+	PartitionTracesByID bool `mapstructure:"partition_traces_by_id"`
+
+	// PartitionMetricsByResourceAttributes sets the message key of outgoing
+	// metric messages to a hash of the resource attributes, splitting batches
+	// so each message holds the metrics of a single key.
+	PartitionMetricsByResourceAttributes bool `mapstructure:"partition_metrics_by_resource_attributes"`
+
+	// PartitionLogsByResourceAttributes sets the message key of outgoing log
+	// messages to a hash of the resource attributes, splitting batches so each
+	// message holds the logs of a single key.
+	PartitionLogsByResourceAttributes bool `mapstructure:"partition_logs_by_resource_attributes"`
+
+	// PartitionResourceAttributes restricts the resource attributes used to
+	// build the message key to the given ones. All resource attributes are
+	// used if empty.
+	PartitionResourceAttributes []string `mapstructure:"partition_resource_attributes"`
+
 	// Metadata is the namespace for metadata management properties used by the
 	// Client, and shared by the Producer/Consumer.
 	Metadata Metadata `mapstructure:"metadata"`
@@ -110,6 +129,10 @@ func (cfg *Config) Validate() error {
 		return err
 	}
 
+	if len(cfg.PartitionResourceAttributes) > 0 && !cfg.PartitionMetricsByResourceAttributes && !cfg.PartitionLogsByResourceAttributes {
+		return fmt.Errorf("partition_resource_attributes requires partition_metrics_by_resource_attributes or partition_logs_by_resource_attributes")
+	}
+
 	return validateSASLConfig(cfg.Authentication.SASL)
 }
 
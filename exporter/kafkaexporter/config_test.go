@@ -54,9 +54,12 @@ func TestLoadConfig(t *testing.T) {
 					NumConsumers: 2,
 					QueueSize:    10,
 				},
-				Topic:    "spans",
-				Encoding: "otlp_proto",
-				Brokers:  []string{"foo:123", "bar:456"},
+				Topic:                                "spans",
+				Encoding:                             "otlp_proto",
+				PartitionTracesByID:                  true,
+				PartitionMetricsByResourceAttributes: true,
+				PartitionResourceAttributes:          []string{"service.name"},
+				Brokers:                              []string{"foo:123", "bar:456"},
 				Authentication: kafka.Authentication{
 					PlainText: &kafka.PlainTextConfig{
 						Username: "jdoe",
@@ -106,9 +109,12 @@ func TestLoadConfig(t *testing.T) {
 					NumConsumers: 2,
 					QueueSize:    10,
 				},
-				Topic:    "spans",
-				Encoding: "otlp_proto",
-				Brokers:  []string{"foo:123", "bar:456"},
+				Topic:                                "spans",
+				Encoding:                             "otlp_proto",
+				PartitionTracesByID:                  true,
+				PartitionMetricsByResourceAttributes: true,
+				PartitionResourceAttributes:          []string{"service.name"},
+				Brokers:                              []string{"foo:123", "bar:456"},
 				Authentication: kafka.Authentication{
 					PlainText: &kafka.PlainTextConfig{
 						Username: "jdoe",
@@ -159,6 +165,9 @@ func TestLoadConfig(t *testing.T) {
 				},
 				Topic:                                "spans",
 				Encoding:                             "otlp_proto",
+				PartitionTracesByID:                  true,
+				PartitionMetricsByResourceAttributes: true,
+				PartitionResourceAttributes:          []string{"service.name"},
 				Brokers:                              []string{"foo:123", "bar:456"},
 				ResolveCanonicalBootstrapServersOnly: true,
 				Authentication: kafka.Authentication{
@@ -197,6 +206,18 @@ func TestLoadConfig(t *testing.T) {
 	}
 }
 
+func TestValidate_err_partition_resource_attributes(t *testing.T) {
+	config := &Config{
+		Producer: Producer{
+			Compression: "none",
+		},
+		PartitionResourceAttributes: []string{"service.name"},
+	}
+
+	err := config.Validate()
+	assert.EqualError(t, err, "partition_resource_attributes requires partition_metrics_by_resource_attributes or partition_logs_by_resource_attributes")
+}
+
 func TestValidate_err_compression(t *testing.T) {
 	config := &Config{
 		Producer: Producer{
@@ -0,0 +1,75 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package kafkaexporter // import "github.com/open-telemetry/opentelemetry-collector-contrib/exporter/kafkaexporter"
+
+import (
+	"fmt"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/pdata/plog"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+)
+
+// loadEncodingExtension looks up the extension referenced by encoding in the host
+// and returns it as T. An error is returned if encoding is not a valid component ID,
+// if no such extension is configured or if it does not implement T.
+func loadEncodingExtension[T any](host component.Host, encoding string) (T, error) {
+	var zero T
+	var id component.ID
+	if err := id.UnmarshalText([]byte(encoding)); err != nil {
+		return zero, err
+	}
+	ext, ok := host.GetExtensions()[id]
+	if !ok {
+		return zero, fmt.Errorf("unknown encoding extension %q", encoding)
+	}
+	marshaler, ok := ext.(T)
+	if !ok {
+		return zero, fmt.Errorf("extension %q is not a marshaler for this signal", encoding)
+	}
+	return marshaler, nil
+}
+
+// loadTracesMarshaler returns the marshaler for the configured encoding. Encoding
+// extensions take precedence over the built-in marshalers. The error of the
+// lookup of the extension is returned when there is no built-in marshaler.
+func loadTracesMarshaler(host component.Host, encoding string, builtin TracesMarshaler) (TracesMarshaler, error) {
+	marshaler, err := loadEncodingExtension[ptrace.Marshaler](host, encoding)
+	if err == nil {
+		return newPdataTracesMarshaler(marshaler, encoding), nil
+	}
+	if builtin == nil {
+		return nil, fmt.Errorf("%w: %w", errUnrecognizedEncoding, err)
+	}
+	return builtin, nil
+}
+
+// loadMetricsMarshaler returns the marshaler for the configured encoding. Encoding
+// extensions take precedence over the built-in marshalers. The error of the
+// lookup of the extension is returned when there is no built-in marshaler.
+func loadMetricsMarshaler(host component.Host, encoding string, builtin MetricsMarshaler) (MetricsMarshaler, error) {
+	marshaler, err := loadEncodingExtension[pmetric.Marshaler](host, encoding)
+	if err == nil {
+		return newPdataMetricsMarshaler(marshaler, encoding), nil
+	}
+	if builtin == nil {
+		return nil, fmt.Errorf("%w: %w", errUnrecognizedEncoding, err)
+	}
+	return builtin, nil
+}
+
+// loadLogsMarshaler returns the marshaler for the configured encoding. Encoding
+// extensions take precedence over the built-in marshalers. The error of the
+// lookup of the extension is returned when there is no built-in marshaler.
+func loadLogsMarshaler(host component.Host, encoding string, builtin LogsMarshaler) (LogsMarshaler, error) {
+	marshaler, err := loadEncodingExtension[plog.Marshaler](host, encoding)
+	if err == nil {
+		return newPdataLogsMarshaler(marshaler, encoding), nil
+	}
+	if builtin == nil {
+		return nil, fmt.Errorf("%w: %w", errUnrecognizedEncoding, err)
+	}
+	return builtin, nil
+}
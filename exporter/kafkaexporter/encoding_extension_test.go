@@ -0,0 +1,113 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package kafkaexporter
+
+import (
+	"context"
+	"testing"
+
+	"github.com/IBM/sarama"
+	"github.com/IBM/sarama/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/pdata/plog"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/coreinternal/testdata"
+)
+
+type testEncodingExtension struct {
+	component.StartFunc
+	component.ShutdownFunc
+}
+
+func (testEncodingExtension) MarshalTraces(td ptrace.Traces) ([]byte, error) {
+	return (&ptrace.JSONMarshaler{}).MarshalTraces(td)
+}
+
+func (testEncodingExtension) MarshalMetrics(md pmetric.Metrics) ([]byte, error) {
+	return (&pmetric.JSONMarshaler{}).MarshalMetrics(md)
+}
+
+func (testEncodingExtension) MarshalLogs(ld plog.Logs) ([]byte, error) {
+	return (&plog.JSONMarshaler{}).MarshalLogs(ld)
+}
+
+type testExtensionHost struct {
+	component.Host
+	extensions map[component.ID]component.Component
+}
+
+func (h testExtensionHost) GetExtensions() map[component.ID]component.Component {
+	return h.extensions
+}
+
+func newTestExtensionHost(id component.ID, ext component.Component) component.Host {
+	return testExtensionHost{
+		Host:       componenttest.NewNopHost(),
+		extensions: map[component.ID]component.Component{id: ext},
+	}
+}
+
+func TestLoadTracesMarshaler(t *testing.T) {
+	id := component.NewIDWithName("otlp_encoding", "json")
+	host := newTestExtensionHost(id, &testEncodingExtension{})
+
+	marshaler, err := loadTracesMarshaler(host, id.String(), nil)
+	require.NoError(t, err)
+	assert.Equal(t, "otlp_encoding/json", marshaler.Encoding())
+
+	builtin := tracesMarshalers()[defaultEncoding]
+	marshaler, err = loadTracesMarshaler(host, defaultEncoding, builtin)
+	require.NoError(t, err)
+	assert.Equal(t, builtin, marshaler)
+
+	_, err = loadTracesMarshaler(host, "otlp_encoding/missing", nil)
+	assert.ErrorIs(t, err, errUnrecognizedEncoding)
+	assert.EqualError(t, err, `unrecognized encoding: unknown encoding extension "otlp_encoding/missing"`)
+
+	// The extension does not implement the marshaler of the signal
+	host = newTestExtensionHost(id, struct {
+		component.StartFunc
+		component.ShutdownFunc
+	}{})
+	_, err = loadTracesMarshaler(host, id.String(), nil)
+	assert.ErrorIs(t, err, errUnrecognizedEncoding)
+	assert.EqualError(t, err, `unrecognized encoding: extension "otlp_encoding/json" is not a marshaler for this signal`)
+}
+
+func TestLoadMetricsMarshaler(t *testing.T) {
+	id := component.NewID("otlp_encoding")
+	host := newTestExtensionHost(id, &testEncodingExtension{})
+
+	marshaler, err := loadMetricsMarshaler(host, id.String(), nil)
+	require.NoError(t, err)
+	assert.Equal(t, "otlp_encoding", marshaler.Encoding())
+
+	_, err = loadMetricsMarshaler(host, "foo", nil)
+	assert.ErrorIs(t, err, errUnrecognizedEncoding)
+	assert.EqualError(t, err, `unrecognized encoding: unknown encoding extension "foo"`)
+}
+
+func TestLogsPusher_encodingExtension(t *testing.T) {
+	id := component.NewID("otlp_encoding")
+	producer := mocks.NewSyncProducer(t, sarama.NewConfig())
+	producer.ExpectSendMessageWithCheckerFunctionAndSucceed(func(val []byte) error {
+		_, err := (&plog.JSONUnmarshaler{}).UnmarshalLogs(val)
+		return err
+	})
+
+	p := kafkaLogsProducer{
+		producer: producer,
+		encoding: id.String(),
+	}
+	t.Cleanup(func() {
+		require.NoError(t, p.Close(context.Background()))
+	})
+	require.NoError(t, p.start(context.Background(), newTestExtensionHost(id, &testEncodingExtension{})))
+	require.NoError(t, p.logsDataPusher(context.Background(), testdata.GenerateLogsOneLogRecord()))
+}
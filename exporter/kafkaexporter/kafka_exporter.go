@@ -9,6 +9,7 @@ import (
 	"fmt"
 
 	"github.com/IBM/sarama"
+	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/consumer/consumererror"
 	"go.opentelemetry.io/collector/exporter"
 	"go.opentelemetry.io/collector/pdata/plog"
@@ -23,10 +24,12 @@ var errUnrecognizedEncoding = fmt.Errorf("unrecognized encoding")
 
 // kafkaTracesProducer uses sarama to produce trace messages to Kafka.
 type kafkaTracesProducer struct {
-	producer  sarama.SyncProducer
-	topic     string
-	marshaler TracesMarshaler
-	logger    *zap.Logger
+	producer    sarama.SyncProducer
+	topic       string
+	marshaler   TracesMarshaler
+	encoding    string
+	partitioned bool
+	logger      *zap.Logger
 }
 
 type kafkaErrors struct {
@@ -39,7 +42,13 @@ func (ke kafkaErrors) Error() string {
 }
 
 func (e *kafkaTracesProducer) tracesPusher(_ context.Context, td ptrace.Traces) error {
-	messages, err := e.marshaler.Marshal(td, e.topic)
+	var messages []*sarama.ProducerMessage
+	var err error
+	if e.partitioned {
+		messages, err = marshalKeyed(splitTracesByID(td), e.topic, e.marshaler.Marshal)
+	} else {
+		messages, err = e.marshaler.Marshal(td, e.topic)
+	}
 	if err != nil {
 		return consumererror.NewPermanent(err)
 	}
@@ -60,16 +69,34 @@ func (e *kafkaTracesProducer) Close(context.Context) error {
 	return e.producer.Close()
 }
 
+func (e *kafkaTracesProducer) start(_ context.Context, host component.Host) error {
+	marshaler, err := loadTracesMarshaler(host, e.encoding, e.marshaler)
+	if err != nil {
+		return err
+	}
+	e.marshaler = marshaler
+	return nil
+}
+
 // kafkaMetricsProducer uses sarama to produce metrics messages to kafka
 type kafkaMetricsProducer struct {
-	producer  sarama.SyncProducer
-	topic     string
-	marshaler MetricsMarshaler
-	logger    *zap.Logger
+	producer      sarama.SyncProducer
+	topic         string
+	marshaler     MetricsMarshaler
+	encoding      string
+	partitioned   bool
+	keyAttributes []string
+	logger        *zap.Logger
 }
 
 func (e *kafkaMetricsProducer) metricsDataPusher(_ context.Context, md pmetric.Metrics) error {
-	messages, err := e.marshaler.Marshal(md, e.topic)
+	var messages []*sarama.ProducerMessage
+	var err error
+	if e.partitioned {
+		messages, err = marshalKeyed(splitMetricsByResource(md, e.keyAttributes), e.topic, e.marshaler.Marshal)
+	} else {
+		messages, err = e.marshaler.Marshal(md, e.topic)
+	}
 	if err != nil {
 		return consumererror.NewPermanent(err)
 	}
@@ -90,16 +117,34 @@ func (e *kafkaMetricsProducer) Close(context.Context) error {
 	return e.producer.Close()
 }
 
+func (e *kafkaMetricsProducer) start(_ context.Context, host component.Host) error {
+	marshaler, err := loadMetricsMarshaler(host, e.encoding, e.marshaler)
+	if err != nil {
+		return err
+	}
+	e.marshaler = marshaler
+	return nil
+}
+
 // kafkaLogsProducer uses sarama to produce logs messages to kafka
 type kafkaLogsProducer struct {
-	producer  sarama.SyncProducer
-	topic     string
-	marshaler LogsMarshaler
-	logger    *zap.Logger
+	producer      sarama.SyncProducer
+	topic         string
+	marshaler     LogsMarshaler
+	encoding      string
+	partitioned   bool
+	keyAttributes []string
+	logger        *zap.Logger
 }
 
 func (e *kafkaLogsProducer) logsDataPusher(_ context.Context, ld plog.Logs) error {
-	messages, err := e.marshaler.Marshal(ld, e.topic)
+	var messages []*sarama.ProducerMessage
+	var err error
+	if e.partitioned {
+		messages, err = marshalKeyed(splitLogsByResource(ld, e.keyAttributes), e.topic, e.marshaler.Marshal)
+	} else {
+		messages, err = e.marshaler.Marshal(ld, e.topic)
+	}
 	if err != nil {
 		return consumererror.NewPermanent(err)
 	}
@@ -120,6 +165,15 @@ func (e *kafkaLogsProducer) Close(context.Context) error {
 	return e.producer.Close()
 }
 
+func (e *kafkaLogsProducer) start(_ context.Context, host component.Host) error {
+	marshaler, err := loadLogsMarshaler(host, e.encoding, e.marshaler)
+	if err != nil {
+		return err
+	}
+	e.marshaler = marshaler
+	return nil
+}
+
 func newSaramaProducer(config Config) (sarama.SyncProducer, error) {
 	c := sarama.NewConfig()
 	// These setting are required by the sarama.SyncProducer implementation.
@@ -164,57 +218,62 @@ func newSaramaProducer(config Config) (sarama.SyncProducer, error) {
 }
 
 func newMetricsExporter(config Config, set exporter.CreateSettings, marshalers map[string]MetricsMarshaler) (*kafkaMetricsProducer, error) {
+	// The marshaler may be nil here if the encoding refers to an encoding
+	// extension, it is resolved when the exporter is started.
 	marshaler := marshalers[config.Encoding]
-	if marshaler == nil {
-		return nil, errUnrecognizedEncoding
-	}
 	producer, err := newSaramaProducer(config)
 	if err != nil {
 		return nil, err
 	}
 
 	return &kafkaMetricsProducer{
-		producer:  producer,
-		topic:     config.Topic,
-		marshaler: marshaler,
-		logger:    set.Logger,
+		producer:      producer,
+		topic:         config.Topic,
+		marshaler:     marshaler,
+		encoding:      config.Encoding,
+		partitioned:   config.PartitionMetricsByResourceAttributes,
+		keyAttributes: config.PartitionResourceAttributes,
+		logger:        set.Logger,
 	}, nil
 
 }
 
 // newTracesExporter creates Kafka exporter.
 func newTracesExporter(config Config, set exporter.CreateSettings, marshalers map[string]TracesMarshaler) (*kafkaTracesProducer, error) {
+	// The marshaler may be nil here if the encoding refers to an encoding
+	// extension, it is resolved when the exporter is started.
 	marshaler := marshalers[config.Encoding]
-	if marshaler == nil {
-		return nil, errUnrecognizedEncoding
-	}
 	producer, err := newSaramaProducer(config)
 	if err != nil {
 		return nil, err
 	}
 	return &kafkaTracesProducer{
-		producer:  producer,
-		topic:     config.Topic,
-		marshaler: marshaler,
-		logger:    set.Logger,
+		producer:    producer,
+		topic:       config.Topic,
+		marshaler:   marshaler,
+		encoding:    config.Encoding,
+		partitioned: config.PartitionTracesByID,
+		logger:      set.Logger,
 	}, nil
 }
 
 func newLogsExporter(config Config, set exporter.CreateSettings, marshalers map[string]LogsMarshaler) (*kafkaLogsProducer, error) {
+	// The marshaler may be nil here if the encoding refers to an encoding
+	// extension, it is resolved when the exporter is started.
 	marshaler := marshalers[config.Encoding]
-	if marshaler == nil {
-		return nil, errUnrecognizedEncoding
-	}
 	producer, err := newSaramaProducer(config)
 	if err != nil {
 		return nil, err
 	}
 
 	return &kafkaLogsProducer{
-		producer:  producer,
-		topic:     config.Topic,
-		marshaler: marshaler,
-		logger:    set.Logger,
+		producer:      producer,
+		topic:         config.Topic,
+		marshaler:     marshaler,
+		encoding:      config.Encoding,
+		partitioned:   config.PartitionLogsByResourceAttributes,
+		keyAttributes: config.PartitionResourceAttributes,
+		logger:        set.Logger,
 	}, nil
 
 }
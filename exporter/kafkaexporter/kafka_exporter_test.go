@@ -12,6 +12,7 @@ import (
 	"github.com/IBM/sarama/mocks"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component/componenttest"
 	"go.opentelemetry.io/collector/config/configtls"
 	"go.opentelemetry.io/collector/exporter/exportertest"
 	"go.opentelemetry.io/collector/pdata/plog"
@@ -30,11 +31,14 @@ func TestNewExporter_err_version(t *testing.T) {
 	assert.Nil(t, texp)
 }
 
-func TestNewExporter_err_encoding(t *testing.T) {
-	c := Config{Encoding: "foo"}
-	texp, err := newTracesExporter(c, exportertest.NewNopCreateSettings(), tracesMarshalers())
-	assert.EqualError(t, err, errUnrecognizedEncoding.Error())
-	assert.Nil(t, texp)
+func TestTracesExporterStart_err_encoding(t *testing.T) {
+	p := kafkaTracesProducer{
+		encoding:  "foo",
+		marshaler: tracesMarshalers()["foo"],
+	}
+	err := p.start(context.Background(), componenttest.NewNopHost())
+	assert.ErrorIs(t, err, errUnrecognizedEncoding)
+	assert.EqualError(t, err, `unrecognized encoding: unknown encoding extension "foo"`)
 }
 
 func TestNewMetricsExporter_err_version(t *testing.T) {
@@ -44,18 +48,24 @@ func TestNewMetricsExporter_err_version(t *testing.T) {
 	assert.Nil(t, mexp)
 }
 
-func TestNewMetricsExporter_err_encoding(t *testing.T) {
-	c := Config{Encoding: "bar"}
-	mexp, err := newMetricsExporter(c, exportertest.NewNopCreateSettings(), metricsMarshalers())
-	assert.EqualError(t, err, errUnrecognizedEncoding.Error())
-	assert.Nil(t, mexp)
+func TestMetricsExporterStart_err_encoding(t *testing.T) {
+	p := kafkaMetricsProducer{
+		encoding:  "bar",
+		marshaler: metricsMarshalers()["bar"],
+	}
+	err := p.start(context.Background(), componenttest.NewNopHost())
+	assert.ErrorIs(t, err, errUnrecognizedEncoding)
+	assert.EqualError(t, err, `unrecognized encoding: unknown encoding extension "bar"`)
 }
 
-func TestNewMetricsExporter_err_traces_encoding(t *testing.T) {
-	c := Config{Encoding: "jaeger_proto"}
-	mexp, err := newMetricsExporter(c, exportertest.NewNopCreateSettings(), metricsMarshalers())
-	assert.EqualError(t, err, errUnrecognizedEncoding.Error())
-	assert.Nil(t, mexp)
+func TestMetricsExporterStart_err_traces_encoding(t *testing.T) {
+	p := kafkaMetricsProducer{
+		encoding:  "jaeger_proto",
+		marshaler: metricsMarshalers()["jaeger_proto"],
+	}
+	err := p.start(context.Background(), componenttest.NewNopHost())
+	assert.ErrorIs(t, err, errUnrecognizedEncoding)
+	assert.EqualError(t, err, `unrecognized encoding: unknown encoding extension "jaeger_proto"`)
 }
 
 func TestNewLogsExporter_err_version(t *testing.T) {
@@ -65,18 +75,24 @@ func TestNewLogsExporter_err_version(t *testing.T) {
 	assert.Nil(t, mexp)
 }
 
-func TestNewLogsExporter_err_encoding(t *testing.T) {
-	c := Config{Encoding: "bar"}
-	mexp, err := newLogsExporter(c, exportertest.NewNopCreateSettings(), logsMarshalers())
-	assert.EqualError(t, err, errUnrecognizedEncoding.Error())
-	assert.Nil(t, mexp)
+func TestLogsExporterStart_err_encoding(t *testing.T) {
+	p := kafkaLogsProducer{
+		encoding:  "bar",
+		marshaler: logsMarshalers()["bar"],
+	}
+	err := p.start(context.Background(), componenttest.NewNopHost())
+	assert.ErrorIs(t, err, errUnrecognizedEncoding)
+	assert.EqualError(t, err, `unrecognized encoding: unknown encoding extension "bar"`)
 }
 
-func TestNewLogsExporter_err_traces_encoding(t *testing.T) {
-	c := Config{Encoding: "jaeger_proto"}
-	mexp, err := newLogsExporter(c, exportertest.NewNopCreateSettings(), logsMarshalers())
-	assert.EqualError(t, err, errUnrecognizedEncoding.Error())
-	assert.Nil(t, mexp)
+func TestLogsExporterStart_err_traces_encoding(t *testing.T) {
+	p := kafkaLogsProducer{
+		encoding:  "jaeger_proto",
+		marshaler: logsMarshalers()["jaeger_proto"],
+	}
+	err := p.start(context.Background(), componenttest.NewNopHost())
+	assert.ErrorIs(t, err, errUnrecognizedEncoding)
+	assert.EqualError(t, err, `unrecognized encoding: unknown encoding extension "jaeger_proto"`)
 }
 
 func TestNewExporter_err_auth_type(t *testing.T) {
@@ -303,3 +319,88 @@ func (e logsErrorMarshaler) Marshal(_ plog.Logs, _ string) ([]*sarama.ProducerMe
 func (e logsErrorMarshaler) Encoding() string {
 	panic("implement me")
 }
+
+func TestTracesPusher_partitioned(t *testing.T) {
+	td := ptrace.NewTraces()
+	spans := td.ResourceSpans().AppendEmpty().ScopeSpans().AppendEmpty().Spans()
+	spans.AppendEmpty().SetTraceID([16]byte{1})
+	spans.AppendEmpty().SetTraceID([16]byte{2})
+	spans.AppendEmpty().SetTraceID([16]byte{1})
+
+	producer := mocks.NewSyncProducer(t, sarama.NewConfig())
+	var keys []string
+	for i := 0; i < 2; i++ {
+		producer.ExpectSendMessageWithMessageCheckerFunctionAndSucceed(func(msg *sarama.ProducerMessage) error {
+			key, err := msg.Key.Encode()
+			require.NoError(t, err)
+			keys = append(keys, string(key))
+			return nil
+		})
+	}
+	p := kafkaTracesProducer{
+		producer:    producer,
+		marshaler:   newPdataTracesMarshaler(&ptrace.ProtoMarshaler{}, defaultEncoding),
+		partitioned: true,
+	}
+	t.Cleanup(func() {
+		require.NoError(t, p.Close(context.Background()))
+	})
+	require.NoError(t, p.tracesPusher(context.Background(), td))
+	assert.ElementsMatch(t, []string{
+		"01000000000000000000000000000000",
+		"02000000000000000000000000000000",
+	}, keys)
+}
+
+func TestMetricsDataPusher_partitioned(t *testing.T) {
+	md := pmetric.NewMetrics()
+	for _, service := range []string{"a", "b", "a"} {
+		rm := md.ResourceMetrics().AppendEmpty()
+		rm.Resource().Attributes().PutStr("service.name", service)
+		rm.ScopeMetrics().AppendEmpty().Metrics().AppendEmpty().SetName("requests")
+	}
+
+	producer := mocks.NewSyncProducer(t, sarama.NewConfig())
+	producer.ExpectSendMessageAndSucceed()
+	producer.ExpectSendMessageAndSucceed()
+	p := kafkaMetricsProducer{
+		producer:    producer,
+		marshaler:   newPdataMetricsMarshaler(&pmetric.ProtoMarshaler{}, defaultEncoding),
+		partitioned: true,
+	}
+	t.Cleanup(func() {
+		require.NoError(t, p.Close(context.Background()))
+	})
+	require.NoError(t, p.metricsDataPusher(context.Background(), md))
+}
+
+func TestLogsDataPusher_partitioned(t *testing.T) {
+	ld := plog.NewLogs()
+	for _, host := range []string{"a", "b"} {
+		rl := ld.ResourceLogs().AppendEmpty()
+		rl.Resource().Attributes().PutStr("service.name", "checkout")
+		rl.Resource().Attributes().PutStr("host.name", host)
+		rl.ScopeLogs().AppendEmpty().LogRecords().AppendEmpty().Body().SetStr("hello")
+	}
+
+	producer := mocks.NewSyncProducer(t, sarama.NewConfig())
+	producer.ExpectSendMessageWithMessageCheckerFunctionAndSucceed(func(msg *sarama.ProducerMessage) error {
+		assert.NotNil(t, msg.Key)
+		bts, err := msg.Value.Encode()
+		require.NoError(t, err)
+		logs, err := (&plog.ProtoUnmarshaler{}).UnmarshalLogs(bts)
+		require.NoError(t, err)
+		assert.Equal(t, 2, logs.ResourceLogs().Len())
+		return nil
+	})
+	p := kafkaLogsProducer{
+		producer:      producer,
+		marshaler:     newPdataLogsMarshaler(&plog.ProtoMarshaler{}, defaultEncoding),
+		partitioned:   true,
+		keyAttributes: []string{"service.name"},
+	}
+	t.Cleanup(func() {
+		require.NoError(t, p.Close(context.Background()))
+	})
+	require.NoError(t, p.logsDataPusher(context.Background(), ld))
+}
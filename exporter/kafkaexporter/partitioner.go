@@ -0,0 +1,114 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package kafkaexporter // import "github.com/open-telemetry/opentelemetry-collector-contrib/exporter/kafkaexporter"
+
+import (
+	"github.com/IBM/sarama"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/plog"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/coreinternal/traceutil"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/batchpersignal"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/pdatautil"
+)
+
+// keyed is a batch of telemetry whose messages share the same key.
+type keyed[T any] struct {
+	key  []byte
+	data T
+}
+
+// marshalKeyed marshals each batch and sets its key on the resulting
+// messages, unless the marshaler already set one.
+func marshalKeyed[T any](batches []keyed[T], topic string, marshal func(T, string) ([]*sarama.ProducerMessage, error)) ([]*sarama.ProducerMessage, error) {
+	var messages []*sarama.ProducerMessage
+	for _, batch := range batches {
+		msgs, err := marshal(batch.data, topic)
+		if err != nil {
+			return nil, err
+		}
+		for _, msg := range msgs {
+			if msg.Key == nil {
+				msg.Key = sarama.ByteEncoder(batch.key)
+			}
+		}
+		messages = append(messages, msgs...)
+	}
+	return messages, nil
+}
+
+// splitTracesByID splits td into batches holding the spans of a single trace,
+// keyed by the hex encoded trace ID.
+func splitTracesByID(td ptrace.Traces) []keyed[ptrace.Traces] {
+	split := batchpersignal.SplitTraces(td)
+	batches := make([]keyed[ptrace.Traces], 0, len(split))
+	for _, batch := range split {
+		// Every batch holds at least one span, all of them with the same trace ID.
+		traceID := batch.ResourceSpans().At(0).ScopeSpans().At(0).Spans().At(0).TraceID()
+		batches = append(batches, keyed[ptrace.Traces]{
+			key:  []byte(traceutil.TraceIDToHexOrEmptyString(traceID)),
+			data: batch,
+		})
+	}
+	return batches
+}
+
+// splitMetricsByResource splits md into batches holding the resources with the
+// same key, as built by resourceKey.
+func splitMetricsByResource(md pmetric.Metrics, attributes []string) []keyed[pmetric.Metrics] {
+	var batches []keyed[pmetric.Metrics]
+	index := make(map[string]int)
+	rms := md.ResourceMetrics()
+	for i := 0; i < rms.Len(); i++ {
+		rm := rms.At(i)
+		key := resourceKey(rm.Resource(), attributes)
+		pos, ok := index[string(key)]
+		if !ok {
+			pos = len(batches)
+			index[string(key)] = pos
+			batches = append(batches, keyed[pmetric.Metrics]{key: key, data: pmetric.NewMetrics()})
+		}
+		rm.CopyTo(batches[pos].data.ResourceMetrics().AppendEmpty())
+	}
+	return batches
+}
+
+// splitLogsByResource splits ld into batches holding the resources with the
+// same key, as built by resourceKey.
+func splitLogsByResource(ld plog.Logs, attributes []string) []keyed[plog.Logs] {
+	var batches []keyed[plog.Logs]
+	index := make(map[string]int)
+	rls := ld.ResourceLogs()
+	for i := 0; i < rls.Len(); i++ {
+		rl := rls.At(i)
+		key := resourceKey(rl.Resource(), attributes)
+		pos, ok := index[string(key)]
+		if !ok {
+			pos = len(batches)
+			index[string(key)] = pos
+			batches = append(batches, keyed[plog.Logs]{key: key, data: plog.NewLogs()})
+		}
+		rl.CopyTo(batches[pos].data.ResourceLogs().AppendEmpty())
+	}
+	return batches
+}
+
+// resourceKey returns a hash of the resource attributes, restricted to the
+// given attributes if any.
+func resourceKey(resource pcommon.Resource, attributes []string) []byte {
+	attrs := resource.Attributes()
+	if len(attributes) > 0 {
+		filtered := pcommon.NewMap()
+		for _, name := range attributes {
+			if v, ok := attrs.Get(name); ok {
+				v.CopyTo(filtered.PutEmpty(name))
+			}
+		}
+		attrs = filtered
+	}
+	hash := pdatautil.MapHash(attrs)
+	return hash[:]
+}
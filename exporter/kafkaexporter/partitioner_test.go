@@ -0,0 +1,100 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package kafkaexporter
+
+import (
+	"testing"
+
+	"github.com/IBM/sarama"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/plog"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+)
+
+func TestSplitTracesByID(t *testing.T) {
+	td := ptrace.NewTraces()
+	rs := td.ResourceSpans().AppendEmpty()
+	rs.Resource().Attributes().PutStr("service.name", "checkout")
+	spans := rs.ScopeSpans().AppendEmpty().Spans()
+	spans.AppendEmpty().SetTraceID([16]byte{1})
+	spans.AppendEmpty().SetTraceID([16]byte{2})
+	spans.AppendEmpty().SetTraceID([16]byte{1})
+
+	batches := splitTracesByID(td)
+	require.Len(t, batches, 2)
+	counts := map[string]int{}
+	for _, batch := range batches {
+		counts[string(batch.key)] = batch.data.SpanCount()
+		service, ok := batch.data.ResourceSpans().At(0).Resource().Attributes().Get("service.name")
+		require.True(t, ok)
+		assert.Equal(t, "checkout", service.Str())
+	}
+	assert.Equal(t, map[string]int{
+		"01000000000000000000000000000000": 2,
+		"02000000000000000000000000000000": 1,
+	}, counts)
+}
+
+func TestSplitMetricsByResource(t *testing.T) {
+	md := pmetric.NewMetrics()
+	for _, service := range []string{"a", "b", "a"} {
+		rm := md.ResourceMetrics().AppendEmpty()
+		rm.Resource().Attributes().PutStr("service.name", service)
+		rm.ScopeMetrics().AppendEmpty().Metrics().AppendEmpty().SetName("requests")
+	}
+
+	batches := splitMetricsByResource(md, nil)
+	require.Len(t, batches, 2)
+	assert.Equal(t, 2, batches[0].data.ResourceMetrics().Len())
+	assert.Equal(t, 1, batches[1].data.ResourceMetrics().Len())
+	assert.NotEqual(t, batches[0].key, batches[1].key)
+}
+
+func TestSplitLogsByResource(t *testing.T) {
+	ld := plog.NewLogs()
+	for _, host := range []string{"a", "b"} {
+		rl := ld.ResourceLogs().AppendEmpty()
+		rl.Resource().Attributes().PutStr("service.name", "checkout")
+		rl.Resource().Attributes().PutStr("host.name", host)
+		rl.ScopeLogs().AppendEmpty().LogRecords().AppendEmpty()
+	}
+
+	assert.Len(t, splitLogsByResource(ld, nil), 2)
+
+	batches := splitLogsByResource(ld, []string{"service.name"})
+	require.Len(t, batches, 1)
+	assert.Equal(t, 2, batches[0].data.LogRecordCount())
+}
+
+func TestResourceKey(t *testing.T) {
+	first := pcommon.NewResource()
+	first.Attributes().PutStr("service.name", "checkout")
+	first.Attributes().PutStr("host.name", "a")
+	second := pcommon.NewResource()
+	second.Attributes().PutStr("host.name", "b")
+	second.Attributes().PutStr("service.name", "checkout")
+
+	assert.NotEqual(t, resourceKey(first, nil), resourceKey(second, nil))
+	assert.Equal(t, resourceKey(first, []string{"service.name"}), resourceKey(second, []string{"service.name"}))
+	assert.Equal(t, resourceKey(first, []string{"missing"}), resourceKey(pcommon.NewResource(), nil))
+}
+
+func TestMarshalKeyed(t *testing.T) {
+	batches := []keyed[string]{{key: []byte("a"), data: "1"}, {key: []byte("b"), data: "2"}}
+	messages, err := marshalKeyed(batches, "topic", func(data string, topic string) ([]*sarama.ProducerMessage, error) {
+		msg := &sarama.ProducerMessage{Topic: topic, Value: sarama.StringEncoder(data)}
+		if data == "2" {
+			// Keys set by the marshaler are preserved.
+			msg.Key = sarama.StringEncoder("own")
+		}
+		return []*sarama.ProducerMessage{msg}, nil
+	})
+	require.NoError(t, err)
+	require.Len(t, messages, 2)
+	assert.Equal(t, sarama.ByteEncoder("a"), messages[0].Key)
+	assert.Equal(t, sarama.StringEncoder("own"), messages[1].Key)
+}
@@ -7,13 +7,16 @@ import (
 	"encoding/hex"
 	"fmt"
 	"sort"
+	"unicode/utf8"
 
 	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
 	"github.com/prometheus/common/model"
 	"go.opentelemetry.io/collector/pdata/pcommon"
 	"go.opentelemetry.io/collector/pdata/pmetric"
 	conventions "go.opentelemetry.io/collector/semconv/v1.6.1"
 	"go.uber.org/zap"
+	"google.golang.org/protobuf/proto"
 
 	prometheustranslator "github.com/open-telemetry/opentelemetry-collector-contrib/pkg/translator/prometheus"
 )
@@ -47,6 +50,8 @@ func newCollector(config *Config, logger *zap.Logger) *collector {
 	}
 }
 
+// convertExemplars converts the exemplars, sorted by timestamp so that the latest exemplar
+// is used when only one can be exposed.
 func convertExemplars(exemplars pmetric.ExemplarSlice) []prometheus.Exemplar {
 	length := exemplars.Len()
 	result := make([]prometheus.Exemplar, length)
@@ -63,6 +68,27 @@ func convertExemplars(exemplars pmetric.ExemplarSlice) []prometheus.Exemplar {
 			exemplarLabels["span_id"] = hex.EncodeToString(spanID[:])
 		}
 
+		// The filtered attributes are added as long as the labels fit in the
+		// limit of OpenMetrics, the trace and span IDs taking precedence.
+		runes := 0
+		for name, value := range exemplarLabels {
+			runes += utf8.RuneCountInString(name) + utf8.RuneCountInString(value)
+		}
+		e.FilteredAttributes().Range(func(k string, v pcommon.Value) bool {
+			name := prometheustranslator.NormalizeLabel(k)
+			if _, ok := exemplarLabels[name]; ok {
+				return true
+			}
+			value := v.AsString()
+			n := utf8.RuneCountInString(name) + utf8.RuneCountInString(value)
+			if runes+n > prometheus.ExemplarMaxRunes {
+				return true
+			}
+			exemplarLabels[name] = value
+			runes += n
+			return true
+		})
+
 		var value float64
 		switch e.ValueType() {
 		case pmetric.ExemplarValueTypeDouble:
@@ -78,6 +104,9 @@ func convertExemplars(exemplars pmetric.ExemplarSlice) []prometheus.Exemplar {
 		}
 
 	}
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].Timestamp.Before(result[j].Timestamp)
+	})
 	return result
 }
 
@@ -102,6 +131,8 @@ func (c *collector) convertMetric(metric pmetric.Metric, resourceAttrs pcommon.M
 		return c.convertSum(metric, resourceAttrs)
 	case pmetric.MetricTypeHistogram:
 		return c.convertDoubleHistogram(metric, resourceAttrs)
+	case pmetric.MetricTypeExponentialHistogram:
+		return c.convertExponentialHistogram(metric, resourceAttrs)
 	case pmetric.MetricTypeSummary:
 		return c.convertSummary(metric, resourceAttrs)
 	}
@@ -271,6 +302,131 @@ func (c *collector) convertDoubleHistogram(metric pmetric.Metric, resourceAttrs
 	return m, nil
 }
 
+// convertExponentialHistogram converts an exponential histogram to a native histogram,
+// which is only exposed by the protobuf exposition format.
+func (c *collector) convertExponentialHistogram(metric pmetric.Metric, resourceAttrs pcommon.Map) (prometheus.Metric, error) {
+	ip := metric.ExponentialHistogram().DataPoints().At(0)
+	desc, attributes := c.getMetricMetadata(metric, ip.Attributes(), resourceAttrs)
+
+	h, err := convertExponentialHistogramDataPoint(ip)
+	if err != nil {
+		return nil, err
+	}
+
+	var m prometheus.Metric = &nativeHistogram{
+		desc:       desc,
+		labelPairs: prometheus.MakeLabelPairs(desc, attributes),
+		histogram:  h,
+	}
+
+	// Only one exemplar can be exposed without classic buckets, in the +Inf bucket.
+	exemplars := convertExemplars(ip.Exemplars())
+	if len(exemplars) > 0 {
+		m, err = prometheus.NewMetricWithExemplars(m, exemplars[len(exemplars)-1])
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if c.sendTimestamps {
+		return prometheus.NewMetricWithTimestamp(ip.Timestamp().AsTime(), m), nil
+	}
+	return m, nil
+}
+
+// nativeHistogram is a constant native histogram metric, which is not supported by client_golang.
+type nativeHistogram struct {
+	desc       *prometheus.Desc
+	labelPairs []*dto.LabelPair
+	histogram  *dto.Histogram
+}
+
+func (h *nativeHistogram) Desc() *prometheus.Desc {
+	return h.desc
+}
+
+func (h *nativeHistogram) Write(m *dto.Metric) error {
+	m.Label = h.labelPairs
+	m.Histogram = proto.Clone(h.histogram).(*dto.Histogram)
+	return nil
+}
+
+func convertExponentialHistogramDataPoint(ip pmetric.ExponentialHistogramDataPoint) (*dto.Histogram, error) {
+	scale := ip.Scale()
+	if scale < -4 {
+		return nil, fmt.Errorf("cannot convert exponential histogram with scale %d, the minimum scale is -4", scale)
+	}
+	// Higher scales are downscaled to the maximum scale of native histograms, by merging the buckets.
+	var scaleDown int32
+	if scale > 8 {
+		scaleDown = scale - 8
+		scale = 8
+	}
+
+	h := &dto.Histogram{
+		SampleCount:   proto.Uint64(ip.Count()),
+		SampleSum:     proto.Float64(ip.Sum()),
+		Schema:        proto.Int32(scale),
+		ZeroThreshold: proto.Float64(ip.ZeroThreshold()),
+		ZeroCount:     proto.Uint64(ip.ZeroCount()),
+	}
+	h.PositiveSpan, h.PositiveDelta = convertBucketsLayout(ip.Positive(), scaleDown)
+	h.NegativeSpan, h.NegativeDelta = convertBucketsLayout(ip.Negative(), scaleDown)
+
+	// A histogram without any bucket needs a span to be identified as a native histogram.
+	if len(h.PositiveSpan) == 0 && len(h.NegativeSpan) == 0 && ip.ZeroThreshold() == 0 && ip.ZeroCount() == 0 {
+		h.PositiveSpan = []*dto.BucketSpan{{Offset: proto.Int32(0), Length: proto.Uint32(0)}}
+	}
+	return h, nil
+}
+
+// convertBucketsLayout converts the buckets of an exponential histogram to the spans and the
+// delta encoded counts of a native histogram. The bucket of index i covers (base^i, base^(i+1)]
+// in exponential histograms but (base^(i-1), base^i] in native histograms, so the indexes are
+// shifted by 1.
+func convertBucketsLayout(buckets pmetric.ExponentialHistogramDataPointBuckets, scaleDown int32) ([]*dto.BucketSpan, []int64) {
+	var (
+		spans     []*dto.BucketSpan
+		deltas    []int64
+		prevCount int64
+		nextIndex int32
+	)
+
+	appendDelta := func(count int64) {
+		*spans[len(spans)-1].Length++
+		deltas = append(deltas, count-prevCount)
+		prevCount = count
+	}
+
+	bucketCounts := buckets.BucketCounts()
+	for i := 0; i < bucketCounts.Len(); {
+		index := (buckets.Offset()+int32(i))>>scaleDown + 1
+		var count int64
+		for ; i < bucketCounts.Len() && (buckets.Offset()+int32(i))>>scaleDown+1 == index; i++ {
+			count += int64(bucketCounts.At(i))
+		}
+		if count == 0 {
+			continue
+		}
+
+		gap := index - nextIndex
+		switch {
+		case len(spans) == 0:
+			spans = append(spans, &dto.BucketSpan{Offset: proto.Int32(index), Length: proto.Uint32(0)})
+		case gap > 2:
+			// A new span is started for gaps of more than two buckets, as done by client_golang.
+			spans = append(spans, &dto.BucketSpan{Offset: proto.Int32(gap), Length: proto.Uint32(0)})
+		default:
+			for j := int32(0); j < gap; j++ {
+				appendDelta(0)
+			}
+		}
+		appendDelta(count)
+		nextIndex = index + 1
+	}
+	return spans, deltas
+}
+
 func (c *collector) createTargetInfoMetrics(resourceAttrs []pcommon.Map) ([]prometheus.Metric, error) {
 	var lastErr error
 
@@ -5,6 +5,7 @@ package prometheusexporter
 
 import (
 	"encoding/hex"
+	"math"
 	"strings"
 	"testing"
 	"time"
@@ -17,6 +18,7 @@ import (
 	conventions "go.opentelemetry.io/collector/semconv/v1.6.1"
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
+	"google.golang.org/protobuf/proto"
 )
 
 type mockAccumulator struct {
@@ -217,6 +219,177 @@ func TestConvertMonotonicSumExemplar(t *testing.T) {
 	exemplarsEqual(t, exemplar, promCounter.GetExemplar())
 }
 
+func TestConvertExemplarsFilteredAttributes(t *testing.T) {
+	exemplars := pmetric.NewExemplarSlice()
+	latest := exemplars.AppendEmpty()
+	setTestExemplarWithDoubleValue(latest, 2)
+	latest.SetTimestamp(pcommon.NewTimestampFromTime(time.Unix(20, 0)))
+	latest.FilteredAttributes().PutStr("http.method", "GET")
+	latest.FilteredAttributes().PutStr("too_long", strings.Repeat("x", prometheus.ExemplarMaxRunes))
+	latest.FilteredAttributes().PutStr("status", "200")
+	earliest := exemplars.AppendEmpty()
+	setTestExemplarWithDoubleValue(earliest, 1)
+	earliest.SetTimestamp(pcommon.NewTimestampFromTime(time.Unix(10, 0)))
+
+	result := convertExemplars(exemplars)
+	require.Len(t, result, 2)
+
+	// The exemplars are sorted by timestamp.
+	require.Equal(t, 1.0, result[0].Value)
+	require.Equal(t, 2.0, result[1].Value)
+	require.Equal(t, prometheus.Labels{
+		"trace_id":    "641d68e314a58152cc2581e7663435d1",
+		"span_id":     "7436d6ac76178623",
+		"http_method": "GET",
+		"status":      "200",
+	}, result[1].Labels)
+
+	c := collector{logger: zap.NewNop()}
+	metric := pmetric.NewMetric()
+	metric.SetName("test_monotonic_sum")
+	sum := metric.SetEmptySum()
+	sum.SetIsMonotonic(true)
+	dataPoint := sum.DataPoints().AppendEmpty()
+	dataPoint.SetIntValue(1)
+	exemplars.CopyTo(dataPoint.Exemplars())
+
+	promMetric, err := c.convertSum(metric, pcommon.NewMap())
+	require.NoError(t, err)
+	outMetric := io_prometheus_client.Metric{}
+	require.NoError(t, promMetric.Write(&outMetric))
+	// The latest exemplar is exposed for counters.
+	require.Equal(t, 2.0, outMetric.GetCounter().GetExemplar().GetValue())
+	require.Len(t, outMetric.GetCounter().GetExemplar().GetLabel(), 4)
+}
+
+func TestConvertExponentialHistogram(t *testing.T) {
+	tests := []struct {
+		name     string
+		fillDP   func(pmetric.ExponentialHistogramDataPoint)
+		expected *io_prometheus_client.Histogram
+		err      string
+	}{
+		{
+			name: "buckets",
+			fillDP: func(dp pmetric.ExponentialHistogramDataPoint) {
+				dp.SetScale(0)
+				dp.SetZeroThreshold(0.001)
+				dp.SetZeroCount(2)
+				dp.Positive().SetOffset(-1)
+				dp.Positive().BucketCounts().FromRaw([]uint64{1, 2, 0, 0, 0, 0, 3})
+				dp.Negative().BucketCounts().FromRaw([]uint64{4, 0, 5})
+			},
+			expected: &io_prometheus_client.Histogram{
+				Schema:        proto.Int32(0),
+				ZeroThreshold: proto.Float64(0.001),
+				ZeroCount:     proto.Uint64(2),
+				PositiveSpan: []*io_prometheus_client.BucketSpan{
+					{Offset: proto.Int32(0), Length: proto.Uint32(2)},
+					{Offset: proto.Int32(4), Length: proto.Uint32(1)},
+				},
+				PositiveDelta: []int64{1, 1, 1},
+				NegativeSpan: []*io_prometheus_client.BucketSpan{
+					{Offset: proto.Int32(1), Length: proto.Uint32(3)},
+				},
+				NegativeDelta: []int64{4, -4, 5},
+			},
+		},
+		{
+			name: "downscale",
+			fillDP: func(dp pmetric.ExponentialHistogramDataPoint) {
+				dp.SetScale(10)
+				dp.Positive().BucketCounts().FromRaw([]uint64{1, 1, 1, 1, 1})
+			},
+			expected: &io_prometheus_client.Histogram{
+				Schema:        proto.Int32(8),
+				ZeroThreshold: proto.Float64(0),
+				ZeroCount:     proto.Uint64(0),
+				PositiveSpan: []*io_prometheus_client.BucketSpan{
+					{Offset: proto.Int32(1), Length: proto.Uint32(2)},
+				},
+				PositiveDelta: []int64{4, -3},
+			},
+		},
+		{
+			name: "no buckets",
+			fillDP: func(dp pmetric.ExponentialHistogramDataPoint) {
+				dp.SetScale(3)
+			},
+			expected: &io_prometheus_client.Histogram{
+				Schema:        proto.Int32(3),
+				ZeroThreshold: proto.Float64(0),
+				ZeroCount:     proto.Uint64(0),
+				PositiveSpan: []*io_prometheus_client.BucketSpan{
+					{Offset: proto.Int32(0), Length: proto.Uint32(0)},
+				},
+			},
+		},
+		{
+			name: "invalid scale",
+			fillDP: func(dp pmetric.ExponentialHistogramDataPoint) {
+				dp.SetScale(-5)
+			},
+			err: "cannot convert exponential histogram with scale -5, the minimum scale is -4",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			metric := pmetric.NewMetric()
+			metric.SetName("test_metric")
+			metric.SetUnit("s")
+			dp := metric.SetEmptyExponentialHistogram().DataPoints().AppendEmpty()
+			dp.SetCount(17)
+			dp.SetSum(42)
+			dp.Attributes().PutStr("label_1", "1")
+			tt.fillDP(dp)
+
+			c := collector{logger: zap.NewNop(), addMetricSuffixes: true}
+			pbMetric, err := c.convertExponentialHistogram(metric, pcommon.NewMap())
+			if tt.err != "" {
+				require.EqualError(t, err, tt.err)
+				return
+			}
+			require.NoError(t, err)
+			require.Contains(t, pbMetric.Desc().String(), `fqName: "test_metric_seconds"`)
+
+			m := io_prometheus_client.Metric{}
+			require.NoError(t, pbMetric.Write(&m))
+			require.Len(t, m.GetLabel(), 1)
+			require.Equal(t, "label_1", m.GetLabel()[0].GetName())
+
+			tt.expected.SampleCount = proto.Uint64(17)
+			tt.expected.SampleSum = proto.Float64(42)
+			require.True(t, proto.Equal(tt.expected, m.GetHistogram()), "expected %v, got %v", tt.expected, m.GetHistogram())
+		})
+	}
+}
+
+func TestConvertExponentialHistogramExemplar(t *testing.T) {
+	metric := pmetric.NewMetric()
+	metric.SetName("test_metric")
+	dp := metric.SetEmptyExponentialHistogram().DataPoints().AppendEmpty()
+	dp.SetCount(3)
+	dp.Positive().BucketCounts().FromRaw([]uint64{1, 2})
+
+	latest := dp.Exemplars().AppendEmpty()
+	setTestExemplarWithDoubleValue(latest, 1.5)
+	latest.SetTimestamp(pcommon.NewTimestampFromTime(time.Unix(20, 0)))
+	setTestExemplarWithDoubleValue(dp.Exemplars().AppendEmpty(), 1)
+
+	c := collector{logger: zap.NewNop()}
+	pbMetric, err := c.convertExponentialHistogram(metric, pcommon.NewMap())
+	require.NoError(t, err)
+
+	m := io_prometheus_client.Metric{}
+	require.NoError(t, pbMetric.Write(&m))
+	buckets := m.GetHistogram().GetBucket()
+	require.Len(t, buckets, 1)
+	require.Equal(t, math.Inf(1), buckets[0].GetUpperBound())
+	require.Equal(t, uint64(3), buckets[0].GetCumulativeCount())
+	exemplarsEqual(t, latest, buckets[0].GetExemplar())
+}
+
 // errorCheckCore keeps track of logged errors
 type errorCheckCore struct {
 	errorMessages []string
@@ -12,6 +12,8 @@ import (
 	"testing"
 	"time"
 
+	io_prometheus_client "github.com/prometheus/client_model/go"
+	"github.com/prometheus/common/expfmt"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"go.opentelemetry.io/collector/component/componenttest"
@@ -470,6 +472,79 @@ func TestPrometheusExporter_endToEndWithResource(t *testing.T) {
 	}
 }
 
+func TestPrometheusExporter_endToEndNativeHistogram(t *testing.T) {
+	cfg := &Config{
+		HTTPServerSettings: confighttp.HTTPServerSettings{
+			Endpoint: "localhost:7778",
+		},
+		MetricExpiration:  120 * time.Minute,
+		EnableOpenMetrics: true,
+		AddMetricSuffixes: true,
+	}
+
+	factory := NewFactory()
+	set := exportertest.NewNopCreateSettings()
+	exp, err := factory.CreateMetricsExporter(context.Background(), set, cfg)
+	require.NoError(t, err)
+
+	t.Cleanup(func() {
+		require.NoError(t, exp.Shutdown(context.Background()))
+		// trigger a get so that the server cleans up our keepalive socket
+		_, err = http.Get("http://localhost:7778/metrics")
+		require.NoError(t, err)
+	})
+
+	require.NoError(t, exp.Start(context.Background(), componenttest.NewNopHost()))
+
+	md := pmetric.NewMetrics()
+	metric := md.ResourceMetrics().AppendEmpty().ScopeMetrics().AppendEmpty().Metrics().AppendEmpty()
+	metric.SetName("request.duration")
+	metric.SetUnit("s")
+	histogram := metric.SetEmptyExponentialHistogram()
+	histogram.SetAggregationTemporality(pmetric.AggregationTemporalityCumulative)
+	dp := histogram.DataPoints().AppendEmpty()
+	dp.SetTimestamp(pcommon.NewTimestampFromTime(time.Now()))
+	dp.SetScale(2)
+	dp.SetCount(3)
+	dp.SetSum(4.5)
+	dp.Positive().SetOffset(3)
+	dp.Positive().BucketCounts().FromRaw([]uint64{1, 2})
+	exemplar := dp.Exemplars().AppendEmpty()
+	exemplar.SetTraceID(pcommon.TraceID([16]byte{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16}))
+	exemplar.SetDoubleValue(1.8)
+	require.NoError(t, exp.ConsumeMetrics(context.Background(), md))
+
+	// The native histogram is exposed with the protobuf exposition format.
+	req, err := http.NewRequest(http.MethodGet, "http://localhost:7778/metrics", nil)
+	require.NoError(t, err)
+	req.Header.Set("Accept", "application/vnd.google.protobuf;proto=io.prometheus.client.MetricFamily;encoding=delimited")
+	res, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer res.Body.Close()
+	require.Equal(t, expfmt.FmtProtoDelim, expfmt.ResponseFormat(res.Header))
+
+	var mf io_prometheus_client.MetricFamily
+	require.NoError(t, expfmt.NewDecoder(res.Body, expfmt.FmtProtoDelim).Decode(&mf))
+	assert.Equal(t, "request_duration_seconds", mf.GetName())
+	assert.Equal(t, io_prometheus_client.MetricType_HISTOGRAM, mf.GetType())
+	require.Len(t, mf.GetMetric(), 1)
+	h := mf.GetMetric()[0].GetHistogram()
+	assert.Equal(t, int32(2), h.GetSchema())
+	assert.Equal(t, uint64(3), h.GetSampleCount())
+	assert.Equal(t, []int64{1, 1}, h.GetPositiveDelta())
+	require.Len(t, h.GetBucket(), 1)
+	assert.Equal(t, 1.8, h.GetBucket()[0].GetExemplar().GetValue())
+
+	// The exemplar is exposed with OpenMetrics.
+	req.Header.Set("Accept", "application/openmetrics-text;version=1.0.0")
+	res2, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer res2.Body.Close()
+	blob, err := io.ReadAll(res2.Body)
+	require.NoError(t, err)
+	assert.Contains(t, string(blob), `request_duration_seconds_bucket{le="+Inf"} 3 # {trace_id="0102030405060708090a0b0c0d0e0f10"} 1.8`)
+}
+
 func metricBuilder(delta int64, prefix, job, instance string) pmetric.Metrics {
 	md := pmetric.NewMetrics()
 	rms := md.ResourceMetrics().AppendEmpty()
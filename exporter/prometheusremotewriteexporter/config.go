@@ -51,8 +51,20 @@ type Config struct {
 
 	// SendMetadata controls whether prometheus metadata will be generated and sent
 	SendMetadata bool `mapstructure:"send_metadata"`
+
+	// ProtobufMessage is the protobuf message sent, `prometheus.WriteRequest` for remote write 1.0
+	// or `io.prometheus.write.v2.Request` for remote write 2.0. Remote write 2.0 requests fall back
+	// to 1.0 when the endpoint does not support them.
+	ProtobufMessage string `mapstructure:"protobuf_message"`
 }
 
+const (
+	// ProtobufMessageV1 is the protobuf message of remote write 1.0.
+	ProtobufMessageV1 = "prometheus.WriteRequest"
+	// ProtobufMessageV2 is the protobuf message of remote write 2.0.
+	ProtobufMessageV2 = "io.prometheus.write.v2.Request"
+)
+
 type CreatedMetric struct {
 	// Enabled if true the _created metrics could be exported
 	Enabled bool `mapstructure:"enabled"`
@@ -114,5 +126,26 @@ func (cfg *Config) Validate() error {
 		cfg.MaxBatchSizeBytes = 3000000
 	}
 
+	if cfg.WAL != nil {
+		if cfg.WAL.Shards < 0 {
+			return fmt.Errorf("wal shards can't be negative")
+		}
+		if cfg.WAL.MaxSizeBytes < 0 {
+			return fmt.Errorf("wal max_size_bytes can't be negative")
+		}
+	}
+
+	switch cfg.ProtobufMessage {
+	case "":
+		cfg.ProtobufMessage = ProtobufMessageV1
+	case ProtobufMessageV1:
+	case ProtobufMessageV2:
+		if cfg.WAL != nil {
+			return fmt.Errorf("the WAL is not supported with protobuf_message %q", ProtobufMessageV2)
+		}
+	default:
+		return fmt.Errorf("unsupported protobuf_message %q, must be %q or %q", cfg.ProtobufMessage, ProtobufMessageV1, ProtobufMessageV2)
+	}
+
 	return nil
 }
@@ -77,7 +77,8 @@ func TestLoadConfig(t *testing.T) {
 				TargetInfo: &TargetInfo{
 					Enabled: true,
 				},
-				CreatedMetric: &CreatedMetric{Enabled: true},
+				CreatedMetric:   &CreatedMetric{Enabled: true},
+				ProtobufMessage: ProtobufMessageV2,
 			},
 		},
 		{
@@ -88,6 +89,22 @@ func TestLoadConfig(t *testing.T) {
 			id:           component.NewIDWithName(metadata.Type, "negative_num_consumers"),
 			errorMessage: "remote write consumer number can't be negative",
 		},
+		{
+			id:           component.NewIDWithName(metadata.Type, "invalid_protobuf_message"),
+			errorMessage: `unsupported protobuf_message "prometheus.WriteRequestV3", must be "prometheus.WriteRequest" or "io.prometheus.write.v2.Request"`,
+		},
+		{
+			id:           component.NewIDWithName(metadata.Type, "protobuf_message_v2_with_wal"),
+			errorMessage: `the WAL is not supported with protobuf_message "io.prometheus.write.v2.Request"`,
+		},
+		{
+			id:           component.NewIDWithName(metadata.Type, "negative_wal_shards"),
+			errorMessage: "wal shards can't be negative",
+		},
+		{
+			id:           component.NewIDWithName(metadata.Type, "negative_wal_max_size_bytes"),
+			errorMessage: "wal max_size_bytes can't be negative",
+		},
 	}
 
 	for _, tt := range tests {
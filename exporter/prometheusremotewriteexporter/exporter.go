@@ -14,6 +14,7 @@ import (
 	"net/url"
 	"strings"
 	"sync"
+	"sync/atomic"
 
 	"github.com/cenkalti/backoff/v4"
 	"github.com/gogo/protobuf/proto"
@@ -29,8 +30,12 @@ import (
 
 	prometheustranslator "github.com/open-telemetry/opentelemetry-collector-contrib/pkg/translator/prometheus"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/translator/prometheusremotewrite"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/translator/prometheusremotewrite/writev2"
 )
 
+// errUnsupportedProtobufMessage is returned when the endpoint rejects the content type of a request.
+var errUnsupportedProtobufMessage = errors.New("unsupported protobuf message")
+
 // prwExporter converts OTLP metrics to Prometheus remote write TimeSeries and sends them to a remote endpoint.
 type prwExporter struct {
 	endpointURL       *url.URL
@@ -43,8 +48,11 @@ type prwExporter struct {
 	clientSettings    *confighttp.HTTPClientSettings
 	settings          component.TelemetrySettings
 	retrySettings     exporterhelper.RetrySettings
-	wal               *prweWAL
+	wal               *shardedWAL
 	exporterSettings  prometheusremotewrite.Settings
+	protobufMessage   string
+	// fallbackToV1 is set once the endpoint rejected a remote write 2.0 request.
+	fallbackToV1 atomic.Bool
 }
 
 // newPRWExporter initializes a new prwExporter instance and sets fields accordingly.
@@ -71,6 +79,7 @@ func newPRWExporter(cfg *Config, set exporter.CreateSettings) (*prwExporter, err
 		clientSettings:    &cfg.HTTPClientSettings,
 		settings:          set.TelemetrySettings,
 		retrySettings:     cfg.RetrySettings,
+		protobufMessage:   cfg.ProtobufMessage,
 		exporterSettings: prometheusremotewrite.Settings{
 			Namespace:           cfg.Namespace,
 			ExternalLabels:      sanitizedLabels,
@@ -84,7 +93,7 @@ func newPRWExporter(cfg *Config, set exporter.CreateSettings) (*prwExporter, err
 		return prwe, nil
 	}
 
-	prwe.wal, err = newWAL(cfg.WAL, prwe.export)
+	prwe.wal, err = newShardedWAL(cfg.WAL, cfg.RemoteWriteQueue.NumConsumers, set.TelemetrySettings, prwe.exportWAL)
 	if err != nil {
 		return nil, err
 	}
@@ -131,19 +140,41 @@ func (prwe *prwExporter) PushMetrics(ctx context.Context, md pmetric.Metrics) er
 	case <-prwe.closeChan:
 		return errors.New("shutdown has been called")
 	default:
-
-		tsMap, err := prometheusremotewrite.FromMetrics(md, prwe.exporterSettings)
-		if err != nil {
-			err = consumererror.NewPermanent(err)
+		if prwe.protobufMessage == ProtobufMessageV2 && !prwe.fallbackToV1.Load() {
+			return prwe.pushMetricsV2(ctx, md)
 		}
+		return prwe.pushMetricsV1(ctx, md)
+	}
+}
 
-		var m []*prompb.MetricMetadata
-		if prwe.exporterSettings.SendMetadata {
-			m = prometheusremotewrite.OtelMetricsToMetadata(md, prwe.exporterSettings.AddMetricSuffixes)
-		}
-		// Call export even if a conversion error, since there may be points that were successfully converted.
-		return multierr.Combine(err, prwe.handleExport(ctx, tsMap, m))
+func (prwe *prwExporter) pushMetricsV1(ctx context.Context, md pmetric.Metrics) error {
+	tsMap, err := prometheusremotewrite.FromMetrics(md, prwe.exporterSettings)
+	if err != nil {
+		err = consumererror.NewPermanent(err)
+	}
+
+	var m []*prompb.MetricMetadata
+	if prwe.exporterSettings.SendMetadata {
+		m = prometheusremotewrite.OtelMetricsToMetadata(md, prwe.exporterSettings.AddMetricSuffixes)
 	}
+	// Call export even if a conversion error, since there may be points that were successfully converted.
+	return multierr.Combine(err, prwe.handleExport(ctx, tsMap, m))
+}
+
+// pushMetricsV2 sends the metrics in the remote write 2.0 format, the metadata and created timestamps are
+// part of the series. The requests rejected by endpoints not supporting 2.0 are resent in the 1.0 format.
+func (prwe *prwExporter) pushMetricsV2(ctx context.Context, md pmetric.Metrics) error {
+	tsMap, symbols, err := prometheusremotewrite.FromMetricsV2(md, prwe.exporterSettings)
+	if err != nil {
+		err = consumererror.NewPermanent(err)
+	}
+	// There are no metrics to export, so return.
+	if len(tsMap) == 0 {
+		return err
+	}
+
+	requests := batchTimeSeriesV2(tsMap, symbols.Symbols(), prwe.maxBatchSizeBytes)
+	return multierr.Combine(err, prwe.exportV2(ctx, requests))
 }
 
 func validateAndSanitizeExternalLabels(cfg *Config) (map[string]string, error) {
@@ -184,7 +215,23 @@ func (prwe *prwExporter) handleExport(ctx context.Context, tsMap map[string]*pro
 
 // export sends a Snappy-compressed WriteRequest containing TimeSeries to a remote write endpoint in order
 func (prwe *prwExporter) export(ctx context.Context, requests []*prompb.WriteRequest) error {
-	input := make(chan *prompb.WriteRequest, len(requests))
+	return exportConcurrently(ctx, prwe.concurrency, requests, prwe.execute)
+}
+
+// exportWAL sends the requests read from a WAL shard one after the other, the concurrency
+// of the WAL comes from its shards being exported independently.
+func (prwe *prwExporter) exportWAL(ctx context.Context, requests []*prompb.WriteRequest) error {
+	return exportConcurrently(ctx, 1, requests, prwe.execute)
+}
+
+// exportV2 sends Snappy-compressed remote write 2.0 requests to a remote write endpoint
+func (prwe *prwExporter) exportV2(ctx context.Context, requests []*writev2.Request) error {
+	return exportConcurrently(ctx, prwe.concurrency, requests, prwe.executeV2)
+}
+
+// exportConcurrently executes the requests with at most concurrency workers.
+func exportConcurrently[T any](ctx context.Context, concurrency int, requests []T, execute func(context.Context, T) error) error {
+	input := make(chan T, len(requests))
 	for _, request := range requests {
 		input <- request
 	}
@@ -192,7 +239,7 @@ func (prwe *prwExporter) export(ctx context.Context, requests []*prompb.WriteReq
 
 	var wg sync.WaitGroup
 
-	concurrencyLimit := int(math.Min(float64(prwe.concurrency), float64(len(requests))))
+	concurrencyLimit := int(math.Min(float64(concurrency), float64(len(requests))))
 	wg.Add(concurrencyLimit) // used to wait for workers to be finished
 
 	var mu sync.Mutex
@@ -211,7 +258,7 @@ func (prwe *prwExporter) export(ctx context.Context, requests []*prompb.WriteReq
 					if !ok {
 						return
 					}
-					if errExecute := prwe.execute(ctx, request); errExecute != nil {
+					if errExecute := execute(ctx, request); errExecute != nil {
 						mu.Lock()
 						errs = multierr.Append(errs, consumererror.NewPermanent(errExecute))
 						mu.Unlock()
@@ -231,6 +278,28 @@ func (prwe *prwExporter) execute(ctx context.Context, writeReq *prompb.WriteRequ
 	if errMarshal != nil {
 		return consumererror.NewPermanent(errMarshal)
 	}
+	return prwe.send(ctx, data, "application/x-protobuf", "0.1.0")
+}
+
+// executeV2 sends a remote write 2.0 request. If the endpoint does not support 2.0, only this
+// request is resent in the 1.0 format and the next metrics are sent in the 1.0 format.
+func (prwe *prwExporter) executeV2(ctx context.Context, writeReq *writev2.Request) error {
+	data, errMarshal := writeReq.Marshal()
+	if errMarshal != nil {
+		return consumererror.NewPermanent(errMarshal)
+	}
+	err := prwe.send(ctx, data, writev2.ContentType, writev2.Version)
+	if !errors.Is(err, errUnsupportedProtobufMessage) {
+		return err
+	}
+	if prwe.fallbackToV1.CompareAndSwap(false, true) {
+		prwe.settings.Logger.Warn("Remote write endpoint does not support remote write 2.0, falling back to remote write 1.0")
+	}
+	return prwe.execute(ctx, writeRequestFromV2(writeReq, prwe.exporterSettings.SendMetadata))
+}
+
+// send posts the Snappy-compressed data with the content type and remote write version headers.
+func (prwe *prwExporter) send(ctx context.Context, data []byte, contentType string, version string) error {
 	buf := make([]byte, len(data), cap(data))
 	compressedData := snappy.Encode(buf, data)
 
@@ -245,8 +314,8 @@ func (prwe *prwExporter) execute(ctx context.Context, writeReq *prompb.WriteRequ
 		// Add necessary headers specified by:
 		// https://cortexmetrics.io/docs/apis/#remote-api
 		req.Header.Add("Content-Encoding", "snappy")
-		req.Header.Set("Content-Type", "application/x-protobuf")
-		req.Header.Set("X-Prometheus-Remote-Write-Version", "0.1.0")
+		req.Header.Set("Content-Type", contentType)
+		req.Header.Set("X-Prometheus-Remote-Write-Version", version)
 		req.Header.Set("User-Agent", prwe.userAgentHeader)
 
 		resp, err := prwe.client.Do(req)
@@ -264,6 +333,11 @@ func (prwe *prwExporter) execute(ctx context.Context, writeReq *prompb.WriteRequ
 		}
 
 		body, err := io.ReadAll(io.LimitReader(resp.Body, 256))
+		// The endpoint does not support the content type, remote write 2.0 senders fall back to 1.0.
+		// See https://prometheus.io/docs/specs/remote_write_spec_2_0/#backward-and-forward-compatibility
+		if resp.StatusCode == http.StatusUnsupportedMediaType {
+			return backoff.Permanent(fmt.Errorf("%w %s: remote write returned HTTP status %v: %s", errUnsupportedProtobufMessage, contentType, resp.Status, body))
+		}
 		rerr := fmt.Errorf("remote write returned HTTP status %v; err = %w: %s", resp.Status, err, body)
 		if resp.StatusCode >= 500 && resp.StatusCode < 600 {
 			return rerr
@@ -9,6 +9,7 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"net/url"
+	"strconv"
 	"sync"
 	"testing"
 	"time"
@@ -30,6 +31,7 @@ import (
 	"go.opentelemetry.io/collector/pdata/pmetric"
 
 	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/coreinternal/testdata"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/translator/prometheusremotewrite/writev2"
 )
 
 // Test_NewPRWExporter checks that a new exporter instance with non-nil fields is initialized
@@ -927,7 +929,7 @@ func TestWALOnExporterRoundTrip(t *testing.T) {
 
 	// 3. Let's now read back all of the WAL records and ensure
 	// that all the prompb.WriteRequest values exist as we sent them.
-	wal, _, werr := cfg.WAL.createWAL()
+	wal, _, werr := cfg.WAL.createWAL(0, 0)
 	assert.NoError(t, werr)
 	assert.NotNil(t, wal)
 	t.Cleanup(func() {
@@ -997,6 +999,73 @@ func TestWALOnExporterRoundTrip(t *testing.T) {
 	assert.Equal(t, gotFromWAL, gotFromUpload)
 }
 
+func TestWALExportConcurrency(t *testing.T) {
+	const numConsumers = 4
+	var mu sync.Mutex
+	inFlight, maxInFlight := 0, 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		mu.Lock()
+		inFlight++
+		if inFlight > maxInFlight {
+			maxInFlight = inFlight
+		}
+		mu.Unlock()
+		// Hold the request until the other consumers send theirs
+		deadline := time.Now().Add(time.Second)
+		for time.Now().Before(deadline) {
+			mu.Lock()
+			done := maxInFlight >= numConsumers
+			mu.Unlock()
+			if done {
+				break
+			}
+			time.Sleep(5 * time.Millisecond)
+		}
+		mu.Lock()
+		inFlight--
+		mu.Unlock()
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	cfg := &Config{
+		HTTPClientSettings: confighttp.HTTPClientSettings{Endpoint: server.URL},
+		RemoteWriteQueue:   RemoteWriteQueue{NumConsumers: numConsumers},
+		WAL: &WALConfig{
+			Directory:         t.TempDir(),
+			BufferSize:        1,
+			TruncateFrequency: time.Millisecond,
+		},
+		TargetInfo:    &TargetInfo{Enabled: false},
+		CreatedMetric: &CreatedMetric{Enabled: false},
+	}
+	prwe, err := newPRWExporter(cfg, exportertest.NewNopCreateSettings())
+	require.NoError(t, err)
+	require.Len(t, prwe.wal.shards, numConsumers)
+
+	ctx := context.Background()
+	require.NoError(t, prwe.Start(ctx, componenttest.NewNopHost()))
+	t.Cleanup(func() {
+		assert.NoError(t, prwe.Shutdown(ctx))
+	})
+
+	tsMap := map[string]*prompb.TimeSeries{}
+	for i := 0; i < 40; i++ {
+		tsMap[strconv.Itoa(i)] = &prompb.TimeSeries{
+			Labels:  []prompb.Label{{Name: "__name__", Value: "metric"}, {Name: "id", Value: strconv.Itoa(i)}},
+			Samples: []prompb.Sample{{Value: float64(i), Timestamp: 100}},
+		}
+	}
+	require.NoError(t, prwe.handleExport(ctx, tsMap, nil))
+
+	// The shards default to the number of consumers, and each one sends its own requests
+	require.Eventually(t, func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		return maxInFlight == numConsumers
+	}, 10*time.Second, 10*time.Millisecond)
+}
+
 func TestRetryOn5xx(t *testing.T) {
 	// Create a mock HTTP server with a counter to simulate a 5xx error on the first attempt and a 2xx success on the second attempt
 	attempts := 0
@@ -1063,3 +1132,138 @@ func TestNoRetryOn4xx(t *testing.T) {
 	assert.True(t, consumererror.IsPermanent(err))
 	assert.Equal(t, 1, attempts)
 }
+
+func TestPushMetricsV2(t *testing.T) {
+	var contentTypes []string
+	var requests []*writev2.Request
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		contentTypes = append(contentTypes, r.Header.Get("Content-Type"))
+		assert.Equal(t, writev2.Version, r.Header.Get("X-Prometheus-Remote-Write-Version"))
+
+		body, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		data, err := snappy.Decode(nil, body)
+		require.NoError(t, err)
+		req := &writev2.Request{}
+		require.NoError(t, req.Unmarshal(data))
+		requests = append(requests, req)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer mockServer.Close()
+
+	cfg := createDefaultConfig().(*Config)
+	cfg.HTTPClientSettings.Endpoint = mockServer.URL
+	cfg.ProtobufMessage = ProtobufMessageV2
+	cfg.TargetInfo.Enabled = false
+	require.NoError(t, cfg.Validate())
+
+	prwe, err := newPRWExporter(cfg, exportertest.NewNopCreateSettings())
+	require.NoError(t, err)
+	require.NoError(t, prwe.Start(context.Background(), componenttest.NewNopHost()))
+	defer func() { require.NoError(t, prwe.Shutdown(context.Background())) }()
+
+	md := testdata.GenerateMetricsOneMetric()
+	md.ResourceMetrics().At(0).ScopeMetrics().At(0).Metrics().At(0).SetDescription("counter help")
+	require.NoError(t, prwe.PushMetrics(context.Background(), md))
+
+	assert.Equal(t, []string{writev2.ContentType}, contentTypes)
+	require.Len(t, requests, 1)
+	require.Len(t, requests[0].Timeseries, 2)
+	for _, ts := range requests[0].Timeseries {
+		assert.Equal(t, writev2.MetricTypeCounter, ts.Metadata.Type)
+		assert.Equal(t, "counter help", requests[0].Symbols[ts.Metadata.HelpRef])
+		assert.NotZero(t, ts.CreatedTimestamp)
+		require.Len(t, ts.Samples, 1)
+	}
+}
+
+func TestPushMetricsV2_FallbackToV1(t *testing.T) {
+	var contentTypes []string
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		contentType := r.Header.Get("Content-Type")
+		contentTypes = append(contentTypes, contentType)
+		if contentType == writev2.ContentType {
+			http.Error(w, "unsupported content type", http.StatusUnsupportedMediaType)
+			return
+		}
+
+		body, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		data, err := snappy.Decode(nil, body)
+		require.NoError(t, err)
+		var req prompb.WriteRequest
+		require.NoError(t, proto.Unmarshal(data, &req))
+		assert.Len(t, req.Timeseries, 2)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer mockServer.Close()
+
+	cfg := createDefaultConfig().(*Config)
+	cfg.HTTPClientSettings.Endpoint = mockServer.URL
+	cfg.ProtobufMessage = ProtobufMessageV2
+	cfg.TargetInfo.Enabled = false
+	require.NoError(t, cfg.Validate())
+
+	prwe, err := newPRWExporter(cfg, exportertest.NewNopCreateSettings())
+	require.NoError(t, err)
+	require.NoError(t, prwe.Start(context.Background(), componenttest.NewNopHost()))
+	defer func() { require.NoError(t, prwe.Shutdown(context.Background())) }()
+
+	require.NoError(t, prwe.PushMetrics(context.Background(), testdata.GenerateMetricsOneMetric()))
+	require.NoError(t, prwe.PushMetrics(context.Background(), testdata.GenerateMetricsOneMetric()))
+
+	// The 2.0 request is rejected once, then the exporter sticks to 1.0.
+	assert.Equal(t, []string{writev2.ContentType, "application/x-protobuf", "application/x-protobuf"}, contentTypes)
+}
+
+func TestPushMetricsV2_FallbackRejectedBatchesOnly(t *testing.T) {
+	var mu sync.Mutex
+	var v2Requests int
+	var v2Series, v1Series int
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		data, err := snappy.Decode(nil, body)
+		require.NoError(t, err)
+
+		mu.Lock()
+		defer mu.Unlock()
+		if r.Header.Get("Content-Type") == writev2.ContentType {
+			v2Requests++
+			// Behind a load balancer, only some of the replicas support remote write 2.0.
+			if v2Requests > 1 {
+				http.Error(w, "unsupported content type", http.StatusUnsupportedMediaType)
+				return
+			}
+			var req writev2.Request
+			require.NoError(t, req.Unmarshal(data))
+			v2Series += len(req.Timeseries)
+		} else {
+			var req prompb.WriteRequest
+			require.NoError(t, proto.Unmarshal(data, &req))
+			v1Series += len(req.Timeseries)
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer mockServer.Close()
+
+	cfg := createDefaultConfig().(*Config)
+	cfg.HTTPClientSettings.Endpoint = mockServer.URL
+	cfg.ProtobufMessage = ProtobufMessageV2
+	cfg.TargetInfo.Enabled = false
+	cfg.RemoteWriteQueue.NumConsumers = 1
+	cfg.MaxBatchSizeBytes = 100
+	require.NoError(t, cfg.Validate())
+
+	prwe, err := newPRWExporter(cfg, exportertest.NewNopCreateSettings())
+	require.NoError(t, err)
+	require.NoError(t, prwe.Start(context.Background(), componenttest.NewNopHost()))
+	defer func() { require.NoError(t, prwe.Shutdown(context.Background())) }()
+
+	require.NoError(t, prwe.PushMetrics(context.Background(), testdata.GenerateMetricsOneMetric()))
+
+	// Only the series of the rejected batch are resent in the 1.0 format.
+	assert.Equal(t, 2, v2Requests)
+	assert.Equal(t, 1, v2Series)
+	assert.Equal(t, 1, v1Series)
+}
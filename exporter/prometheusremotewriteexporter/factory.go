@@ -82,6 +82,7 @@ func createDefaultConfig() component.Config {
 		},
 		AddMetricSuffixes: true,
 		SendMetadata:      false,
+		ProtobufMessage:   ProtobufMessageV1,
 		HTTPClientSettings: confighttp.HTTPClientSettings{
 			Endpoint: "http://some.url:9411/api/prom/push",
 			// We almost read 0 bytes, so no need to tune ReadBufferSize.
@@ -6,8 +6,12 @@ package prometheusremotewriteexporter // import "github.com/open-telemetry/opent
 import (
 	"errors"
 	"sort"
+	"strings"
 
+	"github.com/prometheus/common/model"
 	"github.com/prometheus/prometheus/prompb"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/translator/prometheusremotewrite/writev2"
 )
 
 // batchTimeSeries splits series into multiple batch write requests.
@@ -96,3 +100,165 @@ func orderBySampleTimestamp(tsArray []prompb.TimeSeries) []prompb.TimeSeries {
 	}
 	return tsArray
 }
+
+// batchTimeSeriesV2 splits series into multiple remote write 2.0 requests, the strings
+// referenced by the series of a request are interned in its own symbols table.
+func batchTimeSeriesV2(tsMap map[string]*writev2.TimeSeries, symbols []string, maxBatchByteSize int) []*writev2.Request {
+	var requests []*writev2.Request
+	table := writev2.NewSymbolTable()
+	tsArray := make([]writev2.TimeSeries, 0, len(tsMap))
+	sizeOfCurrentBatch := 0
+
+	for _, v := range tsMap {
+		sizeOfSeries := v.Size() + sizeOfSymbols(v, symbols)
+
+		if sizeOfCurrentBatch+sizeOfSeries >= maxBatchByteSize && len(tsArray) != 0 {
+			requests = append(requests, &writev2.Request{Symbols: table.Symbols(), Timeseries: tsArray})
+
+			table = writev2.NewSymbolTable()
+			tsArray = make([]writev2.TimeSeries, 0, len(tsMap)-len(tsArray))
+			sizeOfCurrentBatch = 0
+		}
+
+		tsArray = append(tsArray, resymbolize(v, symbols, &table))
+		sizeOfCurrentBatch += sizeOfSeries
+	}
+
+	if len(tsArray) != 0 {
+		requests = append(requests, &writev2.Request{Symbols: table.Symbols(), Timeseries: tsArray})
+	}
+	return requests
+}
+
+// sizeOfSymbols returns the size of the strings referenced by the series.
+func sizeOfSymbols(ts *writev2.TimeSeries, symbols []string) int {
+	size := len(symbols[ts.Metadata.HelpRef]) + len(symbols[ts.Metadata.UnitRef])
+	for _, ref := range ts.LabelsRefs {
+		size += len(symbols[ref])
+	}
+	for _, exemplar := range ts.Exemplars {
+		for _, ref := range exemplar.LabelsRefs {
+			size += len(symbols[ref])
+		}
+	}
+	return size
+}
+
+// resymbolize copies the series, interning the strings it references in table. The
+// samples and histograms are sorted by timestamp to avoid out of order problems.
+func resymbolize(ts *writev2.TimeSeries, symbols []string, table *writev2.SymbolsTable) writev2.TimeSeries {
+	converted := *ts
+	converted.LabelsRefs = table.SymbolizeLabels(writev2.DesymbolizeLabels(ts.LabelsRefs, symbols), nil)
+	converted.Metadata.HelpRef = table.Symbolize(symbols[ts.Metadata.HelpRef])
+	converted.Metadata.UnitRef = table.Symbolize(symbols[ts.Metadata.UnitRef])
+	converted.Exemplars = make([]writev2.Exemplar, 0, len(ts.Exemplars))
+	for _, exemplar := range ts.Exemplars {
+		exemplar.LabelsRefs = table.SymbolizeLabels(writev2.DesymbolizeLabels(exemplar.LabelsRefs, symbols), nil)
+		converted.Exemplars = append(converted.Exemplars, exemplar)
+	}
+	sort.Slice(converted.Samples, func(i, j int) bool {
+		return converted.Samples[i].Timestamp < converted.Samples[j].Timestamp
+	})
+	sort.Slice(converted.Histograms, func(i, j int) bool {
+		return converted.Histograms[i].Timestamp < converted.Histograms[j].Timestamp
+	})
+	return converted
+}
+
+// writeRequestFromV2 converts a remote write 2.0 request to a remote write 1.0 request, so that it
+// can be resent to endpoints not supporting 2.0. Created timestamps have no 1.0 counterpart and are
+// dropped, the metadata of the series is sent once per metric family if sendMetadata is true.
+func writeRequestFromV2(req *writev2.Request, sendMetadata bool) *prompb.WriteRequest {
+	converted := &prompb.WriteRequest{Timeseries: make([]prompb.TimeSeries, 0, len(req.Timeseries))}
+	families := map[string]bool{}
+	for i := range req.Timeseries {
+		ts := &req.Timeseries[i]
+		series := prompb.TimeSeries{
+			Labels:     writev2.DesymbolizeLabels(ts.LabelsRefs, req.Symbols),
+			Samples:    make([]prompb.Sample, 0, len(ts.Samples)),
+			Exemplars:  make([]prompb.Exemplar, 0, len(ts.Exemplars)),
+			Histograms: make([]prompb.Histogram, 0, len(ts.Histograms)),
+		}
+		for _, sample := range ts.Samples {
+			series.Samples = append(series.Samples, prompb.Sample{Value: sample.Value, Timestamp: sample.Timestamp})
+		}
+		for _, exemplar := range ts.Exemplars {
+			series.Exemplars = append(series.Exemplars, prompb.Exemplar{
+				Labels:    writev2.DesymbolizeLabels(exemplar.LabelsRefs, req.Symbols),
+				Value:     exemplar.Value,
+				Timestamp: exemplar.Timestamp,
+			})
+		}
+		for _, histogram := range ts.Histograms {
+			series.Histograms = append(series.Histograms, histogramFromV2(histogram))
+		}
+		converted.Timeseries = append(converted.Timeseries, series)
+
+		if !sendMetadata {
+			continue
+		}
+		family := metricFamilyName(series.Labels, ts.Metadata.Type)
+		if family == "" || families[family] {
+			continue
+		}
+		families[family] = true
+		converted.Metadata = append(converted.Metadata, prompb.MetricMetadata{
+			// The metric types of both versions share the same values.
+			Type:             prompb.MetricMetadata_MetricType(ts.Metadata.Type),
+			MetricFamilyName: family,
+			Help:             req.Symbols[ts.Metadata.HelpRef],
+			Unit:             req.Symbols[ts.Metadata.UnitRef],
+		})
+	}
+	return converted
+}
+
+// histogramFromV2 converts a remote write 2.0 native histogram to its remote write 1.0 counterpart.
+func histogramFromV2(h writev2.Histogram) prompb.Histogram {
+	converted := prompb.Histogram{
+		Sum:            h.Sum,
+		Schema:         h.Schema,
+		ZeroThreshold:  h.ZeroThreshold,
+		NegativeDeltas: h.NegativeDeltas,
+		NegativeCounts: h.NegativeCounts,
+		PositiveDeltas: h.PositiveDeltas,
+		PositiveCounts: h.PositiveCounts,
+		ResetHint:      prompb.Histogram_ResetHint(h.ResetHint),
+		Timestamp:      h.Timestamp,
+	}
+	if h.IsFloat {
+		converted.Count = &prompb.Histogram_CountFloat{CountFloat: h.CountFloat}
+		converted.ZeroCount = &prompb.Histogram_ZeroCountFloat{ZeroCountFloat: h.ZeroCountFloat}
+	} else {
+		converted.Count = &prompb.Histogram_CountInt{CountInt: h.CountInt}
+		converted.ZeroCount = &prompb.Histogram_ZeroCountInt{ZeroCountInt: h.ZeroCountInt}
+	}
+	for _, span := range h.NegativeSpans {
+		converted.NegativeSpans = append(converted.NegativeSpans, prompb.BucketSpan{Offset: span.Offset, Length: span.Length})
+	}
+	for _, span := range h.PositiveSpans {
+		converted.PositiveSpans = append(converted.PositiveSpans, prompb.BucketSpan{Offset: span.Offset, Length: span.Length})
+	}
+	return converted
+}
+
+// metricFamilyName returns the name of the metric family of a series, without the suffixes
+// of the classic histogram and summary series.
+func metricFamilyName(lbls []prompb.Label, typ writev2.MetricType) string {
+	var name string
+	for _, label := range lbls {
+		if label.Name == model.MetricNameLabel {
+			name = label.Value
+			break
+		}
+	}
+	switch typ {
+	case writev2.MetricTypeHistogram, writev2.MetricTypeGaugeHistogram, writev2.MetricTypeSummary:
+		for _, suffix := range []string{"_bucket", "_sum", "_count"} {
+			if strings.HasSuffix(name, suffix) {
+				return strings.TrimSuffix(name, suffix)
+			}
+		}
+	}
+	return name
+}
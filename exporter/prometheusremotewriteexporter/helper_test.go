@@ -8,6 +8,9 @@ import (
 
 	"github.com/prometheus/prometheus/prompb"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/translator/prometheusremotewrite/writev2"
 )
 
 // Test_batchTimeSeries checks batchTimeSeries return the correct number of requests
@@ -180,3 +183,102 @@ func TestEnsureTimeseriesPointsAreSortedByTimestamp(t *testing.T) {
 		}
 	}
 }
+
+func Test_batchTimeSeriesV2(t *testing.T) {
+	symbols := []string{"", "__name__", "a", "b", "job", "api"}
+	tsMap := map[string]*writev2.TimeSeries{
+		"a": {
+			LabelsRefs: []uint32{1, 2, 4, 5},
+			Samples:    []writev2.Sample{{Value: 2, Timestamp: 2000}, {Value: 1, Timestamp: 1000}},
+		},
+		"b": {
+			LabelsRefs: []uint32{1, 3, 4, 5},
+			Samples:    []writev2.Sample{{Value: 1, Timestamp: 1000}},
+		},
+	}
+
+	requests := batchTimeSeriesV2(tsMap, symbols, 1000)
+	require.Len(t, requests, 1)
+	assert.Len(t, requests[0].Timeseries, 2)
+	assert.Len(t, requests[0].Symbols, len(symbols))
+
+	requests = batchTimeSeriesV2(tsMap, symbols, 20)
+	require.Len(t, requests, 2)
+	for _, req := range requests {
+		require.Len(t, req.Timeseries, 1)
+		ts := req.Timeseries[0]
+		// every batch has its own symbols table, holding only the symbols of its series
+		assert.Len(t, req.Symbols, 5)
+		labels := writev2.DesymbolizeLabels(ts.LabelsRefs, req.Symbols)
+		assert.Equal(t, "job", labels[1].Name)
+		assert.Equal(t, "api", labels[1].Value)
+		if labels[0].Value == "a" {
+			assert.Equal(t, []writev2.Sample{{Value: 1, Timestamp: 1000}, {Value: 2, Timestamp: 2000}}, ts.Samples)
+		}
+	}
+}
+
+func Test_writeRequestFromV2(t *testing.T) {
+	req := &writev2.Request{
+		Symbols: []string{"", "__name__", "http_duration_bucket", "le", "+Inf", "http_duration_count", "duration of requests", "seconds", "trace_id", "abc"},
+		Timeseries: []writev2.TimeSeries{
+			{
+				LabelsRefs:       []uint32{1, 2, 3, 4},
+				Samples:          []writev2.Sample{{Value: 3, Timestamp: 1000}},
+				Exemplars:        []writev2.Exemplar{{LabelsRefs: []uint32{8, 9}, Value: 0.5, Timestamp: 900}},
+				Metadata:         writev2.Metadata{Type: writev2.MetricTypeHistogram, HelpRef: 6, UnitRef: 7},
+				CreatedTimestamp: 500,
+			},
+			{
+				LabelsRefs: []uint32{1, 5},
+				Samples:    []writev2.Sample{{Value: 3, Timestamp: 1000}},
+				Metadata:   writev2.Metadata{Type: writev2.MetricTypeHistogram, HelpRef: 6, UnitRef: 7},
+			},
+		},
+	}
+
+	converted := writeRequestFromV2(req, true)
+	require.Len(t, converted.Timeseries, 2)
+	assert.Equal(t, prompb.TimeSeries{
+		Labels:     []prompb.Label{{Name: "__name__", Value: "http_duration_bucket"}, {Name: "le", Value: "+Inf"}},
+		Samples:    []prompb.Sample{{Value: 3, Timestamp: 1000}},
+		Exemplars:  []prompb.Exemplar{{Labels: []prompb.Label{{Name: "trace_id", Value: "abc"}}, Value: 0.5, Timestamp: 900}},
+		Histograms: []prompb.Histogram{},
+	}, converted.Timeseries[0])
+	// the metadata is sent once per metric family
+	assert.Equal(t, []prompb.MetricMetadata{{
+		Type:             prompb.MetricMetadata_HISTOGRAM,
+		MetricFamilyName: "http_duration",
+		Help:             "duration of requests",
+		Unit:             "seconds",
+	}}, converted.Metadata)
+
+	assert.Empty(t, writeRequestFromV2(req, false).Metadata)
+}
+
+func Test_histogramFromV2(t *testing.T) {
+	converted := histogramFromV2(writev2.Histogram{
+		CountInt:       4,
+		Sum:            10,
+		Schema:         1,
+		ZeroCountInt:   1,
+		PositiveSpans:  []writev2.BucketSpan{{Offset: 0, Length: 2}},
+		PositiveDeltas: []int64{1, 1},
+		ResetHint:      writev2.ResetHintNo,
+		Timestamp:      1000,
+	})
+	assert.Equal(t, prompb.Histogram{
+		Count:          &prompb.Histogram_CountInt{CountInt: 4},
+		Sum:            10,
+		Schema:         1,
+		ZeroCount:      &prompb.Histogram_ZeroCountInt{ZeroCountInt: 1},
+		PositiveSpans:  []prompb.BucketSpan{{Offset: 0, Length: 2}},
+		PositiveDeltas: []int64{1, 1},
+		ResetHint:      prompb.Histogram_NO,
+		Timestamp:      1000,
+	}, converted)
+
+	converted = histogramFromV2(writev2.Histogram{CountFloat: 2.5, ZeroCountFloat: 0.5, IsFloat: true})
+	assert.Equal(t, &prompb.Histogram_CountFloat{CountFloat: 2.5}, converted.Count)
+	assert.Equal(t, &prompb.Histogram_ZeroCountFloat{ZeroCountFloat: 0.5}, converted.ZeroCount)
+}
@@ -7,7 +7,10 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"hash/fnv"
+	"os"
 	"path/filepath"
+	"strconv"
 	"sync"
 	"sync/atomic"
 	"time"
@@ -16,33 +19,55 @@ import (
 	"github.com/gogo/protobuf/proto"
 	"github.com/prometheus/prometheus/prompb"
 	"github.com/tidwall/wal"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
 	"go.uber.org/multierr"
 	"go.uber.org/zap"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/exporter/prometheusremotewriteexporter/internal/metadata"
 )
 
+// prweWAL is a single shard of the write-ahead log, it has its own reader
+// that exports the persisted requests.
 type prweWAL struct {
-	mu        sync.Mutex // mu protects the fields below.
-	wal       *wal.Log
-	walConfig *WALConfig
-	walPath   string
+	mu           sync.Mutex // mu protects the fields below.
+	wal          *wal.Log
+	walConfig    *WALConfig
+	walPath      string
+	shard        int
+	maxSizeBytes int64
 
 	exportSink func(ctx context.Context, reqL []*prompb.WriteRequest) error
 
-	stopOnce  sync.Once
-	stopChan  chan struct{}
-	rWALIndex *atomic.Uint64
-	wWALIndex *atomic.Uint64
+	stopOnce       sync.Once
+	stopChan       chan struct{}
+	rWALIndex      *atomic.Uint64
+	wWALIndex      *atomic.Uint64
+	droppedSamples *atomic.Int64
+	// samples holds the number of samples of the entries persisted since the WAL was opened that weren't
+	// read yet, keyed by index, so that the samples dropped with the oldest segments can be counted.
+	samples map[uint64]int64
 }
 
 const (
 	defaultWALBufferSize        = 300
 	defaultWALTruncateFrequency = 1 * time.Minute
+
+	walDirectoryName = "prom_remotewrite"
 )
 
 type WALConfig struct {
 	Directory         string        `mapstructure:"directory"`
 	BufferSize        int           `mapstructure:"buffer_size"`
 	TruncateFrequency time.Duration `mapstructure:"truncate_frequency"`
+	// Shards is the number of write-ahead logs the series are spread over, keyed by the hash of
+	// their labels. Every shard is read and exported independently. Defaults to the number of
+	// consumers of the remote write queue.
+	Shards int `mapstructure:"shards"`
+	// MaxSizeBytes bounds the total size on disk of the shards. Once a shard exceeds its part of
+	// the bound its oldest segments are dropped, even if they weren't exported yet. 0 means unbounded.
+	MaxSizeBytes int64 `mapstructure:"max_size_bytes"`
 }
 
 func (wc *WALConfig) bufferSize() int {
@@ -59,7 +84,29 @@ func (wc *WALConfig) truncateFrequency() time.Duration {
 	return defaultWALTruncateFrequency
 }
 
-func newWAL(walConfig *WALConfig, exportSink func(context.Context, []*prompb.WriteRequest) error) (*prweWAL, error) {
+func (wc *WALConfig) shards(numConsumers int) int {
+	if wc.Shards > 0 {
+		return wc.Shards
+	}
+	if numConsumers > 0 {
+		return numConsumers
+	}
+	return 1
+}
+
+// shardMaxSizeBytes returns the part of MaxSizeBytes every shard is bounded to.
+func (wc *WALConfig) shardMaxSizeBytes(shards int) int64 {
+	if wc.MaxSizeBytes <= 0 {
+		return 0
+	}
+	size := wc.MaxSizeBytes / int64(shards)
+	if size < 1 {
+		return 1
+	}
+	return size
+}
+
+func newWAL(walConfig *WALConfig, shard int, exportSink func(context.Context, []*prompb.WriteRequest) error) (*prweWAL, error) {
 	if walConfig == nil {
 		// There are cases for which the WAL can be disabled.
 		// TODO: Perhaps log that the WAL wasn't enabled.
@@ -67,20 +114,47 @@ func newWAL(walConfig *WALConfig, exportSink func(context.Context, []*prompb.Wri
 	}
 
 	return &prweWAL{
-		exportSink: exportSink,
-		walConfig:  walConfig,
-		stopChan:   make(chan struct{}),
-		rWALIndex:  &atomic.Uint64{},
-		wWALIndex:  &atomic.Uint64{},
+		exportSink:     exportSink,
+		walConfig:      walConfig,
+		shard:          shard,
+		stopChan:       make(chan struct{}),
+		rWALIndex:      &atomic.Uint64{},
+		wWALIndex:      &atomic.Uint64{},
+		droppedSamples: &atomic.Int64{},
+		samples:        map[uint64]int64{},
 	}, nil
 }
 
-func (wc *WALConfig) createWAL() (*wal.Log, string, error) {
-	walPath := filepath.Join(wc.Directory, "prom_remotewrite")
-	log, err := wal.Open(walPath, &wal.Options{
+func (wc *WALConfig) shardPath(shard int) string {
+	walPath := filepath.Join(wc.Directory, walDirectoryName)
+	if shard > 0 {
+		walPath += "_" + strconv.Itoa(shard)
+	}
+	return walPath
+}
+
+// existingShards returns the number of shard directories left by a previous run.
+func (wc *WALConfig) existingShards() int {
+	shards := 0
+	for ; ; shards++ {
+		if _, err := os.Stat(wc.shardPath(shards)); err != nil {
+			return shards
+		}
+	}
+}
+
+// createWAL opens the WAL of the shard. The first shard keeps the directory used before the WAL was
+// sharded, so that its entries are still exported. A positive segmentSize overrides the default one.
+func (wc *WALConfig) createWAL(shard int, segmentSize int) (*wal.Log, string, error) {
+	walPath := wc.shardPath(shard)
+	opts := &wal.Options{
 		SegmentCacheSize: wc.bufferSize(),
 		NoCopy:           true,
-	})
+	}
+	if segmentSize > 0 {
+		opts.SegmentSize = segmentSize
+	}
+	log, err := wal.Open(walPath, opts)
 	if err != nil {
 		return nil, "", fmt.Errorf("prometheusremotewriteexporter: failed to open WAL: %w", err)
 	}
@@ -93,6 +167,328 @@ var (
 	errNilConfig     = errors.New("expecting a non-nil configuration")
 )
 
+// shardedWAL spreads the requests over several prweWAL shards keyed by the hash of the series labels.
+type shardedWAL struct {
+	walConfig    *WALConfig
+	shards       []*prweWAL
+	exportSink   func(context.Context, []*prompb.WriteRequest) error
+	registration metric.Registration
+	stopOnce     sync.Once
+	stopChan     chan struct{}
+}
+
+func newShardedWAL(walConfig *WALConfig, numConsumers int, set component.TelemetrySettings, exportSink func(context.Context, []*prompb.WriteRequest) error) (*shardedWAL, error) {
+	if walConfig == nil {
+		return nil, errNilConfig
+	}
+
+	numShards := walConfig.shards(numConsumers)
+	maxSizeBytes := walConfig.shardMaxSizeBytes(numShards)
+	sw := &shardedWAL{
+		walConfig:  walConfig,
+		shards:     make([]*prweWAL, 0, numShards),
+		exportSink: exportSink,
+		stopChan:   make(chan struct{}),
+	}
+	for i := 0; i < numShards; i++ {
+		shard, err := newWAL(walConfig, i, exportSink)
+		if err != nil {
+			return nil, err
+		}
+		shard.maxSizeBytes = maxSizeBytes
+		sw.shards = append(sw.shards, shard)
+	}
+
+	if err := sw.registerMetrics(set.MeterProvider.Meter("otelcol/" + metadata.Type)); err != nil {
+		return nil, err
+	}
+	return sw, nil
+}
+
+func (sw *shardedWAL) registerMetrics(meter metric.Meter) error {
+	lag, err := meter.Int64ObservableGauge(
+		metadata.Type+"_wal_lag",
+		metric.WithDescription("Number of entries written to the WAL that weren't read for export yet."),
+	)
+	if err != nil {
+		return err
+	}
+	segments, err := meter.Int64ObservableGauge(
+		metadata.Type+"_wal_segments",
+		metric.WithDescription("Number of segment files of the WAL."),
+	)
+	if err != nil {
+		return err
+	}
+	size, err := meter.Int64ObservableGauge(
+		metadata.Type+"_wal_size",
+		metric.WithDescription("Size on disk of the WAL."),
+		metric.WithUnit("By"),
+	)
+	if err != nil {
+		return err
+	}
+	dropped, err := meter.Int64ObservableCounter(
+		metadata.Type+"_wal_dropped_samples",
+		metric.WithDescription("Number of samples dropped from the WAL before being exported because it exceeded max_size_bytes."),
+	)
+	if err != nil {
+		return err
+	}
+
+	sw.registration, err = meter.RegisterCallback(func(_ context.Context, o metric.Observer) error {
+		for _, shard := range sw.shards {
+			attrs := metric.WithAttributes(attribute.Int("shard", shard.shard))
+			o.ObserveInt64(lag, int64(shard.lag()), attrs)
+			o.ObserveInt64(dropped, shard.droppedSamples.Load(), attrs)
+
+			// The directory doesn't exist until the shard is started.
+			files, err := readSegments(shard.walConfig.shardPath(shard.shard))
+			if err != nil {
+				continue
+			}
+			var bytes int64
+			for _, f := range files {
+				bytes += f.size
+			}
+			o.ObserveInt64(segments, int64(len(files)), attrs)
+			o.ObserveInt64(size, bytes, attrs)
+		}
+		return nil
+	}, lag, segments, size, dropped)
+	return err
+}
+
+// run starts the reader of every shard. When the number of shards changed since the previous run,
+// the series were persisted to other shards than the ones they are persisted to now. The entries left
+// by the previous run are then exported first, so that the samples of every series are still exported
+// in order, and the directories of the removed shards are deleted before the readers are started.
+func (sw *shardedWAL) run(ctx context.Context) error {
+	logger, err := loggerFromContext(ctx)
+	if err != nil {
+		return err
+	}
+	previousShards := sw.walConfig.existingShards()
+	if previousShards == 0 || previousShards == len(sw.shards) {
+		for _, shard := range sw.shards {
+			if err = shard.run(contextWithLogger(ctx, logger.With(zap.Int("shard", shard.shard)))); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	// The shards are opened right away, so that requests are persisted while the previous entries are exported.
+	backlogs := make([]uint64, len(sw.shards))
+	for i, shard := range sw.shards {
+		if err = shard.retrieveWALIndices(); err != nil {
+			logger.Error("unable to start write-ahead log", zap.Int("shard", shard.shard), zap.Error(err))
+			return err
+		}
+		backlogs[i] = shard.wWALIndex.Load()
+	}
+	logger.Info("Number of WAL shards changed, exporting the entries of the previous shards first",
+		zap.Int("previous_shards", previousShards), zap.Int("shards", len(sw.shards)))
+
+	go func() {
+		for i, shard := range sw.shards {
+			if err := sw.drainShard(ctx, shard, backlogs[i]); err != nil {
+				return
+			}
+		}
+		for i := len(sw.shards); i < previousShards; i++ {
+			if err := sw.drainRemovedShard(ctx, logger, i); err != nil {
+				logger.Error("unable to export the entries of a removed WAL shard", zap.Int("shard", i), zap.Error(err))
+				return
+			}
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-sw.stopChan:
+			return
+		default:
+		}
+		for _, shard := range sw.shards {
+			shard.startReader(ctx, logger.With(zap.Int("shard", shard.shard)))
+		}
+	}()
+	return nil
+}
+
+// drainRemovedShard exports the entries of a shard that isn't configured anymore, then deletes its directory.
+func (sw *shardedWAL) drainRemovedShard(ctx context.Context, logger *zap.Logger, i int) error {
+	shard, err := newWAL(sw.walConfig, i, sw.exportSink)
+	if err != nil {
+		return err
+	}
+	if err = shard.retrieveWALIndices(); err != nil {
+		_ = shard.stop()
+		return err
+	}
+	err = sw.drainShard(ctx, shard, shard.wWALIndex.Load())
+	if errS := shard.stop(); err == nil {
+		err = errS
+	}
+	if err != nil {
+		return err
+	}
+	logger.Info("Exported the entries of a removed WAL shard", zap.Int("shard", i))
+	return os.RemoveAll(sw.walConfig.shardPath(i))
+}
+
+// drainShard exports the entries of the shard up to index last. The export is retried until it succeeds
+// or the WAL is stopped, the entries of a failed export are read again.
+func (sw *shardedWAL) drainShard(ctx context.Context, shard *prweWAL, last uint64) error {
+	logger, err := loggerFromContext(ctx)
+	if err != nil {
+		return err
+	}
+	for {
+		err = shard.drain(ctx, last)
+		if err == nil {
+			return nil
+		}
+		logger.Error("error exporting the entries of the previous WAL shards", zap.Int("shard", shard.shard), zap.Error(err))
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-sw.stopChan:
+			return errAlreadyClosed
+		case <-time.After(shard.walConfig.truncateFrequency()):
+		}
+		if err = shard.retrieveWALIndices(); err != nil {
+			logger.Error("unable to re-start write-ahead log after error", zap.Int("shard", shard.shard), zap.Error(err))
+			return err
+		}
+	}
+}
+
+func (sw *shardedWAL) stop() error {
+	sw.stopOnce.Do(func() { close(sw.stopChan) })
+	var errs error
+	for _, shard := range sw.shards {
+		errs = multierr.Append(errs, shard.stop())
+	}
+	if sw.registration != nil {
+		errs = multierr.Append(errs, sw.registration.Unregister())
+		sw.registration = nil
+	}
+	return errs
+}
+
+// persistToWAL splits the series of the requests by shard and persists them to their shard.
+// The metadata of the requests are persisted to the first shard.
+func (sw *shardedWAL) persistToWAL(requests []*prompb.WriteRequest) error {
+	if len(sw.shards) == 1 {
+		return sw.shards[0].persistToWAL(requests)
+	}
+
+	shardRequests := make([][]*prompb.WriteRequest, len(sw.shards))
+	for _, req := range requests {
+		split := make([]*prompb.WriteRequest, len(sw.shards))
+		for _, ts := range req.Timeseries {
+			i := shardOf(ts.Labels, len(sw.shards))
+			if split[i] == nil {
+				split[i] = &prompb.WriteRequest{}
+			}
+			split[i].Timeseries = append(split[i].Timeseries, ts)
+		}
+		if len(req.Metadata) > 0 {
+			if split[0] == nil {
+				split[0] = &prompb.WriteRequest{}
+			}
+			split[0].Metadata = req.Metadata
+		}
+		for i, shardReq := range split {
+			if shardReq != nil {
+				shardRequests[i] = append(shardRequests[i], shardReq)
+			}
+		}
+	}
+
+	var errs error
+	for i, reqs := range shardRequests {
+		if len(reqs) > 0 {
+			errs = multierr.Append(errs, sw.shards[i].persistToWAL(reqs))
+		}
+	}
+	return errs
+}
+
+// shardOf returns the shard of the series, the same series is always persisted to the same shard
+// so that its samples are exported in order.
+func shardOf(labels []prompb.Label, shards int) int {
+	h := fnv.New64a()
+	for _, l := range labels {
+		_, _ = h.Write([]byte(l.Name))
+		_, _ = h.Write([]byte{0xff})
+		_, _ = h.Write([]byte(l.Value))
+		_, _ = h.Write([]byte{0xff})
+	}
+	return int(h.Sum64() % uint64(shards))
+}
+
+// segmentFile is a segment of a WAL shard, segments are named after the index of their first entry.
+type segmentFile struct {
+	firstIndex uint64
+	size       int64
+}
+
+// readSegments lists the segments of the WAL at walPath ordered from oldest to newest.
+func readSegments(walPath string) ([]segmentFile, error) {
+	entries, err := os.ReadDir(walPath)
+	if err != nil {
+		return nil, err
+	}
+	// os.ReadDir sorts by name and the names are zero padded, so the segments are already ordered.
+	var segments []segmentFile
+	for _, entry := range entries {
+		if entry.IsDir() || len(entry.Name()) != 20 {
+			continue
+		}
+		firstIndex, err := strconv.ParseUint(entry.Name(), 10, 64)
+		if err != nil {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			return nil, err
+		}
+		segments = append(segments, segmentFile{firstIndex: firstIndex, size: info.Size()})
+	}
+	return segments, nil
+}
+
+// segmentSize returns the segment size of the shard, when the shard is bounded the segments are kept
+// small enough for the oldest segment to be dropped before the shard grows much over its bound.
+func (prwe *prweWAL) segmentSize() int {
+	if prwe.maxSizeBytes <= 0 {
+		return 0
+	}
+	size := prwe.maxSizeBytes / 4
+	if size < 1 {
+		size = 1
+	}
+	if size > int64(wal.DefaultOptions.SegmentSize) {
+		return 0
+	}
+	return int(size)
+}
+
+// lag returns the number of entries written to the shard that weren't read yet.
+func (prwe *prweWAL) lag() uint64 {
+	rIndex, wIndex := prwe.rWALIndex.Load(), prwe.wWALIndex.Load()
+	if rIndex == 0 {
+		rIndex = 1
+	}
+	if wIndex < rIndex {
+		return 0
+	}
+	return wIndex - rIndex + 1
+}
+
 // retrieveWALIndices queries the WriteAheadLog for its current first and last indices.
 func (prwe *prweWAL) retrieveWALIndices() (err error) {
 	prwe.mu.Lock()
@@ -103,7 +499,7 @@ func (prwe *prweWAL) retrieveWALIndices() (err error) {
 		return err
 	}
 
-	log, walPath, err := prwe.walConfig.createWAL()
+	log, walPath, err := prwe.walConfig.createWAL(prwe.shard, prwe.segmentSize())
 	if err != nil {
 		return err
 	}
@@ -149,7 +545,12 @@ func (prwe *prweWAL) run(ctx context.Context) (err error) {
 		logger.Error("unable to start write-ahead log", zap.Error(err))
 		return
 	}
+	prwe.startReader(ctx, logger)
+	return nil
+}
 
+// startReader starts exporting the entries of the opened WAL, it waits until the export has started.
+func (prwe *prweWAL) startReader(ctx context.Context, logger *zap.Logger) {
 	runCtx, cancel := context.WithCancel(ctx)
 
 	// Start the process of exporting but wait until the exporting has started.
@@ -179,7 +580,38 @@ func (prwe *prweWAL) run(ctx context.Context) (err error) {
 		}
 	}()
 	<-waitUntilStartedCh
-	return nil
+}
+
+// drain exports the entries of the WAL up to index last, buffer_size entries at a time.
+func (prwe *prweWAL) drain(ctx context.Context, last uint64) error {
+	maxCountPerUpload := prwe.walConfig.bufferSize()
+	var reqL []*prompb.WriteRequest
+	for index := prwe.rWALIndex.Load(); last > 0 && index <= last; index = prwe.rWALIndex.Load() {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-prwe.stopChan:
+			return errAlreadyClosed
+		default:
+		}
+
+		if index == 0 {
+			index = 1
+		}
+		req, _, err := prwe.readEntry(index)
+		if err != nil {
+			return err
+		}
+		reqL = append(reqL, req)
+		if len(reqL) < maxCountPerUpload {
+			continue
+		}
+		if err = prwe.exportThenFrontTruncateWAL(ctx, reqL); err != nil {
+			return err
+		}
+		reqL = reqL[:0]
+	}
+	return prwe.exportThenFrontTruncateWAL(ctx, reqL)
 }
 
 // continuallyPopWALThenExport reads a prompb.WriteRequest proto encoded blob from the WAL, and moves
@@ -291,11 +723,8 @@ func (prwe *prweWAL) exportThenFrontTruncateWAL(ctx context.Context, reqL []*pro
 	if errL := prwe.exportSink(ctx, reqL); errL != nil {
 		return errL
 	}
-	if err := prwe.syncAndTruncateFront(); err != nil {
-		return err
-	}
-	// Reset by retrieving the respective read and write WAL indices.
-	return prwe.retrieveWALIndices()
+	// The read index is kept, so that the entries read but not truncated aren't exported again.
+	return prwe.syncAndTruncateFront()
 }
 
 // persistToWAL is the routine that'll be hooked into the exporter's receiving side and it'll
@@ -314,16 +743,61 @@ func (prwe *prweWAL) persistToWAL(requests []*prompb.WriteRequest) error {
 		}
 		wIndex := prwe.wWALIndex.Add(1)
 		batch.Write(wIndex, protoBlob)
+
+		var samples int64
+		for _, ts := range req.Timeseries {
+			samples += int64(len(ts.Samples) + len(ts.Histograms))
+		}
+		prwe.samples[wIndex] = samples
 	}
 
-	return prwe.wal.WriteBatch(batch)
+	if err := prwe.wal.WriteBatch(batch); err != nil {
+		return err
+	}
+	return prwe.truncateOldestSegments()
 }
 
-func (prwe *prweWAL) readPrompbFromWAL(ctx context.Context, index uint64) (wreq *prompb.WriteRequest, err error) {
-	prwe.mu.Lock()
-	defer prwe.mu.Unlock()
+// truncateOldestSegments drops the oldest segments of the WAL until it fits in maxSizeBytes. The samples
+// of the dropped entries that weren't read yet are counted as dropped, from the counts recorded when they
+// were persisted. The segment being written to is never dropped. It must be called with prwe.mu held.
+func (prwe *prweWAL) truncateOldestSegments() error {
+	if prwe.maxSizeBytes <= 0 {
+		return nil
+	}
+	for {
+		segments, err := readSegments(prwe.walPath)
+		if err != nil {
+			return err
+		}
+		var size int64
+		for _, segment := range segments {
+			size += segment.size
+		}
+		if size <= prwe.maxSizeBytes || len(segments) < 2 {
+			return nil
+		}
+
+		// Keep the entries starting at the second oldest segment.
+		keep := segments[1].firstIndex
+		from := prwe.rWALIndex.Load()
+		if first := segments[0].firstIndex; from < first {
+			from = first
+		}
+		for index := from; index < keep; index++ {
+			prwe.droppedSamples.Add(prwe.samples[index])
+			delete(prwe.samples, index)
+		}
 
-	var protoBlob []byte
+		if err = prwe.wal.TruncateFront(keep); err != nil {
+			return err
+		}
+		if prwe.rWALIndex.Load() < keep {
+			prwe.rWALIndex.Store(keep)
+		}
+	}
+}
+
+func (prwe *prweWAL) readPrompbFromWAL(ctx context.Context, index uint64) (wreq *prompb.WriteRequest, err error) {
 	for i := 0; i < 12; i++ {
 		// Firstly check if we've been terminated, then exit if so.
 		select {
@@ -338,20 +812,9 @@ func (prwe *prweWAL) readPrompbFromWAL(ctx context.Context, index uint64) (wreq
 			index = 1
 		}
 
-		if prwe.wal == nil {
-			return nil, fmt.Errorf("attempt to read from closed WAL")
-		}
-
-		protoBlob, err = prwe.wal.Read(index)
+		var req *prompb.WriteRequest
+		req, index, err = prwe.readEntry(index)
 		if err == nil { // The read succeeded.
-			req := new(prompb.WriteRequest)
-			if err = proto.Unmarshal(protoBlob, req); err != nil {
-				return nil, err
-			}
-
-			// Now increment the WAL's read index.
-			prwe.rWALIndex.Add(1)
-
 			return req, nil
 		}
 
@@ -371,7 +834,7 @@ func (prwe *prweWAL) readPrompbFromWAL(ctx context.Context, index uint64) (wreq
 		if werr != nil {
 			return nil, werr
 		}
-		if werr = walWatcher.Add(prwe.walPath); werr != nil {
+		if werr = walWatcher.Add(prwe.walConfig.shardPath(prwe.shard)); werr != nil {
 			return nil, werr
 		}
 
@@ -423,3 +886,31 @@ func (prwe *prweWAL) readPrompbFromWAL(ctx context.Context, index uint64) (wreq
 	}
 	return nil, err
 }
+
+// readEntry reads the entry at index and moves the read index past it. The lock is only held while
+// reading, so that requests can be persisted while the reader waits for them. When the entry was
+// dropped to bound the size of the WAL, the oldest entry left is read instead, the index read is returned.
+func (prwe *prweWAL) readEntry(index uint64) (*prompb.WriteRequest, uint64, error) {
+	prwe.mu.Lock()
+	defer prwe.mu.Unlock()
+
+	if prwe.wal == nil {
+		return nil, index, fmt.Errorf("attempt to read from closed WAL")
+	}
+	if rIndex := prwe.rWALIndex.Load(); index < rIndex {
+		index = rIndex
+	}
+
+	protoBlob, err := prwe.wal.Read(index)
+	if err != nil {
+		return nil, index, err
+	}
+	req := new(prompb.WriteRequest)
+	if err = proto.Unmarshal(protoBlob, req); err != nil {
+		return nil, index, err
+	}
+	// Now move the WAL's read index past the entry.
+	prwe.rWALIndex.Store(index + 1)
+	delete(prwe.samples, index)
+	return req, index, nil
+}
@@ -5,13 +5,19 @@ package prometheusremotewriteexporter
 
 import (
 	"context"
+	"fmt"
+	"path/filepath"
 	"sort"
+	"strconv"
+	"sync"
 	"testing"
 	"time"
 
 	"github.com/prometheus/prometheus/prompb"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.uber.org/zap"
 )
 
 func doNothingExportSink(_ context.Context, reqL []*prompb.WriteRequest) error {
@@ -21,14 +27,14 @@ func doNothingExportSink(_ context.Context, reqL []*prompb.WriteRequest) error {
 
 func TestWALCreation_nilConfig(t *testing.T) {
 	config := (*WALConfig)(nil)
-	pwal, err := newWAL(config, doNothingExportSink)
+	pwal, err := newWAL(config, 0, doNothingExportSink)
 	require.Equal(t, err, errNilConfig)
 	require.Nil(t, pwal)
 }
 
 func TestWALCreation_nonNilConfig(t *testing.T) {
 	config := &WALConfig{Directory: t.TempDir()}
-	pwal, err := newWAL(config, doNothingExportSink)
+	pwal, err := newWAL(config, 0, doNothingExportSink)
 	require.NotNil(t, pwal)
 	assert.Nil(t, err)
 	assert.NoError(t, pwal.stop())
@@ -80,7 +86,7 @@ func TestWALStopManyTimes(t *testing.T) {
 		TruncateFrequency: 60 * time.Microsecond,
 		BufferSize:        1,
 	}
-	pwal, err := newWAL(config, doNothingExportSink)
+	pwal, err := newWAL(config, 0, doNothingExportSink)
 	require.Nil(t, err)
 	require.NotNil(t, pwal)
 
@@ -99,7 +105,7 @@ func TestWAL_persist(t *testing.T) {
 	// Unit tests that requests written to the WAL persist.
 	config := &WALConfig{Directory: t.TempDir()}
 
-	pwal, err := newWAL(config, doNothingExportSink)
+	pwal, err := newWAL(config, 0, doNothingExportSink)
 	require.Nil(t, err)
 
 	// 1. Write out all the entries.
@@ -156,3 +162,199 @@ func TestWAL_persist(t *testing.T) {
 	require.Equal(t, reqLFromWAL[0], reqL[0])
 	require.Equal(t, reqLFromWAL[1], reqL[1])
 }
+
+func TestWALConfig_shards(t *testing.T) {
+	assert.Equal(t, 3, (&WALConfig{Shards: 3}).shards(5))
+	assert.Equal(t, 5, (&WALConfig{}).shards(5))
+	assert.Equal(t, 1, (&WALConfig{}).shards(0))
+
+	assert.Equal(t, int64(0), (&WALConfig{}).shardMaxSizeBytes(4))
+	assert.Equal(t, int64(250), (&WALConfig{MaxSizeBytes: 1000}).shardMaxSizeBytes(4))
+}
+
+func TestShardedWAL_persist(t *testing.T) {
+	config := &WALConfig{Directory: t.TempDir(), Shards: 3}
+	sw, err := newShardedWAL(config, 1, componenttest.NewNopTelemetrySettings(), doNothingExportSink)
+	require.NoError(t, err)
+	require.Len(t, sw.shards, 3)
+	for _, shard := range sw.shards {
+		require.NoError(t, shard.retrieveWALIndices())
+	}
+	t.Cleanup(func() {
+		assert.NoError(t, sw.stop())
+	})
+
+	var series []prompb.TimeSeries
+	for i := 0; i < 20; i++ {
+		series = append(series, prompb.TimeSeries{
+			Labels:  []prompb.Label{{Name: "__name__", Value: "metric"}, {Name: "id", Value: strconv.Itoa(i)}},
+			Samples: []prompb.Sample{{Value: float64(i), Timestamp: 100}},
+		})
+	}
+	metadata := []prompb.MetricMetadata{{MetricFamilyName: "metric", Type: prompb.MetricMetadata_GAUGE}}
+	require.NoError(t, sw.persistToWAL([]*prompb.WriteRequest{{Timeseries: series, Metadata: metadata}}))
+
+	// Every series is persisted once, to the shard of its labels.
+	total := 0
+	for i, shard := range sw.shards {
+		last, err := shard.wal.LastIndex()
+		require.NoError(t, err)
+		for index := uint64(1); index <= last; index++ {
+			req, err := shard.readPrompbFromWAL(context.Background(), index)
+			require.NoError(t, err)
+			for _, ts := range req.Timeseries {
+				assert.Equal(t, i, shardOf(ts.Labels, 3))
+			}
+			total += len(req.Timeseries)
+			if i == 0 {
+				assert.Equal(t, metadata, req.Metadata)
+			} else {
+				assert.Empty(t, req.Metadata)
+			}
+		}
+		assert.Equal(t, uint64(0), shard.lag())
+	}
+	assert.Equal(t, 20, total)
+
+	// The first shard keeps the directory of the unsharded WAL.
+	assert.DirExists(t, filepath.Join(config.Directory, "prom_remotewrite"))
+	assert.DirExists(t, filepath.Join(config.Directory, "prom_remotewrite_2"))
+}
+
+func TestWAL_truncateOldestSegments(t *testing.T) {
+	config := &WALConfig{Directory: t.TempDir(), MaxSizeBytes: 4096}
+	sw, err := newShardedWAL(config, 1, componenttest.NewNopTelemetrySettings(), doNothingExportSink)
+	require.NoError(t, err)
+	require.Len(t, sw.shards, 1)
+	pwal := sw.shards[0]
+	require.NoError(t, pwal.retrieveWALIndices())
+	t.Cleanup(func() {
+		assert.NoError(t, sw.stop())
+	})
+
+	for i := 0; i < 100; i++ {
+		req := &prompb.WriteRequest{
+			Timeseries: []prompb.TimeSeries{{
+				Labels:  []prompb.Label{{Name: "__name__", Value: "metric_with_a_long_enough_name"}},
+				Samples: []prompb.Sample{{Value: float64(i), Timestamp: int64(i)}},
+			}},
+		}
+		require.NoError(t, sw.persistToWAL([]*prompb.WriteRequest{req}))
+	}
+
+	segments, err := readSegments(pwal.walPath)
+	require.NoError(t, err)
+	var size int64
+	for _, segment := range segments {
+		size += segment.size
+	}
+	// Only the segment being written to can make the WAL exceed its bound.
+	assert.LessOrEqual(t, size-segments[len(segments)-1].size, config.MaxSizeBytes)
+
+	first, err := pwal.wal.FirstIndex()
+	require.NoError(t, err)
+	assert.Greater(t, first, uint64(1))
+	assert.Equal(t, int64(first-1), pwal.droppedSamples.Load())
+	assert.Equal(t, uint64(100)-first+1, pwal.lag())
+
+	// The reader carries on at the oldest entry left.
+	req, err := pwal.readPrompbFromWAL(context.Background(), 1)
+	require.NoError(t, err)
+	assert.Equal(t, float64(first-1), req.Timeseries[0].Samples[0].Value)
+}
+
+func TestShardedWAL_reshard(t *testing.T) {
+	dir := t.TempDir()
+	series := func(timestamp int64) []prompb.TimeSeries {
+		var series []prompb.TimeSeries
+		for i := 0; i < 20; i++ {
+			series = append(series, prompb.TimeSeries{
+				Labels:  []prompb.Label{{Name: "__name__", Value: "metric"}, {Name: "id", Value: strconv.Itoa(i)}},
+				Samples: []prompb.Sample{{Value: float64(i), Timestamp: timestamp}},
+			})
+		}
+		return series
+	}
+
+	// The previous run leaves its entries in 3 shards.
+	sw, err := newShardedWAL(&WALConfig{Directory: dir, Shards: 3}, 1, componenttest.NewNopTelemetrySettings(), doNothingExportSink)
+	require.NoError(t, err)
+	for _, shard := range sw.shards {
+		require.NoError(t, shard.retrieveWALIndices())
+	}
+	require.NoError(t, sw.persistToWAL([]*prompb.WriteRequest{{Timeseries: series(1)}}))
+	require.NoError(t, sw.stop())
+
+	var mu sync.Mutex
+	timestamps := map[string][]int64{}
+	exportSink := func(_ context.Context, reqL []*prompb.WriteRequest) error {
+		mu.Lock()
+		defer mu.Unlock()
+		for _, req := range reqL {
+			for _, ts := range req.Timeseries {
+				id := ts.Labels[1].Value
+				timestamps[id] = append(timestamps[id], ts.Samples[0].Timestamp)
+			}
+		}
+		return nil
+	}
+	config := &WALConfig{Directory: dir, Shards: 2, BufferSize: 1, TruncateFrequency: time.Millisecond}
+	sw, err = newShardedWAL(config, 1, componenttest.NewNopTelemetrySettings(), exportSink)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		assert.NoError(t, sw.stop())
+	})
+	require.NoError(t, sw.run(contextWithLogger(context.Background(), zap.NewNop())))
+	require.NoError(t, sw.persistToWAL([]*prompb.WriteRequest{{Timeseries: series(2)}}))
+
+	// The samples of the previous run are exported first, and the directory of the removed shard is deleted.
+	require.Eventually(t, func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		for i := 0; i < 20; i++ {
+			if len(timestamps[strconv.Itoa(i)]) < 2 {
+				return false
+			}
+		}
+		return true
+	}, 10*time.Second, 10*time.Millisecond)
+	mu.Lock()
+	for i := 0; i < 20; i++ {
+		assert.Equal(t, []int64{1, 2}, timestamps[strconv.Itoa(i)])
+	}
+	mu.Unlock()
+	assert.NoDirExists(t, filepath.Join(dir, "prom_remotewrite_2"))
+	assert.Equal(t, 2, config.existingShards())
+}
+
+func BenchmarkShardedWAL_persistToWAL(b *testing.B) {
+	const seriesPerRequest = 1000
+	var series []prompb.TimeSeries
+	for i := 0; i < seriesPerRequest; i++ {
+		series = append(series, prompb.TimeSeries{
+			Labels:  []prompb.Label{{Name: "__name__", Value: "metric"}, {Name: "id", Value: strconv.Itoa(i)}},
+			Samples: []prompb.Sample{{Value: float64(i), Timestamp: 100}},
+		})
+	}
+	requests := []*prompb.WriteRequest{{Timeseries: series}}
+
+	for _, shards := range []int{1, 4} {
+		b.Run(fmt.Sprintf("shards=%d", shards), func(b *testing.B) {
+			sw, err := newShardedWAL(&WALConfig{Directory: b.TempDir(), Shards: shards}, 1, componenttest.NewNopTelemetrySettings(), doNothingExportSink)
+			require.NoError(b, err)
+			for _, shard := range sw.shards {
+				require.NoError(b, shard.retrieveWALIndices())
+			}
+			b.Cleanup(func() {
+				assert.NoError(b, sw.stop())
+			})
+
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				require.NoError(b, sw.persistToWAL(requests))
+			}
+			b.ReportMetric(float64(b.N*seriesPerRequest)/b.Elapsed().Seconds(), "samples/s")
+		})
+	}
+}
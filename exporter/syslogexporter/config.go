@@ -5,7 +5,9 @@ package syslogexporter // import "github.com/open-telemetry/opentelemetry-collec
 
 import (
 	"errors"
+	"fmt"
 	"strings"
+	"time"
 
 	"go.opentelemetry.io/collector/config/configtls"
 	"go.opentelemetry.io/collector/exporter/exporterhelper"
@@ -17,6 +19,9 @@ var (
 	errInvalidEndpoint     = errors.New("invalid endpoint: endpoint is required but it is not configured")
 	errUnsupportedNetwork  = errors.New("unsupported network: network is required, only tcp/udp supported")
 	errUnsupportedProtocol = errors.New("unsupported protocol: Only rfc5424 and rfc3164 supported")
+	errUnsupportedFraming  = errors.New("unsupported framing: only non_transparent and octet_counting supported, octet_counting over tcp")
+	errUnsupportedFacility = errors.New("unsupported facility: must be in the range 0-23")
+	errInvalidMaxIdleConns = errors.New("invalid max_idle_conns: must not be negative")
 )
 
 // Config defines configuration for Syslog exporter.
@@ -31,6 +36,21 @@ type Config struct {
 	// Protocol of syslog messages
 	// options: rfc5424, rfc3164
 	Protocol string `mapstructure:"protocol"`
+	// Framing of syslog messages over tcp
+	// options: non_transparent, octet_counting
+	// defaults to octet_counting over TLS, non_transparent otherwise
+	Framing string `mapstructure:"framing"`
+
+	// Fields maps the fields of the syslog messages to the fields of the log records
+	Fields FieldsConfig `mapstructure:"fields"`
+	// Facility of the messages whose priority is derived from the severity of the log records
+	Facility int `mapstructure:"facility"`
+
+	// MaxIdleConns is the maximum number of idle connections kept open to the syslog server,
+	// connections are not reused when it is zero
+	MaxIdleConns int `mapstructure:"max_idle_conns"`
+	// IdleConnTimeout is the duration after which the idle connections are closed
+	IdleConnTimeout time.Duration `mapstructure:"idle_conn_timeout"`
 
 	// TLSSetting struct exposes TLS client configuration.
 	TLSSetting configtls.TLSClientSetting `mapstructure:"tls"`
@@ -40,6 +60,18 @@ type Config struct {
 	exporterhelper.TimeoutSettings `mapstructure:",squash"` // squash ensures fields are correctly decoded in embedded struct
 }
 
+// FieldsConfig defines the fields of the log records the fields of the syslog messages are read from.
+// The fields are either `body`, `body.<key>`, `attributes.<key>` or `resource.<key>`.
+type FieldsConfig struct {
+	Priority       string `mapstructure:"priority"`
+	Hostname       string `mapstructure:"hostname"`
+	Appname        string `mapstructure:"appname"`
+	ProcID         string `mapstructure:"proc_id"`
+	MsgID          string `mapstructure:"msg_id"`
+	StructuredData string `mapstructure:"structured_data"`
+	Message        string `mapstructure:"message"`
+}
+
 // Validate the configuration for errors. This is required by component.Config.
 func (cfg *Config) Validate() error {
 	invalidFields := []error{}
@@ -62,6 +94,28 @@ func (cfg *Config) Validate() error {
 		invalidFields = append(invalidFields, errUnsupportedProtocol)
 	}
 
+	switch cfg.Framing {
+	case "", framingNonTransparentStr:
+	case framingOctetCountingStr:
+		if strings.ToLower(cfg.Network) != "tcp" {
+			invalidFields = append(invalidFields, errUnsupportedFraming)
+		}
+	default:
+		invalidFields = append(invalidFields, errUnsupportedFraming)
+	}
+
+	if cfg.Facility < 0 || cfg.Facility > 23 {
+		invalidFields = append(invalidFields, errUnsupportedFacility)
+	}
+
+	if cfg.MaxIdleConns < 0 {
+		invalidFields = append(invalidFields, errInvalidMaxIdleConns)
+	}
+
+	if _, err := cfg.Fields.build(); err != nil {
+		invalidFields = append(invalidFields, err)
+	}
+
 	if len(invalidFields) > 0 {
 		return multierr.Combine(invalidFields...)
 	}
@@ -69,6 +123,28 @@ func (cfg *Config) Validate() error {
 	return nil
 }
 
+// build returns the references to the fields of the log records.
+func (f FieldsConfig) build() (*fields, error) {
+	var errs error
+	parse := func(name, value string) fieldRef {
+		ref, err := parseFieldRef(value)
+		if err != nil {
+			errs = multierr.Append(errs, fmt.Errorf("invalid fields::%s: %w", name, err))
+		}
+		return ref
+	}
+	built := &fields{
+		priority:       parse("priority", f.Priority),
+		hostname:       parse("hostname", f.Hostname),
+		appname:        parse("appname", f.Appname),
+		procID:         parse("proc_id", f.ProcID),
+		msgID:          parse("msg_id", f.MsgID),
+		structuredData: parse("structured_data", f.StructuredData),
+		message:        parse("message", f.Message),
+	}
+	return built, errs
+}
+
 const (
 	// Syslog Network
 	DefaultNetwork = "tcp"
@@ -76,4 +152,25 @@ const (
 	DefaultPort = 514
 	// Syslog Protocol
 	DefaultProtocol = "rfc5424"
+	// Syslog Framing without TLS
+	DefaultFraming = "non_transparent"
+	// Syslog Facility of the messages without priority, local4
+	DefaultFacility = 20
+	// Maximum number of idle connections
+	DefaultMaxIdleConns = 10
+	// Duration after which the idle connections are closed
+	DefaultIdleConnTimeout = 90 * time.Second
 )
+
+// defaultFieldsConfig reads the fields from the attributes set by the syslog parser.
+func defaultFieldsConfig() FieldsConfig {
+	return FieldsConfig{
+		Priority:       "attributes." + priority,
+		Hostname:       "attributes." + hostname,
+		Appname:        "attributes." + app,
+		ProcID:         "attributes." + pid,
+		MsgID:          "attributes." + msgID,
+		StructuredData: "attributes." + structuredData,
+		Message:        "attributes." + message,
+	}
+}
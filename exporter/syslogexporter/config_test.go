@@ -57,6 +57,49 @@ func TestValidate(t *testing.T) {
 			},
 			err: "unsupported protocol: Only rfc5424 and rfc3164 supported",
 		},
+		{
+			name: "Octet counting over udp",
+			cfg: &Config{
+				Port:     514,
+				Endpoint: "host.domain.com",
+				Network:  "udp",
+				Protocol: "rfc5424",
+				Framing:  "octet_counting",
+			},
+			err: "unsupported framing: only non_transparent and octet_counting supported, octet_counting over tcp",
+		},
+		{
+			name: "Invalid facility, connections and fields",
+			cfg: &Config{
+				Port:         514,
+				Endpoint:     "host.domain.com",
+				Network:      "tcp",
+				Protocol:     "rfc5424",
+				Framing:      "octet_counting",
+				Facility:     24,
+				MaxIdleConns: -1,
+				Fields: FieldsConfig{
+					Hostname: "resource",
+				},
+			},
+			err: "unsupported facility: must be in the range 0-23; " +
+				"invalid max_idle_conns: must not be negative; " +
+				"invalid fields::hostname: field 'resource' has no key",
+		},
+		{
+			name: "Valid",
+			cfg: &Config{
+				Port:     6514,
+				Endpoint: "host.domain.com",
+				Network:  "tcp",
+				Protocol: "rfc3164",
+				Framing:  "non_transparent",
+				Fields: FieldsConfig{
+					Hostname: "resource.host.name",
+					Message:  "body",
+				},
+			},
+		},
 	}
 	for _, testInstance := range tests {
 		t.Run(testInstance.name, func(t *testing.T) {
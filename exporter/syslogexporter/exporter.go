@@ -22,6 +22,7 @@ type syslogexporter struct {
 	logger    *zap.Logger
 	tlsConfig *tls.Config
 	formatter formatter
+	pool      *connPool
 }
 
 func initExporter(cfg *Config, createSettings exporter.CreateSettings) (*syslogexporter, error) {
@@ -30,18 +31,32 @@ func initExporter(cfg *Config, createSettings exporter.CreateSettings) (*sysloge
 		return nil, err
 	}
 
+	fields, err := cfg.Fields.build()
+	if err != nil {
+		return nil, err
+	}
+
 	cfg.Network = strings.ToLower(cfg.Network)
+	if cfg.Framing == "" {
+		// RFC5425 requires the octet counting framing over TLS
+		cfg.Framing = DefaultFraming
+		if tlsConfig != nil && cfg.Network == "tcp" {
+			cfg.Framing = framingOctetCountingStr
+		}
+	}
 
 	s := &syslogexporter{
 		config:    cfg,
 		logger:    createSettings.Logger,
 		tlsConfig: tlsConfig,
-		formatter: createFormatter(cfg.Protocol),
+		formatter: createFormatter(cfg.Protocol, fields, cfg.Facility),
+		pool:      newConnPool(createSettings.Logger, cfg, tlsConfig),
 	}
 
 	s.logger.Info("Syslog Exporter configured",
 		zap.String("endpoint", cfg.Endpoint),
 		zap.String("protocol", cfg.Protocol),
+		zap.String("framing", cfg.Framing),
 		zap.Int("port", cfg.Port),
 	)
 
@@ -66,9 +81,15 @@ func newLogsExporter(
 		exporterhelper.WithTimeout(cfg.TimeoutSettings),
 		exporterhelper.WithRetry(cfg.RetrySettings),
 		exporterhelper.WithQueue(cfg.QueueSettings),
+		exporterhelper.WithShutdown(s.shutdown),
 	)
 }
 
+func (se *syslogexporter) shutdown(context.Context) error {
+	se.pool.close()
+	return nil
+}
+
 func (se *syslogexporter) pushLogsData(_ context.Context, logs plog.Logs) error {
 	batchMessages := strings.ToLower(se.config.Network) == "tcp"
 	var err error
@@ -88,19 +109,19 @@ func (se *syslogexporter) exportBatch(logs plog.Logs) error {
 			scopeLogs := resourceLogs.ScopeLogs().At(j)
 			for k := 0; k < scopeLogs.LogRecords().Len(); k++ {
 				logRecord := scopeLogs.LogRecords().At(k)
-				formatted := se.formatter.format(logRecord)
-				payload.WriteString(formatted)
+				formatted := se.formatter.format(logRecord, resourceLogs.Resource())
+				payload.WriteString(frame(formatted, se.config.Framing))
 			}
 		}
 	}
 
 	if payload.Len() > 0 {
-		sender, err := connect(se.logger, se.config, se.tlsConfig)
+		sender, err := se.pool.get()
 		if err != nil {
 			return consumererror.NewLogs(err, logs)
 		}
-		defer sender.close()
 		err = sender.Write(payload.String())
+		se.pool.put(sender, err)
 		if err != nil {
 			return consumererror.NewLogs(err, logs)
 		}
@@ -109,11 +130,10 @@ func (se *syslogexporter) exportBatch(logs plog.Logs) error {
 }
 
 func (se *syslogexporter) exportNonBatch(logs plog.Logs) error {
-	sender, err := connect(se.logger, se.config, se.tlsConfig)
+	sender, err := se.pool.get()
 	if err != nil {
 		return consumererror.NewLogs(err, logs)
 	}
-	defer sender.close()
 
 	errs := []error{}
 	droppedLogs := plog.NewLogs()
@@ -125,8 +145,8 @@ func (se *syslogexporter) exportNonBatch(logs plog.Logs) error {
 			droppedScopeLogs := droppedResourceLogs.ScopeLogs().AppendEmpty()
 			for k := 0; k < scopeLogs.LogRecords().Len(); k++ {
 				logRecord := scopeLogs.LogRecords().At(k)
-				formatted := se.formatter.format(logRecord)
-				err = sender.Write(formatted)
+				formatted := se.formatter.format(logRecord, resourceLogs.Resource())
+				err = sender.Write(frame(formatted, se.config.Framing))
 				if err != nil {
 					errs = append(errs, err)
 					droppedLogRecord := droppedScopeLogs.LogRecords().AppendEmpty()
@@ -136,6 +156,8 @@ func (se *syslogexporter) exportNonBatch(logs plog.Logs) error {
 		}
 	}
 
+	se.pool.put(sender, multierr.Combine(errs...))
+
 	if len(errs) > 0 {
 		errs = deduplicateErrors(errs)
 		return consumererror.NewLogs(multierr.Combine(errs...), droppedLogs)
@@ -15,6 +15,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/config/configtls"
 	"go.opentelemetry.io/collector/consumer/consumererror"
 	"go.opentelemetry.io/collector/exporter"
 	"go.opentelemetry.io/collector/pdata/pcommon"
@@ -68,11 +69,13 @@ func createExporterCreateSettings() exporter.CreateSettings {
 }
 
 func TestInitExporter(t *testing.T) {
-	_, err := initExporter(&Config{Endpoint: "test.com",
-		Network:  "tcp",
-		Port:     514,
-		Protocol: "rfc5424"}, createExporterCreateSettings())
+	exp, err := initExporter(&Config{Endpoint: "test.com",
+		Network:    "tcp",
+		Port:       514,
+		Protocol:   "rfc5424",
+		TLSSetting: configtls.TLSClientSetting{Insecure: true}}, createExporterCreateSettings())
 	assert.NoError(t, err)
+	assert.Equal(t, DefaultFraming, exp.config.Framing)
 }
 
 func buildValidExporter(t *testing.T, server net.TCPListener, cfg *Config) (*syslogexporter, error) {
@@ -147,6 +150,8 @@ func TestSyslogExportSuccess(t *testing.T) {
 		logs := logRecordsToLogs(buffer)
 		err := test.exp.pushLogsData(context.Background(), logs)
 		require.NoError(t, err, "could not send message")
+		// The idle connection is closed with the exporter
+		require.NoError(t, test.exp.shutdown(context.Background()))
 	}()
 	err := test.srv.SetDeadline(time.Now().Add(time.Second * 1))
 	require.NoError(t, err, "cannot set deadline")
@@ -31,6 +31,10 @@ func createDefaultConfig() component.Config {
 		Port:            DefaultPort,
 		Network:         DefaultNetwork,
 		Protocol:        DefaultProtocol,
+		Fields:          defaultFieldsConfig(),
+		Facility:        DefaultFacility,
+		MaxIdleConns:    DefaultMaxIdleConns,
+		IdleConnTimeout: DefaultIdleConnTimeout,
 		RetrySettings:   exporterhelper.NewDefaultRetrySettings(),
 		QueueSettings:   qs,
 		TimeoutSettings: exporterhelper.NewDefaultTimeoutSettings(),
@@ -26,6 +26,18 @@ func TestCreateDefaultConfig(t *testing.T) {
 		Port:     514,
 		Network:  "tcp",
 		Protocol: "rfc5424",
+		Fields: FieldsConfig{
+			Priority:       "attributes.priority",
+			Hostname:       "attributes.hostname",
+			Appname:        "attributes.appname",
+			ProcID:         "attributes.proc_id",
+			MsgID:          "attributes.msg_id",
+			StructuredData: "attributes.structured_data",
+			Message:        "attributes.message",
+		},
+		Facility:        20,
+		MaxIdleConns:    10,
+		IdleConnTimeout: 90 * time.Second,
 		QueueSettings: exporterhelper.QueueSettings{
 			Enabled:      false,
 			NumConsumers: 10,
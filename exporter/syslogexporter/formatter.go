@@ -4,26 +4,148 @@
 package syslogexporter // import "github.com/open-telemetry/opentelemetry-collector-contrib/exporter/syslogexporter"
 
 import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"go.opentelemetry.io/collector/pdata/pcommon"
 	"go.opentelemetry.io/collector/pdata/plog"
 )
 
-func createFormatter(protocol string) formatter {
+func createFormatter(protocol string, fields *fields, facility int) formatter {
 	if protocol == protocolRFC5424Str {
-		return newRFC5424Formatter()
+		return newRFC5424Formatter(fields, facility)
 	}
-	return newRFC3164Formatter()
+	return newRFC3164Formatter(fields, facility)
 }
 
+// formatter formats a log record as a syslog message, without framing.
 type formatter interface {
-	format(plog.LogRecord) string
+	format(logRecord plog.LogRecord, resource pcommon.Resource) string
+}
+
+// fieldRef references a field of a log record, or of its resource.
+type fieldRef struct {
+	source string
+	key    string
+}
+
+const (
+	sourceBody       = "body"
+	sourceAttributes = "attributes"
+	sourceResource   = "resource"
+)
+
+// parseFieldRef parses a field of a log record: `body`, `body.<key>`,
+// `attributes.<key>` or `resource.<key>`. The key is not split on dots, as
+// the names of the attributes usually contain some.
+func parseFieldRef(field string) (fieldRef, error) {
+	if field == "" || field == sourceBody {
+		return fieldRef{source: field}, nil
+	}
+	source, key, _ := strings.Cut(field, ".")
+	switch {
+	case source != sourceBody && source != sourceAttributes && source != sourceResource:
+		return fieldRef{}, fmt.Errorf("field '%s' must start with body, attributes or resource", field)
+	case key == "":
+		return fieldRef{}, errors.New("field '" + field + "' has no key")
+	}
+	return fieldRef{source: source, key: key}, nil
+}
+
+// get returns the value of the field, and false when it is not found.
+func (r fieldRef) get(logRecord plog.LogRecord, resource pcommon.Resource) (pcommon.Value, bool) {
+	switch r.source {
+	case sourceBody:
+		if r.key == "" {
+			return logRecord.Body(), logRecord.Body().Type() != pcommon.ValueTypeEmpty
+		}
+		if logRecord.Body().Type() != pcommon.ValueTypeMap {
+			return pcommon.Value{}, false
+		}
+		return logRecord.Body().Map().Get(r.key)
+	case sourceAttributes:
+		return logRecord.Attributes().Get(r.key)
+	case sourceResource:
+		return resource.Attributes().Get(r.key)
+	default:
+		return pcommon.Value{}, false
+	}
+}
+
+// getString returns the value of the field as a string.
+// If the field was not found, it returns the provided default value.
+func (r fieldRef) getString(logRecord plog.LogRecord, resource pcommon.Resource, defaultValue string) string {
+	if value, found := r.get(logRecord, resource); found {
+		return value.AsString()
+	}
+	return defaultValue
+}
+
+// fields are the fields of the log records the fields of the syslog messages are read from.
+type fields struct {
+	priority       fieldRef
+	hostname       fieldRef
+	appname        fieldRef
+	procID         fieldRef
+	msgID          fieldRef
+	structuredData fieldRef
+	message        fieldRef
+}
+
+// formatPriority returns the priority of the log record. It is derived from
+// its severity number and the facility when the priority field is not found.
+func (f *fields) formatPriority(logRecord plog.LogRecord, resource pcommon.Resource, facility int) string {
+	if value, found := f.priority.get(logRecord, resource); found {
+		if p, err := strconv.Atoi(value.AsString()); err == nil && p >= 0 && p <= 191 {
+			return strconv.Itoa(p)
+		}
+	}
+	return strconv.Itoa(facility*8 + severity(logRecord.SeverityNumber()))
+}
+
+// formatMessage returns the message of the log record, which is its body
+// when the message field is not found.
+func (f *fields) formatMessage(logRecord plog.LogRecord, resource pcommon.Resource) string {
+	if value, found := f.message.get(logRecord, resource); found {
+		return value.AsString()
+	}
+	return logRecord.Body().AsString()
 }
 
-// getAttributeValueOrDefault returns the value of the requested log record's attribute as a string.
-// If the attribute was not found, it returns the provided default value.
-func getAttributeValueOrDefault(logRecord plog.LogRecord, attributeName string, defaultValue string) string {
-	value := defaultValue
-	if attributeValue, found := logRecord.Attributes().Get(attributeName); found {
-		value = attributeValue.AsString()
+// severity returns the syslog severity of a severity number.
+func severity(severityNumber plog.SeverityNumber) int {
+	switch {
+	case severityNumber == plog.SeverityNumberUnspecified:
+		return severityNotice
+	case severityNumber < plog.SeverityNumberInfo:
+		return severityDebug
+	case severityNumber < plog.SeverityNumberWarn:
+		return severityInformational
+	case severityNumber < plog.SeverityNumberError:
+		return severityWarning
+	case severityNumber < plog.SeverityNumberFatal:
+		return severityError
+	default:
+		return severityCritical
+	}
+}
+
+// formatHeaderField returns a field of the header of a message, which has no
+// spaces and only printable ASCII characters, and is at most maxLength long.
+func formatHeaderField(value string, maxLength int, invalid string) string {
+	if value == "" {
+		return emptyValue
+	}
+	formatted := []byte(value)
+	if len(formatted) > maxLength {
+		formatted = formatted[:maxLength]
+	}
+	for i, c := range formatted {
+		if c < '!' || c > '~' || strings.IndexByte(invalid, c) >= 0 {
+			formatted[i] = '_'
+		}
 	}
-	return value
+	return string(formatted)
 }
@@ -0,0 +1,233 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package syslogexporter
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/plog"
+	"go.uber.org/zap"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/entry"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/operator/parser/syslog"
+)
+
+func newTestFormatter(t *testing.T, protocol string) formatter {
+	return newTestFormatterWithFields(t, protocol, defaultFieldsConfig())
+}
+
+func newTestFormatterWithFields(t *testing.T, protocol string, cfg FieldsConfig) formatter {
+	fields, err := cfg.build()
+	require.NoError(t, err)
+	return createFormatter(protocol, fields, DefaultFacility)
+}
+
+func TestParseFieldRef(t *testing.T) {
+	for field, expected := range map[string]fieldRef{
+		"":                      {},
+		"body":                  {source: "body"},
+		"body.msg":              {source: "body", key: "msg"},
+		"attributes.log.source": {source: "attributes", key: "log.source"},
+		"resource.host.name":    {source: "resource", key: "host.name"},
+	} {
+		ref, err := parseFieldRef(field)
+		require.NoError(t, err, field)
+		assert.Equal(t, expected, ref, field)
+	}
+
+	_, err := parseFieldRef("attribute.host")
+	assert.EqualError(t, err, "field 'attribute.host' must start with body, attributes or resource")
+	_, err = parseFieldRef("resource.")
+	assert.EqualError(t, err, "field 'resource.' has no key")
+
+	cfg := defaultFieldsConfig()
+	cfg.Hostname = "host.name"
+	_, err = cfg.build()
+	assert.EqualError(t, err, "invalid fields::hostname: field 'host.name' must start with body, attributes or resource")
+}
+
+func TestFormatterFields(t *testing.T) {
+	timestamp := time.Date(2023, 10, 11, 22, 14, 15, 3000000, time.UTC)
+	logRecord := plog.NewLogRecord()
+	logRecord.SetTimestamp(pcommon.NewTimestampFromTime(timestamp))
+	logRecord.SetSeverityNumber(plog.SeverityNumberError)
+	logRecord.Body().SetEmptyMap().PutStr("msg", "connection refused")
+	logRecord.Attributes().PutStr("event.name", "connect")
+	sd := logRecord.Attributes().PutEmptyMap("sd")
+	sd.PutEmptyMap("origin@32473").PutStr("ip", "192.0.2.1")
+	params := sd.PutEmptyMap("meta")
+	params.PutStr("z", `quote " backslash \ bracket ]`)
+	params.PutInt("a", 42)
+	resource := pcommon.NewResource()
+	resource.Attributes().PutStr("host.name", "web 01")
+	resource.Attributes().PutStr("service.name", "checkout")
+	resource.Attributes().PutInt("process.pid", 1234)
+
+	cfg := FieldsConfig{
+		Hostname:       "resource.host.name",
+		Appname:        "resource.service.name",
+		ProcID:         "resource.process.pid",
+		MsgID:          "attributes.event.name",
+		StructuredData: "attributes.sd",
+		Message:        "body.msg",
+	}
+
+	actual := newTestFormatterWithFields(t, protocolRFC5424Str, cfg).format(logRecord, resource)
+	assert.Equal(t, `<163>1 2023-10-11T22:14:15.003Z web_01 checkout 1234 connect `+
+		`[meta a="42" z="quote \" backslash \\ bracket \]"][origin@32473 ip="192.0.2.1"] connection refused`, actual)
+
+	actual = newTestFormatterWithFields(t, protocolRFC3164Str, cfg).format(logRecord, resource)
+	assert.Equal(t, "<163>Oct 11 22:14:15 web_01 checkout[1234]: connection refused", actual)
+
+	// The body is the message when the message field is not found
+	logRecord.Body().SetStr("plain body")
+	actual = newTestFormatterWithFields(t, protocolRFC3164Str, cfg).format(logRecord, resource)
+	assert.Equal(t, "<163>Oct 11 22:14:15 web_01 checkout[1234]: plain body", actual)
+}
+
+func TestFormatterPriority(t *testing.T) {
+	f := &fields{priority: fieldRef{source: sourceAttributes, key: priority}}
+	resource := pcommon.NewResource()
+
+	for severityNumber, expected := range map[plog.SeverityNumber]string{
+		plog.SeverityNumberUnspecified: "165",
+		plog.SeverityNumberTrace:       "167",
+		plog.SeverityNumberDebug4:      "167",
+		plog.SeverityNumberInfo:        "166",
+		plog.SeverityNumberWarn2:       "164",
+		plog.SeverityNumberError:       "163",
+		plog.SeverityNumberFatal4:      "162",
+	} {
+		logRecord := plog.NewLogRecord()
+		logRecord.SetSeverityNumber(severityNumber)
+		assert.Equal(t, expected, f.formatPriority(logRecord, resource, DefaultFacility), severityNumber.String())
+	}
+
+	logRecord := plog.NewLogRecord()
+	logRecord.SetSeverityNumber(plog.SeverityNumberError)
+	assert.Equal(t, "11", f.formatPriority(logRecord, resource, 1))
+	logRecord.Attributes().PutStr(priority, "34")
+	assert.Equal(t, "34", f.formatPriority(logRecord, resource, 1))
+	// An invalid priority is derived from the severity too
+	logRecord.Attributes().PutInt(priority, 192)
+	assert.Equal(t, "11", f.formatPriority(logRecord, resource, 1))
+}
+
+func TestFormatHeaderField(t *testing.T) {
+	assert.Equal(t, "-", formatHeaderField("", 10, ""))
+	assert.Equal(t, "my_app_", formatHeaderField("my app\n", 10, ""))
+	assert.Equal(t, "abcde", formatHeaderField("abcdefgh", 5, ""))
+	assert.Equal(t, "a_b_c", formatHeaderField("a:b[c", 5, ":["))
+	assert.Equal(t, "h__llo", formatHeaderField("héllo", 10, ""))
+}
+
+func TestFrame(t *testing.T) {
+	assert.Equal(t, "<34>1 - - - - - - msg\n", frame("<34>1 - - - - - - msg", framingNonTransparentStr))
+	assert.Equal(t, "<34>1 - - - - - - msg\n", frame("<34>1 - - - - - - msg", ""))
+	assert.Equal(t, "21 <34>1 - - - - - - msg", frame("<34>1 - - - - - - msg", framingOctetCountingStr))
+}
+
+// parseSyslog parses a message with the syslog parser of the syslog receiver.
+func parseSyslog(t *testing.T, cfg *syslog.Config, msg string) *entry.Entry {
+	op, err := cfg.Build(zap.NewNop().Sugar())
+	require.NoError(t, err)
+
+	e := entry.New()
+	e.Body = msg
+	require.NoError(t, op.Process(context.Background(), e))
+	return e
+}
+
+func TestRoundTrip(t *testing.T) {
+	timestamp := time.Date(time.Now().Year(), 8, 5, 21, 58, 59, 693012000, time.UTC)
+	logRecord := plog.NewLogRecord()
+	logRecord.SetTimestamp(pcommon.NewTimestampFromTime(timestamp))
+	logRecord.Attributes().PutStr("appname", "SecureAuth0")
+	logRecord.Attributes().PutStr("hostname", "192.168.2.132")
+	logRecord.Attributes().PutStr("message", `Found the user for "retrieving" user's profile`)
+	logRecord.Attributes().PutStr("msg_id", "ID52020")
+	logRecord.Attributes().PutInt("priority", 86)
+	logRecord.Attributes().PutStr("proc_id", "23108")
+	sd := logRecord.Attributes().PutEmptyMap("structured_data").PutEmptyMap("SecureAuth@27389")
+	sd.PutStr("UserHostAddress", "192.168.2.132")
+	sd.PutStr("Realm", `Secure "Auth" [0]`)
+	resource := pcommon.NewResource()
+
+	expected := map[string]any{
+		"appname":  "SecureAuth0",
+		"hostname": "192.168.2.132",
+		"message":  `Found the user for "retrieving" user's profile`,
+		"msg_id":   "ID52020",
+		"priority": 86,
+		"facility": 10,
+		"proc_id":  "23108",
+		"structured_data": map[string]any{
+			"SecureAuth@27389": map[string]any{
+				"UserHostAddress": "192.168.2.132",
+				"Realm":           `Secure "Auth" [0]`,
+			},
+		},
+		"version": 1,
+	}
+
+	t.Run("rfc5424", func(t *testing.T) {
+		cfg := syslog.NewConfig()
+		cfg.Protocol = syslog.RFC5424
+		msg := newTestFormatter(t, protocolRFC5424Str).format(logRecord, resource)
+
+		e := parseSyslog(t, cfg, msg)
+		assert.Equal(t, expected, e.Attributes)
+		assert.Equal(t, entry.Info, e.Severity)
+		assert.Equal(t, timestamp, e.Timestamp)
+	})
+
+	t.Run("rfc5424_octet_counting", func(t *testing.T) {
+		cfg := syslog.NewConfig()
+		cfg.Protocol = syslog.RFC5424
+		cfg.EnableOctetCounting = true
+		msg := newTestFormatter(t, protocolRFC5424Str).format(logRecord, resource)
+
+		e := parseSyslog(t, cfg, frame(msg, framingOctetCountingStr))
+		assert.Equal(t, expected, e.Attributes)
+		assert.Equal(t, timestamp, e.Timestamp)
+	})
+
+	t.Run("rfc3164", func(t *testing.T) {
+		cfg := syslog.NewConfig()
+		cfg.Protocol = syslog.RFC3164
+		msg := newTestFormatter(t, protocolRFC3164Str).format(logRecord, resource)
+
+		e := parseSyslog(t, cfg, msg)
+		assert.Equal(t, map[string]any{
+			"appname":  "SecureAuth0",
+			"hostname": "192.168.2.132",
+			"message":  `Found the user for "retrieving" user's profile`,
+			"priority": 86,
+			"facility": 10,
+			"proc_id":  "23108",
+		}, e.Attributes)
+		// RFC3164 timestamps have no fractional seconds
+		assert.Equal(t, timestamp.Truncate(time.Second), e.Timestamp)
+	})
+
+	t.Run("rfc3164_single_digit_day", func(t *testing.T) {
+		single := plog.NewLogRecord()
+		logRecord.CopyTo(single)
+		singleTimestamp := time.Date(time.Now().Year(), 8, 5, 1, 2, 3, 0, time.UTC)
+		single.SetTimestamp(pcommon.NewTimestampFromTime(singleTimestamp))
+
+		cfg := syslog.NewConfig()
+		cfg.Protocol = syslog.RFC3164
+		msg := newTestFormatter(t, protocolRFC3164Str).format(single, resource)
+		assert.Equal(t, `<86>Aug  5 01:02:03 192.168.2.132 SecureAuth0[23108]: Found the user for "retrieving" user's profile`, msg)
+
+		e := parseSyslog(t, cfg, msg)
+		assert.Equal(t, singleTimestamp, e.Timestamp)
+	})
+}
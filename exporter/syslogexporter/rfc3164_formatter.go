@@ -5,52 +5,65 @@ package syslogexporter // import "github.com/open-telemetry/opentelemetry-collec
 
 import (
 	"fmt"
-	"strconv"
+	"time"
 
+	"go.opentelemetry.io/collector/pdata/pcommon"
 	"go.opentelemetry.io/collector/pdata/plog"
 )
 
+// maxTagLength is the maximum length of the tag in RFC3164
+const maxTagLength = 32
+
 type rfc3164Formatter struct {
+	fields   *fields
+	facility int
 }
 
-func newRFC3164Formatter() *rfc3164Formatter {
-	return &rfc3164Formatter{}
+func newRFC3164Formatter(fields *fields, facility int) *rfc3164Formatter {
+	return &rfc3164Formatter{
+		fields:   fields,
+		facility: facility,
+	}
 }
 
-func (f *rfc3164Formatter) format(logRecord plog.LogRecord) string {
-	priorityString := f.formatPriority(logRecord)
+func (f *rfc3164Formatter) format(logRecord plog.LogRecord, resource pcommon.Resource) string {
+	priorityString := f.fields.formatPriority(logRecord, resource, f.facility)
 	timestampString := f.formatTimestamp(logRecord)
-	hostnameString := f.formatHostname(logRecord)
-	appnameString := f.formatAppname(logRecord)
-	messageString := f.formatMessage(logRecord)
+	hostnameString := f.formatHostname(logRecord, resource)
+	appnameString := f.formatAppname(logRecord, resource)
+	messageString := f.fields.formatMessage(logRecord, resource)
 	appnameMessageDelimiter := ""
 	if len(appnameString) > 0 && messageString != emptyMessage {
 		appnameMessageDelimiter = " "
 	}
-	formatted := fmt.Sprintf("<%s>%s %s %s%s%s\n", priorityString, timestampString, hostnameString, appnameString, appnameMessageDelimiter, messageString)
+	formatted := fmt.Sprintf("<%s>%s %s %s%s%s", priorityString, timestampString, hostnameString, appnameString, appnameMessageDelimiter, messageString)
 	return formatted
 }
 
-func (f *rfc3164Formatter) formatPriority(logRecord plog.LogRecord) string {
-	return getAttributeValueOrDefault(logRecord, priority, strconv.Itoa(defaultPriority))
-}
-
+// formatTimestamp formats the timestamp with a day padded with a space, as
+// defined by RFC3164.
 func (f *rfc3164Formatter) formatTimestamp(logRecord plog.LogRecord) string {
-	return logRecord.Timestamp().AsTime().Format("Jan 02 15:04:05")
+	timestamp := logRecord.Timestamp()
+	if timestamp == 0 {
+		timestamp = logRecord.ObservedTimestamp()
+	}
+	return timestamp.AsTime().Format(time.Stamp)
 }
 
-func (f *rfc3164Formatter) formatHostname(logRecord plog.LogRecord) string {
-	return getAttributeValueOrDefault(logRecord, hostname, emptyValue)
+func (f *rfc3164Formatter) formatHostname(logRecord plog.LogRecord, resource pcommon.Resource) string {
+	return formatHeaderField(f.fields.hostname.getString(logRecord, resource, emptyValue), maxHostnameLength, "")
 }
 
-func (f *rfc3164Formatter) formatAppname(logRecord plog.LogRecord) string {
-	value := getAttributeValueOrDefault(logRecord, app, "")
-	if value != "" {
-		value += ":"
+// formatAppname formats the tag of the message, followed by the process id
+// when it is set.
+func (f *rfc3164Formatter) formatAppname(logRecord plog.LogRecord, resource pcommon.Resource) string {
+	value := f.fields.appname.getString(logRecord, resource, "")
+	if value == "" {
+		return ""
 	}
-	return value
-}
-
-func (f *rfc3164Formatter) formatMessage(logRecord plog.LogRecord) string {
-	return getAttributeValueOrDefault(logRecord, message, emptyMessage)
+	value = formatHeaderField(value, maxTagLength, ":[]")
+	if procID := f.fields.procID.getString(logRecord, resource, ""); procID != "" {
+		value += "[" + formatHeaderField(procID, maxProcIDLength, ":[]") + "]"
+	}
+	return value + ":"
 }
@@ -14,7 +14,7 @@ import (
 )
 
 func TestRFC3164Formatter(t *testing.T) {
-	expected := "<34>Aug 24 05:14:15 mymachine su: 'su root' failed for lonvick on /dev/pts/8\n"
+	expected := "<34>Aug 24 05:14:15 mymachine su: 'su root' failed for lonvick on /dev/pts/8"
 	logRecord := plog.NewLogRecord()
 	logRecord.Attributes().PutStr("appname", "su")
 	logRecord.Attributes().PutStr("hostname", "mymachine")
@@ -24,18 +24,18 @@ func TestRFC3164Formatter(t *testing.T) {
 	require.NoError(t, err)
 	logRecord.SetTimestamp(pcommon.NewTimestampFromTime(timestamp))
 
-	actual := newRFC3164Formatter().format(logRecord)
+	actual := newTestFormatter(t, protocolRFC3164Str).format(logRecord, pcommon.NewResource())
 	assert.NoError(t, err)
 	assert.Equal(t, expected, actual)
 
-	expected = "<165>Aug 24 05:14:15 - -\n"
+	expected = "<165>Aug 24 05:14:15 - -"
 	logRecord = plog.NewLogRecord()
 	logRecord.Attributes().PutStr("message", "-")
 	timestamp, err = time.Parse(time.RFC3339Nano, "2003-08-24T05:14:15.000003Z")
 	require.NoError(t, err)
 	logRecord.SetTimestamp(pcommon.NewTimestampFromTime(timestamp))
 
-	actual = newRFC3164Formatter().format(logRecord)
+	actual = newTestFormatter(t, protocolRFC3164Str).format(logRecord, pcommon.NewResource())
 	assert.NoError(t, err)
 	assert.Equal(t, expected, actual)
 }
@@ -5,92 +5,119 @@ package syslogexporter // import "github.com/open-telemetry/opentelemetry-collec
 
 import (
 	"fmt"
+	"sort"
 	"strconv"
+	"strings"
 	"time"
 
 	"go.opentelemetry.io/collector/pdata/pcommon"
 	"go.opentelemetry.io/collector/pdata/plog"
 )
 
+// The maximum lengths of the fields of the header, and of the names of
+// the structured data, in RFC5424
+const (
+	maxHostnameLength = 255
+	maxAppnameLength  = 48
+	maxProcIDLength   = 128
+	maxMsgIDLength    = 32
+	maxSDNameLength   = 32
+)
+
 type rfc5424Formatter struct {
+	fields   *fields
+	facility int
 }
 
-func newRFC5424Formatter() *rfc5424Formatter {
-	return &rfc5424Formatter{}
+func newRFC5424Formatter(fields *fields, facility int) *rfc5424Formatter {
+	return &rfc5424Formatter{
+		fields:   fields,
+		facility: facility,
+	}
 }
 
-func (f *rfc5424Formatter) format(logRecord plog.LogRecord) string {
-	priorityString := f.formatPriority(logRecord)
+func (f *rfc5424Formatter) format(logRecord plog.LogRecord, resource pcommon.Resource) string {
+	priorityString := f.fields.formatPriority(logRecord, resource, f.facility)
 	versionString := f.formatVersion(logRecord)
 	timestampString := f.formatTimestamp(logRecord)
-	hostnameString := f.formatHostname(logRecord)
-	appnameString := f.formatAppname(logRecord)
-	pidString := f.formatPid(logRecord)
-	messageIDString := f.formatMessageID(logRecord)
-	structuredData := f.formatStructuredData(logRecord)
-	messageString := f.formatMessage(logRecord)
-	formatted := fmt.Sprintf("<%s>%s %s %s %s %s %s %s%s\n", priorityString, versionString, timestampString, hostnameString, appnameString, pidString, messageIDString, structuredData, messageString)
+	hostnameString := f.formatHostname(logRecord, resource)
+	appnameString := f.formatAppname(logRecord, resource)
+	pidString := f.formatPid(logRecord, resource)
+	messageIDString := f.formatMessageID(logRecord, resource)
+	structuredData := f.formatStructuredData(logRecord, resource)
+	messageString := f.formatMessage(logRecord, resource)
+	formatted := fmt.Sprintf("<%s>%s %s %s %s %s %s %s%s", priorityString, versionString, timestampString, hostnameString, appnameString, pidString, messageIDString, structuredData, messageString)
 	return formatted
 }
 
-func (f *rfc5424Formatter) formatPriority(logRecord plog.LogRecord) string {
-	return getAttributeValueOrDefault(logRecord, priority, strconv.Itoa(defaultPriority))
-}
-
 func (f *rfc5424Formatter) formatVersion(logRecord plog.LogRecord) string {
-	return getAttributeValueOrDefault(logRecord, version, strconv.Itoa(versionRFC5424))
+	if attributeValue, found := logRecord.Attributes().Get(version); found {
+		return attributeValue.AsString()
+	}
+	return strconv.Itoa(versionRFC5424)
 }
 
 func (f *rfc5424Formatter) formatTimestamp(logRecord plog.LogRecord) string {
-	return logRecord.Timestamp().AsTime().Format(time.RFC3339Nano)
+	timestamp := logRecord.Timestamp()
+	if timestamp == 0 {
+		timestamp = logRecord.ObservedTimestamp()
+	}
+	return timestamp.AsTime().Format(time.RFC3339Nano)
 }
 
-func (f *rfc5424Formatter) formatHostname(logRecord plog.LogRecord) string {
-	return getAttributeValueOrDefault(logRecord, hostname, emptyValue)
+func (f *rfc5424Formatter) formatHostname(logRecord plog.LogRecord, resource pcommon.Resource) string {
+	return formatHeaderField(f.fields.hostname.getString(logRecord, resource, emptyValue), maxHostnameLength, "")
 }
 
-func (f *rfc5424Formatter) formatAppname(logRecord plog.LogRecord) string {
-	return getAttributeValueOrDefault(logRecord, app, emptyValue)
+func (f *rfc5424Formatter) formatAppname(logRecord plog.LogRecord, resource pcommon.Resource) string {
+	return formatHeaderField(f.fields.appname.getString(logRecord, resource, emptyValue), maxAppnameLength, "")
 }
 
-func (f *rfc5424Formatter) formatPid(logRecord plog.LogRecord) string {
-	return getAttributeValueOrDefault(logRecord, pid, emptyValue)
+func (f *rfc5424Formatter) formatPid(logRecord plog.LogRecord, resource pcommon.Resource) string {
+	return formatHeaderField(f.fields.procID.getString(logRecord, resource, emptyValue), maxProcIDLength, "")
 }
 
-func (f *rfc5424Formatter) formatMessageID(logRecord plog.LogRecord) string {
-	return getAttributeValueOrDefault(logRecord, msgID, emptyValue)
+func (f *rfc5424Formatter) formatMessageID(logRecord plog.LogRecord, resource pcommon.Resource) string {
+	return formatHeaderField(f.fields.msgID.getString(logRecord, resource, emptyValue), maxMsgIDLength, "")
 }
 
-func (f *rfc5424Formatter) formatStructuredData(logRecord plog.LogRecord) string {
-	structuredDataAttributeValue, found := logRecord.Attributes().Get(structuredData)
-	if !found {
-		return emptyValue
-	}
-	if structuredDataAttributeValue.Type() != pcommon.ValueTypeMap {
+// formatStructuredData formats a map of SD-IDs to maps of parameters. The
+// elements and their parameters are sorted, so that the output is stable.
+func (f *rfc5424Formatter) formatStructuredData(logRecord plog.LogRecord, resource pcommon.Resource) string {
+	structuredDataValue, found := f.fields.structuredData.get(logRecord, resource)
+	if !found || structuredDataValue.Type() != pcommon.ValueTypeMap || structuredDataValue.Map().Len() == 0 {
 		return emptyValue
 	}
 
-	sdElements := []string{}
-	for key, val := range structuredDataAttributeValue.Map().AsRaw() {
-		sdElements = append(sdElements, key)
-		vval, ok := val.(map[string]any)
-		if !ok {
-			continue
-		}
-		for k, v := range vval {
-			vv, ok := v.(string)
-			if !ok {
-				continue
+	var sdElements []string
+	structuredDataValue.Map().Range(func(id string, params pcommon.Value) bool {
+		element := "[" + formatHeaderField(id, maxSDNameLength, `=]"`)
+		if params.Type() == pcommon.ValueTypeMap {
+			var sdParams []string
+			params.Map().Range(func(name string, value pcommon.Value) bool {
+				sdParams = append(sdParams, fmt.Sprintf(`%s="%s"`, formatHeaderField(name, maxSDNameLength, `=]"`), escapeParamValue(value.AsString())))
+				return true
+			})
+			sort.Strings(sdParams)
+			for _, param := range sdParams {
+				element += " " + param
 			}
-			sdElements = append(sdElements, fmt.Sprintf("%s=\"%s\"", k, vv))
 		}
-	}
-	return fmt.Sprint(sdElements)
+		sdElements = append(sdElements, element+"]")
+		return true
+	})
+	sort.Strings(sdElements)
+	return strings.Join(sdElements, "")
+}
+
+var paramValueEscaper = strings.NewReplacer(`"`, `\"`, `\`, `\\`, `]`, `\]`)
 
+func escapeParamValue(value string) string {
+	return paramValueEscaper.Replace(value)
 }
 
-func (f *rfc5424Formatter) formatMessage(logRecord plog.LogRecord) string {
-	formatted := getAttributeValueOrDefault(logRecord, message, emptyMessage)
+func (f *rfc5424Formatter) formatMessage(logRecord plog.LogRecord, resource pcommon.Resource) string {
+	formatted := f.fields.formatMessage(logRecord, resource)
 	if len(formatted) > 0 {
 		formatted = " " + formatted
 	}
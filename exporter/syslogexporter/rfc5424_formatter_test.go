@@ -17,7 +17,7 @@ import (
 )
 
 func TestRFC5424Formatter(t *testing.T) {
-	expected := "<165>1 2003-08-24T05:14:15.000003Z 192.0.2.1 myproc 8710 - - It's time to make the do-nuts.\n"
+	expected := "<165>1 2003-08-24T05:14:15.000003Z 192.0.2.1 myproc 8710 - - It's time to make the do-nuts."
 	logRecord := plog.NewLogRecord()
 	logRecord.Attributes().PutStr("appname", "myproc")
 	logRecord.Attributes().PutStr("hostname", "192.0.2.1")
@@ -29,10 +29,10 @@ func TestRFC5424Formatter(t *testing.T) {
 	require.NoError(t, err)
 	logRecord.SetTimestamp(pcommon.NewTimestampFromTime(timestamp))
 
-	actual := newRFC5424Formatter().format(logRecord)
+	actual := newTestFormatter(t, protocolRFC5424Str).format(logRecord, pcommon.NewResource())
 	assert.Equal(t, expected, actual)
 
-	expected = "<165>1 2003-10-11T22:14:15.003Z mymachine.example.com evntslog 111 ID47 - BOMAn application event log entry...\n"
+	expected = "<165>1 2003-10-11T22:14:15.003Z mymachine.example.com evntslog 111 ID47 - BOMAn application event log entry..."
 	logRecord = plog.NewLogRecord()
 	logRecord.Attributes().PutStr("appname", "evntslog")
 	logRecord.Attributes().PutStr("hostname", "mymachine.example.com")
@@ -45,12 +45,12 @@ func TestRFC5424Formatter(t *testing.T) {
 	require.NoError(t, err)
 	logRecord.SetTimestamp(pcommon.NewTimestampFromTime(timestamp))
 
-	actual = newRFC5424Formatter().format(logRecord)
+	actual = newTestFormatter(t, protocolRFC5424Str).format(logRecord, pcommon.NewResource())
 	assert.Equal(t, expected, actual)
 
 	// Test structured data
 	expectedRegex := "\\<165\\>1 2003-08-24T12:14:15.000003Z 192\\.0\\.2\\.1 myproc 8710 - " +
-		"\\[\\S+ \\S+ \\S+ \\S+ \\S+\\] It's time to make the do-nuts\\.\n"
+		"\\[\\S+ \\S+ \\S+ \\S+ \\S+\\] It's time to make the do-nuts\\."
 	logRecord = plog.NewLogRecord()
 	logRecord.Attributes().PutStr("appname", "myproc")
 	logRecord.Attributes().PutStr("hostname", "192.0.2.1")
@@ -72,7 +72,7 @@ func TestRFC5424Formatter(t *testing.T) {
 	require.NoError(t, err)
 	logRecord.SetTimestamp(pcommon.NewTimestampFromTime(timestamp))
 
-	actual = newRFC5424Formatter().format(logRecord)
+	actual = newTestFormatter(t, protocolRFC5424Str).format(logRecord, pcommon.NewResource())
 	assert.NoError(t, err)
 	matched, err := regexp.MatchString(expectedRegex, actual)
 	assert.NoError(t, err)
@@ -83,12 +83,12 @@ func TestRFC5424Formatter(t *testing.T) {
 	assert.True(t, strings.Contains(actual, "PEN=\"27389\""))
 
 	// Test defaults
-	expected = "<165>1 2003-08-24T12:14:15.000003Z - - - - -\n"
+	expected = "<165>1 2003-08-24T12:14:15.000003Z - - - - -"
 	logRecord = plog.NewLogRecord()
 	timestamp, err = time.Parse(time.RFC3339Nano, "2003-08-24T05:14:15.000003-07:00")
 	require.NoError(t, err)
 	logRecord.SetTimestamp(pcommon.NewTimestampFromTime(timestamp))
 
-	actual = newRFC5424Formatter().format(logRecord)
+	actual = newTestFormatter(t, protocolRFC5424Str).format(logRecord, pcommon.NewResource())
 	assert.Equal(t, expected, actual)
 }
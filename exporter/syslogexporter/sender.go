@@ -5,20 +5,24 @@ package syslogexporter // import "github.com/open-telemetry/opentelemetry-collec
 
 import (
 	"crypto/tls"
+	"errors"
 	"fmt"
 	"net"
-	"strings"
+	"strconv"
 	"sync"
+	"time"
 
 	"go.uber.org/zap"
 )
 
-const defaultPriority = 165
 const versionRFC5424 = 1
 
 const protocolRFC5424Str = "rfc5424"
 const protocolRFC3164Str = "rfc3164"
 
+const framingNonTransparentStr = "non_transparent"
+const framingOctetCountingStr = "octet_counting"
+
 const priority = "priority"
 const version = "version"
 const hostname = "hostname"
@@ -28,17 +32,42 @@ const msgID = "msg_id"
 const structuredData = "structured_data"
 const message = "message"
 
+// livenessCheckTimeout is how long an idle connection is read from, to find out
+// whether the server closed it
+const livenessCheckTimeout = time.Millisecond
+
 const emptyValue = "-"
 const emptyMessage = ""
 
+// Syslog severities
+const (
+	severityCritical      = 2
+	severityError         = 3
+	severityWarning       = 4
+	severityNotice        = 5
+	severityInformational = 6
+	severityDebug         = 7
+)
+
+// frame frames a message for the transport, as defined by RFC6587: the
+// octet counting framing prefixes the message with its length, and the
+// non transparent framing ends it with a newline.
+func frame(msg string, framing string) string {
+	if framing == framingOctetCountingStr {
+		return strconv.Itoa(len(msg)) + " " + msg
+	}
+	return msg + "\n"
+}
+
 type sender struct {
 	network   string
 	addr      string
-	protocol  string
 	tlsConfig *tls.Config
 	logger    *zap.Logger
 	mu        sync.Mutex
 	conn      net.Conn
+	// lastUsed is the time the sender was last returned to the pool
+	lastUsed time.Time
 }
 
 func connect(logger *zap.Logger, cfg *Config, tlsConfig *tls.Config) (*sender, error) {
@@ -46,7 +75,6 @@ func connect(logger *zap.Logger, cfg *Config, tlsConfig *tls.Config) (*sender, e
 		logger:    logger,
 		network:   cfg.Network,
 		addr:      fmt.Sprintf("%s:%d", cfg.Endpoint, cfg.Port),
-		protocol:  cfg.Protocol,
 		tlsConfig: tlsConfig,
 	}
 
@@ -86,6 +114,8 @@ func (s *sender) dial() error {
 	return err
 }
 
+// Write writes the framed messages, and reconnects once if the connection
+// was closed by the server.
 func (s *sender) Write(msgStr string) error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
@@ -101,11 +131,107 @@ func (s *sender) Write(msgStr string) error {
 
 	return s.write(msgStr)
 }
-func (s *sender) write(msg string) error {
-	// check if logs contains new line character at the end, if not add it
-	if !strings.HasSuffix(msg, "\n") {
-		msg = fmt.Sprintf("%s%s", msg, "\n")
+
+// alive reports whether the connection is still open. The syslog server doesn't
+// send data, so a read which doesn't time out means that the connection was
+// closed, e.g. by a load balancer dropping the idle connections. Writing to it
+// could succeed anyway, and the messages be lost.
+func (s *sender) alive() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.conn == nil {
+		return false
+	}
+	if err := s.conn.SetReadDeadline(time.Now().Add(livenessCheckTimeout)); err != nil {
+		return false
 	}
+	var netErr net.Error
+	if _, err := s.conn.Read(make([]byte, 1)); !errors.As(err, &netErr) || !netErr.Timeout() {
+		return false
+	}
+	return s.conn.SetReadDeadline(time.Time{}) == nil
+}
+
+func (s *sender) write(msg string) error {
 	_, err := fmt.Fprint(s.conn, msg)
 	return err
 }
+
+// connPool keeps the connections to the syslog server open between the
+// exports, so that they are not dialed, and their TLS handshake done, for
+// every batch of logs.
+type connPool struct {
+	logger      *zap.Logger
+	cfg         *Config
+	tlsConfig   *tls.Config
+	idleTimeout time.Duration
+
+	mu     sync.Mutex
+	idle   []*sender
+	closed bool
+}
+
+func newConnPool(logger *zap.Logger, cfg *Config, tlsConfig *tls.Config) *connPool {
+	return &connPool{
+		logger:      logger,
+		cfg:         cfg,
+		tlsConfig:   tlsConfig,
+		idleTimeout: cfg.IdleConnTimeout,
+	}
+}
+
+// get returns the most recently used idle connection which is still open, or a
+// new one.
+func (p *connPool) get() (*sender, error) {
+	for {
+		s := p.pop()
+		if s == nil {
+			return connect(p.logger, p.cfg, p.tlsConfig)
+		}
+		if (p.idleTimeout > 0 && time.Since(s.lastUsed) > p.idleTimeout) || !s.alive() {
+			s.close()
+			continue
+		}
+		return s, nil
+	}
+}
+
+// pop removes the most recently used idle connection from the pool.
+func (p *connPool) pop() *sender {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if len(p.idle) == 0 {
+		return nil
+	}
+	s := p.idle[len(p.idle)-1]
+	p.idle = p.idle[:len(p.idle)-1]
+	return s
+}
+
+// put returns a connection to the pool. It is closed when the pool is full,
+// or when it failed.
+func (p *connPool) put(s *sender, err error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if err != nil || p.closed || len(p.idle) >= p.cfg.MaxIdleConns {
+		s.close()
+		return
+	}
+	s.lastUsed = time.Now()
+	p.idle = append(p.idle, s)
+}
+
+// close closes the idle connections, and the connections put afterwards.
+func (p *connPool) close() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	for _, s := range p.idle {
+		s.close()
+	}
+	p.idle = nil
+	p.closed = true
+}
@@ -0,0 +1,225 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package syslogexporter
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// acceptConns accepts the connections of the listener until it is closed.
+func acceptConns(listener net.Listener) chan net.Conn {
+	conns := make(chan net.Conn, 10)
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				close(conns)
+				return
+			}
+			// The client waits for the handshake before writing
+			if tlsConn, ok := conn.(*tls.Conn); ok {
+				if err := tlsConn.Handshake(); err != nil {
+					conn.Close()
+					continue
+				}
+			}
+			conns <- conn
+		}
+	}()
+	return conns
+}
+
+func readMessage(t *testing.T, conn net.Conn, expected string) {
+	require.NoError(t, conn.SetReadDeadline(time.Now().Add(time.Second)))
+	buf := make([]byte, len(expected))
+	_, err := io.ReadFull(conn, buf)
+	require.NoError(t, err)
+	assert.Equal(t, expected, string(buf))
+}
+
+func newTestExporter(t *testing.T, listener net.Listener, cfg *Config) *syslogexporter {
+	host, port, err := net.SplitHostPort(listener.Addr().String())
+	require.NoError(t, err)
+	cfg.Endpoint = host
+	cfg.Port, err = strconv.Atoi(port)
+	require.NoError(t, err)
+	exp, err := initExporter(cfg, createExporterCreateSettings())
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		require.NoError(t, exp.shutdown(context.Background()))
+	})
+	return exp
+}
+
+func TestSyslogExportOctetCounting(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer listener.Close()
+	conns := acceptConns(listener)
+
+	cfg := createTestConfig()
+	cfg.Framing = framingOctetCountingStr
+	exp := newTestExporter(t, listener, cfg)
+
+	// The connection is reused by the following exports
+	expected := strconv.Itoa(len(expectedForm)-1) + " " + expectedForm[:len(expectedForm)-1]
+	for i := 0; i < 3; i++ {
+		require.NoError(t, exp.pushLogsData(context.Background(), logRecordsToLogs(exampleLog(t))))
+	}
+	conn := <-conns
+	defer conn.Close()
+	for i := 0; i < 3; i++ {
+		readMessage(t, conn, expected)
+	}
+	select {
+	case <-conns:
+		t.Fatal("unexpected connection")
+	default:
+	}
+}
+
+func TestSyslogExportIdleConnTimeout(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer listener.Close()
+	conns := acceptConns(listener)
+
+	cfg := createTestConfig()
+	cfg.IdleConnTimeout = time.Millisecond
+	exp := newTestExporter(t, listener, cfg)
+
+	require.NoError(t, exp.pushLogsData(context.Background(), logRecordsToLogs(exampleLog(t))))
+	first := <-conns
+	defer first.Close()
+	readMessage(t, first, expectedForm)
+
+	// The idle connection is closed, and another one dialed
+	time.Sleep(10 * time.Millisecond)
+	require.NoError(t, exp.pushLogsData(context.Background(), logRecordsToLogs(exampleLog(t))))
+	second := <-conns
+	defer second.Close()
+	readMessage(t, second, expectedForm)
+	_, err = first.Read(make([]byte, 1))
+	assert.ErrorIs(t, err, io.EOF)
+}
+
+func TestSyslogExportClosedIdleConn(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer listener.Close()
+	conns := acceptConns(listener)
+
+	exp := newTestExporter(t, listener, createTestConfig())
+
+	require.NoError(t, exp.pushLogsData(context.Background(), logRecordsToLogs(exampleLog(t))))
+	first := <-conns
+	readMessage(t, first, expectedForm)
+
+	// The idle connection closed by the server isn't reused, another one is dialed
+	require.NoError(t, first.Close())
+	time.Sleep(10 * time.Millisecond)
+	require.NoError(t, exp.pushLogsData(context.Background(), logRecordsToLogs(exampleLog(t))))
+	select {
+	case second := <-conns:
+		defer second.Close()
+		readMessage(t, second, expectedForm)
+	case <-time.After(time.Second):
+		t.Fatal("the closed connection was reused")
+	}
+}
+
+func TestSyslogExportWithoutPool(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer listener.Close()
+	conns := acceptConns(listener)
+
+	cfg := createTestConfig()
+	cfg.MaxIdleConns = 0
+	exp := newTestExporter(t, listener, cfg)
+
+	require.NoError(t, exp.pushLogsData(context.Background(), logRecordsToLogs(exampleLog(t))))
+	conn := <-conns
+	defer conn.Close()
+	b, err := io.ReadAll(conn)
+	require.NoError(t, err)
+	assert.Equal(t, expectedForm, string(b))
+}
+
+func TestSyslogExportTLS(t *testing.T) {
+	certFile, keyFile := writeTestCertificate(t)
+	cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+	require.NoError(t, err)
+	listener, err := tls.Listen("tcp", "127.0.0.1:0", &tls.Config{
+		Certificates: []tls.Certificate{cert},
+		MinVersion:   tls.VersionTLS12,
+	})
+	require.NoError(t, err)
+	defer listener.Close()
+	conns := acceptConns(listener)
+
+	// The messages are octet counted over TLS by default
+	cfg := createTestConfig()
+	cfg.Framing = ""
+	cfg.TLSSetting.Insecure = false
+	cfg.TLSSetting.CAFile = certFile
+	exp := newTestExporter(t, listener, cfg)
+	assert.Equal(t, framingOctetCountingStr, exp.config.Framing)
+
+	expected := strconv.Itoa(len(expectedForm)-1) + " " + expectedForm[:len(expectedForm)-1]
+	for i := 0; i < 2; i++ {
+		require.NoError(t, exp.pushLogsData(context.Background(), logRecordsToLogs(exampleLog(t))))
+	}
+	conn := <-conns
+	defer conn.Close()
+	for i := 0; i < 2; i++ {
+		readMessage(t, conn, expected)
+	}
+}
+
+// writeTestCertificate writes a self-signed certificate for 127.0.0.1.
+func writeTestCertificate(t *testing.T) (string, string) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "syslog"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		IPAddresses:           []net.IP{net.IPv4(127, 0, 0, 1)},
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+	keyDer, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+
+	dir := t.TempDir()
+	certFile := filepath.Join(dir, "cert.pem")
+	keyFile := filepath.Join(dir, "key.pem")
+	require.NoError(t, os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0600))
+	require.NoError(t, os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDer}), 0600))
+	return certFile, keyFile
+}
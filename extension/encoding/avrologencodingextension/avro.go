@@ -0,0 +1,223 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package avrologencodingextension // import "github.com/open-telemetry/opentelemetry-collector-contrib/extension/encoding/avrologencodingextension"
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/linkedin/goavro/v2"
+)
+
+// avroDecoder decodes binary Avro datums of a schema into plain Go values.
+type avroDecoder struct {
+	codec  *goavro.Codec
+	schema any
+	// named holds the definitions of the named types of the schema by full name.
+	named map[string]map[string]any
+}
+
+func newAvroDecoder(schema string) (*avroDecoder, error) {
+	codec, err := goavro.NewCodec(schema)
+	if err != nil {
+		return nil, err
+	}
+	d := &avroDecoder{
+		codec: codec,
+		named: make(map[string]map[string]any),
+	}
+	if err = json.Unmarshal([]byte(schema), &d.schema); err != nil {
+		return nil, err
+	}
+	d.collectNamed(d.schema, "")
+	return d, nil
+}
+
+// decode decodes a single datum, which must be a record.
+func (d *avroDecoder) decode(buf []byte) (map[string]any, error) {
+	native, _, err := d.codec.NativeFromBinary(buf)
+	if err != nil {
+		return nil, err
+	}
+	record, ok := d.normalize(d.schema, "", native).(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("expected an Avro record, got %T", native)
+	}
+	return record, nil
+}
+
+func (d *avroDecoder) collectNamed(schema any, namespace string) {
+	switch s := schema.(type) {
+	case []any:
+		for _, branch := range s {
+			d.collectNamed(branch, namespace)
+		}
+	case map[string]any:
+		switch s["type"] {
+		case "record", "error", "enum", "fixed":
+			name := fullName(s, namespace)
+			d.named[name] = s
+			namespace = namespaceOf(name)
+			if fields, ok := s["fields"].([]any); ok {
+				for _, field := range fields {
+					if f, ok := field.(map[string]any); ok {
+						d.collectNamed(f["type"], namespace)
+					}
+				}
+			}
+		case "array":
+			d.collectNamed(s["items"], namespace)
+		case "map":
+			d.collectNamed(s["values"], namespace)
+		default:
+			d.collectNamed(s["type"], namespace)
+		}
+	}
+}
+
+// normalize converts the native representation of goavro into values that
+// can be used in pcommon maps: unions are unwrapped, numbers are widened and
+// logical types are converted.
+func (d *avroDecoder) normalize(schema any, namespace string, v any) any {
+	switch s := schema.(type) {
+	case string:
+		if named, ok := d.lookup(s, namespace); ok {
+			return d.normalize(named, namespaceOf(fullName(named, namespace)), v)
+		}
+		return normalizeLeaf(v)
+	case []any:
+		wrapped, ok := v.(map[string]any)
+		if !ok || len(wrapped) != 1 {
+			return normalizeLeaf(v)
+		}
+		for name, value := range wrapped {
+			for _, branch := range s {
+				if d.branchName(branch, namespace) == name {
+					return d.normalize(branch, namespace, value)
+				}
+			}
+			return normalizeLeaf(value)
+		}
+	case map[string]any:
+		switch s["type"] {
+		case "record", "error":
+			m, ok := v.(map[string]any)
+			if !ok {
+				return normalizeLeaf(v)
+			}
+			namespace = namespaceOf(fullName(s, namespace))
+			fields, _ := s["fields"].([]any)
+			out := make(map[string]any, len(m))
+			for _, field := range fields {
+				f, ok := field.(map[string]any)
+				if !ok {
+					continue
+				}
+				name, _ := f["name"].(string)
+				if value, ok := m[name]; ok {
+					out[name] = d.normalize(f["type"], namespace, value)
+				}
+			}
+			return out
+		case "array":
+			items, ok := v.([]any)
+			if !ok {
+				return normalizeLeaf(v)
+			}
+			out := make([]any, len(items))
+			for i, item := range items {
+				out[i] = d.normalize(s["items"], namespace, item)
+			}
+			return out
+		case "map":
+			values, ok := v.(map[string]any)
+			if !ok {
+				return normalizeLeaf(v)
+			}
+			out := make(map[string]any, len(values))
+			for k, value := range values {
+				out[k] = d.normalize(s["values"], namespace, value)
+			}
+			return out
+		case "enum", "fixed":
+			return normalizeLeaf(v)
+		default:
+			return d.normalize(s["type"], namespace, v)
+		}
+	}
+	return normalizeLeaf(v)
+}
+
+func (d *avroDecoder) lookup(name, namespace string) (map[string]any, bool) {
+	if !strings.Contains(name, ".") && namespace != "" {
+		if named, ok := d.named[namespace+"."+name]; ok {
+			return named, true
+		}
+	}
+	named, ok := d.named[name]
+	return named, ok
+}
+
+// branchName returns the name goavro uses to wrap values of the union branch.
+func (d *avroDecoder) branchName(branch any, namespace string) string {
+	switch b := branch.(type) {
+	case string:
+		if named, ok := d.lookup(b, namespace); ok {
+			return fullName(named, namespace)
+		}
+		return b
+	case map[string]any:
+		switch b["type"] {
+		case "record", "error", "enum", "fixed":
+			return fullName(b, namespace)
+		}
+		if typ, ok := b["type"].(string); ok {
+			if logicalType, ok := b["logicalType"].(string); ok {
+				return typ + "." + logicalType
+			}
+			return typ
+		}
+	}
+	return ""
+}
+
+func fullName(schema map[string]any, namespace string) string {
+	name, _ := schema["name"].(string)
+	if strings.Contains(name, ".") {
+		return name
+	}
+	if ns, ok := schema["namespace"].(string); ok {
+		namespace = ns
+	}
+	if namespace == "" {
+		return name
+	}
+	return namespace + "." + name
+}
+
+func namespaceOf(name string) string {
+	if i := strings.LastIndex(name, "."); i >= 0 {
+		return name[:i]
+	}
+	return ""
+}
+
+func normalizeLeaf(v any) any {
+	switch t := v.(type) {
+	case int32:
+		return int64(t)
+	case float32:
+		return float64(t)
+	case time.Duration:
+		return t.String()
+	case *big.Rat:
+		f, _ := t.Float64()
+		return f
+	default:
+		return v
+	}
+}
@@ -0,0 +1,81 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package avrologencodingextension
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/linkedin/goavro/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testTimestamp = time.Date(2023, 12, 1, 10, 0, 0, 0, time.UTC)
+
+func readTestSchema(t *testing.T) string {
+	schema, err := os.ReadFile(filepath.Join("testdata", "schema.avsc"))
+	require.NoError(t, err)
+	return string(schema)
+}
+
+func encodeTestRecord(t *testing.T, schema string) []byte {
+	codec, err := goavro.NewCodec(schema)
+	require.NoError(t, err)
+	buf, err := codec.BinaryFromNative(nil, map[string]any{
+		"message":   "connection refused",
+		"timestamp": testTimestamp,
+		"level":     goavro.Union("string", "error"),
+		"count":     int32(3),
+		"host": goavro.Union("com.example.Host", map[string]any{
+			"name": "server-1",
+			"ip":   nil,
+		}),
+		"tags": []any{"a", "b"},
+		"labels": map[string]any{
+			"retries": goavro.Union("long", int64(2)),
+			"unset":   nil,
+		},
+	})
+	require.NoError(t, err)
+	return buf
+}
+
+func TestAvroDecoder(t *testing.T) {
+	schema := readTestSchema(t)
+	decoder, err := newAvroDecoder(schema)
+	require.NoError(t, err)
+
+	record, err := decoder.decode(encodeTestRecord(t, schema))
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{
+		"message":   "connection refused",
+		"timestamp": testTimestamp,
+		"level":     "error",
+		"count":     int64(3),
+		"host": map[string]any{
+			"name": "server-1",
+			"ip":   nil,
+		},
+		"tags": []any{"a", "b"},
+		"labels": map[string]any{
+			"retries": int64(2),
+			"unset":   nil,
+		},
+	}, record)
+}
+
+func TestAvroDecoder_notRecord(t *testing.T) {
+	decoder, err := newAvroDecoder(`"string"`)
+	require.NoError(t, err)
+	_, err = decoder.decode([]byte{0x6, 'f', 'o', 'o'})
+	assert.EqualError(t, err, "expected an Avro record, got string")
+}
+
+func TestNewAvroDecoder_invalid(t *testing.T) {
+	_, err := newAvroDecoder(`{"type": "unknown"}`)
+	assert.Error(t, err)
+}
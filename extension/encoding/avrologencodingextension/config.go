@@ -0,0 +1,42 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package avrologencodingextension // import "github.com/open-telemetry/opentelemetry-collector-contrib/extension/encoding/avrologencodingextension"
+
+import (
+	"errors"
+
+	"go.opentelemetry.io/collector/config/confighttp"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/extension/encoding/internal/logmapping"
+)
+
+var (
+	errNoSchema                 = errors.New("one of schema or schema_registry must be set")
+	errBothSchemas              = errors.New("only one of schema or schema_registry can be set")
+	errNoSchemaRegistryEndpoint = errors.New("schema_registry.endpoint must be set")
+)
+
+type Config struct {
+	// Schema is the path of a file containing the Avro schema of the messages.
+	// Messages are expected to contain a single binary encoded datum.
+	Schema string `mapstructure:"schema"`
+	// SchemaRegistry configures the client of a Confluent compatible schema registry.
+	// Messages are expected to be in the Confluent wire format.
+	SchemaRegistry *confighttp.HTTPClientSettings `mapstructure:"schema_registry"`
+	// Mapping defines how the fields of the records are mapped into log records.
+	Mapping logmapping.Config `mapstructure:"mapping"`
+}
+
+func (c *Config) Validate() error {
+	if c.Schema == "" && c.SchemaRegistry == nil {
+		return errNoSchema
+	}
+	if c.Schema != "" && c.SchemaRegistry != nil {
+		return errBothSchemas
+	}
+	if c.SchemaRegistry != nil && c.SchemaRegistry.Endpoint == "" {
+		return errNoSchemaRegistryEndpoint
+	}
+	return c.Mapping.Validate()
+}
@@ -0,0 +1,66 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package avrologencodingextension
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/collector/config/confighttp"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/extension/encoding/internal/logmapping"
+)
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name   string
+		config *Config
+		err    string
+	}{
+		{
+			name:   "local schema",
+			config: &Config{Schema: "schema.avsc"},
+		},
+		{
+			name:   "schema registry",
+			config: &Config{SchemaRegistry: &confighttp.HTTPClientSettings{Endpoint: "http://localhost:8081"}},
+		},
+		{
+			name:   "no schema",
+			config: &Config{},
+			err:    errNoSchema.Error(),
+		},
+		{
+			name: "both schemas",
+			config: &Config{
+				Schema:         "schema.avsc",
+				SchemaRegistry: &confighttp.HTTPClientSettings{Endpoint: "http://localhost:8081"},
+			},
+			err: errBothSchemas.Error(),
+		},
+		{
+			name:   "no schema registry endpoint",
+			config: &Config{SchemaRegistry: &confighttp.HTTPClientSettings{}},
+			err:    errNoSchemaRegistryEndpoint.Error(),
+		},
+		{
+			name: "invalid mapping",
+			config: &Config{
+				Schema:  "schema.avsc",
+				Mapping: logmapping.Config{Body: "."},
+			},
+			err: `invalid field path "."`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.config.Validate()
+			if tt.err == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, tt.err)
+			}
+		})
+	}
+}
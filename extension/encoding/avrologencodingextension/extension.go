@@ -0,0 +1,107 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package avrologencodingextension // import "github.com/open-telemetry/opentelemetry-collector-contrib/extension/encoding/avrologencodingextension"
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/pdata/plog"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/extension/encoding"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/extension/encoding/internal/schemaregistry"
+)
+
+var _ encoding.LogsUnmarshalerExtension = (*avroLogExtension)(nil)
+
+type avroLogExtension struct {
+	config   *Config
+	settings component.TelemetrySettings
+
+	// decoder is used when the schema is read from a local file.
+	decoder *avroDecoder
+
+	registry *schemaregistry.Client
+	decoders *schemaregistry.Cache[*avroDecoder]
+}
+
+func (e *avroLogExtension) UnmarshalLogs(buf []byte) (plog.Logs, error) {
+	p := plog.NewLogs()
+
+	decoder, payload, err := e.decoderFor(buf)
+	if err != nil {
+		return p, err
+	}
+	record, err := decoder.decode(payload)
+	if err != nil {
+		return p, err
+	}
+
+	lr := p.ResourceLogs().AppendEmpty().ScopeLogs().AppendEmpty().LogRecords().AppendEmpty()
+	if err = e.config.Mapping.Map(record, lr); err != nil {
+		return p, err
+	}
+	return p, nil
+}
+
+// decoderFor returns the decoder for the message and its payload.
+func (e *avroLogExtension) decoderFor(buf []byte) (*avroDecoder, []byte, error) {
+	if e.registry == nil {
+		return e.decoder, buf, nil
+	}
+
+	id, payload, err := schemaregistry.ParseWireFormat(buf)
+	if err != nil {
+		return nil, nil, err
+	}
+	decoder, err := e.decoders.Get(id)
+	if err != nil {
+		return nil, nil, err
+	}
+	return decoder, payload, nil
+}
+
+// buildDecoder builds the decoder of the schema registered with the ID.
+func (e *avroLogExtension) buildDecoder(ctx context.Context, id uint32) (*avroDecoder, error) {
+	schema, err := e.registry.SchemaByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if schema.Type() != "AVRO" {
+		return nil, fmt.Errorf("schema %d is of type %s, expected AVRO", id, schema.Type())
+	}
+	decoder, err := newAvroDecoder(schema.Schema)
+	if err != nil {
+		return nil, fmt.Errorf("invalid schema %d: %w", id, err)
+	}
+	return decoder, nil
+}
+
+func (e *avroLogExtension) Start(_ context.Context, host component.Host) error {
+	if e.config.SchemaRegistry != nil {
+		client, err := e.config.SchemaRegistry.ToClient(host, e.settings)
+		if err != nil {
+			return err
+		}
+		e.registry = schemaregistry.NewClient(e.config.SchemaRegistry.Endpoint, client, "")
+		e.decoders = schemaregistry.NewCache(e.config.SchemaRegistry.Timeout, e.buildDecoder)
+		return nil
+	}
+
+	schema, err := os.ReadFile(e.config.Schema)
+	if err != nil {
+		return fmt.Errorf("failed to read schema: %w", err)
+	}
+	e.decoder, err = newAvroDecoder(string(schema))
+	if err != nil {
+		return fmt.Errorf("invalid schema %q: %w", e.config.Schema, err)
+	}
+	return nil
+}
+
+func (e *avroLogExtension) Shutdown(_ context.Context) error {
+	return nil
+}
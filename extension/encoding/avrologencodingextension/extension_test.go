@@ -0,0 +1,102 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package avrologencodingextension
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/config/confighttp"
+	"go.opentelemetry.io/collector/extension/extensiontest"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/extension/encoding/internal/logmapping"
+)
+
+var testMapping = logmapping.Config{
+	Body:       "message",
+	Timestamp:  "timestamp",
+	Attributes: []string{"level", "host.name"},
+}
+
+func newTestExtension(t *testing.T, cfg *Config) *avroLogExtension {
+	ext, err := createExtension(context.Background(), extensiontest.NewNopCreateSettings(), cfg)
+	require.NoError(t, err)
+	require.NoError(t, ext.Start(context.Background(), componenttest.NewNopHost()))
+	t.Cleanup(func() {
+		require.NoError(t, ext.Shutdown(context.Background()))
+	})
+	return ext.(*avroLogExtension)
+}
+
+func TestUnmarshalLogs_localSchema(t *testing.T) {
+	ext := newTestExtension(t, &Config{
+		Schema:  filepath.Join("testdata", "schema.avsc"),
+		Mapping: testMapping,
+	})
+
+	ld, err := ext.UnmarshalLogs(encodeTestRecord(t, readTestSchema(t)))
+	require.NoError(t, err)
+	require.Equal(t, 1, ld.LogRecordCount())
+	lr := ld.ResourceLogs().At(0).ScopeLogs().At(0).LogRecords().At(0)
+	assert.Equal(t, "connection refused", lr.Body().Str())
+	assert.Equal(t, testTimestamp, lr.Timestamp().AsTime())
+	assert.Equal(t, map[string]any{
+		"level":     "error",
+		"host.name": "server-1",
+	}, lr.Attributes().AsRaw())
+}
+
+func TestUnmarshalLogs_schemaRegistry(t *testing.T) {
+	schema := readTestSchema(t)
+	requests := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if r.URL.Path != "/schemas/ids/42" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		require.NoError(t, json.NewEncoder(w).Encode(map[string]string{"schema": schema}))
+	}))
+	defer srv.Close()
+
+	ext := newTestExtension(t, &Config{
+		SchemaRegistry: &confighttp.HTTPClientSettings{Endpoint: srv.URL},
+	})
+
+	msg := []byte{0}
+	msg = binary.BigEndian.AppendUint32(msg, 42)
+	msg = append(msg, encodeTestRecord(t, schema)...)
+	for i := 0; i < 2; i++ {
+		ld, err := ext.UnmarshalLogs(msg)
+		require.NoError(t, err)
+		lr := ld.ResourceLogs().At(0).ScopeLogs().At(0).LogRecords().At(0)
+		body := lr.Body().Map().AsRaw()
+		assert.Equal(t, "connection refused", body["message"])
+		assert.Equal(t, "2023-12-01T10:00:00Z", body["timestamp"])
+	}
+	assert.Equal(t, 1, requests)
+
+	msg[4] = 43
+	_, err := ext.UnmarshalLogs(msg)
+	assert.ErrorContains(t, err, "failed to get schema 43")
+
+	_, err = ext.UnmarshalLogs([]byte("not framed"))
+	assert.Error(t, err)
+}
+
+func TestStart_invalidSchema(t *testing.T) {
+	ext, err := createExtension(context.Background(), extensiontest.NewNopCreateSettings(), &Config{
+		Schema: filepath.Join("testdata", "missing.avsc"),
+	})
+	require.NoError(t, err)
+	assert.ErrorContains(t, ext.Start(context.Background(), componenttest.NewNopHost()), "failed to read schema")
+}
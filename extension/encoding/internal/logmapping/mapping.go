@@ -0,0 +1,139 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+// Package logmapping maps decoded records into log records.
+package logmapping // import "github.com/open-telemetry/opentelemetry-collector-contrib/extension/encoding/internal/logmapping"
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/plog"
+)
+
+// Config defines which fields of a decoded record are mapped to the log record.
+// Fields are referenced by their path, with nested fields separated by dots.
+type Config struct {
+	// Body is the field used as the log body. If empty, the whole record is used.
+	Body string `mapstructure:"body"`
+	// Timestamp is the field used as the log timestamp. Strings are parsed as RFC 3339
+	// and numbers are interpreted as milliseconds since the Unix epoch.
+	Timestamp string `mapstructure:"timestamp"`
+	// Attributes are the fields copied to the log attributes, using the path as key.
+	Attributes []string `mapstructure:"attributes"`
+}
+
+// Validate checks that no path is malformed.
+func (c Config) Validate() error {
+	paths := append([]string{c.Body, c.Timestamp}, c.Attributes...)
+	for i, path := range paths {
+		if path == "" && i < 2 {
+			continue
+		}
+		for _, part := range strings.Split(path, ".") {
+			if part == "" {
+				return fmt.Errorf("invalid field path %q", path)
+			}
+		}
+	}
+	return nil
+}
+
+// Map fills lr with the fields of record. Values of type time.Time in
+// record are converted to RFC 3339 strings when used in the body or attributes.
+func (c Config) Map(record map[string]any, lr plog.LogRecord) error {
+	lr.SetObservedTimestamp(pcommon.NewTimestampFromTime(time.Now()))
+
+	if c.Timestamp != "" {
+		if v, ok := lookup(record, c.Timestamp); ok {
+			ts, err := toTime(v)
+			if err != nil {
+				return fmt.Errorf("field %q: %w", c.Timestamp, err)
+			}
+			lr.SetTimestamp(pcommon.NewTimestampFromTime(ts))
+		}
+	}
+
+	for _, path := range c.Attributes {
+		v, ok := lookup(record, path)
+		if !ok {
+			continue
+		}
+		if err := lr.Attributes().PutEmpty(path).FromRaw(toRaw(v)); err != nil {
+			return fmt.Errorf("field %q: %w", path, err)
+		}
+	}
+
+	if c.Body == "" {
+		return lr.Body().SetEmptyMap().FromRaw(toRaw(record).(map[string]any))
+	}
+	if v, ok := lookup(record, c.Body); ok {
+		if err := lr.Body().FromRaw(toRaw(v)); err != nil {
+			return fmt.Errorf("field %q: %w", c.Body, err)
+		}
+	}
+	return nil
+}
+
+func lookup(record map[string]any, path string) (any, bool) {
+	var current any = record
+	for _, part := range strings.Split(path, ".") {
+		m, ok := current.(map[string]any)
+		if !ok {
+			return nil, false
+		}
+		if current, ok = m[part]; !ok {
+			return nil, false
+		}
+	}
+	return current, true
+}
+
+func toTime(v any) (time.Time, error) {
+	switch t := v.(type) {
+	case time.Time:
+		return t, nil
+	case string:
+		return time.Parse(time.RFC3339Nano, t)
+	case int64:
+		return time.UnixMilli(t), nil
+	case int32:
+		return time.UnixMilli(int64(t)), nil
+	case int:
+		return time.UnixMilli(int64(t)), nil
+	case uint64:
+		return time.UnixMilli(int64(t)), nil
+	case uint32:
+		return time.UnixMilli(int64(t)), nil
+	case float64:
+		return time.UnixMilli(int64(t)), nil
+	case float32:
+		return time.UnixMilli(int64(t)), nil
+	default:
+		return time.Time{}, fmt.Errorf("unsupported timestamp type %T", v)
+	}
+}
+
+// toRaw converts v into a value accepted by pcommon.Value.FromRaw.
+func toRaw(v any) any {
+	switch t := v.(type) {
+	case time.Time:
+		return t.Format(time.RFC3339Nano)
+	case map[string]any:
+		m := make(map[string]any, len(t))
+		for k, e := range t {
+			m[k] = toRaw(e)
+		}
+		return m
+	case []any:
+		s := make([]any, len(t))
+		for i, e := range t {
+			s[i] = toRaw(e)
+		}
+		return s
+	default:
+		return v
+	}
+}
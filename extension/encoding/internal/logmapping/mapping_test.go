@@ -0,0 +1,88 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package logmapping
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/pdata/plog"
+)
+
+func TestValidate(t *testing.T) {
+	assert.NoError(t, Config{}.Validate())
+	assert.NoError(t, Config{Body: "a.b", Timestamp: "ts", Attributes: []string{"c"}}.Validate())
+	assert.EqualError(t, Config{Body: "a..b"}.Validate(), `invalid field path "a..b"`)
+	assert.EqualError(t, Config{Attributes: []string{""}}.Validate(), `invalid field path ""`)
+}
+
+func TestMap(t *testing.T) {
+	ts := time.Date(2023, 12, 1, 10, 0, 0, 0, time.UTC)
+	record := map[string]any{
+		"message": "hello",
+		"time":    ts,
+		"host": map[string]any{
+			"name": "server-1",
+		},
+		"tags": []any{"a", "b"},
+	}
+
+	tests := []struct {
+		name     string
+		config   Config
+		validate func(t *testing.T, lr plog.LogRecord)
+	}{
+		{
+			name:   "whole record",
+			config: Config{},
+			validate: func(t *testing.T, lr plog.LogRecord) {
+				assert.Equal(t, map[string]any{
+					"message": "hello",
+					"time":    "2023-12-01T10:00:00Z",
+					"host":    map[string]any{"name": "server-1"},
+					"tags":    []any{"a", "b"},
+				}, lr.Body().Map().AsRaw())
+				assert.Zero(t, lr.Timestamp())
+				assert.NotZero(t, lr.ObservedTimestamp())
+			},
+		},
+		{
+			name: "mapped fields",
+			config: Config{
+				Body:       "message",
+				Timestamp:  "time",
+				Attributes: []string{"host.name", "tags", "missing"},
+			},
+			validate: func(t *testing.T, lr plog.LogRecord) {
+				assert.Equal(t, "hello", lr.Body().Str())
+				assert.Equal(t, ts, lr.Timestamp().AsTime())
+				assert.Equal(t, map[string]any{
+					"host.name": "server-1",
+					"tags":      []any{"a", "b"},
+				}, lr.Attributes().AsRaw())
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lr := plog.NewLogRecord()
+			require.NoError(t, tt.config.Map(record, lr))
+			tt.validate(t, lr)
+		})
+	}
+}
+
+func TestMapTimestamp(t *testing.T) {
+	ts := time.Date(2023, 12, 1, 10, 0, 0, 0, time.UTC)
+	for _, v := range []any{ts, "2023-12-01T10:00:00Z", ts.UnixMilli(), float64(ts.UnixMilli())} {
+		lr := plog.NewLogRecord()
+		require.NoError(t, Config{Timestamp: "ts"}.Map(map[string]any{"ts": v}, lr))
+		assert.Equal(t, ts, lr.Timestamp().AsTime().UTC())
+	}
+
+	lr := plog.NewLogRecord()
+	assert.EqualError(t, Config{Timestamp: "ts"}.Map(map[string]any{"ts": true}, lr), `field "ts": unsupported timestamp type bool`)
+}
@@ -0,0 +1,200 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+// Package schemaregistry provides a minimal client for Confluent compatible
+// schema registries and helpers for the Confluent wire format.
+package schemaregistry // import "github.com/open-telemetry/opentelemetry-collector-contrib/extension/encoding/internal/schemaregistry"
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// magicByte is the first byte of every message in the Confluent wire format.
+	magicByte = 0x0
+	// headerSize is the size of the magic byte and the schema ID.
+	headerSize = 5
+
+	contentType = "application/vnd.schemaregistry.v1+json"
+
+	// DefaultTimeout bounds the requests to the registry when the HTTP client
+	// has no timeout.
+	DefaultTimeout = 10 * time.Second
+)
+
+var errInvalidWireFormat = errors.New("message is not in the Confluent wire format")
+
+// Reference is a reference from a schema to another schema registered under a subject.
+type Reference struct {
+	Name    string `json:"name"`
+	Subject string `json:"subject"`
+	Version int    `json:"version"`
+}
+
+// Schema is a schema as returned by the registry.
+type Schema struct {
+	// Schema is the textual representation of the schema, or the base64
+	// encoded FileDescriptorProto when requested in the serialized format.
+	Schema string `json:"schema"`
+	// SchemaType is empty for Avro schemas.
+	SchemaType string      `json:"schemaType"`
+	References []Reference `json:"references"`
+}
+
+// Type returns the type of the schema, AVRO, PROTOBUF or JSON.
+func (s *Schema) Type() string {
+	if s.SchemaType == "" {
+		return "AVRO"
+	}
+	return s.SchemaType
+}
+
+// Client retrieves schemas from a schema registry. The schemas are not cached,
+// see Cache.
+type Client struct {
+	endpoint string
+	client   *http.Client
+	// format is passed as the format query parameter, if not empty.
+	format string
+}
+
+// NewClient creates a client for the registry at endpoint. If format is not
+// empty it is requested for all schemas, e.g. "serialized" for Protobuf.
+func NewClient(endpoint string, client *http.Client, format string) *Client {
+	return &Client{
+		endpoint: strings.TrimSuffix(endpoint, "/"),
+		client:   client,
+		format:   format,
+	}
+}
+
+// SchemaByID returns the schema registered with the given ID.
+func (c *Client) SchemaByID(ctx context.Context, id uint32) (*Schema, error) {
+	schema, err := c.get(ctx, fmt.Sprintf("/schemas/ids/%d", id))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get schema %d: %w", id, err)
+	}
+	return schema, nil
+}
+
+// SchemaByReference returns the schema registered under the subject and version of ref.
+func (c *Client) SchemaByReference(ctx context.Context, ref Reference) (*Schema, error) {
+	schema, err := c.get(ctx, fmt.Sprintf("/subjects/%s/versions/%d", url.PathEscape(ref.Subject), ref.Version))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get schema for subject %q version %d: %w", ref.Subject, ref.Version, err)
+	}
+	return schema, nil
+}
+
+func (c *Client) get(ctx context.Context, path string) (*Schema, error) {
+	u := c.endpoint + path
+	if c.format != "" {
+		u += "?format=" + url.QueryEscape(c.format)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", contentType)
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status code %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+	schema := &Schema{}
+	if err := json.Unmarshal(body, schema); err != nil {
+		return nil, err
+	}
+	return schema, nil
+}
+
+// Cache caches the values built from the schemas of the registry, such as
+// decoders, by schema ID. The schemas are fetched without holding the lock of
+// the cache, and only once for concurrent lookups of the same ID. Failures
+// are not cached.
+type Cache[T any] struct {
+	timeout time.Duration
+	build   func(ctx context.Context, id uint32) (T, error)
+
+	mu     sync.Mutex
+	values map[uint32]T
+	calls  map[uint32]*call[T]
+}
+
+// call is a pending build of a value.
+type call[T any] struct {
+	done  chan struct{}
+	value T
+	err   error
+}
+
+// NewCache creates a cache building its values with build, with a context
+// bounded by timeout.
+func NewCache[T any](timeout time.Duration, build func(ctx context.Context, id uint32) (T, error)) *Cache[T] {
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
+	return &Cache[T]{
+		timeout: timeout,
+		build:   build,
+		values:  make(map[uint32]T),
+		calls:   make(map[uint32]*call[T]),
+	}
+}
+
+// Get returns the value of the schema ID, building it if needed.
+func (c *Cache[T]) Get(id uint32) (T, error) {
+	c.mu.Lock()
+	if value, ok := c.values[id]; ok {
+		c.mu.Unlock()
+		return value, nil
+	}
+	if pending, ok := c.calls[id]; ok {
+		c.mu.Unlock()
+		<-pending.done
+		return pending.value, pending.err
+	}
+	pending := &call[T]{done: make(chan struct{})}
+	c.calls[id] = pending
+	c.mu.Unlock()
+
+	ctx, cancel := context.WithTimeout(context.Background(), c.timeout)
+	pending.value, pending.err = c.build(ctx, id)
+	cancel()
+
+	c.mu.Lock()
+	delete(c.calls, id)
+	if pending.err == nil {
+		c.values[id] = pending.value
+	}
+	c.mu.Unlock()
+	close(pending.done)
+	return pending.value, pending.err
+}
+
+// ParseWireFormat splits a message in the Confluent wire format into
+// the schema ID and the remaining payload.
+func ParseWireFormat(buf []byte) (uint32, []byte, error) {
+	if len(buf) < headerSize || buf[0] != magicByte {
+		return 0, nil, errInvalidWireFormat
+	}
+	return binary.BigEndian.Uint32(buf[1:headerSize]), buf[headerSize:], nil
+}
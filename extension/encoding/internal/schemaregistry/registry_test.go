@@ -0,0 +1,123 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package schemaregistry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClient(t *testing.T) {
+	var requests []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.URL.RequestURI())
+		switch r.URL.Path {
+		case "/schemas/ids/1":
+			_, _ = w.Write([]byte(`{"schema":"{\"type\":\"string\"}"}`))
+		case "/subjects/other-value/versions/2":
+			_, _ = w.Write([]byte(`{"schema":"c2VyaWFsaXplZA==","schemaType":"PROTOBUF"}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"error_code":40403,"message":"Schema not found"}`))
+		}
+	}))
+	defer srv.Close()
+
+	c := NewClient(srv.URL+"/", srv.Client(), "serialized")
+
+	schema, err := c.SchemaByID(context.Background(), 1)
+	require.NoError(t, err)
+	assert.Equal(t, `{"type":"string"}`, schema.Schema)
+	assert.Equal(t, "AVRO", schema.Type())
+
+	schema, err = c.SchemaByReference(context.Background(), Reference{Name: "other.proto", Subject: "other-value", Version: 2})
+	require.NoError(t, err)
+	assert.Equal(t, "PROTOBUF", schema.Type())
+
+	_, err = c.SchemaByID(context.Background(), 2)
+	assert.ErrorContains(t, err, "unexpected status code 404")
+
+	assert.Equal(t, []string{
+		"/schemas/ids/1?format=serialized",
+		"/subjects/other-value/versions/2?format=serialized",
+		"/schemas/ids/2?format=serialized",
+	}, requests)
+}
+
+func TestCache(t *testing.T) {
+	var builds atomic.Int32
+	release := make(chan struct{})
+	cache := NewCache(time.Second, func(ctx context.Context, id uint32) (string, error) {
+		builds.Add(1)
+		if id == 2 {
+			return "", errors.New("not found")
+		}
+		select {
+		case <-release:
+		case <-ctx.Done():
+			return "", ctx.Err()
+		}
+		return fmt.Sprintf("value %d", id), nil
+	})
+
+	// Concurrent lookups of the same ID build the value once
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			value, err := cache.Get(1)
+			assert.NoError(t, err)
+			assert.Equal(t, "value 1", value)
+		}()
+	}
+	// Other IDs are not blocked by a pending build
+	_, err := cache.Get(2)
+	assert.EqualError(t, err, "not found")
+	close(release)
+	wg.Wait()
+	assert.Equal(t, int32(2), builds.Load())
+
+	value, err := cache.Get(1)
+	require.NoError(t, err)
+	assert.Equal(t, "value 1", value)
+	assert.Equal(t, int32(2), builds.Load())
+
+	// Failures are not cached
+	_, err = cache.Get(2)
+	assert.Error(t, err)
+	assert.Equal(t, int32(3), builds.Load())
+}
+
+func TestCacheTimeout(t *testing.T) {
+	cache := NewCache(10*time.Millisecond, func(ctx context.Context, id uint32) (string, error) {
+		<-ctx.Done()
+		return "", ctx.Err()
+	})
+	_, err := cache.Get(1)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
+func TestParseWireFormat(t *testing.T) {
+	id, payload, err := ParseWireFormat([]byte{0, 0, 0, 1, 2, 'a', 'b'})
+	require.NoError(t, err)
+	assert.Equal(t, uint32(258), id)
+	assert.Equal(t, []byte("ab"), payload)
+
+	_, _, err = ParseWireFormat([]byte{1, 0, 0, 0, 1})
+	assert.ErrorIs(t, err, errInvalidWireFormat)
+
+	_, _, err = ParseWireFormat([]byte{0, 0})
+	assert.ErrorIs(t, err, errInvalidWireFormat)
+}
@@ -0,0 +1,49 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package protobuflogencodingextension // import "github.com/open-telemetry/opentelemetry-collector-contrib/extension/encoding/protobuflogencodingextension"
+
+import (
+	"errors"
+
+	"go.opentelemetry.io/collector/config/confighttp"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/extension/encoding/internal/logmapping"
+)
+
+var (
+	errNoSchema                 = errors.New("one of descriptor_file or schema_registry must be set")
+	errBothSchemas              = errors.New("only one of descriptor_file or schema_registry can be set")
+	errNoMessageName            = errors.New("message_name must be set when using descriptor_file")
+	errNoSchemaRegistryEndpoint = errors.New("schema_registry.endpoint must be set")
+)
+
+type Config struct {
+	// DescriptorFile is the path of a file containing a serialized FileDescriptorSet,
+	// as generated by `protoc --include_imports --descriptor_set_out`.
+	// Messages are expected to contain a single serialized message of type MessageName.
+	DescriptorFile string `mapstructure:"descriptor_file"`
+	// MessageName is the fully qualified name of the message type in DescriptorFile.
+	MessageName string `mapstructure:"message_name"`
+	// SchemaRegistry configures the client of a Confluent compatible schema registry.
+	// Messages are expected to be in the Confluent Protobuf wire format.
+	SchemaRegistry *confighttp.HTTPClientSettings `mapstructure:"schema_registry"`
+	// Mapping defines how the fields of the messages are mapped into log records.
+	Mapping logmapping.Config `mapstructure:"mapping"`
+}
+
+func (c *Config) Validate() error {
+	if c.DescriptorFile == "" && c.SchemaRegistry == nil {
+		return errNoSchema
+	}
+	if c.DescriptorFile != "" && c.SchemaRegistry != nil {
+		return errBothSchemas
+	}
+	if c.DescriptorFile != "" && c.MessageName == "" {
+		return errNoMessageName
+	}
+	if c.SchemaRegistry != nil && c.SchemaRegistry.Endpoint == "" {
+		return errNoSchemaRegistryEndpoint
+	}
+	return c.Mapping.Validate()
+}
@@ -0,0 +1,57 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package protobuflogencodingextension
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/collector/config/confighttp"
+)
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name   string
+		config *Config
+		err    error
+	}{
+		{
+			name:   "descriptor file",
+			config: &Config{DescriptorFile: "log.pb", MessageName: "example.LogEvent"},
+		},
+		{
+			name:   "schema registry",
+			config: &Config{SchemaRegistry: &confighttp.HTTPClientSettings{Endpoint: "http://localhost:8081"}},
+		},
+		{
+			name:   "no schema",
+			config: &Config{},
+			err:    errNoSchema,
+		},
+		{
+			name: "both schemas",
+			config: &Config{
+				DescriptorFile: "log.pb",
+				MessageName:    "example.LogEvent",
+				SchemaRegistry: &confighttp.HTTPClientSettings{Endpoint: "http://localhost:8081"},
+			},
+			err: errBothSchemas,
+		},
+		{
+			name:   "no message name",
+			config: &Config{DescriptorFile: "log.pb"},
+			err:    errNoMessageName,
+		},
+		{
+			name:   "no schema registry endpoint",
+			config: &Config{SchemaRegistry: &confighttp.HTTPClientSettings{}},
+			err:    errNoSchemaRegistryEndpoint,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.err, tt.config.Validate())
+		})
+	}
+}
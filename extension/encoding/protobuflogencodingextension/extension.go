@@ -0,0 +1,168 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package protobuflogencodingextension // import "github.com/open-telemetry/opentelemetry-collector-contrib/extension/encoding/protobuflogencodingextension"
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"os"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/pdata/plog"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/descriptorpb"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/extension/encoding"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/extension/encoding/internal/schemaregistry"
+)
+
+// serializedFormat requests schemas from the registry as base64 encoded FileDescriptorProtos.
+const serializedFormat = "serialized"
+
+var _ encoding.LogsUnmarshalerExtension = (*protobufLogExtension)(nil)
+
+type protobufLogExtension struct {
+	config   *Config
+	settings component.TelemetrySettings
+
+	// message is used when the descriptors are read from a local file.
+	message protoreflect.MessageDescriptor
+
+	registry *schemaregistry.Client
+	files    *schemaregistry.Cache[protoreflect.FileDescriptor]
+}
+
+func (e *protobufLogExtension) UnmarshalLogs(buf []byte) (plog.Logs, error) {
+	p := plog.NewLogs()
+
+	md, payload, err := e.messageFor(buf)
+	if err != nil {
+		return p, err
+	}
+	record, err := decodeMessage(md, payload)
+	if err != nil {
+		return p, err
+	}
+
+	lr := p.ResourceLogs().AppendEmpty().ScopeLogs().AppendEmpty().LogRecords().AppendEmpty()
+	if err = e.config.Mapping.Map(record, lr); err != nil {
+		return p, err
+	}
+	return p, nil
+}
+
+// messageFor returns the descriptor of the message type and its payload.
+func (e *protobufLogExtension) messageFor(buf []byte) (protoreflect.MessageDescriptor, []byte, error) {
+	if e.registry == nil {
+		return e.message, buf, nil
+	}
+
+	id, payload, err := schemaregistry.ParseWireFormat(buf)
+	if err != nil {
+		return nil, nil, err
+	}
+	indexes, payload, err := readMessageIndexes(payload)
+	if err != nil {
+		return nil, nil, err
+	}
+	fd, err := e.files.Get(id)
+	if err != nil {
+		return nil, nil, err
+	}
+	md, err := messageByIndexes(fd, indexes)
+	if err != nil {
+		return nil, nil, err
+	}
+	return md, payload, nil
+}
+
+// buildFileByID builds the file descriptor of the schema registered with the ID.
+func (e *protobufLogExtension) buildFileByID(ctx context.Context, id uint32) (protoreflect.FileDescriptor, error) {
+	schema, err := e.registry.SchemaByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if schema.Type() != "PROTOBUF" {
+		return nil, fmt.Errorf("schema %d is of type %s, expected PROTOBUF", id, schema.Type())
+	}
+	fd, err := e.buildFile(ctx, schema, &protoregistry.Files{})
+	if err != nil {
+		return nil, fmt.Errorf("invalid schema %d: %w", id, err)
+	}
+	return fd, nil
+}
+
+// buildFile builds the file descriptor of schema, registering the files of its
+// references in files first.
+func (e *protobufLogExtension) buildFile(ctx context.Context, schema *schemaregistry.Schema, files *protoregistry.Files) (protoreflect.FileDescriptor, error) {
+	for _, ref := range schema.References {
+		if _, err := files.FindFileByPath(ref.Name); err == nil {
+			continue
+		}
+		refSchema, err := e.registry.SchemaByReference(ctx, ref)
+		if err != nil {
+			return nil, err
+		}
+		fd, err := e.buildFile(ctx, refSchema, files)
+		if err != nil {
+			return nil, fmt.Errorf("reference %q: %w", ref.Name, err)
+		}
+		if err = files.RegisterFile(fd); err != nil {
+			return nil, err
+		}
+	}
+
+	raw, err := base64.StdEncoding.DecodeString(schema.Schema)
+	if err != nil {
+		return nil, err
+	}
+	fdp := &descriptorpb.FileDescriptorProto{}
+	if err = proto.Unmarshal(raw, fdp); err != nil {
+		return nil, err
+	}
+	return protodesc.NewFile(fdp, fallbackResolver{files: files})
+}
+
+func (e *protobufLogExtension) Start(_ context.Context, host component.Host) error {
+	if e.config.SchemaRegistry != nil {
+		client, err := e.config.SchemaRegistry.ToClient(host, e.settings)
+		if err != nil {
+			return err
+		}
+		e.registry = schemaregistry.NewClient(e.config.SchemaRegistry.Endpoint, client, serializedFormat)
+		e.files = schemaregistry.NewCache(e.config.SchemaRegistry.Timeout, e.buildFileByID)
+		return nil
+	}
+
+	raw, err := os.ReadFile(e.config.DescriptorFile)
+	if err != nil {
+		return fmt.Errorf("failed to read descriptor file: %w", err)
+	}
+	set := &descriptorpb.FileDescriptorSet{}
+	if err = proto.Unmarshal(raw, set); err != nil {
+		return fmt.Errorf("invalid descriptor file %q: %w", e.config.DescriptorFile, err)
+	}
+	files, err := protodesc.NewFiles(set)
+	if err != nil {
+		return fmt.Errorf("invalid descriptor file %q: %w", e.config.DescriptorFile, err)
+	}
+	d, err := files.FindDescriptorByName(protoreflect.FullName(e.config.MessageName))
+	if err != nil {
+		return fmt.Errorf("message %q: %w", e.config.MessageName, err)
+	}
+	md, ok := d.(protoreflect.MessageDescriptor)
+	if !ok {
+		return fmt.Errorf("%q is not a message", e.config.MessageName)
+	}
+	e.message = md
+	return nil
+}
+
+func (e *protobufLogExtension) Shutdown(_ context.Context) error {
+	return nil
+}
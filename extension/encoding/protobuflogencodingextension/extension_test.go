@@ -0,0 +1,168 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package protobuflogencodingextension
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/config/confighttp"
+	"go.opentelemetry.io/collector/extension/extensiontest"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/extension/encoding/internal/logmapping"
+)
+
+var testMapping = logmapping.Config{
+	Body:       "message",
+	Timestamp:  "time",
+	Attributes: []string{"level", "host.name"},
+}
+
+func newTestExtension(t *testing.T, cfg *Config) *protobufLogExtension {
+	ext, err := createExtension(context.Background(), extensiontest.NewNopCreateSettings(), cfg)
+	require.NoError(t, err)
+	require.NoError(t, ext.Start(context.Background(), componenttest.NewNopHost()))
+	t.Cleanup(func() {
+		require.NoError(t, ext.Shutdown(context.Background()))
+	})
+	return ext.(*protobufLogExtension)
+}
+
+func writeTestDescriptorSet(t *testing.T) string {
+	set := &descriptorpb.FileDescriptorSet{
+		File: []*descriptorpb.FileDescriptorProto{
+			protodesc.ToFileDescriptorProto(timestamppb.File_google_protobuf_timestamp_proto),
+			testFileDescriptorProto(),
+		},
+	}
+	raw, err := proto.Marshal(set)
+	require.NoError(t, err)
+	path := filepath.Join(t.TempDir(), "log.pb")
+	require.NoError(t, os.WriteFile(path, raw, 0600))
+	return path
+}
+
+func TestUnmarshalLogs_descriptorFile(t *testing.T) {
+	ext := newTestExtension(t, &Config{
+		DescriptorFile: writeTestDescriptorSet(t),
+		MessageName:    "example.LogEvent",
+		Mapping:        testMapping,
+	})
+
+	ld, err := ext.UnmarshalLogs(encodeTestMessage(t))
+	require.NoError(t, err)
+	require.Equal(t, 1, ld.LogRecordCount())
+	lr := ld.ResourceLogs().At(0).ScopeLogs().At(0).LogRecords().At(0)
+	assert.Equal(t, "connection refused", lr.Body().Str())
+	assert.Equal(t, testTimestamp, lr.Timestamp().AsTime())
+	assert.Equal(t, map[string]any{
+		"level":     "ERROR",
+		"host.name": "server-1",
+	}, lr.Attributes().AsRaw())
+}
+
+func TestStart_descriptorFileErrors(t *testing.T) {
+	path := writeTestDescriptorSet(t)
+	tests := []struct {
+		name   string
+		config *Config
+		err    string
+	}{
+		{
+			name:   "missing file",
+			config: &Config{DescriptorFile: filepath.Join(t.TempDir(), "missing.pb"), MessageName: "example.LogEvent"},
+			err:    "failed to read descriptor file",
+		},
+		{
+			name:   "unknown message",
+			config: &Config{DescriptorFile: path, MessageName: "example.Missing"},
+			err:    `message "example.Missing"`,
+		},
+		{
+			name:   "not a message",
+			config: &Config{DescriptorFile: path, MessageName: "example.LogEvent.Level"},
+			err:    `"example.LogEvent.Level" is not a message`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ext, err := createExtension(context.Background(), extensiontest.NewNopCreateSettings(), tt.config)
+			require.NoError(t, err)
+			assert.ErrorContains(t, ext.Start(context.Background(), componenttest.NewNopHost()), tt.err)
+		})
+	}
+}
+
+func TestUnmarshalLogs_schemaRegistry(t *testing.T) {
+	serialize := func(fdp *descriptorpb.FileDescriptorProto) string {
+		raw, err := proto.Marshal(fdp)
+		require.NoError(t, err)
+		return base64.StdEncoding.EncodeToString(raw)
+	}
+	var requests []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.URL.Path)
+		assert.Equal(t, "serialized", r.URL.Query().Get("format"))
+		var resp map[string]any
+		switch r.URL.Path {
+		case "/schemas/ids/7":
+			resp = map[string]any{
+				"schema":     serialize(testFileDescriptorProto()),
+				"schemaType": "PROTOBUF",
+				"references": []map[string]any{
+					{"name": "google/protobuf/timestamp.proto", "subject": "timestamp", "version": 1},
+				},
+			}
+		case "/subjects/timestamp/versions/1":
+			resp = map[string]any{
+				"schema":     serialize(protodesc.ToFileDescriptorProto(timestamppb.File_google_protobuf_timestamp_proto)),
+				"schemaType": "PROTOBUF",
+			}
+		case "/schemas/ids/8":
+			resp = map[string]any{"schema": `{"type":"string"}`}
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		require.NoError(t, json.NewEncoder(w).Encode(resp))
+	}))
+	defer srv.Close()
+
+	ext := newTestExtension(t, &Config{
+		SchemaRegistry: &confighttp.HTTPClientSettings{Endpoint: srv.URL},
+		Mapping:        testMapping,
+	})
+
+	msg := []byte{0}
+	msg = binary.BigEndian.AppendUint32(msg, 7)
+	// message indexes [1], example.LogEvent
+	msg = append(msg, 2, 2)
+	msg = append(msg, encodeTestMessage(t)...)
+	for i := 0; i < 2; i++ {
+		ld, err := ext.UnmarshalLogs(msg)
+		require.NoError(t, err)
+		lr := ld.ResourceLogs().At(0).ScopeLogs().At(0).LogRecords().At(0)
+		assert.Equal(t, "connection refused", lr.Body().Str())
+		assert.Equal(t, testTimestamp, lr.Timestamp().AsTime())
+	}
+	assert.Equal(t, []string{"/schemas/ids/7", "/subjects/timestamp/versions/1"}, requests)
+
+	msg[4] = 8
+	_, err := ext.UnmarshalLogs(msg)
+	assert.EqualError(t, err, "schema 8 is of type AVRO, expected PROTOBUF")
+}
@@ -0,0 +1,164 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package protobuflogencodingextension // import "github.com/open-telemetry/opentelemetry-collector-contrib/extension/encoding/protobuflogencodingextension"
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"time"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/dynamicpb"
+
+	// Well-known types are commonly imported without being registered as references.
+	_ "google.golang.org/protobuf/types/known/anypb"
+	_ "google.golang.org/protobuf/types/known/durationpb"
+	_ "google.golang.org/protobuf/types/known/structpb"
+	_ "google.golang.org/protobuf/types/known/timestamppb"
+	_ "google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+var errInvalidMessageIndexes = errors.New("invalid message indexes")
+
+// readMessageIndexes reads the message indexes that follow the schema ID in the
+// Confluent Protobuf wire format. They are the path to the message type in the
+// file, starting from the top level messages.
+func readMessageIndexes(buf []byte) ([]int, []byte, error) {
+	count, n := binary.Varint(buf)
+	if n <= 0 || count < 0 {
+		return nil, nil, errInvalidMessageIndexes
+	}
+	buf = buf[n:]
+	if count == 0 {
+		// Shortcut for the first message of the file.
+		return []int{0}, buf, nil
+	}
+	indexes := make([]int, count)
+	for i := range indexes {
+		index, n := binary.Varint(buf)
+		if n <= 0 || index < 0 {
+			return nil, nil, errInvalidMessageIndexes
+		}
+		indexes[i] = int(index)
+		buf = buf[n:]
+	}
+	return indexes, buf, nil
+}
+
+// messageByIndexes returns the message descriptor at the given path in the file.
+func messageByIndexes(fd protoreflect.FileDescriptor, indexes []int) (protoreflect.MessageDescriptor, error) {
+	messages := fd.Messages()
+	var md protoreflect.MessageDescriptor
+	for _, index := range indexes {
+		if index >= messages.Len() {
+			return nil, fmt.Errorf("message index %v not found in %s", indexes, fd.Path())
+		}
+		md = messages.Get(index)
+		messages = md.Messages()
+	}
+	return md, nil
+}
+
+// fallbackResolver resolves descriptors from files, falling back to the
+// files registered globally, which include the well-known types.
+type fallbackResolver struct {
+	files *protoregistry.Files
+}
+
+func (r fallbackResolver) FindFileByPath(path string) (protoreflect.FileDescriptor, error) {
+	if fd, err := r.files.FindFileByPath(path); err == nil {
+		return fd, nil
+	}
+	return protoregistry.GlobalFiles.FindFileByPath(path)
+}
+
+func (r fallbackResolver) FindDescriptorByName(name protoreflect.FullName) (protoreflect.Descriptor, error) {
+	if d, err := r.files.FindDescriptorByName(name); err == nil {
+		return d, nil
+	}
+	return protoregistry.GlobalFiles.FindDescriptorByName(name)
+}
+
+// decodeMessage unmarshals buf as a message of type md and converts it to a map.
+func decodeMessage(md protoreflect.MessageDescriptor, buf []byte) (map[string]any, error) {
+	msg := dynamicpb.NewMessage(md)
+	if err := proto.Unmarshal(buf, msg); err != nil {
+		return nil, err
+	}
+	return messageToMap(msg), nil
+}
+
+// messageToMap converts a message into a map keyed by field name. Fields with
+// presence are only included when set, other fields are always included.
+func messageToMap(msg protoreflect.Message) map[string]any {
+	fields := msg.Descriptor().Fields()
+	m := make(map[string]any, fields.Len())
+	for i := 0; i < fields.Len(); i++ {
+		fd := fields.Get(i)
+		if !msg.Has(fd) && (fd.HasPresence() || fd.IsList() || fd.IsMap()) {
+			continue
+		}
+		m[string(fd.Name())] = fieldValue(fd, msg.Get(fd))
+	}
+	return m
+}
+
+func fieldValue(fd protoreflect.FieldDescriptor, v protoreflect.Value) any {
+	switch {
+	case fd.IsList():
+		list := v.List()
+		s := make([]any, list.Len())
+		for i := range s {
+			s[i] = singularValue(fd, list.Get(i))
+		}
+		return s
+	case fd.IsMap():
+		m := make(map[string]any, v.Map().Len())
+		v.Map().Range(func(k protoreflect.MapKey, mv protoreflect.Value) bool {
+			m[k.String()] = singularValue(fd.MapValue(), mv)
+			return true
+		})
+		return m
+	default:
+		return singularValue(fd, v)
+	}
+}
+
+func singularValue(fd protoreflect.FieldDescriptor, v protoreflect.Value) any {
+	switch fd.Kind() {
+	case protoreflect.BoolKind:
+		return v.Bool()
+	case protoreflect.Int32Kind, protoreflect.Sint32Kind, protoreflect.Sfixed32Kind,
+		protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Sfixed64Kind:
+		return v.Int()
+	case protoreflect.Uint32Kind, protoreflect.Fixed32Kind,
+		protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
+		return v.Uint()
+	case protoreflect.FloatKind, protoreflect.DoubleKind:
+		return v.Float()
+	case protoreflect.StringKind:
+		return v.String()
+	case protoreflect.BytesKind:
+		return v.Bytes()
+	case protoreflect.EnumKind:
+		if ev := fd.Enum().Values().ByNumber(v.Enum()); ev != nil {
+			return string(ev.Name())
+		}
+		return int64(v.Enum())
+	case protoreflect.MessageKind, protoreflect.GroupKind:
+		msg := v.Message()
+		if msg.Descriptor().FullName() == "google.protobuf.Timestamp" {
+			fields := msg.Descriptor().Fields()
+			seconds := msg.Get(fields.ByName("seconds")).Int()
+			nanos := msg.Get(fields.ByName("nanos")).Int()
+			return time.Unix(seconds, nanos).UTC()
+		}
+		return messageToMap(msg)
+	default:
+		return nil
+	}
+}
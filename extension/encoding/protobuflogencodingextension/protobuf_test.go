@@ -0,0 +1,201 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package protobuflogencodingextension
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/dynamicpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+var testTimestamp = time.Date(2023, 12, 1, 10, 0, 0, 0, time.UTC)
+
+// testFileDescriptorProto describes:
+//
+//	syntax = "proto3";
+//	package example;
+//	import "google/protobuf/timestamp.proto";
+//	message Other {}
+//	message LogEvent {
+//	  enum Level { UNKNOWN = 0; INFO = 1; ERROR = 2; }
+//	  message Host { string name = 1; }
+//	  string message = 1;
+//	  google.protobuf.Timestamp time = 2;
+//	  Level level = 3;
+//	  map<string, string> labels = 4;
+//	  repeated string tags = 5;
+//	  Host host = 6;
+//	  int64 count = 7;
+//	}
+func testFileDescriptorProto() *descriptorpb.FileDescriptorProto {
+	field := func(name string, number int32, typ descriptorpb.FieldDescriptorProto_Type, label descriptorpb.FieldDescriptorProto_Label, typeName string) *descriptorpb.FieldDescriptorProto {
+		f := &descriptorpb.FieldDescriptorProto{
+			Name:     proto.String(name),
+			JsonName: proto.String(name),
+			Number:   proto.Int32(number),
+			Type:     typ.Enum(),
+			Label:    label.Enum(),
+		}
+		if typeName != "" {
+			f.TypeName = proto.String(typeName)
+		}
+		return f
+	}
+	optional := descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL
+	repeated := descriptorpb.FieldDescriptorProto_LABEL_REPEATED
+	return &descriptorpb.FileDescriptorProto{
+		Name:       proto.String("log.proto"),
+		Package:    proto.String("example"),
+		Syntax:     proto.String("proto3"),
+		Dependency: []string{"google/protobuf/timestamp.proto"},
+		MessageType: []*descriptorpb.DescriptorProto{
+			{Name: proto.String("Other")},
+			{
+				Name: proto.String("LogEvent"),
+				Field: []*descriptorpb.FieldDescriptorProto{
+					field("message", 1, descriptorpb.FieldDescriptorProto_TYPE_STRING, optional, ""),
+					field("time", 2, descriptorpb.FieldDescriptorProto_TYPE_MESSAGE, optional, ".google.protobuf.Timestamp"),
+					field("level", 3, descriptorpb.FieldDescriptorProto_TYPE_ENUM, optional, ".example.LogEvent.Level"),
+					field("labels", 4, descriptorpb.FieldDescriptorProto_TYPE_MESSAGE, repeated, ".example.LogEvent.LabelsEntry"),
+					field("tags", 5, descriptorpb.FieldDescriptorProto_TYPE_STRING, repeated, ""),
+					field("host", 6, descriptorpb.FieldDescriptorProto_TYPE_MESSAGE, optional, ".example.LogEvent.Host"),
+					field("count", 7, descriptorpb.FieldDescriptorProto_TYPE_INT64, optional, ""),
+				},
+				NestedType: []*descriptorpb.DescriptorProto{
+					{
+						Name: proto.String("Host"),
+						Field: []*descriptorpb.FieldDescriptorProto{
+							field("name", 1, descriptorpb.FieldDescriptorProto_TYPE_STRING, optional, ""),
+						},
+					},
+					{
+						Name: proto.String("LabelsEntry"),
+						Field: []*descriptorpb.FieldDescriptorProto{
+							field("key", 1, descriptorpb.FieldDescriptorProto_TYPE_STRING, optional, ""),
+							field("value", 2, descriptorpb.FieldDescriptorProto_TYPE_STRING, optional, ""),
+						},
+						Options: &descriptorpb.MessageOptions{MapEntry: proto.Bool(true)},
+					},
+				},
+				EnumType: []*descriptorpb.EnumDescriptorProto{
+					{
+						Name: proto.String("Level"),
+						Value: []*descriptorpb.EnumValueDescriptorProto{
+							{Name: proto.String("UNKNOWN"), Number: proto.Int32(0)},
+							{Name: proto.String("INFO"), Number: proto.Int32(1)},
+							{Name: proto.String("ERROR"), Number: proto.Int32(2)},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func testMessageDescriptor(t *testing.T) protoreflect.MessageDescriptor {
+	fd, err := protodesc.NewFile(testFileDescriptorProto(), protoregistry.GlobalFiles)
+	require.NoError(t, err)
+	return fd.Messages().ByName("LogEvent")
+}
+
+func encodeTestMessage(t *testing.T) []byte {
+	md := testMessageDescriptor(t)
+	fields := md.Fields()
+	msg := dynamicpb.NewMessage(md)
+	msg.Set(fields.ByName("message"), protoreflect.ValueOfString("connection refused"))
+	msg.Set(fields.ByName("time"), protoreflect.ValueOfMessage(timestamppb.New(testTimestamp).ProtoReflect()))
+	msg.Set(fields.ByName("level"), protoreflect.ValueOfEnum(2))
+	labels := msg.Mutable(fields.ByName("labels")).Map()
+	labels.Set(protoreflect.ValueOfString("env").MapKey(), protoreflect.ValueOfString("prod"))
+	tags := msg.Mutable(fields.ByName("tags")).List()
+	tags.Append(protoreflect.ValueOfString("a"))
+	tags.Append(protoreflect.ValueOfString("b"))
+	host := msg.Mutable(fields.ByName("host")).Message()
+	host.Set(host.Descriptor().Fields().ByName("name"), protoreflect.ValueOfString("server-1"))
+
+	buf, err := proto.Marshal(msg)
+	require.NoError(t, err)
+	return buf
+}
+
+func TestDecodeMessage(t *testing.T) {
+	record, err := decodeMessage(testMessageDescriptor(t), encodeTestMessage(t))
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{
+		"message": "connection refused",
+		"time":    testTimestamp,
+		"level":   "ERROR",
+		"labels":  map[string]any{"env": "prod"},
+		"tags":    []any{"a", "b"},
+		"host":    map[string]any{"name": "server-1"},
+		"count":   int64(0),
+	}, record)
+}
+
+func TestReadMessageIndexes(t *testing.T) {
+	tests := []struct {
+		name    string
+		buf     []byte
+		indexes []int
+		err     error
+	}{
+		{
+			name:    "first message",
+			buf:     []byte{0, 'x'},
+			indexes: []int{0},
+		},
+		{
+			name:    "nested message",
+			buf:     []byte{4, 2, 0, 'x'},
+			indexes: []int{1, 0},
+		},
+		{
+			name: "empty",
+			buf:  []byte{},
+			err:  errInvalidMessageIndexes,
+		},
+		{
+			name: "truncated",
+			buf:  []byte{4, 2},
+			err:  errInvalidMessageIndexes,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			indexes, rest, err := readMessageIndexes(tt.buf)
+			if tt.err != nil {
+				assert.ErrorIs(t, err, tt.err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.indexes, indexes)
+			assert.Equal(t, []byte{'x'}, rest)
+		})
+	}
+}
+
+func TestMessageByIndexes(t *testing.T) {
+	fd, err := protodesc.NewFile(testFileDescriptorProto(), protoregistry.GlobalFiles)
+	require.NoError(t, err)
+
+	md, err := messageByIndexes(fd, []int{1})
+	require.NoError(t, err)
+	assert.Equal(t, protoreflect.FullName("example.LogEvent"), md.FullName())
+
+	md, err = messageByIndexes(fd, []int{1, 0})
+	require.NoError(t, err)
+	assert.Equal(t, protoreflect.FullName("example.LogEvent.Host"), md.FullName())
+
+	_, err = messageByIndexes(fd, []int{2})
+	assert.EqualError(t, err, "message index [2] not found in log.proto")
+}
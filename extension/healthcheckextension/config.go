@@ -9,6 +9,7 @@ import (
 	"time"
 
 	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/config/configgrpc"
 	"go.opentelemetry.io/collector/config/confighttp"
 )
 
@@ -37,6 +38,30 @@ type Config struct {
 
 	// CheckCollectorPipeline contains the list of settings of collector pipeline health check
 	CheckCollectorPipeline checkCollectorPipelineSettings `mapstructure:"check_collector_pipeline"`
+
+	// ComponentHealth contains the settings of the health reporting based on
+	// the status events of the components.
+	ComponentHealth ComponentHealthSettings `mapstructure:"component_health"`
+}
+
+// ComponentHealthSettings configures the health reporting based on the status
+// events of the components, aggregated per pipeline.
+type ComponentHealthSettings struct {
+	// Enabled indicates whether to serve the component health or not.
+	Enabled bool `mapstructure:"enabled"`
+
+	// Path is the path of the HTTP endpoint serving the component health as JSON.
+	// The default path is "/status".
+	Path string `mapstructure:"path"`
+
+	// RecoveryDuration is how long a component that recovers from an error
+	// has to stay OK before it is reported healthy again. The default is 0,
+	// reporting it healthy as soon as it recovers.
+	RecoveryDuration time.Duration `mapstructure:"recovery_duration"`
+
+	// GRPC configures a gRPC server exposing the grpc.health.v1.Health service.
+	// It is disabled if not set.
+	GRPC *configgrpc.GRPCServerSettings `mapstructure:"grpc"`
 }
 
 var _ component.Config = (*Config)(nil)
@@ -44,6 +69,9 @@ var (
 	errNoEndpointProvided                      = errors.New("bad config: endpoint must be specified")
 	errInvalidExporterFailureThresholdProvided = errors.New("bad config: exporter_failure_threshold expects a positive number")
 	errInvalidPath                             = errors.New("bad config: path must start with /")
+	errInvalidComponentHealthPath              = errors.New("bad config: component_health::path must start with / and be different from path")
+	errInvalidRecoveryDuration                 = errors.New("bad config: component_health::recovery_duration must not be negative")
+	errNoGRPCEndpointProvided                  = errors.New("bad config: component_health::grpc::endpoint must be specified")
 )
 
 // Validate checks if the extension configuration is valid
@@ -61,6 +89,17 @@ func (cfg *Config) Validate() error {
 	if !strings.HasPrefix(cfg.Path, "/") {
 		return errInvalidPath
 	}
+	if cfg.ComponentHealth.Enabled {
+		if !strings.HasPrefix(cfg.ComponentHealth.Path, "/") || cfg.ComponentHealth.Path == cfg.Path {
+			return errInvalidComponentHealthPath
+		}
+		if cfg.ComponentHealth.RecoveryDuration < 0 {
+			return errInvalidRecoveryDuration
+		}
+		if cfg.ComponentHealth.GRPC != nil && cfg.ComponentHealth.GRPC.NetAddr.Endpoint == "" {
+			return errNoGRPCEndpointProvided
+		}
+	}
 	return nil
 }
 
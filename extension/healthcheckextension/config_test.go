@@ -6,11 +6,14 @@ package healthcheckextension
 import (
 	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/config/configgrpc"
 	"go.opentelemetry.io/collector/config/confighttp"
+	"go.opentelemetry.io/collector/config/confignet"
 	"go.opentelemetry.io/collector/config/configtls"
 	"go.opentelemetry.io/collector/confmap/confmaptest"
 
@@ -45,8 +48,40 @@ func TestLoadConfig(t *testing.T) {
 				CheckCollectorPipeline: defaultCheckCollectorPipelineSettings(),
 				Path:                   "/",
 				ResponseBody:           nil,
+				ComponentHealth: ComponentHealthSettings{
+					Path: defaultComponentHealthPath,
+				},
 			},
 		},
+		{
+			id: component.NewIDWithName(metadata.Type, "componenthealth"),
+			expected: &Config{
+				HTTPServerSettings: confighttp.HTTPServerSettings{
+					Endpoint: "localhost:13",
+				},
+				CheckCollectorPipeline: defaultCheckCollectorPipelineSettings(),
+				Path:                   "/",
+				ComponentHealth: ComponentHealthSettings{
+					Enabled:          true,
+					Path:             "/health/components",
+					RecoveryDuration: time.Minute,
+					GRPC: &configgrpc.GRPCServerSettings{
+						NetAddr: confignet.NetAddr{
+							Endpoint:  "localhost:14",
+							Transport: "tcp",
+						},
+					},
+				},
+			},
+		},
+		{
+			id:          component.NewIDWithName(metadata.Type, "invalidcomponenthealthpath"),
+			expectedErr: errInvalidComponentHealthPath,
+		},
+		{
+			id:          component.NewIDWithName(metadata.Type, "missinggrpcendpoint"),
+			expectedErr: errNoGRPCEndpointProvided,
+		},
 		{
 			id:          component.NewIDWithName(metadata.Type, "missingendpoint"),
 			expectedErr: errNoEndpointProvided,
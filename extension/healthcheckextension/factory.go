@@ -17,6 +17,8 @@ const (
 	// Use 0.0.0.0 to make the health check endpoint accessible
 	// in container orchestration environments like Kubernetes.
 	defaultEndpoint = "0.0.0.0:13133"
+
+	defaultComponentHealthPath = "/status"
 )
 
 // NewFactory creates a factory for HealthCheck extension.
@@ -36,6 +38,9 @@ func createDefaultConfig() component.Config {
 		},
 		CheckCollectorPipeline: defaultCheckCollectorPipelineSettings(),
 		Path:                   "/",
+		ComponentHealth: ComponentHealthSettings{
+			Path: defaultComponentHealthPath,
+		},
 	}
 }
 
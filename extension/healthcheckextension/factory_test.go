@@ -24,6 +24,9 @@ func TestFactory_CreateDefaultConfig(t *testing.T) {
 		},
 		CheckCollectorPipeline: defaultCheckCollectorPipelineSettings(),
 		Path:                   "/",
+		ComponentHealth: ComponentHealthSettings{
+			Path: defaultComponentHealthPath,
+		},
 	}, cfg)
 
 	assert.NoError(t, componenttest.CheckConfigStruct(cfg))
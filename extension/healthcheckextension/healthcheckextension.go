@@ -14,21 +14,28 @@ import (
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/extension"
 	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
 
 	"github.com/open-telemetry/opentelemetry-collector-contrib/extension/healthcheckextension/internal/healthcheck"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/extension/healthcheckextension/internal/status"
 )
 
 type healthCheckExtension struct {
-	config   Config
-	logger   *zap.Logger
-	state    *healthcheck.HealthCheck
-	server   *http.Server
-	stopCh   chan struct{}
-	exporter *healthCheckExporter
-	settings component.TelemetrySettings
+	config     Config
+	logger     *zap.Logger
+	state      *healthcheck.HealthCheck
+	server     *http.Server
+	stopCh     chan struct{}
+	exporter   *healthCheckExporter
+	settings   component.TelemetrySettings
+	aggregator *status.Aggregator
+	grpcServer *grpc.Server
+	grpcStopCh chan struct{}
 }
 
 var _ extension.PipelineWatcher = (*healthCheckExtension)(nil)
+var _ extension.StatusWatcher = (*healthCheckExtension)(nil)
 
 func (hc *healthCheckExtension) Start(_ context.Context, host component.Host) error {
 
@@ -47,6 +54,7 @@ func (hc *healthCheckExtension) Start(_ context.Context, host component.Host) er
 		// Mount HC handler
 		mux := http.NewServeMux()
 		mux.Handle(hc.config.Path, hc.baseHandler())
+		hc.handleComponentHealth(mux)
 		hc.server.Handler = mux
 		hc.stopCh = make(chan struct{})
 		go func() {
@@ -72,6 +80,7 @@ func (hc *healthCheckExtension) Start(_ context.Context, host component.Host) er
 
 		mux := http.NewServeMux()
 		mux.Handle(hc.config.Path, hc.checkCollectorPipelineHandler())
+		hc.handleComponentHealth(mux)
 		hc.server.Handler = mux
 		hc.stopCh = make(chan struct{})
 		go func() {
@@ -96,6 +105,38 @@ func (hc *healthCheckExtension) Start(_ context.Context, host component.Host) er
 		}()
 	}
 
+	return hc.startGRPC(host)
+}
+
+// handleComponentHealth mounts the component health handler, if enabled.
+func (hc *healthCheckExtension) handleComponentHealth(mux *http.ServeMux) {
+	if hc.aggregator != nil {
+		mux.Handle(hc.config.ComponentHealth.Path, hc.aggregator.Handler())
+	}
+}
+
+// startGRPC starts the gRPC server exposing the component health, if configured.
+func (hc *healthCheckExtension) startGRPC(host component.Host) error {
+	if hc.aggregator == nil || hc.config.ComponentHealth.GRPC == nil {
+		return nil
+	}
+	ln, err := hc.config.ComponentHealth.GRPC.ToListener()
+	if err != nil {
+		return fmt.Errorf("failed to bind to address %s: %w", hc.config.ComponentHealth.GRPC.NetAddr.Endpoint, err)
+	}
+	hc.grpcServer, err = hc.config.ComponentHealth.GRPC.ToServer(host, hc.settings)
+	if err != nil {
+		_ = ln.Close()
+		return err
+	}
+	healthpb.RegisterHealthServer(hc.grpcServer, status.NewHealthServer(hc.aggregator))
+	hc.grpcStopCh = make(chan struct{})
+	go func() {
+		defer close(hc.grpcStopCh)
+		if errGRPC := hc.grpcServer.Serve(ln); !errors.Is(errGRPC, grpc.ErrServerStopped) && errGRPC != nil {
+			host.ReportFatalError(errGRPC)
+		}
+	}()
 	return nil
 }
 
@@ -137,6 +178,10 @@ func (hc *healthCheckExtension) check() bool {
 }
 
 func (hc *healthCheckExtension) Shutdown(context.Context) error {
+	if hc.grpcServer != nil {
+		hc.grpcServer.Stop()
+		<-hc.grpcStopCh
+	}
 	if hc.server == nil {
 		return nil
 	}
@@ -157,6 +202,14 @@ func (hc *healthCheckExtension) NotReady() error {
 	return nil
 }
 
+// ComponentStatusChanged records the status events used to report the
+// component health.
+func (hc *healthCheckExtension) ComponentStatusChanged(source *component.InstanceID, event *component.StatusEvent) {
+	if hc.aggregator != nil {
+		hc.aggregator.RecordStatus(source, event)
+	}
+}
+
 func newServer(config Config, settings component.TelemetrySettings) *healthCheckExtension {
 	hc := &healthCheckExtension{
 		config:   config,
@@ -164,6 +217,9 @@ func newServer(config Config, settings component.TelemetrySettings) *healthCheck
 		state:    healthcheck.New(),
 		settings: settings,
 	}
+	if config.ComponentHealth.Enabled {
+		hc.aggregator = status.NewAggregator(config.ComponentHealth.RecoveryDuration)
+	}
 
 	hc.state.SetLogger(settings.Logger)
 
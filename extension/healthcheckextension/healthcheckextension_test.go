@@ -5,6 +5,7 @@ package healthcheckextension
 
 import (
 	"context"
+	"errors"
 	"io"
 	"net"
 	"net/http"
@@ -17,7 +18,12 @@ import (
 	"go.opencensus.io/stats/view"
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/config/configgrpc"
 	"go.opentelemetry.io/collector/config/confighttp"
+	"go.opentelemetry.io/collector/config/confignet"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
 
 	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/common/testutil"
 )
@@ -344,6 +350,66 @@ func TestHealthCheckExtensionUsage(t *testing.T) {
 	}
 }
 
+func TestHealthCheckExtensionComponentHealth(t *testing.T) {
+	endpoint := testutil.GetAvailableLocalAddress(t)
+	grpcEndpoint := testutil.GetAvailableLocalAddress(t)
+	config := Config{
+		HTTPServerSettings: confighttp.HTTPServerSettings{
+			Endpoint: endpoint,
+		},
+		CheckCollectorPipeline: defaultCheckCollectorPipelineSettings(),
+		Path:                   "/",
+		ComponentHealth: ComponentHealthSettings{
+			Enabled: true,
+			Path:    "/status",
+			GRPC: &configgrpc.GRPCServerSettings{
+				NetAddr: confignet.NetAddr{
+					Endpoint:  grpcEndpoint,
+					Transport: "tcp",
+				},
+			},
+		},
+	}
+	hcExt := newServer(config, componenttest.NewNopTelemetrySettings())
+	require.NotNil(t, hcExt)
+
+	require.NoError(t, hcExt.Start(context.Background(), componenttest.NewNopHost()))
+	t.Cleanup(func() { require.NoError(t, hcExt.Shutdown(context.Background())) })
+
+	// Give a chance for the server goroutine to run.
+	runtime.Gosched()
+	require.Eventually(t, ensureServerRunning(endpoint), 30*time.Second, 1*time.Second)
+
+	exporter := &component.InstanceID{
+		ID:          component.NewID("otlp"),
+		Kind:        component.KindExporter,
+		PipelineIDs: map[component.ID]struct{}{component.NewID("traces"): {}},
+	}
+	hcExt.ComponentStatusChanged(exporter, component.NewStatusEvent(component.StatusOK))
+
+	client := &http.Client{}
+	resp, err := client.Get("http://" + endpoint + "/status")
+	require.NoError(t, err)
+	require.NoError(t, resp.Body.Close())
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	hcExt.ComponentStatusChanged(exporter, component.NewRecoverableErrorEvent(errors.New("connection refused")))
+	resp, err = client.Get("http://" + endpoint + "/status?pipeline=traces")
+	require.NoError(t, err)
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	require.NoError(t, resp.Body.Close())
+	assert.Equal(t, http.StatusServiceUnavailable, resp.StatusCode)
+	assert.Contains(t, string(body), "connection refused")
+
+	conn, err := grpc.Dial(grpcEndpoint, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	defer conn.Close()
+	check, err := healthpb.NewHealthClient(conn).Check(context.Background(), &healthpb.HealthCheckRequest{Service: "traces"})
+	require.NoError(t, err)
+	assert.Equal(t, healthpb.HealthCheckResponse_NOT_SERVING, check.Status)
+}
+
 func TestHealthCheckExtensionPortAlreadyInUse(t *testing.T) {
 	endpoint := testutil.GetAvailableLocalAddress(t)
 
@@ -0,0 +1,219 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+// Package status aggregates the status events reported by the collector
+// components into the health of each pipeline and of the collector.
+package status // import "github.com/open-telemetry/opentelemetry-collector-contrib/extension/healthcheckextension/internal/status"
+
+import (
+	"strings"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/collector/component"
+)
+
+// ExtensionsKey groups the components that are not part of any pipeline,
+// i.e. the extensions.
+const ExtensionsKey = "extensions"
+
+// ErrorDetail describes the last error reported by a component.
+type ErrorDetail struct {
+	Error     string    `json:"error"`
+	Status    string    `json:"status"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// Health is the health of a component, a pipeline or the collector.
+type Health struct {
+	Healthy bool `json:"healthy"`
+	// Recovering is set when the status is OK but the recovery duration
+	// since the last error has not elapsed yet.
+	Recovering bool               `json:"recovering,omitempty"`
+	Status     string             `json:"status"`
+	Timestamp  time.Time          `json:"timestamp"`
+	LastError  *ErrorDetail       `json:"last_error,omitempty"`
+	Components map[string]*Health `json:"components,omitempty"`
+	Pipelines  map[string]*Health `json:"pipelines,omitempty"`
+	event      *component.StatusEvent
+	lastError  *component.StatusEvent
+}
+
+type componentStatus struct {
+	event     *component.StatusEvent
+	lastError *component.StatusEvent
+}
+
+// Aggregator keeps the latest status event of every component, grouped by
+// pipeline, and derives the health of the pipelines and of the collector.
+type Aggregator struct {
+	recoveryDuration time.Duration
+	now              func() time.Time
+
+	mu          sync.RWMutex
+	pipelines   map[string]map[string]*componentStatus
+	subscribers map[chan struct{}]struct{}
+}
+
+// NewAggregator creates an aggregator. Components that recover from an error
+// are reported healthy once they have been OK for recoveryDuration.
+func NewAggregator(recoveryDuration time.Duration) *Aggregator {
+	return &Aggregator{
+		recoveryDuration: recoveryDuration,
+		now:              time.Now,
+		pipelines:        make(map[string]map[string]*componentStatus),
+		subscribers:      make(map[chan struct{}]struct{}),
+	}
+}
+
+// RecordStatus records the status event of the source component in all its pipelines.
+func (a *Aggregator) RecordStatus(source *component.InstanceID, event *component.StatusEvent) {
+	key := strings.ToLower(source.Kind.String()) + ":" + source.ID.String()
+	pipelines := make([]string, 0, len(source.PipelineIDs))
+	for id := range source.PipelineIDs {
+		pipelines = append(pipelines, id.String())
+	}
+	if len(pipelines) == 0 {
+		pipelines = append(pipelines, ExtensionsKey)
+	}
+
+	a.mu.Lock()
+	for _, pipeline := range pipelines {
+		components, ok := a.pipelines[pipeline]
+		if !ok {
+			components = make(map[string]*componentStatus)
+			a.pipelines[pipeline] = components
+		}
+		cs, ok := components[key]
+		if !ok {
+			cs = &componentStatus{}
+			components[key] = cs
+		}
+		cs.event = event
+		if component.StatusIsError(event.Status()) {
+			cs.lastError = event
+		}
+	}
+	for ch := range a.subscribers {
+		select {
+		case ch <- struct{}{}:
+		default:
+		}
+	}
+	a.mu.Unlock()
+}
+
+// Subscribe returns a channel notified when a status event is recorded, and
+// a function to cancel the subscription.
+func (a *Aggregator) Subscribe() (<-chan struct{}, func()) {
+	ch := make(chan struct{}, 1)
+	a.mu.Lock()
+	a.subscribers[ch] = struct{}{}
+	a.mu.Unlock()
+	return ch, func() {
+		a.mu.Lock()
+		delete(a.subscribers, ch)
+		a.mu.Unlock()
+	}
+}
+
+// Collector returns the health of the collector, including the details of
+// every pipeline. The collector is healthy if all of its pipelines are.
+func (a *Aggregator) Collector() *Health {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	now := a.now()
+	health := &Health{
+		Healthy:   len(a.pipelines) > 0,
+		Pipelines: make(map[string]*Health, len(a.pipelines)),
+	}
+	events := make(map[string]*component.StatusEvent, len(a.pipelines))
+	for name, components := range a.pipelines {
+		pipeline := a.pipelineHealth(components, now)
+		health.Pipelines[name] = pipeline
+		events[name] = pipeline.event
+		health.merge(pipeline)
+	}
+	health.setStatus(events)
+	return health
+}
+
+// Pipeline returns the health of the named pipeline, including the details
+// of its components. Components not part of any pipeline are grouped under
+// ExtensionsKey.
+func (a *Aggregator) Pipeline(name string) (*Health, bool) {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	components, ok := a.pipelines[name]
+	if !ok {
+		return nil, false
+	}
+	return a.pipelineHealth(components, a.now()), true
+}
+
+func (a *Aggregator) pipelineHealth(components map[string]*componentStatus, now time.Time) *Health {
+	health := &Health{
+		Healthy:    true,
+		Components: make(map[string]*Health, len(components)),
+	}
+	events := make(map[string]*component.StatusEvent, len(components))
+	for key, cs := range components {
+		c := a.componentHealth(cs, now)
+		health.Components[key] = c
+		events[key] = cs.event
+		health.merge(c)
+	}
+	health.setStatus(events)
+	return health
+}
+
+func (a *Aggregator) componentHealth(cs *componentStatus, now time.Time) *Health {
+	health := &Health{
+		Status:    cs.event.Status().String(),
+		Timestamp: cs.event.Timestamp(),
+		event:     cs.event,
+		lastError: cs.lastError,
+	}
+	if cs.event.Status() == component.StatusOK {
+		health.Recovering = cs.lastError != nil && now.Sub(cs.event.Timestamp()) < a.recoveryDuration
+		health.Healthy = !health.Recovering
+	}
+	if cs.lastError != nil {
+		health.LastError = newErrorDetail(cs.lastError)
+	}
+	return health
+}
+
+// merge combines the health of a child into h.
+func (h *Health) merge(child *Health) {
+	h.Healthy = h.Healthy && child.Healthy
+	h.Recovering = h.Recovering || child.Recovering
+	if child.lastError != nil && (h.lastError == nil || h.lastError.Timestamp().Before(child.lastError.Timestamp())) {
+		h.lastError = child.lastError
+		h.LastError = newErrorDetail(child.lastError)
+	}
+}
+
+// setStatus sets the status and timestamp of h from the aggregated events of its children.
+func (h *Health) setStatus(events map[string]*component.StatusEvent) {
+	if len(events) == 0 {
+		h.Status = component.StatusNone.String()
+		return
+	}
+	h.event = component.AggregateStatusEvent(events)
+	h.Status = h.event.Status().String()
+	h.Timestamp = h.event.Timestamp()
+}
+
+func newErrorDetail(event *component.StatusEvent) *ErrorDetail {
+	detail := &ErrorDetail{
+		Status:    event.Status().String(),
+		Timestamp: event.Timestamp(),
+	}
+	if event.Err() != nil {
+		detail.Error = event.Err().Error()
+	}
+	return detail
+}
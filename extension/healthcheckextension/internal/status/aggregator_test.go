@@ -0,0 +1,124 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package status
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component"
+)
+
+func newInstanceID(kind component.Kind, id string, pipelines ...string) *component.InstanceID {
+	instance := &component.InstanceID{
+		ID:          component.NewID(component.Type(id)),
+		Kind:        kind,
+		PipelineIDs: make(map[component.ID]struct{}),
+	}
+	for _, p := range pipelines {
+		instance.PipelineIDs[component.NewID(component.Type(p))] = struct{}{}
+	}
+	return instance
+}
+
+func TestAggregator_empty(t *testing.T) {
+	a := NewAggregator(0)
+	health := a.Collector()
+	assert.False(t, health.Healthy)
+	assert.Equal(t, "StatusNone", health.Status)
+
+	_, ok := a.Pipeline("traces")
+	assert.False(t, ok)
+}
+
+func TestAggregator(t *testing.T) {
+	a := NewAggregator(0)
+	receiver := newInstanceID(component.KindReceiver, "otlp", "traces", "metrics")
+	exporter := newInstanceID(component.KindExporter, "otlp", "traces")
+	ext := newInstanceID(component.KindExtension, "health_check")
+
+	for _, source := range []*component.InstanceID{receiver, exporter, ext} {
+		a.RecordStatus(source, component.NewStatusEvent(component.StatusStarting))
+	}
+	assert.False(t, a.Collector().Healthy)
+
+	for _, source := range []*component.InstanceID{receiver, exporter, ext} {
+		a.RecordStatus(source, component.NewStatusEvent(component.StatusOK))
+	}
+	health := a.Collector()
+	assert.True(t, health.Healthy)
+	assert.Equal(t, "StatusOK", health.Status)
+	assert.Len(t, health.Pipelines, 3)
+	assert.Contains(t, health.Pipelines, ExtensionsKey)
+	assert.Contains(t, health.Pipelines["traces"].Components, "receiver:otlp")
+	assert.Contains(t, health.Pipelines["traces"].Components, "exporter:otlp")
+
+	failure := component.NewRecoverableErrorEvent(errors.New("connection refused"))
+	a.RecordStatus(exporter, failure)
+	health = a.Collector()
+	assert.False(t, health.Healthy)
+	assert.Equal(t, "StatusRecoverableError", health.Status)
+	require.NotNil(t, health.LastError)
+	assert.Equal(t, "connection refused", health.LastError.Error)
+	assert.Equal(t, failure.Timestamp(), health.LastError.Timestamp)
+	assert.False(t, health.Pipelines["traces"].Healthy)
+	assert.True(t, health.Pipelines["metrics"].Healthy)
+
+	traces, ok := a.Pipeline("traces")
+	require.True(t, ok)
+	assert.False(t, traces.Healthy)
+	assert.Equal(t, "StatusRecoverableError", traces.Components["exporter:otlp"].Status)
+	assert.True(t, traces.Components["receiver:otlp"].Healthy)
+
+	a.RecordStatus(exporter, component.NewStatusEvent(component.StatusOK))
+	health = a.Collector()
+	assert.True(t, health.Healthy)
+	require.NotNil(t, health.LastError, "the last error is kept after recovering")
+	assert.Equal(t, "connection refused", health.LastError.Error)
+}
+
+func TestAggregator_recoveryDuration(t *testing.T) {
+	a := NewAggregator(time.Minute)
+	now := time.Now()
+	a.now = func() time.Time { return now }
+	exporter := newInstanceID(component.KindExporter, "otlp", "traces")
+
+	a.RecordStatus(exporter, component.NewStatusEvent(component.StatusOK))
+	assert.True(t, a.Collector().Healthy, "components without errors are healthy right away")
+
+	a.RecordStatus(exporter, component.NewPermanentErrorEvent(errors.New("invalid credentials")))
+	a.RecordStatus(exporter, component.NewStatusEvent(component.StatusOK))
+	health := a.Collector()
+	assert.False(t, health.Healthy)
+	assert.True(t, health.Recovering)
+	assert.Equal(t, "StatusOK", health.Status)
+
+	now = now.Add(2 * time.Minute)
+	health = a.Collector()
+	assert.True(t, health.Healthy)
+	assert.False(t, health.Recovering)
+}
+
+func TestAggregator_subscribe(t *testing.T) {
+	a := NewAggregator(0)
+	events, unsubscribe := a.Subscribe()
+
+	a.RecordStatus(newInstanceID(component.KindReceiver, "otlp", "traces"), component.NewStatusEvent(component.StatusOK))
+	select {
+	case <-events:
+	default:
+		t.Fatal("expected a notification")
+	}
+
+	unsubscribe()
+	a.RecordStatus(newInstanceID(component.KindReceiver, "otlp", "traces"), component.NewStatusEvent(component.StatusOK))
+	select {
+	case <-events:
+		t.Fatal("unexpected notification")
+	default:
+	}
+}
@@ -0,0 +1,85 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package status // import "github.com/open-telemetry/opentelemetry-collector-contrib/extension/healthcheckextension/internal/status"
+
+import (
+	"context"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	grpcstatus "google.golang.org/grpc/status"
+)
+
+// watchInterval is how often Watch re-evaluates the health when no events are
+// recorded, so the end of recovery durations is noticed.
+const watchInterval = time.Second
+
+// HealthServer implements the grpc.health.v1.Health service. The empty
+// service name refers to the collector, any other to the pipeline of that name.
+type HealthServer struct {
+	healthpb.UnimplementedHealthServer
+	aggregator *Aggregator
+}
+
+var _ healthpb.HealthServer = (*HealthServer)(nil)
+
+// NewHealthServer creates a health service backed by aggregator.
+func NewHealthServer(aggregator *Aggregator) *HealthServer {
+	return &HealthServer{aggregator: aggregator}
+}
+
+// Check returns the serving status of the requested service.
+func (s *HealthServer) Check(_ context.Context, req *healthpb.HealthCheckRequest) (*healthpb.HealthCheckResponse, error) {
+	st, ok := s.servingStatus(req.Service)
+	if !ok {
+		return nil, grpcstatus.Errorf(codes.NotFound, "unknown service %q", req.Service)
+	}
+	return &healthpb.HealthCheckResponse{Status: st}, nil
+}
+
+// Watch sends the serving status of the requested service, and then again
+// every time it changes until the client cancels the stream.
+func (s *HealthServer) Watch(req *healthpb.HealthCheckRequest, stream healthpb.Health_WatchServer) error {
+	events, unsubscribe := s.aggregator.Subscribe()
+	defer unsubscribe()
+	ticker := time.NewTicker(watchInterval)
+	defer ticker.Stop()
+
+	// Start from an invalid status so the current one is always sent first.
+	last := healthpb.HealthCheckResponse_ServingStatus(-1)
+	for {
+		// Unknown services are reported as SERVICE_UNKNOWN, as they may appear later.
+		st, _ := s.servingStatus(req.Service)
+		if st != last {
+			if err := stream.Send(&healthpb.HealthCheckResponse{Status: st}); err != nil {
+				return grpcstatus.Errorf(codes.Canceled, "stream has ended: %v", err)
+			}
+			last = st
+		}
+
+		select {
+		case <-events:
+		case <-ticker.C:
+		case <-stream.Context().Done():
+			return grpcstatus.Error(codes.Canceled, "stream has ended")
+		}
+	}
+}
+
+func (s *HealthServer) servingStatus(service string) (healthpb.HealthCheckResponse_ServingStatus, bool) {
+	var health *Health
+	if service == "" {
+		health = s.aggregator.Collector()
+	} else {
+		var ok bool
+		if health, ok = s.aggregator.Pipeline(service); !ok {
+			return healthpb.HealthCheckResponse_SERVICE_UNKNOWN, false
+		}
+	}
+	if health.Healthy {
+		return healthpb.HealthCheckResponse_SERVING, true
+	}
+	return healthpb.HealthCheckResponse_NOT_SERVING, true
+}
@@ -0,0 +1,80 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package status
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	grpcstatus "google.golang.org/grpc/status"
+)
+
+func startHealthServer(t *testing.T, a *Aggregator) healthpb.HealthClient {
+	ln, err := net.Listen("tcp", "localhost:0")
+	require.NoError(t, err)
+	srv := grpc.NewServer()
+	healthpb.RegisterHealthServer(srv, NewHealthServer(a))
+	go func() {
+		_ = srv.Serve(ln)
+	}()
+	t.Cleanup(srv.Stop)
+
+	conn, err := grpc.Dial(ln.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	t.Cleanup(func() { assert.NoError(t, conn.Close()) })
+	return healthpb.NewHealthClient(conn)
+}
+
+func TestHealthServer_Check(t *testing.T) {
+	a := NewAggregator(0)
+	client := startHealthServer(t, a)
+	exporter := newInstanceID(component.KindExporter, "otlp", "traces")
+	a.RecordStatus(exporter, component.NewStatusEvent(component.StatusOK))
+
+	resp, err := client.Check(context.Background(), &healthpb.HealthCheckRequest{})
+	require.NoError(t, err)
+	assert.Equal(t, healthpb.HealthCheckResponse_SERVING, resp.Status)
+
+	a.RecordStatus(exporter, component.NewRecoverableErrorEvent(errors.New("connection refused")))
+	resp, err = client.Check(context.Background(), &healthpb.HealthCheckRequest{Service: "traces"})
+	require.NoError(t, err)
+	assert.Equal(t, healthpb.HealthCheckResponse_NOT_SERVING, resp.Status)
+
+	_, err = client.Check(context.Background(), &healthpb.HealthCheckRequest{Service: "logs"})
+	assert.Equal(t, codes.NotFound, grpcstatus.Code(err))
+}
+
+func TestHealthServer_Watch(t *testing.T) {
+	a := NewAggregator(0)
+	client := startHealthServer(t, a)
+	exporter := newInstanceID(component.KindExporter, "otlp", "traces")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	stream, err := client.Watch(ctx, &healthpb.HealthCheckRequest{Service: "traces"})
+	require.NoError(t, err)
+
+	resp, err := stream.Recv()
+	require.NoError(t, err)
+	assert.Equal(t, healthpb.HealthCheckResponse_SERVICE_UNKNOWN, resp.Status)
+
+	a.RecordStatus(exporter, component.NewStatusEvent(component.StatusOK))
+	resp, err = stream.Recv()
+	require.NoError(t, err)
+	assert.Equal(t, healthpb.HealthCheckResponse_SERVING, resp.Status)
+
+	a.RecordStatus(exporter, component.NewPermanentErrorEvent(errors.New("invalid credentials")))
+	resp, err = stream.Recv()
+	require.NoError(t, err)
+	assert.Equal(t, healthpb.HealthCheckResponse_NOT_SERVING, resp.Status)
+}
@@ -0,0 +1,40 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package status // import "github.com/open-telemetry/opentelemetry-collector-contrib/extension/healthcheckextension/internal/status"
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+// Handler returns an HTTP handler responding with the health of the
+// collector as JSON, or of a single pipeline if the pipeline query parameter
+// is set. The response status is 200 when healthy and 503 otherwise.
+func (a *Aggregator) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var health *Health
+		if pipeline := r.URL.Query().Get("pipeline"); pipeline != "" {
+			var ok bool
+			if health, ok = a.Pipeline(pipeline); !ok {
+				http.Error(w, "unknown pipeline "+pipeline, http.StatusNotFound)
+				return
+			}
+		} else {
+			health = a.Collector()
+		}
+
+		body, err := json.Marshal(health)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		if health.Healthy {
+			w.WriteHeader(http.StatusOK)
+		} else {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+		_, _ = w.Write(body)
+	})
+}
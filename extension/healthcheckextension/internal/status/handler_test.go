@@ -0,0 +1,85 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package status
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component"
+)
+
+func TestHandler(t *testing.T) {
+	a := NewAggregator(0)
+	receiver := newInstanceID(component.KindReceiver, "otlp", "traces", "logs")
+	exporter := newInstanceID(component.KindExporter, "otlp", "traces")
+	a.RecordStatus(receiver, component.NewStatusEvent(component.StatusOK))
+	a.RecordStatus(exporter, component.NewRecoverableErrorEvent(errors.New("connection refused")))
+
+	tests := []struct {
+		name       string
+		target     string
+		statusCode int
+		check      func(t *testing.T, health map[string]any)
+	}{
+		{
+			name:       "collector",
+			target:     "/status",
+			statusCode: http.StatusServiceUnavailable,
+			check: func(t *testing.T, health map[string]any) {
+				assert.Equal(t, false, health["healthy"])
+				assert.Equal(t, "StatusRecoverableError", health["status"])
+				assert.Equal(t, "connection refused", health["last_error"].(map[string]any)["error"])
+				assert.Contains(t, health["pipelines"], "traces")
+				assert.Contains(t, health["pipelines"], "logs")
+			},
+		},
+		{
+			name:       "healthy pipeline",
+			target:     "/status?pipeline=logs",
+			statusCode: http.StatusOK,
+			check: func(t *testing.T, health map[string]any) {
+				assert.Equal(t, true, health["healthy"])
+				assert.Equal(t, "StatusOK", health["status"])
+				assert.Contains(t, health["components"], "receiver:otlp")
+				assert.NotContains(t, health, "last_error")
+			},
+		},
+		{
+			name:       "unhealthy pipeline",
+			target:     "/status?pipeline=traces",
+			statusCode: http.StatusServiceUnavailable,
+			check: func(t *testing.T, health map[string]any) {
+				components := health["components"].(map[string]any)
+				exporter := components["exporter:otlp"].(map[string]any)
+				assert.Equal(t, "StatusRecoverableError", exporter["status"])
+				assert.Equal(t, "connection refused", exporter["last_error"].(map[string]any)["error"])
+			},
+		},
+		{
+			name:       "unknown pipeline",
+			target:     "/status?pipeline=metrics",
+			statusCode: http.StatusNotFound,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			a.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, tt.target, nil))
+			require.Equal(t, tt.statusCode, rec.Code)
+			if tt.check == nil {
+				return
+			}
+			assert.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+			var health map[string]any
+			require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &health))
+			tt.check(t, health)
+		})
+	}
+}
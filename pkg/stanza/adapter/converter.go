@@ -147,33 +147,38 @@ func (c *Converter) workerLoop() {
 				return
 			}
 
-			resourceHashToIdx := make(map[uint64]int)
-
-			pLogs := plog.NewLogs()
-			var sl plog.ScopeLogs
-			for _, e := range entries {
-				resourceID := HashResource(e.Resource)
-				resourceIdx, ok := resourceHashToIdx[resourceID]
-				if !ok {
-					resourceHashToIdx[resourceID] = pLogs.ResourceLogs().Len()
-					rl := pLogs.ResourceLogs().AppendEmpty()
-					upsertToMap(e.Resource, rl.Resource().Attributes())
-					sl = rl.ScopeLogs().AppendEmpty()
-				} else {
-					sl = pLogs.ResourceLogs().At(resourceIdx).ScopeLogs().At(0)
-				}
-				convertInto(e, sl.LogRecords().AppendEmpty())
-			}
-
 			// Send plogs directly to flushChan
 			select {
-			case c.flushChan <- pLogs:
+			case c.flushChan <- convertEntries(entries):
 			case <-c.stopChan:
 			}
 		}
 	}
 }
 
+// convertEntries converts a batch of entry.Entry into plog.Logs, aggregating the
+// entries coming from the same Resource.
+func convertEntries(entries []*entry.Entry) plog.Logs {
+	resourceHashToIdx := make(map[uint64]int)
+
+	pLogs := plog.NewLogs()
+	var sl plog.ScopeLogs
+	for _, e := range entries {
+		resourceID := HashResource(e.Resource)
+		resourceIdx, ok := resourceHashToIdx[resourceID]
+		if !ok {
+			resourceHashToIdx[resourceID] = pLogs.ResourceLogs().Len()
+			rl := pLogs.ResourceLogs().AppendEmpty()
+			upsertToMap(e.Resource, rl.Resource().Attributes())
+			sl = rl.ScopeLogs().AppendEmpty()
+		} else {
+			sl = pLogs.ResourceLogs().At(resourceIdx).ScopeLogs().At(0)
+		}
+		convertInto(e, sl.LogRecords().AppendEmpty())
+	}
+	return pLogs
+}
+
 func (c *Converter) flushLoop() {
 	defer c.wg.Done()
 	ctx, cancel := context.WithCancel(context.Background())
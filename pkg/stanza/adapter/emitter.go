@@ -112,6 +112,10 @@ func (e *LogEmitter) OutChannel() <-chan []*entry.Entry {
 
 // Process will emit an entry to the output channel
 func (e *LogEmitter) Process(ctx context.Context, ent *entry.Entry) error {
+	if ent.Ack != nil {
+		// The entry is acknowledged once its batch has been consumed
+		ent.Ack.Track()
+	}
 	if oldBatch := e.appendEntry(ent); len(oldBatch) > 0 {
 		e.flush(ctx, oldBatch)
 	}
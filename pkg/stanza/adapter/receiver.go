@@ -11,11 +11,13 @@ import (
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/consumer"
 	"go.opentelemetry.io/collector/extension/experimental/storage"
+	"go.opentelemetry.io/collector/pdata/plog"
 	rcvr "go.opentelemetry.io/collector/receiver"
 	"go.opentelemetry.io/collector/receiver/receiverhelper"
 	"go.uber.org/multierr"
 	"go.uber.org/zap"
 
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/entry"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/pipeline"
 )
 
@@ -95,6 +97,11 @@ func (r *receiver) emitterLoop(ctx context.Context) {
 				continue
 			}
 
+			if isTracked(e) {
+				r.consumeTracked(ctx, e)
+				continue
+			}
+
 			if err := r.converter.Batch(e); err != nil {
 				r.logger.Error("Could not add entry to batch", zap.Error(err))
 			}
@@ -120,15 +127,42 @@ func (r *receiver) consumerLoop(ctx context.Context) {
 				r.logger.Debug("Converter channel got closed")
 				continue
 			}
-			obsrecvCtx := r.obsrecv.StartLogsOp(ctx)
-			logRecordCount := pLogs.LogRecordCount()
-			cErr := r.consumer.ConsumeLogs(ctx, pLogs)
-			if cErr != nil {
-				r.logger.Error("ConsumeLogs() failed", zap.Error(cErr))
-			}
-			r.obsrecv.EndLogsOp(obsrecvCtx, "stanza", logRecordCount, cErr)
+			r.consume(ctx, pLogs)
+		}
+	}
+}
+
+// consumeTracked converts and consumes a batch of entries whose delivery is tracked by
+// their input, then acknowledges them. The batch is consumed before the next one is read
+// from the emitter, so that the entries of an input are acknowledged in order.
+func (r *receiver) consumeTracked(ctx context.Context, entries []*entry.Entry) {
+	cErr := r.consume(ctx, convertEntries(entries))
+	for _, e := range entries {
+		if e.Ack != nil {
+			e.Ack.Done(cErr)
+		}
+	}
+}
+
+func (r *receiver) consume(ctx context.Context, pLogs plog.Logs) error {
+	obsrecvCtx := r.obsrecv.StartLogsOp(ctx)
+	logRecordCount := pLogs.LogRecordCount()
+	cErr := r.consumer.ConsumeLogs(ctx, pLogs)
+	if cErr != nil {
+		r.logger.Error("ConsumeLogs() failed", zap.Error(cErr))
+	}
+	r.obsrecv.EndLogsOp(obsrecvCtx, "stanza", logRecordCount, cErr)
+	return cErr
+}
+
+// isTracked returns true if the delivery of any entry of the batch is tracked.
+func isTracked(entries []*entry.Entry) bool {
+	for _, e := range entries {
+		if e.Ack != nil {
+			return true
 		}
 	}
+	return false
 }
 
 // Shutdown is invoked during service shutdown
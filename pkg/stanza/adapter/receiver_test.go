@@ -5,15 +5,18 @@ package adapter
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
+	"sync/atomic"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/require"
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/consumer"
 	"go.opentelemetry.io/collector/consumer/consumertest"
 	"go.opentelemetry.io/collector/receiver/receivertest"
 	"go.uber.org/zap"
@@ -117,6 +120,66 @@ func TestHandleConsumeRetry(t *testing.T) {
 	require.NoError(t, logsReceiver.Shutdown(context.Background()))
 }
 
+type testAck struct {
+	tracked atomic.Int64
+	done    chan error
+}
+
+func (a *testAck) Track() {
+	a.tracked.Add(1)
+}
+
+func (a *testAck) Done(err error) {
+	a.done <- err
+}
+
+func TestHandleConsumeTracked(t *testing.T) {
+	testCases := []struct {
+		name        string
+		consumer    consumer.Logs
+		expectedErr error
+	}{
+		{
+			name:     "Accepted",
+			consumer: &consumertest.LogsSink{},
+		},
+		{
+			name:        "Rejected",
+			consumer:    consumertest.NewErr(errors.New("export failed")),
+			expectedErr: errors.New("export failed"),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			factory := NewFactory(TestReceiverType{}, component.StabilityLevelDevelopment)
+			logsReceiver, err := factory.CreateLogsReceiver(context.Background(), receivertest.NewNopCreateSettings(), factory.CreateDefaultConfig(), tc.consumer)
+			require.NoError(t, err, "receiver should successfully build")
+			require.NoError(t, logsReceiver.Start(context.Background(), componenttest.NewNopHost()))
+
+			ack := &testAck{done: make(chan error, 2)}
+			tracked, untracked := entry.New(), entry.New()
+			tracked.Ack = ack
+
+			stanzaReceiver := logsReceiver.(*receiver)
+			require.NoError(t, stanzaReceiver.emitter.Process(context.Background(), tracked))
+			require.NoError(t, stanzaReceiver.emitter.Process(context.Background(), untracked))
+			require.Equal(t, int64(1), ack.tracked.Load())
+
+			select {
+			case err := <-ack.done:
+				require.Equal(t, tc.expectedErr, err)
+			case <-time.After(time.Second):
+				require.FailNow(t, "Timed out waiting for the entry to be acknowledged")
+			}
+			if sink, ok := tc.consumer.(*consumertest.LogsSink); ok {
+				require.Equal(t, 2, sink.LogRecordCount())
+			}
+			require.NoError(t, logsReceiver.Shutdown(context.Background()))
+		})
+	}
+}
+
 func BenchmarkReadLine(b *testing.B) {
 	filePath := filepath.Join(b.TempDir(), "bench.log")
 
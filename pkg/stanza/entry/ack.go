@@ -0,0 +1,14 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package entry // import "github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/entry"
+
+// Acknowledger is notified of the delivery of an entry to the consumer of the pipeline.
+// It allows the input which created the entry to checkpoint only the delivered data.
+type Acknowledger interface {
+	// Track is called when the entry is emitted by the pipeline.
+	Track()
+	// Done is called once for each call to Track, with the error returned
+	// by the consumer of the entry, if any.
+	Done(err error)
+}
@@ -23,6 +23,8 @@ type Entry struct {
 	TraceFlags        []byte         `json:"trace_flags,omitempty"   yaml:"trace_flags,omitempty"`
 	Severity          Severity       `json:"severity"                yaml:"severity"`
 	ScopeName         string         `json:"scope_name"              yaml:"scope_name"`
+	// Ack is set when the input which created the entry tracks its delivery.
+	Ack Acknowledger `json:"-" yaml:"-"`
 }
 
 // New will create a new log entry with current timestamp and an empty body.
@@ -176,5 +178,6 @@ func (entry *Entry) Copy() *Entry {
 		SpanID:            copyByteArray(entry.SpanID),
 		TraceFlags:        copyByteArray(entry.TraceFlags),
 		ScopeName:         entry.ScopeName,
+		Ack:               entry.Ack,
 	}
 }
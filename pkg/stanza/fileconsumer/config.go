@@ -84,6 +84,8 @@ type Config struct {
 	Encoding                string          `mapstructure:"encoding,omitempty"`
 	FlushPeriod             time.Duration   `mapstructure:"force_flush_period,omitempty"`
 	Header                  *HeaderConfig   `mapstructure:"header,omitempty"`
+	Compression             string          `mapstructure:"compression,omitempty"`
+	AtLeastOnce             bool            `mapstructure:"at_least_once,omitempty"`
 }
 
 type HeaderConfig struct {
@@ -155,6 +157,12 @@ func (c Config) buildManager(logger *zap.SugaredLogger, emit emit.Callback, spli
 		return nil, err
 	}
 
+	// Files drained in order are read by batches of at most the configured number of files
+	maxBatchFiles := c.MaxConcurrentFiles / 2
+	if batchSize := fileMatcher.BatchSize(); batchSize > 0 && batchSize < maxBatchFiles {
+		maxBatchFiles = batchSize
+	}
+
 	return &Manager{
 		SugaredLogger: logger.With("component", "fileconsumer"),
 		cancel:        func() {},
@@ -170,6 +178,8 @@ func (c Config) buildManager(logger *zap.SugaredLogger, emit emit.Callback, spli
 				IncludeFilePathResolved: c.IncludeFilePathResolved,
 				DeleteAtEOF:             c.DeleteAfterRead,
 				FlushTimeout:            c.FlushPeriod,
+				Compression:             c.Compression,
+				AtLeastOnce:             c.AtLeastOnce,
 			},
 			FromBeginning: startAtBeginning,
 			Encoding:      enc,
@@ -179,7 +189,7 @@ func (c Config) buildManager(logger *zap.SugaredLogger, emit emit.Callback, spli
 		},
 		fileMatcher:       fileMatcher,
 		pollInterval:      c.PollInterval,
-		maxBatchFiles:     c.MaxConcurrentFiles / 2,
+		maxBatchFiles:     maxBatchFiles,
 		maxBatches:        c.MaxBatches,
 		previousPollFiles: make([]*reader.Reader, 0, c.MaxConcurrentFiles/2),
 		knownFiles:        []*reader.Metadata{},
@@ -215,10 +225,20 @@ func (c Config) validate() error {
 		return fmt.Errorf("`delete_after_read` cannot be used with `start_at: end`")
 	}
 
+	if c.DeleteAfterRead && c.AtLeastOnce {
+		return fmt.Errorf("`delete_after_read` cannot be used with `at_least_once`")
+	}
+
 	if c.Header != nil && c.StartAt == "end" {
 		return fmt.Errorf("`header` cannot be specified with `start_at: end`")
 	}
 
+	switch c.Compression {
+	case "", reader.CompressionGzip, reader.CompressionAuto:
+	default:
+		return fmt.Errorf("invalid `compression` '%s', must be one of '%s' or '%s'", c.Compression, reader.CompressionGzip, reader.CompressionAuto)
+	}
+
 	if c.MaxBatches < 0 {
 		return errors.New("`max_batches` must not be negative")
 	}
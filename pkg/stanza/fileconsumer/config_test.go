@@ -398,6 +398,22 @@ func TestUnmarshal(t *testing.T) {
 					return newMockOperatorConfig(cfg)
 				}(),
 			},
+			{
+				Name: "compression_gzip",
+				Expect: func() *mockOperatorConfig {
+					cfg := NewConfig()
+					cfg.Compression = "gzip"
+					return newMockOperatorConfig(cfg)
+				}(),
+			},
+			{
+				Name: "at_least_once",
+				Expect: func() *mockOperatorConfig {
+					cfg := NewConfig()
+					cfg.AtLeastOnce = true
+					return newMockOperatorConfig(cfg)
+				}(),
+			},
 			{
 				Name: "header_config",
 				Expect: func() *mockOperatorConfig {
@@ -424,6 +440,28 @@ func TestUnmarshal(t *testing.T) {
 					return newMockOperatorConfig(cfg)
 				}(),
 			},
+			{
+				Name: "ordering_criteria_drain",
+				Expect: func() *mockOperatorConfig {
+					cfg := NewConfig()
+					cfg.OrderingCriteria = matcher.OrderingCriteria{
+						Regex:  `^.*-(?P<timestamp>\d{12})\.log$`,
+						TopN:   4,
+						Mode:   "drain",
+						MinAge: time.Minute,
+						MaxAge: 24 * time.Hour,
+						SortBy: []matcher.Sort{
+							{
+								SortType:  "timestamp",
+								RegexKey:  "timestamp",
+								Ascending: true,
+								Layout:    "%Y%m%d%H%M",
+							},
+						},
+					}
+					return newMockOperatorConfig(cfg)
+				}(),
+			},
 		},
 	}.Run(t)
 }
@@ -599,6 +637,65 @@ func TestBuild(t *testing.T) {
 			require.Error,
 			nil,
 		},
+		{
+			"CompressionAuto",
+			func(cfg *Config) {
+				cfg.Compression = "auto"
+			},
+			require.NoError,
+			func(t *testing.T, m *Manager) {
+				require.Equal(t, "auto", m.readerFactory.Config.Compression)
+			},
+		},
+		{
+			"InvalidCompression",
+			func(cfg *Config) {
+				cfg.Compression = "zstd"
+			},
+			require.Error,
+			nil,
+		},
+		{
+			"AtLeastOnce",
+			func(cfg *Config) {
+				cfg.AtLeastOnce = true
+			},
+			require.NoError,
+			func(t *testing.T, m *Manager) {
+				require.True(t, m.readerFactory.Config.AtLeastOnce)
+			},
+		},
+		{
+			"InvalidAtLeastOnceDelete",
+			func(cfg *Config) {
+				cfg.StartAt = "beginning"
+				cfg.DeleteAfterRead = true
+				cfg.AtLeastOnce = true
+			},
+			require.Error,
+			nil,
+		},
+		{
+			"OrderingCriteriaDrain",
+			func(cfg *Config) {
+				cfg.OrderingCriteria = matcher.OrderingCriteria{
+					Regex: `(?P<value>\d+)`,
+					TopN:  3,
+					Mode:  "drain",
+					SortBy: []matcher.Sort{
+						{
+							SortType:  "numeric",
+							RegexKey:  "value",
+							Ascending: true,
+						},
+					},
+				}
+			},
+			require.NoError,
+			func(t *testing.T, m *Manager) {
+				require.Equal(t, 3, m.maxBatchFiles)
+			},
+		},
 		{
 			"GoodOrderingCriteriaTimestamp",
 			func(cfg *Config) {
@@ -0,0 +1,24 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package emit // import "github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/fileconsumer/emit"
+
+import (
+	"context"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/entry"
+)
+
+type ackKey struct{}
+
+// WithAck returns a context carrying the acknowledger of the token emitted with it.
+func WithAck(ctx context.Context, ack entry.Acknowledger) context.Context {
+	return context.WithValue(ctx, ackKey{}, ack)
+}
+
+// AckFromContext returns the acknowledger of the token emitted with the context.
+// It returns nil when the delivery of the token is not tracked.
+func AckFromContext(ctx context.Context) entry.Acknowledger {
+	ack, _ := ctx.Value(ackKey{}).(entry.Acknowledger)
+	return ack
+}
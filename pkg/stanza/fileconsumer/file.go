@@ -75,6 +75,9 @@ func (m *Manager) Start(persister operator.Persister) error {
 
 func (m *Manager) closePreviousFiles() {
 	if len(m.knownFiles) > 4*m.movingAverageMatches {
+		for _, dropped := range m.knownFiles[:m.movingAverageMatches] {
+			dropped.Release()
+		}
 		m.knownFiles = m.knownFiles[m.movingAverageMatches:]
 	}
 	for _, r := range m.previousPollFiles {
@@ -93,6 +96,9 @@ func (m *Manager) Stop() error {
 			m.Errorw("save offsets", zap.Error(err))
 		}
 	}
+	for _, knownFile := range m.knownFiles {
+		knownFile.Release()
+	}
 	m.cancel = nil
 	return nil
 }
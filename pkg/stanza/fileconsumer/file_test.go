@@ -4,6 +4,7 @@
 package fileconsumer
 
 import (
+	"compress/gzip"
 	"context"
 	"fmt"
 	"os"
@@ -19,6 +20,7 @@ import (
 	"go.opentelemetry.io/collector/featuregate"
 
 	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/attrs"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/fileconsumer/internal/checkpoint"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/fileconsumer/matcher"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/operator/helper"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/testutil"
@@ -1698,3 +1700,309 @@ func TestWindowsFilesClosedImmediately(t *testing.T) {
 	// On Windows, poll should close the file after reading it. We can test this by trying to move it.
 	require.NoError(t, os.Rename(temp.Name(), temp.Name()+"_renamed"))
 }
+
+func writeGzip(t *testing.T, path string, content string) {
+	file := openFile(t, path)
+	gz := gzip.NewWriter(file)
+	_, err := gz.Write([]byte(content))
+	require.NoError(t, err)
+	require.NoError(t, gz.Close())
+	require.NoError(t, file.Close())
+}
+
+func TestReadGzipFiles(t *testing.T) {
+	t.Parallel()
+
+	tempDir := t.TempDir()
+	cfg := NewConfig().includeDir(tempDir)
+	cfg.StartAt = "beginning"
+	cfg.Compression = "auto"
+	persister := testutil.NewUnscopedMockPersister()
+
+	writeGzip(t, filepath.Join(tempDir, "archive.log.gz"), "archived1\narchived2\n")
+	temp := openTemp(t, tempDir)
+	writeString(t, temp, "plain1\n")
+
+	operator, emitCalls := buildTestManager(t, cfg)
+	operator.persister = persister
+	operator.poll(context.Background())
+	waitForTokens(t, emitCalls, []byte("archived1"), []byte("archived2"), []byte("plain1"))
+
+	// The archive is finished, only the plain file is still read
+	writeString(t, temp, "plain2\n")
+	operator.poll(context.Background())
+	waitForToken(t, emitCalls, []byte("plain2"))
+	expectNoTokens(t, emitCalls)
+	operator.poll(context.Background())
+	expectNoTokens(t, emitCalls)
+	operator.closePreviousFiles()
+	require.NoError(t, checkpoint.Save(context.Background(), persister, operator.knownFiles))
+
+	// The archive is skipped after a restart
+	operator, emitCalls = buildTestManager(t, cfg)
+	require.NoError(t, operator.Start(persister))
+	defer func() {
+		require.NoError(t, operator.Stop())
+	}()
+	writeString(t, temp, "plain3\n")
+	waitForToken(t, emitCalls, []byte("plain3"))
+	expectNoTokensUntil(t, emitCalls, 2*cfg.PollInterval)
+}
+
+func TestReadGzipFileBeingWritten(t *testing.T) {
+	t.Parallel()
+
+	tempDir := t.TempDir()
+	cfg := NewConfig().includeDir(tempDir)
+	cfg.StartAt = "beginning"
+	cfg.Compression = "gzip"
+	cfg.FlushPeriod = 0
+	operator, emitCalls := buildTestManager(t, cfg)
+
+	file := openFile(t, filepath.Join(tempDir, "archive.gz"))
+	gz := gzip.NewWriter(file)
+	_, err := gz.Write([]byte("line1\nline2\n"))
+	require.NoError(t, err)
+	require.NoError(t, gz.Flush())
+
+	operator.poll(context.Background())
+	waitForTokens(t, emitCalls, []byte("line1"), []byte("line2"))
+	require.Len(t, operator.previousPollFiles, 1)
+	require.False(t, operator.previousPollFiles[0].Finished)
+
+	// The partial line is emitted once it is complete
+	_, err = gz.Write([]byte("li"))
+	require.NoError(t, err)
+	require.NoError(t, gz.Flush())
+	operator.poll(context.Background())
+	expectNoTokens(t, emitCalls)
+	require.False(t, operator.previousPollFiles[0].Finished)
+
+	_, err = gz.Write([]byte("ne3\n"))
+	require.NoError(t, err)
+	require.NoError(t, gz.Close())
+
+	operator.poll(context.Background())
+	waitForToken(t, emitCalls, []byte("line3"))
+	expectNoTokens(t, emitCalls)
+	require.Len(t, operator.previousPollFiles, 1)
+	require.True(t, operator.previousPollFiles[0].Finished)
+}
+
+func TestReadGzipRotatedFile(t *testing.T) {
+	t.Parallel()
+
+	tempDir := t.TempDir()
+	cfg := NewConfig().includeDir(tempDir)
+	cfg.StartAt = "beginning"
+	cfg.Compression = "auto"
+	operator, emitCalls := buildTestManager(t, cfg)
+
+	temp := openFile(t, filepath.Join(tempDir, "app.log"))
+	writeString(t, temp, "line1\n")
+	operator.poll(context.Background())
+	waitForToken(t, emitCalls, []byte("line1"))
+
+	// The file is compressed on rotation, after more logs were written.
+	// The fingerprint of the decompressed head matches the rotated file.
+	writeString(t, temp, "line2\n")
+	require.NoError(t, temp.Close())
+	writeGzip(t, filepath.Join(tempDir, "app.log.1.gz"), "line1\nline2\n")
+	require.NoError(t, os.Remove(temp.Name()))
+
+	operator.poll(context.Background())
+	waitForToken(t, emitCalls, []byte("line2"))
+	expectNoTokens(t, emitCalls)
+}
+
+func TestGzipDeleteAfterRead(t *testing.T) {
+	require.NoError(t, featuregate.GlobalRegistry().Set(allowFileDeletion.ID(), true))
+	defer func() {
+		require.NoError(t, featuregate.GlobalRegistry().Set(allowFileDeletion.ID(), false))
+	}()
+
+	tempDir := t.TempDir()
+	cfg := NewConfig().includeDir(tempDir)
+	cfg.StartAt = "beginning"
+	cfg.Compression = "auto"
+	cfg.DeleteAfterRead = true
+	operator, emitCalls := buildTestManager(t, cfg)
+
+	archive := filepath.Join(tempDir, "archive.log.gz")
+	writeGzip(t, archive, "archived1\narchived2\n")
+
+	operator.poll(context.Background())
+	waitForTokens(t, emitCalls, []byte("archived1"), []byte("archived2"))
+	require.NoFileExists(t, archive)
+}
+
+func TestDrainFilesInOrder(t *testing.T) {
+	t.Parallel()
+
+	tempDir := t.TempDir()
+	cfg := NewConfig().includeDir(tempDir)
+	cfg.StartAt = "beginning"
+	cfg.OrderingCriteria = matcher.OrderingCriteria{
+		Regex: `batch-(?P<timestamp>\d{12})\.log$`,
+		Mode:  matcher.ModeDrain,
+		SortBy: []matcher.Sort{
+			{
+				SortType:  "timestamp",
+				RegexKey:  "timestamp",
+				Ascending: true,
+				Layout:    "%Y%m%d%H%M",
+			},
+		},
+	}
+	operator, emitCalls := buildTestManager(t, cfg)
+
+	minutes := []string{"202312011203", "202312011201", "202312011204", "202312011202"}
+	for _, minute := range minutes {
+		file := openFile(t, filepath.Join(tempDir, "batch-"+minute+".log"))
+		writeString(t, file, minute+"-1\n"+minute+"-2\n")
+	}
+
+	operator.poll(context.Background())
+	for _, minute := range []string{"202312011201", "202312011202", "202312011203", "202312011204"} {
+		waitForToken(t, emitCalls, []byte(minute+"-1"))
+		waitForToken(t, emitCalls, []byte(minute+"-2"))
+	}
+	expectNoTokens(t, emitCalls)
+}
+
+func TestMinAgeHoldsBackFiles(t *testing.T) {
+	t.Parallel()
+
+	tempDir := t.TempDir()
+	cfg := NewConfig().includeDir(tempDir)
+	cfg.StartAt = "beginning"
+	cfg.OrderingCriteria = matcher.OrderingCriteria{
+		MinAge: time.Hour,
+	}
+	operator, emitCalls := buildTestManager(t, cfg)
+
+	temp := openTemp(t, tempDir)
+	writeString(t, temp, "testlog\n")
+
+	operator.poll(context.Background())
+	expectNoTokens(t, emitCalls)
+
+	mtime := time.Now().Add(-2 * time.Hour)
+	require.NoError(t, os.Chtimes(temp.Name(), mtime, mtime))
+	operator.poll(context.Background())
+	waitForToken(t, emitCalls, []byte("testlog"))
+}
+
+func TestAtLeastOnce(t *testing.T) {
+	t.Parallel()
+
+	tempDir := t.TempDir()
+	cfg := NewConfig().includeDir(tempDir)
+	cfg.StartAt = "beginning"
+	cfg.AtLeastOnce = true
+
+	temp := openTemp(t, tempDir)
+	writeString(t, temp, "testlog1\ntestlog2\n")
+
+	persister := testutil.NewUnscopedMockPersister()
+
+	op1, emitCalls1 := buildTestManager(t, cfg)
+	require.NoError(t, op1.Start(persister))
+	call1 := waitForEmit(t, emitCalls1)
+	call2 := waitForEmit(t, emitCalls1)
+	require.Equal(t, []byte("testlog1"), call1.token)
+	require.Equal(t, []byte("testlog2"), call2.token)
+	call1.ack.Track()
+	call2.ack.Track()
+	// The second log is delivered before the first one, neither is checkpointed
+	call2.ack.Done(nil)
+	require.NoError(t, op1.Stop())
+
+	op2, emitCalls2 := buildTestManager(t, cfg)
+	require.NoError(t, op2.Start(persister))
+	call1 = waitForEmit(t, emitCalls2)
+	call2 = waitForEmit(t, emitCalls2)
+	require.Equal(t, []byte("testlog1"), call1.token)
+	require.Equal(t, []byte("testlog2"), call2.token)
+	call1.ack.Track()
+	call1.ack.Done(nil)
+	require.NoError(t, op2.Stop())
+
+	op3, emitCalls3 := buildTestManager(t, cfg)
+	require.NoError(t, op3.Start(persister))
+	call2 = waitForEmit(t, emitCalls3)
+	require.Equal(t, []byte("testlog2"), call2.token)
+	call2.ack.Track()
+	call2.ack.Done(nil)
+	require.NoError(t, op3.Stop())
+
+	op4, emitCalls4 := buildTestManager(t, cfg)
+	require.NoError(t, op4.Start(persister))
+	expectNoTokens(t, emitCalls4)
+	require.NoError(t, op4.Stop())
+}
+
+func TestAtLeastOnceFailedDelivery(t *testing.T) {
+	t.Parallel()
+
+	tempDir := t.TempDir()
+	cfg := NewConfig().includeDir(tempDir)
+	cfg.StartAt = "beginning"
+	cfg.AtLeastOnce = true
+
+	temp := openTemp(t, tempDir)
+	writeString(t, temp, "testlog1\ntestlog2\n")
+
+	persister := testutil.NewUnscopedMockPersister()
+
+	op1, emitCalls1 := buildTestManager(t, cfg)
+	require.NoError(t, op1.Start(persister))
+	call1 := waitForEmit(t, emitCalls1)
+	call2 := waitForEmit(t, emitCalls1)
+	call1.ack.Track()
+	call2.ack.Track()
+	call1.ack.Done(fmt.Errorf("export failed"))
+	call2.ack.Done(nil)
+
+	// Logs written after the failure are not checkpointed either
+	writeString(t, temp, "testlog3\n")
+	call3 := waitForEmit(t, emitCalls1)
+	require.Equal(t, []byte("testlog3"), call3.token)
+	call3.ack.Track()
+	call3.ack.Done(nil)
+	require.NoError(t, op1.Stop())
+
+	op2, emitCalls2 := buildTestManager(t, cfg)
+	require.NoError(t, op2.Start(persister))
+	waitForTokens(t, emitCalls2, []byte("testlog1"), []byte("testlog2"), []byte("testlog3"))
+	require.NoError(t, op2.Stop())
+}
+
+func TestAtLeastOnceDroppedLogs(t *testing.T) {
+	t.Parallel()
+
+	tempDir := t.TempDir()
+	cfg := NewConfig().includeDir(tempDir)
+	cfg.StartAt = "beginning"
+	cfg.AtLeastOnce = true
+
+	temp := openTemp(t, tempDir)
+	writeString(t, temp, "testlog1\ntestlog2\n")
+
+	persister := testutil.NewUnscopedMockPersister()
+
+	// The first log is never tracked, e.g. it was dropped by an operator,
+	// it is checkpointed along with the second one.
+	op1, emitCalls1 := buildTestManager(t, cfg)
+	require.NoError(t, op1.Start(persister))
+	waitForEmit(t, emitCalls1)
+	call2 := waitForEmit(t, emitCalls1)
+	call2.ack.Track()
+	call2.ack.Done(nil)
+	require.NoError(t, op1.Stop())
+
+	op2, emitCalls2 := buildTestManager(t, cfg)
+	require.NoError(t, op2.Start(persister))
+	expectNoTokens(t, emitCalls2)
+	require.NoError(t, op2.Stop())
+}
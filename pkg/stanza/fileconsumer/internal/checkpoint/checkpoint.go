@@ -29,7 +29,7 @@ func Save(ctx context.Context, persister operator.Persister, rmds []*reader.Meta
 	var errs []error
 	// Encode each known file
 	for _, rmd := range rmds {
-		if err := enc.Encode(rmd); err != nil {
+		if err := enc.Encode(rmd.Checkpoint()); err != nil {
 			errs = append(errs, fmt.Errorf("encode metadata: %w", err))
 		}
 	}
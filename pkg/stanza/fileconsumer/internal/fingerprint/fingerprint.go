@@ -5,9 +5,11 @@ package fingerprint // import "github.com/open-telemetry/opentelemetry-collector
 
 import (
 	"bytes"
+	"compress/gzip"
 	"errors"
 	"fmt"
 	"io"
+	"math"
 	"os"
 )
 
@@ -37,6 +39,33 @@ func New(file *os.File, size int) (*Fingerprint, error) {
 	return fp, nil
 }
 
+// NewFromGzip creates a new fingerprint from the decompressed head of an open gzip file.
+// The fingerprint of a gzip file which is still being written only contains the bytes
+// which could be decompressed so far.
+func NewFromGzip(file *os.File, size int) (*Fingerprint, error) {
+	gz, err := gzip.NewReader(io.NewSectionReader(file, 0, math.MaxInt64))
+	if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+		// The gzip header is not written yet
+		return &Fingerprint{FirstBytes: []byte{}}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading gzip header: %w", err)
+	}
+	defer gz.Close()
+
+	buf := make([]byte, size)
+	n, err := io.ReadFull(gz, buf)
+	if err != nil && !errors.Is(err, io.EOF) && !errors.Is(err, io.ErrUnexpectedEOF) {
+		return nil, fmt.Errorf("reading fingerprint bytes: %w", err)
+	}
+
+	fp := &Fingerprint{
+		FirstBytes: buf[:n],
+	}
+
+	return fp, nil
+}
+
 // Copy creates a new copy of the fingerprint
 func (f Fingerprint) Copy() *Fingerprint {
 	buf := make([]byte, len(f.FirstBytes), cap(f.FirstBytes))
@@ -4,6 +4,7 @@
 package fingerprint
 
 import (
+	"compress/gzip"
 	"fmt"
 	"math/rand"
 	"os"
@@ -277,3 +278,50 @@ func tokenWithLength(length int) []byte {
 	}
 	return b
 }
+
+func TestNewFromGzip(t *testing.T) {
+	content := "this is the decompressed content of the file"
+
+	temp, err := os.CreateTemp(t.TempDir(), "")
+	require.NoError(t, err)
+	defer temp.Close()
+
+	// The gzip header is not written yet
+	fp, err := NewFromGzip(temp, 16)
+	require.NoError(t, err)
+	require.Empty(t, fp.FirstBytes)
+
+	gz := gzip.NewWriter(temp)
+	_, err = gz.Write([]byte(content[:10]))
+	require.NoError(t, err)
+	require.NoError(t, gz.Flush())
+
+	// The stream is truncated, the fingerprint contains what was written so far
+	fp, err = NewFromGzip(temp, 16)
+	require.NoError(t, err)
+	require.Equal(t, []byte(content[:10]), fp.FirstBytes)
+
+	_, err = gz.Write([]byte(content[10:]))
+	require.NoError(t, err)
+	require.NoError(t, gz.Close())
+
+	fp, err = NewFromGzip(temp, 16)
+	require.NoError(t, err)
+	require.Equal(t, []byte(content[:16]), fp.FirstBytes)
+
+	fp, err = NewFromGzip(temp, 1000)
+	require.NoError(t, err)
+	require.Equal(t, []byte(content), fp.FirstBytes)
+}
+
+func TestNewFromGzipInvalid(t *testing.T) {
+	temp, err := os.CreateTemp(t.TempDir(), "")
+	require.NoError(t, err)
+	defer temp.Close()
+
+	_, err = temp.WriteString("this is not a gzip file")
+	require.NoError(t, err)
+
+	_, err = NewFromGzip(temp, 16)
+	require.ErrorContains(t, err, "reading gzip header")
+}
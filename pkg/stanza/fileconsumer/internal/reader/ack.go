@@ -0,0 +1,90 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package reader // import "github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/fileconsumer/internal/reader"
+
+import (
+	"sync"
+)
+
+// tracker follows the delivery of the tokens emitted from a file. The committed
+// offset only advances past the tokens accepted by the consumer of the pipeline.
+// Tokens which never reach the end of the pipeline, such as the ones dropped by
+// an operator, are committed along with the next delivered token.
+type tracker struct {
+	mu        sync.Mutex
+	pending   []*tokenAck
+	committed int64
+	// failed is set once the delivery of a token has failed. The committed offset
+	// does not advance anymore, so that the token is read again after a restart.
+	failed bool
+}
+
+func newTracker(offset int64) *tracker {
+	return &tracker{committed: offset}
+}
+
+// newAck creates the acknowledger of a token ending at the offset.
+func (t *tracker) newAck(offset int64) *tokenAck {
+	return &tokenAck{tracker: t, offset: offset}
+}
+
+// skipTo commits the offset if no token is waiting for its delivery.
+func (t *tracker) skipTo(offset int64) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if !t.failed && len(t.pending) == 0 && offset > t.committed {
+		t.committed = offset
+	}
+}
+
+func (t *tracker) committedOffset() int64 {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.committed
+}
+
+// tokenAck tracks the delivery of the entries made from a single token.
+type tokenAck struct {
+	tracker *tracker
+	offset  int64
+	// inFlight is the number of copies of the entry being delivered
+	inFlight int
+}
+
+func (a *tokenAck) Track() {
+	t := a.tracker
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.failed {
+		return
+	}
+	if a.inFlight == 0 {
+		t.pending = append(t.pending, a)
+	}
+	a.inFlight++
+}
+
+func (a *tokenAck) Done(err error) {
+	t := a.tracker
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.failed {
+		return
+	}
+	if err != nil {
+		t.failed = true
+		t.pending = nil
+		return
+	}
+
+	a.inFlight--
+	// Commit the tokens delivered in the order they were emitted
+	for len(t.pending) > 0 && t.pending[0].inFlight == 0 {
+		if t.pending[0].offset > t.committed {
+			t.committed = t.pending[0].offset
+		}
+		t.pending[0] = nil
+		t.pending = t.pending[1:]
+	}
+}
@@ -0,0 +1,87 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package reader
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTrackerCommitsInOrder(t *testing.T) {
+	tr := newTracker(10)
+	ack1, ack2, ack3 := tr.newAck(20), tr.newAck(30), tr.newAck(40)
+	ack1.Track()
+	ack2.Track()
+	ack3.Track()
+
+	ack2.Done(nil)
+	assert.Equal(t, int64(10), tr.committedOffset())
+
+	ack1.Done(nil)
+	assert.Equal(t, int64(30), tr.committedOffset())
+
+	ack3.Done(nil)
+	assert.Equal(t, int64(40), tr.committedOffset())
+	assert.Empty(t, tr.pending)
+}
+
+func TestTrackerCopies(t *testing.T) {
+	tr := newTracker(0)
+	ack := tr.newAck(20)
+	ack.Track()
+	ack.Track()
+
+	ack.Done(nil)
+	assert.Equal(t, int64(0), tr.committedOffset())
+
+	ack.Done(nil)
+	assert.Equal(t, int64(20), tr.committedOffset())
+}
+
+func TestTrackerFailure(t *testing.T) {
+	tr := newTracker(0)
+	ack1, ack2 := tr.newAck(20), tr.newAck(30)
+	ack1.Track()
+	ack2.Track()
+
+	ack1.Done(errors.New("export failed"))
+	ack2.Done(nil)
+	assert.Equal(t, int64(0), tr.committedOffset())
+
+	ack3 := tr.newAck(40)
+	ack3.Track()
+	ack3.Done(nil)
+	tr.skipTo(50)
+	assert.Equal(t, int64(0), tr.committedOffset())
+	assert.Empty(t, tr.pending)
+}
+
+func TestTrackerSkipTo(t *testing.T) {
+	tr := newTracker(0)
+	tr.skipTo(10)
+	assert.Equal(t, int64(10), tr.committedOffset())
+
+	ack := tr.newAck(20)
+	ack.Track()
+	tr.skipTo(30)
+	assert.Equal(t, int64(10), tr.committedOffset())
+
+	ack.Done(nil)
+	assert.Equal(t, int64(20), tr.committedOffset())
+}
+
+func TestMetadataCheckpoint(t *testing.T) {
+	m := &Metadata{Offset: 40}
+	assert.Same(t, m, m.Checkpoint())
+
+	m.tracker = newTracker(10)
+	m.Finished = true
+	checkpoint := m.Checkpoint()
+	assert.Equal(t, int64(10), checkpoint.Offset)
+	assert.False(t, checkpoint.Finished)
+	assert.Equal(t, int64(40), m.Offset)
+	assert.True(t, m.Finished)
+}
@@ -32,6 +32,9 @@ type Factory struct {
 }
 
 func (f *Factory) NewFingerprint(file *os.File) (*fingerprint.Fingerprint, error) {
+	if f.Config.IsCompressed(file.Name()) {
+		return fingerprint.NewFromGzip(file, f.Config.FingerprintSize)
+	}
 	return fingerprint.New(file, f.Config.FingerprintSize)
 }
 
@@ -48,6 +51,8 @@ func (f *Factory) NewReaderFromMetadata(file *os.File, m *Metadata) (r *Reader,
 		Config:        f.Config,
 		Metadata:      m,
 		file:          file,
+		source:        file,
+		compressed:    f.Config.IsCompressed(file.Name()),
 		fileName:      file.Name(),
 		logger:        f.SugaredLogger.With("path", file.Name()),
 		decoder:       decode.New(f.Encoding),
@@ -63,6 +68,10 @@ func (f *Factory) NewReaderFromMetadata(file *os.File, m *Metadata) (r *Reader,
 		}
 	}
 
+	if f.Config.AtLeastOnce && m.tracker == nil {
+		m.tracker = newTracker(m.Offset)
+	}
+
 	if f.HeaderConfig == nil || m.HeaderFinalized {
 		r.splitFunc = r.lineSplitFunc
 		r.processFunc = f.Config.Emit
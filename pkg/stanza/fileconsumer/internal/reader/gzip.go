@@ -0,0 +1,292 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package reader // import "github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/fileconsumer/internal/reader"
+
+import (
+	"compress/gzip"
+	"errors"
+	"io"
+	"os"
+	"sync"
+)
+
+const gzipChunkSize = 32 * 1024
+
+var errGzipStreamStopped = errors.New("gzip stream stopped")
+
+// gzipStream decompresses a gzip file which may still be written. A decompressor cannot
+// resume once it has read a truncated stream, so the decompression runs in its own goroutine
+// which waits for the file to grow instead. The stream is kept between polls, so that each
+// poll only decompresses the data written since the previous one.
+type gzipStream struct {
+	mu   sync.Mutex
+	cond *sync.Cond
+
+	// file is the handle the compressed data is read from, it is nil between polls
+	file *os.File
+	// offset is the offset of the compressed data read from the file
+	offset int64
+	// waiting is set while the decompression waits for more compressed data
+	waiting bool
+
+	// data holds the decompressed bytes from the offset start. The bytes which have been
+	// read are kept until they are discarded, so that the stream can seek back to them.
+	data     []byte
+	start    int64
+	pos      int64
+	complete bool
+	stopped  bool
+	err      error
+}
+
+func newGzipStream(file *os.File) *gzipStream {
+	g := &gzipStream{file: file}
+	g.cond = sync.NewCond(&g.mu)
+	go g.decompress()
+	return g
+}
+
+func (g *gzipStream) decompress() {
+	src := &gzipSource{stream: g, buf: make([]byte, gzipChunkSize)}
+	gz, err := gzip.NewReader(src)
+	buf := make([]byte, gzipChunkSize)
+	for err == nil {
+		// Members are read one at a time to find out whether another one follows
+		// without waiting for it.
+		gz.Multistream(false)
+		var n int
+		n, err = gz.Read(buf)
+		if n > 0 && !g.write(buf[:n]) {
+			return
+		}
+		if !errors.Is(err, io.EOF) {
+			continue
+		}
+		more, moreErr := src.more()
+		switch {
+		case moreErr != nil:
+			err = moreErr
+		case more:
+			err = gz.Reset(src)
+		}
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if errors.Is(err, io.EOF) {
+		g.complete = true
+	} else if !g.stopped {
+		g.err = err
+	}
+	g.cond.Broadcast()
+}
+
+// write appends decompressed data once the previous data has been read. It returns
+// false if the stream has been stopped.
+func (g *gzipStream) write(p []byte) bool {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	for g.pos < g.end() && !g.stopped {
+		g.cond.Wait()
+	}
+	if g.stopped {
+		return false
+	}
+	g.data = append(g.data, p...)
+	g.cond.Broadcast()
+	return true
+}
+
+func (g *gzipStream) end() int64 {
+	return g.start + int64(len(g.data))
+}
+
+// next waits until decompressed data can be read. It returns io.EOF at the end of the
+// data written so far and must be called with the lock held.
+func (g *gzipStream) next() error {
+	for g.pos == g.end() {
+		switch {
+		case g.err != nil:
+			return g.err
+		case g.complete, g.waiting, g.stopped:
+			return io.EOF
+		}
+		g.cond.Wait()
+	}
+	return nil
+}
+
+// Read reads the decompressed data
+func (g *gzipStream) Read(dst []byte) (int, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if err := g.next(); err != nil {
+		return 0, err
+	}
+	n := copy(dst, g.data[g.pos-g.start:])
+	g.pos += int64(n)
+	g.cond.Broadcast()
+	return n, nil
+}
+
+// skip drops the decompressed data up to the offset, or up to the end of the data written
+// so far if it comes first. It returns the offset reached.
+func (g *gzipStream) skip(offset int64) (int64, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	for g.pos < offset {
+		if err := g.next(); err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			return g.pos, err
+		}
+		g.pos = g.end()
+		if g.pos > offset {
+			g.pos = offset
+		}
+		g.data = g.data[g.pos-g.start:]
+		g.start = g.pos
+		g.cond.Broadcast()
+	}
+	return g.pos, nil
+}
+
+// discard drops the decompressed data before the offset, it cannot be read again.
+func (g *gzipStream) discard(offset int64) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if offset <= g.start || offset > g.pos {
+		return
+	}
+	g.data = g.data[offset-g.start:]
+	g.start = offset
+}
+
+// seek positions the stream at the offset. It returns false if the data at the offset
+// has been discarded.
+func (g *gzipStream) seek(offset int64) bool {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if offset < g.start || offset > g.end() {
+		return false
+	}
+	g.pos = offset
+	g.cond.Broadcast()
+	return true
+}
+
+// finished returns true if the whole stream has been decompressed and read up to the offset.
+func (g *gzipStream) finished(offset int64) bool {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return g.complete && offset == g.end()
+}
+
+// resume continues the decompression with a new handle of the file.
+func (g *gzipStream) resume(file *os.File) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.file = file
+	g.waiting = false
+	g.cond.Broadcast()
+}
+
+// detach stops reading from the file handle, which is about to be closed.
+func (g *gzipStream) detach() {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.file = nil
+}
+
+// stop ends the decompression goroutine and releases the decompressed data.
+func (g *gzipStream) stop() {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.stopped = true
+	g.file = nil
+	g.data = nil
+	g.start = g.pos
+	g.cond.Broadcast()
+}
+
+// gzipSource reads the compressed data of a gzip stream. It implements io.ByteReader, so that
+// the decompressor does not buffer the data following the end of a member.
+type gzipSource struct {
+	stream *gzipStream
+	buf    []byte
+	in     []byte
+}
+
+func (s *gzipSource) Read(dst []byte) (int, error) {
+	if err := s.wait(); err != nil {
+		return 0, err
+	}
+	n := copy(dst, s.in)
+	s.in = s.in[n:]
+	return n, nil
+}
+
+func (s *gzipSource) ReadByte() (byte, error) {
+	if err := s.wait(); err != nil {
+		return 0, err
+	}
+	b := s.in[0]
+	s.in = s.in[1:]
+	return b, nil
+}
+
+// wait waits until compressed data can be read, while the stream is waiting the
+// decompressed data read so far is the end of the data written to the file.
+func (s *gzipSource) wait() error {
+	if len(s.in) > 0 {
+		return nil
+	}
+	g := s.stream
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	for {
+		if g.stopped {
+			return errGzipStreamStopped
+		}
+		ok, err := s.fill()
+		if err != nil || ok {
+			return err
+		}
+		g.waiting = true
+		g.cond.Broadcast()
+		for g.waiting && !g.stopped {
+			g.cond.Wait()
+		}
+	}
+}
+
+// more returns true if compressed data can be read without waiting.
+func (s *gzipSource) more() (bool, error) {
+	if len(s.in) > 0 {
+		return true, nil
+	}
+	s.stream.mu.Lock()
+	defer s.stream.mu.Unlock()
+	return s.fill()
+}
+
+// fill reads the next compressed data from the file, it returns false at the end of the file.
+// It must be called with the lock of the stream held.
+func (s *gzipSource) fill() (bool, error) {
+	g := s.stream
+	if g.file == nil {
+		return false, nil
+	}
+	n, err := g.file.ReadAt(s.buf, g.offset)
+	g.offset += int64(n)
+	s.in = s.buf[:n]
+	if n > 0 {
+		return true, nil
+	}
+	if err != nil && !errors.Is(err, io.EOF) && !errors.Is(err, os.ErrClosed) {
+		return false, err
+	}
+	return false, nil
+}
@@ -0,0 +1,124 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package reader
+
+import (
+	"compress/gzip"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeGzipPart(t *testing.T, gz *gzip.Writer, content string) {
+	_, err := gz.Write([]byte(content))
+	require.NoError(t, err)
+	require.NoError(t, gz.Flush())
+}
+
+func readStream(t *testing.T, g *gzipStream) string {
+	content, err := io.ReadAll(g)
+	require.NoError(t, err)
+	return string(content)
+}
+
+func TestGzipStreamResume(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "file.gz")
+	file, err := os.Create(path)
+	require.NoError(t, err)
+	defer file.Close()
+	gz := gzip.NewWriter(file)
+
+	writeGzipPart(t, gz, "part1\n")
+	g := newGzipStream(file)
+	defer g.stop()
+	assert.Equal(t, "part1\n", readStream(t, g))
+	assert.False(t, g.finished(6))
+
+	// The stream goes on with another handle without decompressing the file again
+	g.detach()
+	writeGzipPart(t, gz, "part2\n")
+	other, err := os.Open(path)
+	require.NoError(t, err)
+	defer other.Close()
+	require.True(t, g.seek(6))
+	g.resume(other)
+	assert.Equal(t, "part2\n", readStream(t, g))
+
+	require.NoError(t, gz.Close())
+	g.resume(other)
+	assert.Equal(t, "", readStream(t, g))
+	assert.True(t, g.finished(12))
+}
+
+func TestGzipStreamSeek(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "file.gz")
+	file, err := os.Create(path)
+	require.NoError(t, err)
+	defer file.Close()
+	gz := gzip.NewWriter(file)
+	writeGzipPart(t, gz, "line1\nline2\npartial")
+
+	g := newGzipStream(file)
+	defer g.stop()
+	n, err := g.skip(6)
+	require.NoError(t, err)
+	assert.Equal(t, int64(6), n)
+	assert.Equal(t, "line2\npartial", readStream(t, g))
+
+	// The data which has not been discarded is read again
+	g.discard(12)
+	assert.False(t, g.seek(6))
+	require.True(t, g.seek(12))
+	g.resume(file)
+	assert.Equal(t, "partial", readStream(t, g))
+}
+
+func TestGzipStreamMultipleMembers(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "file.gz")
+	file, err := os.Create(path)
+	require.NoError(t, err)
+	defer file.Close()
+	for _, content := range []string{"member1\n", "member2\n"} {
+		gz := gzip.NewWriter(file)
+		writeGzipPart(t, gz, content)
+		require.NoError(t, gz.Close())
+	}
+
+	g := newGzipStream(file)
+	defer g.stop()
+	assert.Equal(t, "member1\nmember2\n", readStream(t, g))
+	assert.True(t, g.finished(16))
+}
+
+func TestGzipStreamStop(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "file.gz")
+	file, err := os.Create(path)
+	require.NoError(t, err)
+	defer file.Close()
+	writeGzipPart(t, gzip.NewWriter(file), "line1\n")
+
+	g := newGzipStream(file)
+	assert.Equal(t, "line1\n", readStream(t, g))
+	g.stop()
+	g.resume(file)
+	assert.Equal(t, "", readStream(t, g))
+	assert.False(t, g.finished(6))
+}
+
+func TestGzipStreamInvalid(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "file.gz")
+	require.NoError(t, os.WriteFile(path, []byte("not gzip data"), 0600))
+	file, err := os.Open(path)
+	require.NoError(t, err)
+	defer file.Close()
+
+	g := newGzipStream(file)
+	defer g.stop()
+	_, err = io.ReadAll(g)
+	assert.ErrorIs(t, err, gzip.ErrHeader)
+}
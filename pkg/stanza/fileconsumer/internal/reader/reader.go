@@ -8,7 +8,10 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"io"
+	"math"
 	"os"
+	"path/filepath"
 	"time"
 
 	"go.uber.org/zap"
@@ -21,6 +24,13 @@ import (
 	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/flush"
 )
 
+const (
+	// CompressionGzip reads all the files as gzip files.
+	CompressionGzip = "gzip"
+	// CompressionAuto reads the files with a .gz extension as gzip files.
+	CompressionAuto = "auto"
+)
+
 type Config struct {
 	FingerprintSize         int
 	MaxLogSize              int
@@ -31,6 +41,22 @@ type Config struct {
 	IncludeFilePathResolved bool
 	DeleteAtEOF             bool
 	FlushTimeout            time.Duration
+	Compression             string
+	// AtLeastOnce tracks the delivery of the emitted tokens, so that the
+	// checkpoints only advance past the tokens accepted by the consumer.
+	AtLeastOnce bool
+}
+
+// IsCompressed returns true if the file at the path is read as a gzip file.
+func (c *Config) IsCompressed(path string) bool {
+	switch c.Compression {
+	case CompressionGzip:
+		return true
+	case CompressionAuto:
+		return filepath.Ext(path) == ".gz"
+	default:
+		return false
+	}
 }
 
 type Metadata struct {
@@ -39,6 +65,32 @@ type Metadata struct {
 	FileAttributes  map[string]any
 	HeaderFinalized bool
 	FlushState      *flush.State
+	// Finished is set once a compressed file has been read entirely, its content cannot change anymore.
+	Finished bool
+
+	tracker    *tracker
+	gzipStream *gzipStream
+}
+
+// Checkpoint returns the metadata to persist. When the delivery of the tokens is
+// tracked, its offset is the end of the last token accepted by the consumer.
+func (m *Metadata) Checkpoint() *Metadata {
+	if m == nil || m.tracker == nil {
+		return m
+	}
+	committed := *m
+	committed.Offset = m.tracker.committedOffset()
+	committed.Finished = m.Finished && committed.Offset == m.Offset
+	return &committed
+}
+
+// Release stops the decompression of a compressed file kept between polls.
+func (m *Metadata) Release() {
+	if m == nil || m.gzipStream == nil {
+		return
+	}
+	m.gzipStream.stop()
+	m.gzipStream = nil
 }
 
 // Reader manages a single file
@@ -48,6 +100,8 @@ type Reader struct {
 	fileName      string
 	logger        *zap.SugaredLogger
 	file          *os.File
+	source        io.Reader
+	compressed    bool
 	lineSplitFunc bufio.SplitFunc
 	splitFunc     bufio.SplitFunc
 	decoder       *decode.Decoder
@@ -57,6 +111,20 @@ type Reader struct {
 
 // offsetToEnd sets the starting offset
 func (r *Reader) offsetToEnd() error {
+	if r.compressed {
+		// The decompressed size is only known once the whole file is decompressed
+		r.Offset = 0
+		if err := r.seekToOffset(); err != nil {
+			return err
+		}
+		n, err := r.gzipStream.skip(math.MaxInt64)
+		if err != nil {
+			return fmt.Errorf("decompress: %w", err)
+		}
+		r.Offset = n
+		return nil
+	}
+
 	info, err := r.file.Stat()
 	if err != nil {
 		return fmt.Errorf("stat: %w", err)
@@ -65,16 +133,58 @@ func (r *Reader) offsetToEnd() error {
 	return nil
 }
 
+// seekToOffset positions the source of the reader at the offset. The offset of a compressed
+// file counts decompressed bytes, so unless the decompression kept from the previous poll
+// is still at the offset, the file is decompressed from its start up to the offset.
+func (r *Reader) seekToOffset() error {
+	if !r.compressed {
+		if _, err := r.file.Seek(r.Offset, 0); err != nil {
+			return err
+		}
+		r.source = r.file
+		return nil
+	}
+
+	if r.gzipStream != nil && r.gzipStream.seek(r.Offset) {
+		r.gzipStream.resume(r.file)
+		r.source = r.gzipStream
+		return nil
+	}
+
+	r.Release()
+	r.gzipStream = newGzipStream(r.file)
+	r.source = r.gzipStream
+	n, err := r.gzipStream.skip(r.Offset)
+	if err != nil {
+		return fmt.Errorf("decompress up to offset %d: %w", r.Offset, err)
+	}
+	if n < r.Offset {
+		return fmt.Errorf("decompress up to offset %d: %w", r.Offset, io.EOF)
+	}
+	return nil
+}
+
 func (r *Reader) NewFingerprintFromFile() (*fingerprint.Fingerprint, error) {
 	if r.file == nil {
 		return nil, errors.New("file is nil")
 	}
+	return r.newFingerprint()
+}
+
+func (r *Reader) newFingerprint() (*fingerprint.Fingerprint, error) {
+	if r.compressed {
+		return fingerprint.NewFromGzip(r.file, r.FingerprintSize)
+	}
 	return fingerprint.New(r.file, r.FingerprintSize)
 }
 
 // ReadToEnd will read until the end of the file
 func (r *Reader) ReadToEnd(ctx context.Context) {
-	if _, err := r.file.Seek(r.Offset, 0); err != nil {
+	if r.Finished {
+		return
+	}
+
+	if err := r.seekToOffset(); err != nil {
 		r.logger.Errorw("Failed to seek", zap.Error(err))
 		return
 	}
@@ -93,7 +203,18 @@ func (r *Reader) ReadToEnd(ctx context.Context) {
 		if !ok {
 			if err := s.Error(); err != nil {
 				r.logger.Errorw("Failed during scan", zap.Error(err))
-			} else if r.DeleteAtEOF {
+				break
+			}
+			if r.compressed {
+				// A compressed file is finished once its whole content has been emitted,
+				// until then more data may still be written or flushed.
+				if !r.gzipStream.finished(r.Offset) {
+					break
+				}
+				r.Finished = true
+				r.Release()
+			}
+			if r.DeleteAtEOF {
 				r.delete()
 			}
 			break
@@ -102,7 +223,7 @@ func (r *Reader) ReadToEnd(ctx context.Context) {
 		token, err := r.decoder.Decode(s.Bytes())
 		if err != nil {
 			r.logger.Errorw("decode: %w", zap.Error(err))
-		} else if err := r.processFunc(ctx, token, r.FileAttributes); err != nil {
+		} else if err := r.processFunc(r.withAck(ctx, s.Pos()), token, r.FileAttributes); err != nil {
 			if errors.Is(err, header.ErrEndOfHeader) {
 				r.finalizeHeader()
 
@@ -112,7 +233,7 @@ func (r *Reader) ReadToEnd(ctx context.Context) {
 				// could be split differently with the new splitter.
 				r.splitFunc = r.lineSplitFunc
 				r.processFunc = r.Emit
-				if _, err = r.file.Seek(r.Offset, 0); err != nil {
+				if err = r.seekToOffset(); err != nil {
 					r.logger.Errorw("Failed to seek post-header", zap.Error(err))
 					return
 				}
@@ -125,12 +246,24 @@ func (r *Reader) ReadToEnd(ctx context.Context) {
 	}
 }
 
+// withAck attaches the acknowledger of the token ending at the offset to the context.
+func (r *Reader) withAck(ctx context.Context, offset int64) context.Context {
+	if r.tracker == nil {
+		return ctx
+	}
+	return emit.WithAck(ctx, r.tracker.newAck(offset))
+}
+
 func (r *Reader) finalizeHeader() {
 	if err := r.headerReader.Stop(); err != nil {
 		r.logger.Errorw("Failed to stop header pipeline during finalization", zap.Error(err))
 	}
 	r.headerReader = nil
 	r.HeaderFinalized = true
+	if r.tracker != nil {
+		// The header is not emitted, so it does not need to be read again
+		r.tracker.skipTo(r.Offset)
+	}
 }
 
 // Delete will close and delete the file
@@ -143,6 +276,9 @@ func (r *Reader) delete() {
 
 // Close will close the file and return the metadata
 func (r *Reader) Close() *Metadata {
+	if r.Metadata != nil && r.gzipStream != nil {
+		r.gzipStream.detach()
+	}
 	if r.file != nil {
 		if err := r.file.Close(); err != nil {
 			r.logger.Debugw("Problem closing reader", zap.Error(err))
@@ -165,9 +301,9 @@ func (r *Reader) Read(dst []byte) (int, error) {
 	// Skip if fingerprint is already built
 	// or if fingerprint is behind Offset
 	if len(r.Fingerprint.FirstBytes) == r.FingerprintSize || int(r.Offset) > len(r.Fingerprint.FirstBytes) {
-		return r.file.Read(dst)
+		return r.readSource(dst)
 	}
-	n, err := r.file.Read(dst)
+	n, err := r.readSource(dst)
 	appendCount := min0(n, r.FingerprintSize-int(r.Offset))
 	// return for n == 0 or r.Offset >= r.FingerprintSize
 	if appendCount == 0 {
@@ -179,6 +315,14 @@ func (r *Reader) Read(dst []byte) (int, error) {
 	return n, err
 }
 
+func (r *Reader) readSource(dst []byte) (int, error) {
+	if r.compressed {
+		// The data before the offset has been emitted, it is not read again
+		r.gzipStream.discard(r.Offset)
+	}
+	return r.source.Read(dst)
+}
+
 func min0(a, b int) int {
 	if a < 0 || b < 0 {
 		return 0
@@ -198,7 +342,7 @@ func (r *Reader) Validate() bool {
 	if r.file == nil {
 		return false
 	}
-	refreshedFingerprint, err := fingerprint.New(r.file, r.FingerprintSize)
+	refreshedFingerprint, err := r.newFingerprint()
 	if err != nil {
 		return false
 	}
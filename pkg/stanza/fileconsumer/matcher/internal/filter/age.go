@@ -0,0 +1,51 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package filter // import "github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/fileconsumer/matcher/internal/filter"
+
+import (
+	"os"
+	"time"
+
+	"go.uber.org/multierr"
+)
+
+type mtimeAgeOption struct {
+	minAge time.Duration
+	maxAge time.Duration
+	now    func() time.Time
+}
+
+func (o mtimeAgeOption) apply(items []*item) ([]*item, error) {
+	now := o.now()
+	filtered := make([]*item, 0, len(items))
+	var errs error
+	for _, it := range items {
+		fi, err := os.Stat(it.value)
+		if err != nil {
+			errs = multierr.Append(errs, err)
+			continue
+		}
+
+		age := now.Sub(fi.ModTime())
+		if age < o.minAge {
+			// The file may still be written
+			continue
+		}
+		if o.maxAge > 0 && age > o.maxAge {
+			continue
+		}
+		filtered = append(filtered, it)
+	}
+	return filtered, errs
+}
+
+// MtimeAge keeps the files whose modification time is at least minAge ago and,
+// if maxAge is not zero, at most maxAge ago.
+func MtimeAge(minAge, maxAge time.Duration) Option {
+	return mtimeAgeOption{
+		minAge: minAge,
+		maxAge: maxAge,
+		now:    time.Now,
+	}
+}
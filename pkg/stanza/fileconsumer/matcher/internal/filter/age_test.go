@@ -0,0 +1,94 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package filter
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMtimeAgeFilter(t *testing.T) {
+	now := time.Date(2023, time.December, 1, 12, 0, 0, 0, time.UTC)
+	cases := []struct {
+		name       string
+		minAge     time.Duration
+		maxAge     time.Duration
+		files      []string
+		fileMTimes []time.Time
+		expect     []string
+	}{
+		{
+			name:       "No files",
+			minAge:     time.Minute,
+			files:      []string{},
+			fileMTimes: []time.Time{},
+			expect:     []string{},
+		},
+		{
+			name:       "Min age",
+			minAge:     time.Minute,
+			files:      []string{"a.log", "b.log", "c.log"},
+			fileMTimes: []time.Time{now.Add(-time.Hour), now.Add(-time.Minute), now.Add(-time.Second)},
+			expect:     []string{"a.log", "b.log"},
+		},
+		{
+			name:       "Max age",
+			maxAge:     time.Hour,
+			files:      []string{"a.log", "b.log", "c.log"},
+			fileMTimes: []time.Time{now.Add(-2 * time.Hour), now.Add(-time.Hour), now},
+			expect:     []string{"b.log", "c.log"},
+		},
+		{
+			name:       "Min and max age",
+			minAge:     time.Minute,
+			maxAge:     time.Hour,
+			files:      []string{"a.log", "b.log", "c.log"},
+			fileMTimes: []time.Time{now.Add(-2 * time.Hour), now.Add(-30 * time.Minute), now.Add(-time.Second)},
+			expect:     []string{"b.log"},
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			tmpDir := t.TempDir()
+			items := []*item{}
+			for i, file := range tc.files {
+				fullPath := filepath.Join(tmpDir, file)
+
+				f, err := os.Create(fullPath)
+				require.NoError(t, err)
+				require.NoError(t, f.Close())
+				require.NoError(t, os.Chtimes(fullPath, now, tc.fileMTimes[i]))
+
+				it, err := newItem(fullPath, nil)
+				require.NoError(t, err)
+
+				items = append(items, it)
+			}
+
+			f := MtimeAge(tc.minAge, tc.maxAge).(mtimeAgeOption)
+			f.now = func() time.Time { return now }
+			result, err := f.apply(items)
+			require.NoError(t, err)
+
+			relativeResult := []string{}
+			for _, r := range result {
+				relativeResult = append(relativeResult, filepath.Base(r.value))
+			}
+			require.Equal(t, tc.expect, relativeResult)
+		})
+	}
+}
+
+func TestMtimeAgeFilterMissingFile(t *testing.T) {
+	it, err := newItem(filepath.Join(t.TempDir(), "missing.log"), nil)
+	require.NoError(t, err)
+
+	result, err := MtimeAge(time.Minute, 0).apply([]*item{it})
+	require.Error(t, err)
+	require.Empty(t, result)
+}
@@ -7,6 +7,7 @@ import (
 	"errors"
 	"fmt"
 	"regexp"
+	"time"
 
 	"go.opentelemetry.io/collector/featuregate"
 
@@ -21,6 +22,13 @@ const (
 	sortTypeMtime        = "mtime"
 )
 
+const (
+	// ModeTopN keeps the top N files after sorting.
+	ModeTopN = "top_n"
+	// ModeDrain keeps all the files after sorting, they are read in order by batches of top N files.
+	ModeDrain = "drain"
+)
+
 const (
 	defaultOrderingCriteriaTopN = 1
 )
@@ -39,9 +47,12 @@ type Criteria struct {
 }
 
 type OrderingCriteria struct {
-	Regex  string `mapstructure:"regex,omitempty"`
-	TopN   int    `mapstructure:"top_n,omitempty"`
-	SortBy []Sort `mapstructure:"sort_by,omitempty"`
+	Regex  string        `mapstructure:"regex,omitempty"`
+	TopN   int           `mapstructure:"top_n,omitempty"`
+	SortBy []Sort        `mapstructure:"sort_by,omitempty"`
+	Mode   string        `mapstructure:"mode,omitempty"`
+	MinAge time.Duration `mapstructure:"min_age,omitempty"`
+	MaxAge time.Duration `mapstructure:"max_age,omitempty"`
 }
 
 type Sort struct {
@@ -66,10 +77,36 @@ func New(c Criteria) (*Matcher, error) {
 		return nil, fmt.Errorf("exclude: %w", err)
 	}
 
+	if c.OrderingCriteria.MinAge < 0 {
+		return nil, fmt.Errorf("'min_age' must not be negative")
+	}
+	if c.OrderingCriteria.MaxAge < 0 {
+		return nil, fmt.Errorf("'max_age' must not be negative")
+	}
+	if c.OrderingCriteria.MaxAge != 0 && c.OrderingCriteria.MaxAge <= c.OrderingCriteria.MinAge {
+		return nil, fmt.Errorf("'max_age' must be greater than 'min_age'")
+	}
+
+	var filterOpts []filter.Option
+	if c.OrderingCriteria.MinAge > 0 || c.OrderingCriteria.MaxAge > 0 {
+		filterOpts = append(filterOpts, filter.MtimeAge(c.OrderingCriteria.MinAge, c.OrderingCriteria.MaxAge))
+	}
+
+	switch c.OrderingCriteria.Mode {
+	case "", ModeTopN:
+	case ModeDrain:
+		if len(c.OrderingCriteria.SortBy) == 0 {
+			return nil, fmt.Errorf("'sort_by' must be specified when 'mode' is %q", ModeDrain)
+		}
+	default:
+		return nil, fmt.Errorf("'mode' must be %q or %q", ModeTopN, ModeDrain)
+	}
+
 	if len(c.OrderingCriteria.SortBy) == 0 {
 		return &Matcher{
-			include: c.Include,
-			exclude: c.Exclude,
+			include:    c.Include,
+			exclude:    c.Exclude,
+			filterOpts: filterOpts,
 		}, nil
 	}
 
@@ -94,7 +131,6 @@ func New(c Criteria) (*Matcher, error) {
 		}
 	}
 
-	var filterOpts []filter.Option
 	for _, sc := range c.OrderingCriteria.SortBy {
 		switch sc.SortType {
 		case sortTypeNumeric:
@@ -125,13 +161,18 @@ func New(c Criteria) (*Matcher, error) {
 		}
 	}
 
-	return &Matcher{
+	m := &Matcher{
 		include:    c.Include,
 		exclude:    c.Exclude,
 		regex:      regex,
-		topN:       c.OrderingCriteria.TopN,
 		filterOpts: filterOpts,
-	}, nil
+	}
+	if c.OrderingCriteria.Mode == ModeDrain {
+		m.batchSize = c.OrderingCriteria.TopN
+	} else {
+		m.topN = c.OrderingCriteria.TopN
+	}
+	return m, nil
 }
 
 // orderingCriteriaNeedsRegex returns true if any of the sort options require a regex to be set.
@@ -150,9 +191,16 @@ type Matcher struct {
 	exclude    []string
 	regex      *regexp.Regexp
 	topN       int
+	batchSize  int
 	filterOpts []filter.Option
 }
 
+// BatchSize returns the number of files which must be read concurrently at most,
+// in order to read the matched files in order. It returns 0 if there is no such limit.
+func (m Matcher) BatchSize() int {
+	return m.batchSize
+}
+
 // MatchFiles gets a list of paths given an array of glob patterns to include and exclude
 func (m Matcher) MatchFiles() ([]string, error) {
 	var errs error
@@ -172,7 +220,7 @@ func (m Matcher) MatchFiles() ([]string, error) {
 		return result, errors.Join(err, errs)
 	}
 
-	if len(result) <= m.topN {
+	if m.topN == 0 || len(result) <= m.topN {
 		return result, errors.Join(err, errs)
 	}
 
@@ -7,6 +7,7 @@ import (
 	"os"
 	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -195,6 +196,83 @@ func TestNew(t *testing.T) {
 			},
 			enableMtimeFeatureGate: true,
 		},
+		{
+			name: "DrainMode",
+			criteria: Criteria{
+				Include: []string{"*.log"},
+				OrderingCriteria: OrderingCriteria{
+					Regex: "[a-z]+",
+					Mode:  "drain",
+					SortBy: []Sort{
+						{
+							SortType: "alphabetical",
+							RegexKey: "value",
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "DrainModeWithoutSortBy",
+			criteria: Criteria{
+				Include: []string{"*.log"},
+				OrderingCriteria: OrderingCriteria{
+					Mode: "drain",
+				},
+			},
+			expectedErr: `'sort_by' must be specified when 'mode' is "drain"`,
+		},
+		{
+			name: "InvalidMode",
+			criteria: Criteria{
+				Include: []string{"*.log"},
+				OrderingCriteria: OrderingCriteria{
+					Mode: "all",
+				},
+			},
+			expectedErr: `'mode' must be "top_n" or "drain"`,
+		},
+		{
+			name: "MinAndMaxAge",
+			criteria: Criteria{
+				Include: []string{"*.log"},
+				OrderingCriteria: OrderingCriteria{
+					MinAge: time.Minute,
+					MaxAge: time.Hour,
+				},
+			},
+		},
+		{
+			name: "NegativeMinAge",
+			criteria: Criteria{
+				Include: []string{"*.log"},
+				OrderingCriteria: OrderingCriteria{
+					MinAge: -time.Minute,
+				},
+			},
+			expectedErr: "'min_age' must not be negative",
+		},
+		{
+			name: "NegativeMaxAge",
+			criteria: Criteria{
+				Include: []string{"*.log"},
+				OrderingCriteria: OrderingCriteria{
+					MaxAge: -time.Minute,
+				},
+			},
+			expectedErr: "'max_age' must not be negative",
+		},
+		{
+			name: "MaxAgeNotGreaterThanMinAge",
+			criteria: Criteria{
+				Include: []string{"*.log"},
+				OrderingCriteria: OrderingCriteria{
+					MinAge: time.Hour,
+					MaxAge: time.Minute,
+				},
+			},
+			expectedErr: "'max_age' must be greater than 'min_age'",
+		},
 		{
 			name: "SortByMtimeGateDisabled",
 			criteria: Criteria{
@@ -757,3 +835,69 @@ func enableSortByMTimeFeature(t *testing.T) {
 		})
 	}
 }
+
+func TestMatcherDrain(t *testing.T) {
+	tempDir := t.TempDir()
+	files := []string{"err.2023020611.log", "err.2023020612.log", "err.2023020610.log", "err.2023020609.log"}
+	for _, f := range files {
+		require.NoError(t, os.WriteFile(filepath.Join(tempDir, f), []byte(f), 0600))
+	}
+
+	matcher, err := New(Criteria{
+		Include: []string{filepath.Join(tempDir, "err.*.log")},
+		OrderingCriteria: OrderingCriteria{
+			Regex: `err\.(?P<value>\d{10})\.log`,
+			Mode:  ModeDrain,
+			TopN:  2,
+			SortBy: []Sort{
+				{
+					SortType:  sortTypeTimestamp,
+					RegexKey:  "value",
+					Ascending: true,
+					Location:  "UTC",
+					Layout:    `%Y%m%d%H`,
+				},
+			},
+		},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 2, matcher.BatchSize())
+
+	// All the files are matched, oldest first
+	matches, err := matcher.MatchFiles()
+	require.NoError(t, err)
+	expected := []string{"err.2023020609.log", "err.2023020610.log", "err.2023020611.log", "err.2023020612.log"}
+	require.Len(t, matches, len(expected))
+	for i, m := range matches {
+		assert.Equal(t, expected[i], filepath.Base(m))
+	}
+}
+
+func TestMatcherAge(t *testing.T) {
+	tempDir := t.TempDir()
+	now := time.Now()
+	mtimes := map[string]time.Time{
+		"old.log":     now.Add(-2 * time.Hour),
+		"done.log":    now.Add(-10 * time.Minute),
+		"writing.log": now,
+	}
+	for f, mtime := range mtimes {
+		path := filepath.Join(tempDir, f)
+		require.NoError(t, os.WriteFile(path, []byte(f), 0600))
+		require.NoError(t, os.Chtimes(path, mtime, mtime))
+	}
+
+	matcher, err := New(Criteria{
+		Include: []string{filepath.Join(tempDir, "*.log")},
+		OrderingCriteria: OrderingCriteria{
+			MinAge: time.Minute,
+			MaxAge: time.Hour,
+		},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 0, matcher.BatchSize())
+
+	matches, err := matcher.MatchFiles()
+	require.NoError(t, err)
+	assert.Equal(t, []string{filepath.Join(tempDir, "done.log")}, matches)
+}
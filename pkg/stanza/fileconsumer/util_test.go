@@ -14,6 +14,7 @@ import (
 
 	"github.com/stretchr/testify/require"
 
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/entry"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/fileconsumer/emit"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/testutil"
 )
@@ -23,10 +24,10 @@ func nopEmitFunc(_ context.Context, _ []byte, _ map[string]any) error {
 }
 
 func testEmitFunc(emitChan chan *emitParams) emit.Callback {
-	return func(_ context.Context, token []byte, attrs map[string]any) error {
+	return func(ctx context.Context, token []byte, attrs map[string]any) error {
 		copied := make([]byte, len(token))
 		copy(copied, token)
-		emitChan <- &emitParams{attrs, copied}
+		emitChan <- &emitParams{attrs, copied, emit.AckFromContext(ctx)}
 		return nil
 	}
 }
@@ -34,6 +35,7 @@ func testEmitFunc(emitChan chan *emitParams) emit.Callback {
 type emitParams struct {
 	attrs map[string]any
 	token []byte
+	ack   entry.Acknowledger
 }
 
 type testManagerConfig struct {
@@ -0,0 +1,162 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package helper // import "github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/operator/helper"
+
+import (
+	"fmt"
+	"math"
+	"sync"
+	"time"
+)
+
+// defaultSweepInterval is the interval at which the buckets of the sources
+// which have not been seen for a while are removed
+const defaultSweepInterval = time.Minute
+
+// defaultDropReportInterval is the minimum interval between two reports of
+// the entries dropped by the rate limiter
+const defaultDropReportInterval = 10 * time.Second
+
+// defaultMaxSources is the maximum number of sources which get their own
+// bucket. Source addresses can be spoofed, so the number of buckets is capped
+// to bound the memory used by a flood of sources.
+const defaultMaxSources = 10000
+
+// RateLimitConfig is the configuration of a rate limit applied to each source
+type RateLimitConfig struct {
+	// Rate is the number of entries per second allowed for each source
+	Rate float64 `mapstructure:"rate,omitempty"`
+	// Burst is the number of entries a source may send at once
+	Burst int `mapstructure:"burst,omitempty"`
+}
+
+// Build creates a rate limiter from the config
+func (c RateLimitConfig) Build() (*RateLimiter, error) {
+	if c.Rate <= 0 {
+		return nil, fmt.Errorf("invalid value for parameter 'rate', must be greater than 0")
+	}
+	if c.Burst < 0 {
+		return nil, fmt.Errorf("invalid value for parameter 'burst', must not be negative")
+	}
+
+	// Allow at least one second worth of entries at once by default
+	burst := float64(c.Burst)
+	if burst == 0 {
+		burst = math.Max(1, math.Ceil(c.Rate))
+	}
+
+	return &RateLimiter{
+		rate:               c.Rate,
+		burst:              burst,
+		buckets:            make(map[string]*bucket),
+		maxSources:         defaultMaxSources,
+		sweepInterval:      defaultSweepInterval,
+		dropReportInterval: defaultDropReportInterval,
+		now:                time.Now,
+	}, nil
+}
+
+// bucket keeps the tokens left to a source
+type bucket struct {
+	tokens float64
+	last   time.Time
+}
+
+// RateLimiter limits the rate of the entries of each source, using a token
+// bucket per source. Once the maximum number of sources is tracked, the new
+// sources share a single bucket until the sweep makes room for them.
+type RateLimiter struct {
+	rate  float64
+	burst float64
+
+	mutex         sync.Mutex
+	buckets       map[string]*bucket
+	maxSources    int
+	overflow      *bucket
+	lastSweep     time.Time
+	sweepInterval time.Duration
+	now           func() time.Time
+
+	dropped            int
+	lastDropReport     time.Time
+	dropReportInterval time.Duration
+}
+
+// Allow reports whether an entry of the source may be read now, and takes
+// a token from its bucket if so.
+func (r *RateLimiter) Allow(source string) bool {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	now := r.now()
+	r.sweep(now)
+
+	b, ok := r.buckets[source]
+	if !ok {
+		b = r.newBucket(source, now)
+	}
+
+	b.tokens = math.Min(r.burst, b.tokens+now.Sub(b.last).Seconds()*r.rate)
+	b.last = now
+	if b.tokens < 1 {
+		return false
+	}
+	b.tokens--
+	return true
+}
+
+// newBucket returns the bucket of a new source, which is the shared bucket
+// once the maximum number of sources is tracked.
+func (r *RateLimiter) newBucket(source string, now time.Time) *bucket {
+	if len(r.buckets) < r.maxSources {
+		b := &bucket{tokens: r.burst, last: now}
+		r.buckets[source] = b
+		return b
+	}
+	if r.overflow == nil {
+		r.overflow = &bucket{tokens: r.burst, last: now}
+	}
+	return r.overflow
+}
+
+// Drop records an entry dropped because its source exceeded the rate limit.
+// It returns the number of entries dropped since the last report, and whether
+// they should be reported now, which happens at most once per interval so that
+// a flooding source doesn't flood the logs as well.
+func (r *RateLimiter) Drop() (int, bool) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	r.dropped++
+	now := r.now()
+	if now.Sub(r.lastDropReport) < r.dropReportInterval {
+		return r.dropped, false
+	}
+	dropped := r.dropped
+	r.dropped = 0
+	r.lastDropReport = now
+	return dropped, true
+}
+
+// sweep removes the buckets which are full again, including the shared one,
+// as they don't differ from the bucket of a new source.
+func (r *RateLimiter) sweep(now time.Time) {
+	if now.Sub(r.lastSweep) < r.sweepInterval {
+		return
+	}
+	r.lastSweep = now
+
+	for source, b := range r.buckets {
+		if r.full(b, now) {
+			delete(r.buckets, source)
+		}
+	}
+	if r.overflow != nil && r.full(r.overflow, now) {
+		r.overflow = nil
+	}
+}
+
+func (r *RateLimiter) full(b *bucket, now time.Time) bool {
+	return b.tokens+now.Sub(b.last).Seconds()*r.rate >= r.burst
+}
@@ -0,0 +1,122 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package helper
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func newTestRateLimiter(t *testing.T, cfg RateLimitConfig) (*RateLimiter, *time.Time) {
+	limiter, err := cfg.Build()
+	require.NoError(t, err)
+	now := time.Unix(1000, 0)
+	limiter.now = func() time.Time { return now }
+	return limiter, &now
+}
+
+func TestRateLimiterInvalidConfig(t *testing.T) {
+	_, err := RateLimitConfig{}.Build()
+	require.EqualError(t, err, "invalid value for parameter 'rate', must be greater than 0")
+
+	_, err = RateLimitConfig{Rate: 1, Burst: -1}.Build()
+	require.EqualError(t, err, "invalid value for parameter 'burst', must not be negative")
+}
+
+func TestRateLimiterBurst(t *testing.T) {
+	limiter, now := newTestRateLimiter(t, RateLimitConfig{Rate: 2, Burst: 3})
+
+	for i := 0; i < 3; i++ {
+		require.True(t, limiter.Allow("10.0.0.1"))
+	}
+	require.False(t, limiter.Allow("10.0.0.1"))
+
+	// Each source has its own bucket
+	require.True(t, limiter.Allow("10.0.0.2"))
+
+	// The bucket is refilled at the rate, up to the burst
+	*now = now.Add(500 * time.Millisecond)
+	require.True(t, limiter.Allow("10.0.0.1"))
+	require.False(t, limiter.Allow("10.0.0.1"))
+
+	*now = now.Add(time.Hour)
+	for i := 0; i < 3; i++ {
+		require.True(t, limiter.Allow("10.0.0.1"))
+	}
+	require.False(t, limiter.Allow("10.0.0.1"))
+}
+
+func TestRateLimiterDefaultBurst(t *testing.T) {
+	limiter, _ := newTestRateLimiter(t, RateLimitConfig{Rate: 0.5})
+	require.True(t, limiter.Allow("10.0.0.1"))
+	require.False(t, limiter.Allow("10.0.0.1"))
+
+	limiter, _ = newTestRateLimiter(t, RateLimitConfig{Rate: 2.5})
+	for i := 0; i < 3; i++ {
+		require.True(t, limiter.Allow("10.0.0.1"))
+	}
+	require.False(t, limiter.Allow("10.0.0.1"))
+}
+
+func TestRateLimiterSweep(t *testing.T) {
+	limiter, now := newTestRateLimiter(t, RateLimitConfig{Rate: 0.1, Burst: 10})
+
+	require.True(t, limiter.Allow("10.0.0.1"))
+	for i := 0; i < 10; i++ {
+		require.True(t, limiter.Allow("10.0.0.2"))
+	}
+	require.Len(t, limiter.buckets, 2)
+
+	// The bucket of the first source is full again, not the one of the second
+	*now = now.Add(defaultSweepInterval)
+	require.True(t, limiter.Allow("10.0.0.3"))
+	require.Len(t, limiter.buckets, 2)
+	require.NotContains(t, limiter.buckets, "10.0.0.1")
+}
+
+func TestRateLimiterMaxSources(t *testing.T) {
+	limiter, now := newTestRateLimiter(t, RateLimitConfig{Rate: 1, Burst: 2})
+	limiter.maxSources = 2
+
+	require.True(t, limiter.Allow("10.0.0.1"))
+	require.True(t, limiter.Allow("10.0.0.2"))
+
+	// The sources over the maximum share a bucket
+	require.True(t, limiter.Allow("10.0.0.3"))
+	require.True(t, limiter.Allow("10.0.0.4"))
+	require.False(t, limiter.Allow("10.0.0.5"))
+	require.Len(t, limiter.buckets, 2)
+
+	// The tracked sources keep their own bucket
+	require.True(t, limiter.Allow("10.0.0.1"))
+	require.False(t, limiter.Allow("10.0.0.1"))
+
+	// The sweep makes room for new sources
+	*now = now.Add(defaultSweepInterval)
+	require.True(t, limiter.Allow("10.0.0.5"))
+	require.Len(t, limiter.buckets, 1)
+	require.Nil(t, limiter.overflow)
+}
+
+func TestRateLimiterDrop(t *testing.T) {
+	limiter, now := newTestRateLimiter(t, RateLimitConfig{Rate: 1})
+
+	// The first drop is reported right away
+	dropped, report := limiter.Drop()
+	require.True(t, report)
+	require.Equal(t, 1, dropped)
+
+	// The next drops are reported together, once per interval
+	for i := 2; i <= 4; i++ {
+		dropped, report = limiter.Drop()
+		require.False(t, report)
+		require.Equal(t, i-1, dropped)
+	}
+	*now = now.Add(defaultDropReportInterval)
+	dropped, report = limiter.Drop()
+	require.True(t, report)
+	require.Equal(t, 4, dropped)
+}
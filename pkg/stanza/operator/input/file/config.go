@@ -4,16 +4,24 @@
 package file // import "github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/operator/input/file"
 
 import (
+	"fmt"
+
 	"go.uber.org/zap"
 
 	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/decode"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/fileconsumer"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/operator"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/operator/helper"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/operator/input/file/internal/evtx"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/split"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/trim"
 )
 
 const operatorType = "file_input"
 
+// FormatEvtx reads the files as Windows XML Event Log (EVTX) files.
+const FormatEvtx = "evtx"
+
 func init() {
 	operator.Register(operatorType, func() operator.Builder { return NewConfig() })
 }
@@ -35,6 +43,7 @@ func NewConfigWithID(operatorID string) *Config {
 type Config struct {
 	helper.InputConfig  `mapstructure:",squash"`
 	fileconsumer.Config `mapstructure:",squash"`
+	Format              string `mapstructure:"format,omitempty"`
 }
 
 // Build will build a file input operator from the supplied configuration
@@ -44,6 +53,10 @@ func (c Config) Build(logger *zap.SugaredLogger) (operator.Operator, error) {
 		return nil, err
 	}
 
+	if c.Format != "" {
+		return c.buildWithFormat(logger, inputOperator)
+	}
+
 	var toBody toBodyFunc = func(token []byte) any {
 		return string(token)
 	}
@@ -67,3 +80,37 @@ func (c Config) Build(logger *zap.SugaredLogger) (operator.Operator, error) {
 
 	return input, nil
 }
+
+// buildWithFormat builds a file input operator reading the events of files in a binary format.
+func (c Config) buildWithFormat(logger *zap.SugaredLogger, inputOperator helper.InputOperator) (operator.Operator, error) {
+	if c.Format != FormatEvtx {
+		return nil, fmt.Errorf("invalid format '%s', must be '%s'", c.Format, FormatEvtx)
+	}
+	if c.SplitConfig != (split.Config{}) {
+		return nil, fmt.Errorf("`multiline` cannot be used with `format: %s`", c.Format)
+	}
+	if c.Header != nil {
+		return nil, fmt.Errorf("`header` cannot be used with `format: %s`", c.Format)
+	}
+	if c.MaxLogSize < evtx.ChunkSize {
+		return nil, fmt.Errorf("`max_log_size` must be at least %d bytes with `format: %s`", evtx.ChunkSize, c.Format)
+	}
+
+	// The files are read by chunks of events, which are kept as is until they are complete
+	cfg := c.Config
+	cfg.Encoding = "nop"
+	cfg.TrimConfig = trim.Config{PreserveLeading: true, PreserveTrailing: true}
+	cfg.FlushPeriod = 0
+
+	input := &Input{
+		InputOperator: inputOperator,
+	}
+
+	var err error
+	input.fileConsumer, err = cfg.BuildWithSplitFunc(logger, input.emitEvtx, evtx.SplitFunc)
+	if err != nil {
+		return nil, err
+	}
+
+	return input, nil
+}
@@ -354,6 +354,15 @@ func TestUnmarshal(t *testing.T) {
 					return cfg
 				}(),
 			},
+			{
+				Name:      "format_evtx",
+				ExpectErr: false,
+				Expect: func() *Config {
+					cfg := NewConfig()
+					cfg.Format = FormatEvtx
+					return cfg
+				}(),
+			},
 			{
 				Name:      "max_concurrent_large",
 				ExpectErr: false,
@@ -528,6 +537,40 @@ func TestBuild(t *testing.T) {
 			require.Error,
 			nil,
 		},
+		{
+			"FormatEvtx",
+			func(cfg *Config) {
+				cfg.Format = FormatEvtx
+			},
+			require.NoError,
+			func(t *testing.T, f *Input) {},
+		},
+		{
+			"InvalidFormat",
+			func(cfg *Config) {
+				cfg.Format = "xml"
+			},
+			require.Error,
+			nil,
+		},
+		{
+			"FormatEvtxMultiline",
+			func(cfg *Config) {
+				cfg.Format = FormatEvtx
+				cfg.SplitConfig.LineStartPattern = "START.*"
+			},
+			require.Error,
+			nil,
+		},
+		{
+			"FormatEvtxSmallMaxLogSize",
+			func(cfg *Config) {
+				cfg.Format = FormatEvtx
+				cfg.MaxLogSize = 1024
+			},
+			require.Error,
+			nil,
+		},
 	}
 
 	for _, tc := range cases {
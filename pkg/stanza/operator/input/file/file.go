@@ -7,10 +7,15 @@ import (
 	"context"
 	"fmt"
 
+	"go.uber.org/zap"
+
 	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/entry"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/fileconsumer"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/fileconsumer/emit"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/operator"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/operator/helper"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/operator/input/file/internal/evtx"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/operator/input/windows"
 )
 
 type toBodyFunc func([]byte) any
@@ -43,6 +48,7 @@ func (f *Input) emit(ctx context.Context, token []byte, attrs map[string]any) er
 	if err != nil {
 		return fmt.Errorf("create entry: %w", err)
 	}
+	ent.Ack = emit.AckFromContext(ctx)
 
 	for k, v := range attrs {
 		if err := ent.Set(entry.NewAttributeField(k), v); err != nil {
@@ -52,3 +58,46 @@ func (f *Input) emit(ctx context.Context, token []byte, attrs map[string]any) er
 	f.Write(ctx, ent)
 	return nil
 }
+
+// emitEvtx creates an entry for each event of a chunk of an evtx file. The entries
+// have the same body as the ones created by the windows_eventlog_input operator.
+func (f *Input) emitEvtx(ctx context.Context, chunk []byte, attrs map[string]any) error {
+	ack := emit.AckFromContext(ctx)
+	if ack != nil {
+		// The chunk must not be acknowledged before all its events are written
+		ack.Track()
+		defer ack.Done(nil)
+	}
+
+	records, err := evtx.ParseChunk(chunk)
+	if err != nil {
+		f.Errorw("Failed to read events", zap.Error(err))
+	}
+
+	for _, record := range records {
+		event, err := windows.UnmarshalEventXML(record.XML)
+		if err != nil {
+			f.Errorw("Failed to parse event", zap.Uint64("record_id", record.ID), zap.Error(err))
+			continue
+		}
+
+		ent, err := f.NewEntry(event.Body())
+		if err != nil {
+			return fmt.Errorf("create entry: %w", err)
+		}
+		ent.Ack = ack
+		ent.Severity = event.Severity()
+		ent.Timestamp = record.Written
+		if timestamp, ok := event.Timestamp(); ok {
+			ent.Timestamp = timestamp
+		}
+
+		for k, v := range attrs {
+			if err := ent.Set(entry.NewAttributeField(k), v); err != nil {
+				f.Errorf("set attribute: %w", err)
+			}
+		}
+		f.Write(ctx, ent)
+	}
+	return nil
+}
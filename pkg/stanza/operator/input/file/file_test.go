@@ -13,6 +13,7 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/entry"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/operator/input/windows"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/testutil"
 )
 
@@ -238,3 +239,69 @@ func TestSkipEmpty(t *testing.T) {
 	waitForMessage(t, logReceived, "testlog1")
 	waitForMessage(t, logReceived, "testlog2")
 }
+
+func TestAtLeastOnce(t *testing.T) {
+	t.Parallel()
+	operator, logReceived, tempDir := newTestFileOperator(t, func(cfg *Config) {
+		cfg.AtLeastOnce = true
+	})
+
+	temp := openTemp(t, tempDir)
+	writeString(t, temp, "testlog\n")
+
+	require.NoError(t, operator.Start(testutil.NewUnscopedMockPersister()))
+	defer func() {
+		require.NoError(t, operator.Stop())
+	}()
+
+	e := waitForOne(t, logReceived)
+	require.Equal(t, "testlog", e.Body)
+	require.NotNil(t, e.Ack)
+}
+
+func TestEvtx(t *testing.T) {
+	t.Parallel()
+	operator, logReceived, tempDir := newTestFileOperator(t, func(cfg *Config) {
+		cfg.Format = FormatEvtx
+		cfg.IncludeFileName = true
+	})
+
+	data, err := os.ReadFile(filepath.Join("internal", "evtx", "testdata", "sample.evtx"))
+	require.NoError(t, err)
+
+	// The chunk of events is read once it has been entirely written
+	temp := openTemp(t, tempDir)
+	_, err = temp.Write(data[:len(data)/2])
+	require.NoError(t, err)
+
+	require.NoError(t, operator.Start(testutil.NewUnscopedMockPersister()))
+	defer func() {
+		require.NoError(t, operator.Stop())
+	}()
+
+	expectNoMessages(t, logReceived)
+	_, err = temp.Write(data[len(data)/2:])
+	require.NoError(t, err)
+
+	// The events have the same body as the ones of the windows_eventlog_input operator
+	sample, err := os.ReadFile(filepath.Join("..", "windows", "testdata", "xmlSample.xml"))
+	require.NoError(t, err)
+	event, err := windows.UnmarshalEventXML(sample)
+	require.NoError(t, err)
+
+	e := waitForOne(t, logReceived)
+	require.Equal(t, event.Body(), e.Body)
+	require.Equal(t, time.Date(2022, time.April, 22, 10, 20, 52, 377862500, time.UTC), e.Timestamp.UTC())
+	require.Equal(t, entry.Info, e.Severity)
+	require.Equal(t, filepath.Base(temp.Name()), e.Attributes["log.file.name"])
+
+	e = waitForOne(t, logReceived)
+	require.Equal(t, uint64(2590526), e.Body.(map[string]any)["record_id"])
+	require.Equal(t, map[string]any{"user_id": "S-1-5-18"}, e.Body.(map[string]any)["security"])
+
+	e = waitForOne(t, logReceived)
+	require.Equal(t, uint64(23403), e.Body.(map[string]any)["record_id"])
+	require.Equal(t, entry.Error, e.Severity)
+
+	expectNoMessages(t, logReceived)
+}
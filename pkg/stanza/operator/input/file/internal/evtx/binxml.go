@@ -0,0 +1,563 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package evtx // import "github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/operator/input/file/internal/evtx"
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf16"
+)
+
+// The tokens of the binary xml. The tokens with the more flag set are followed
+// by other attributes, or are elements which have attributes.
+const (
+	tokenEOF                  = 0x00
+	tokenOpenStartElement     = 0x01
+	tokenCloseStartElement    = 0x02
+	tokenCloseEmptyElement    = 0x03
+	tokenEndElement           = 0x04
+	tokenValue                = 0x05
+	tokenAttribute            = 0x06
+	tokenCDATASection         = 0x07
+	tokenCharRef              = 0x08
+	tokenEntityRef            = 0x09
+	tokenPITarget             = 0x0a
+	tokenPIData               = 0x0b
+	tokenTemplateInstance     = 0x0c
+	tokenNormalSubstitution   = 0x0d
+	tokenOptionalSubstitution = 0x0e
+	tokenFragmentHeader       = 0x0f
+
+	tokenMoreFlag = 0x40
+)
+
+// The types of the values.
+const (
+	typeNull       = 0x00
+	typeString     = 0x01
+	typeAnsiString = 0x02
+	typeInt8       = 0x03
+	typeUint8      = 0x04
+	typeInt16      = 0x05
+	typeUint16     = 0x06
+	typeInt32      = 0x07
+	typeUint32     = 0x08
+	typeInt64      = 0x09
+	typeUint64     = 0x0a
+	typeReal32     = 0x0b
+	typeReal64     = 0x0c
+	typeBool       = 0x0d
+	typeBinary     = 0x0e
+	typeGUID       = 0x0f
+	typeSizeT      = 0x10
+	typeFileTime   = 0x11
+	typeSystemTime = 0x12
+	typeSID        = 0x13
+	typeHexInt32   = 0x14
+	typeHexInt64   = 0x15
+	typeBinXML     = 0x21
+
+	typeArrayFlag = 0x80
+)
+
+// valueSizes are the sizes of the values of fixed size.
+var valueSizes = map[byte]int{
+	typeInt8: 1, typeUint8: 1, typeInt16: 2, typeUint16: 2, typeInt32: 4, typeUint32: 4,
+	typeInt64: 8, typeUint64: 8, typeReal32: 4, typeReal64: 8, typeBool: 4, typeGUID: 16,
+	typeFileTime: 8, typeSystemTime: 16, typeHexInt32: 4, typeHexInt64: 8,
+}
+
+// The time format of the xml rendered by the Windows APIs.
+const systemTimeFormat = "2006-01-02T15:04:05.0000000Z"
+
+// maxDepth limits the nesting of the templates and of the embedded fragments.
+const maxDepth = 16
+
+var errOutOfBounds = errors.New("unexpected end of data")
+
+// value is a substitution value of a template instance.
+type value struct {
+	valueType byte
+	offset    int
+	size      int
+}
+
+// renderer renders the binary xml of a chunk. The offsets of the names
+// and of the templates are relative to the start of the chunk.
+type renderer struct {
+	chunk []byte
+	names map[int]string
+	out   bytes.Buffer
+	depth int
+}
+
+// render renders the binary xml fragment between the offsets.
+func (r *renderer) render(start, end int) error {
+	return r.renderFragment(&reader{data: r.chunk[:end], pos: start}, nil)
+}
+
+func (r *renderer) renderFragment(rd *reader, values []value) error {
+	for rd.err == nil {
+		token := rd.u8()
+		switch token {
+		case tokenEOF:
+			return rd.err
+		case tokenFragmentHeader:
+			// Major version, minor version and flags
+			rd.skip(3)
+		case tokenTemplateInstance:
+			if err := r.renderTemplateInstance(rd); err != nil {
+				return err
+			}
+		case tokenOpenStartElement, tokenOpenStartElement | tokenMoreFlag:
+			if err := r.renderElement(rd, values, token&tokenMoreFlag != 0); err != nil {
+				return err
+			}
+		default:
+			if rd.err == nil {
+				return fmt.Errorf("unexpected token 0x%02x at offset %d", token, rd.pos-1)
+			}
+		}
+	}
+	return rd.err
+}
+
+func (r *renderer) renderTemplateInstance(rd *reader) error {
+	rd.skip(1) // Unknown
+	rd.skip(4) // Identifier of the template
+	definition := int(rd.u32())
+	if definition == rd.pos {
+		// The definition is only stored along the first instance of the template in the chunk
+		rd.skip(4 + 16) // Offset of the next template and identifier
+		rd.skip(int(rd.u32()))
+	}
+
+	count := int(rd.u32())
+	if rd.err != nil {
+		return rd.err
+	}
+	if count > len(rd.data)-rd.pos {
+		return fmt.Errorf("invalid number of substitutions %d", count)
+	}
+	values := make([]value, count)
+	for i := range values {
+		values[i].size = int(rd.u16())
+		values[i].valueType = rd.u8()
+		rd.skip(1)
+	}
+	for i := range values {
+		values[i].offset = rd.pos
+		rd.skip(values[i].size)
+	}
+	if rd.err != nil {
+		return rd.err
+	}
+
+	// Offset of the next template, identifier and size of the definition
+	header := &reader{data: r.chunk, pos: definition + 4 + 16}
+	size := int(header.u32())
+	if header.err != nil || header.pos+size > len(r.chunk) {
+		return fmt.Errorf("invalid template definition at offset %d", definition)
+	}
+	return r.nested(&reader{data: r.chunk[:header.pos+size], pos: header.pos}, values)
+}
+
+// nested renders a template definition or an embedded fragment.
+func (r *renderer) nested(rd *reader, values []value) error {
+	if r.depth == maxDepth {
+		return fmt.Errorf("more than %d nested templates and fragments", maxDepth)
+	}
+	r.depth++
+	defer func() { r.depth-- }()
+	return r.renderFragment(rd, values)
+}
+
+func (r *renderer) renderElement(rd *reader, values []value, hasAttributes bool) error {
+	if !rd.embedded {
+		// The dependency identifier is not set in the fragments embedded in the substitution values
+		rd.skip(2)
+	}
+	rd.skip(4) // Size of the element
+	name, err := r.readName(rd)
+	if err != nil {
+		return err
+	}
+
+	r.out.WriteString("<")
+	r.out.WriteString(name)
+	if hasAttributes {
+		rd.skip(4) // Size of the attributes
+		for more := true; more && rd.err == nil; {
+			token := rd.u8()
+			if token&^tokenMoreFlag != tokenAttribute {
+				return fmt.Errorf("unexpected token 0x%02x in the attributes at offset %d", token, rd.pos-1)
+			}
+			more = token&tokenMoreFlag != 0
+			if err = r.renderAttribute(rd, values); err != nil {
+				return err
+			}
+		}
+	}
+
+	switch token := rd.u8(); token {
+	case tokenCloseEmptyElement:
+		r.out.WriteString("/>")
+		return rd.err
+	case tokenCloseStartElement:
+		r.out.WriteString(">")
+	default:
+		if rd.err != nil {
+			return rd.err
+		}
+		return fmt.Errorf("unexpected token 0x%02x after the attributes at offset %d", token, rd.pos-1)
+	}
+
+	for rd.err == nil {
+		token := rd.u8()
+		switch token &^ tokenMoreFlag {
+		case tokenEndElement:
+			r.out.WriteString("</")
+			r.out.WriteString(name)
+			r.out.WriteString(">")
+			return rd.err
+		case tokenOpenStartElement:
+			err = r.renderElement(rd, values, token&tokenMoreFlag != 0)
+		case tokenNormalSubstitution, tokenOptionalSubstitution:
+			var v *value
+			if v, err = substitution(rd, values, token); err == nil && v != nil {
+				err = r.renderContent(*v)
+			}
+		case tokenValue, tokenCDATASection, tokenCharRef, tokenEntityRef:
+			err = r.renderNode(rd, token, true)
+		case tokenPITarget:
+			var target string
+			if target, err = r.readName(rd); err == nil {
+				r.out.WriteString("<?")
+				r.out.WriteString(target)
+			}
+		case tokenPIData:
+			r.out.WriteString(" ")
+			r.out.WriteString(rd.utf16String(int(rd.u16())))
+			r.out.WriteString("?>")
+		default:
+			if rd.err == nil {
+				err = fmt.Errorf("unexpected token 0x%02x in element %s at offset %d", token, name, rd.pos-1)
+			}
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return rd.err
+}
+
+// renderAttribute renders an attribute. The attributes whose value is an
+// optional substitution without value are not rendered.
+func (r *renderer) renderAttribute(rd *reader, values []value) error {
+	name, err := r.readName(rd)
+	if err != nil {
+		return err
+	}
+
+	token := rd.u8()
+	switch token &^ tokenMoreFlag {
+	case tokenNormalSubstitution, tokenOptionalSubstitution:
+		v, err := substitution(rd, values, token)
+		if err != nil || v == nil {
+			return err
+		}
+		s, err := r.valueString(*v)
+		if err != nil {
+			return err
+		}
+		r.out.WriteString(" " + name + `="`)
+		escape(&r.out, s)
+		r.out.WriteString(`"`)
+		return nil
+	case tokenValue, tokenCharRef, tokenEntityRef:
+		r.out.WriteString(" " + name + `="`)
+		if err = r.renderNode(rd, token, false); err != nil {
+			return err
+		}
+		r.out.WriteString(`"`)
+		return nil
+	default:
+		if rd.err != nil {
+			return rd.err
+		}
+		return fmt.Errorf("unexpected token 0x%02x in attribute %s at offset %d", token, name, rd.pos-1)
+	}
+}
+
+// renderNode renders the nodes which hold their own value.
+func (r *renderer) renderNode(rd *reader, token byte, content bool) error {
+	switch token &^ tokenMoreFlag {
+	case tokenValue:
+		if valueType := rd.u8(); valueType != typeString && rd.err == nil {
+			return fmt.Errorf("unsupported type 0x%02x of value at offset %d", valueType, rd.pos-1)
+		}
+		escape(&r.out, rd.utf16String(int(rd.u16())))
+	case tokenCDATASection:
+		s := rd.utf16String(int(rd.u16()))
+		if content {
+			r.out.WriteString("<![CDATA[" + s + "]]>")
+		} else {
+			escape(&r.out, s)
+		}
+	case tokenCharRef:
+		fmt.Fprintf(&r.out, "&#%d;", rd.u16())
+	case tokenEntityRef:
+		name, err := r.readName(rd)
+		if err != nil {
+			return err
+		}
+		r.out.WriteString("&" + name + ";")
+	}
+	return rd.err
+}
+
+// renderContent renders a substitution value as the content of an element.
+func (r *renderer) renderContent(v value) error {
+	if v.valueType == typeBinXML {
+		return r.nested(&reader{data: r.chunk[:v.offset+v.size], pos: v.offset, embedded: true}, nil)
+	}
+	s, err := r.valueString(v)
+	if err != nil {
+		return err
+	}
+	escape(&r.out, s)
+	return nil
+}
+
+// substitution returns the value substituted to the token, or nil if an optional substitution has no value.
+func substitution(rd *reader, values []value, token byte) (*value, error) {
+	id := int(rd.u16())
+	rd.skip(1) // Type of the value, also set in the substitution values
+	if rd.err != nil {
+		return nil, rd.err
+	}
+	if id >= len(values) {
+		return nil, fmt.Errorf("invalid substitution %d, the template instance has %d values", id, len(values))
+	}
+	v := values[id]
+	if token&^tokenMoreFlag == tokenOptionalSubstitution && (v.valueType == typeNull || v.size == 0) {
+		return nil, nil
+	}
+	return &v, nil
+}
+
+// readName reads a name from its offset. The name is stored right after its
+// offset the first time it is used in the chunk.
+func (r *renderer) readName(rd *reader) (string, error) {
+	offset := int(rd.u32())
+	if rd.err != nil {
+		return "", rd.err
+	}
+	if offset == rd.pos {
+		// Offset of the next name and hash
+		rd.skip(4 + 2)
+		rd.skip(2*int(rd.u16()) + 2)
+	}
+	if name, ok := r.names[offset]; ok {
+		return name, nil
+	}
+
+	nr := &reader{data: r.chunk, pos: offset + 4 + 2}
+	name := nr.utf16String(int(nr.u16()))
+	if nr.err != nil {
+		return "", fmt.Errorf("invalid name at offset %d", offset)
+	}
+	r.names[offset] = name
+	return name, nil
+}
+
+// valueString formats a substitution value the way the Windows APIs render it.
+func (r *renderer) valueString(v value) (string, error) {
+	data := r.chunk[v.offset : v.offset+v.size]
+
+	if v.valueType&typeArrayFlag != 0 {
+		return arrayString(v.valueType&^typeArrayFlag, data)
+	}
+
+	switch v.valueType {
+	case typeNull:
+		return "", nil
+	case typeString:
+		return strings.TrimRight(decodeUTF16(data), "\x00"), nil
+	case typeAnsiString:
+		return strings.TrimRight(string(data), "\x00"), nil
+	case typeBinary:
+		return strings.ToUpper(hex.EncodeToString(data)), nil
+	case typeBinXML:
+		return "", errors.New("binary xml cannot be rendered as an attribute value")
+	}
+
+	if expected, ok := valueSizes[v.valueType]; ok && len(data) != expected {
+		return "", fmt.Errorf("invalid size %d of value of type 0x%02x", len(data), v.valueType)
+	}
+
+	le := binary.LittleEndian
+	switch v.valueType {
+	case typeInt8:
+		return strconv.FormatInt(int64(int8(data[0])), 10), nil
+	case typeUint8:
+		return strconv.FormatUint(uint64(data[0]), 10), nil
+	case typeInt16:
+		return strconv.FormatInt(int64(int16(le.Uint16(data))), 10), nil
+	case typeUint16:
+		return strconv.FormatUint(uint64(le.Uint16(data)), 10), nil
+	case typeInt32:
+		return strconv.FormatInt(int64(int32(le.Uint32(data))), 10), nil
+	case typeUint32:
+		return strconv.FormatUint(uint64(le.Uint32(data)), 10), nil
+	case typeInt64:
+		return strconv.FormatInt(int64(le.Uint64(data)), 10), nil
+	case typeUint64:
+		return strconv.FormatUint(le.Uint64(data), 10), nil
+	case typeReal32:
+		return strconv.FormatFloat(float64(math.Float32frombits(le.Uint32(data))), 'g', -1, 32), nil
+	case typeReal64:
+		return strconv.FormatFloat(math.Float64frombits(le.Uint64(data)), 'g', -1, 64), nil
+	case typeBool:
+		return strconv.FormatBool(le.Uint32(data) != 0), nil
+	case typeGUID:
+		return fmt.Sprintf("{%08X-%04X-%04X-%X-%X}", le.Uint32(data), le.Uint16(data[4:]), le.Uint16(data[6:]), data[8:10], data[10:16]), nil
+	case typeSizeT:
+		switch len(data) {
+		case 4:
+			return fmt.Sprintf("0x%08x", le.Uint32(data)), nil
+		case 8:
+			return fmt.Sprintf("0x%016x", le.Uint64(data)), nil
+		}
+		return "", fmt.Errorf("invalid size %d of value of type 0x%02x", len(data), v.valueType)
+	case typeFileTime:
+		return fileTime(le.Uint64(data)).Format(systemTimeFormat), nil
+	case typeSystemTime:
+		t := time.Date(int(le.Uint16(data)), time.Month(le.Uint16(data[2:])), int(le.Uint16(data[6:])),
+			int(le.Uint16(data[8:])), int(le.Uint16(data[10:])), int(le.Uint16(data[12:])),
+			int(le.Uint16(data[14:]))*int(time.Millisecond), time.UTC)
+		return t.Format(systemTimeFormat), nil
+	case typeSID:
+		return sidString(data)
+	case typeHexInt32:
+		return fmt.Sprintf("0x%x", le.Uint32(data)), nil
+	case typeHexInt64:
+		return fmt.Sprintf("0x%x", le.Uint64(data)), nil
+	default:
+		return "", fmt.Errorf("unsupported type 0x%02x of value", v.valueType)
+	}
+}
+
+// arrayString formats the arrays of values, whose items are separated by commas.
+func arrayString(itemType byte, data []byte) (string, error) {
+	var items []string
+	switch itemType {
+	case typeString:
+		items = strings.Split(strings.TrimRight(decodeUTF16(data), "\x00"), "\x00")
+	case typeAnsiString:
+		items = strings.Split(strings.TrimRight(string(data), "\x00"), "\x00")
+	default:
+		size := valueSizes[itemType]
+		if size == 0 || len(data)%size != 0 {
+			return "", fmt.Errorf("unsupported array of values of type 0x%02x", itemType)
+		}
+		r := &renderer{chunk: data}
+		for offset := 0; offset < len(data); offset += size {
+			item, err := r.valueString(value{valueType: itemType, offset: offset, size: size})
+			if err != nil {
+				return "", err
+			}
+			items = append(items, item)
+		}
+	}
+	return strings.Join(items, ","), nil
+}
+
+func sidString(data []byte) (string, error) {
+	if len(data) < 8 || len(data) != 8+4*int(data[1]) {
+		return "", fmt.Errorf("invalid size %d of sid", len(data))
+	}
+	var authority uint64
+	for _, b := range data[2:8] {
+		authority = authority<<8 | uint64(b)
+	}
+	sid := fmt.Sprintf("S-%d-%d", data[0], authority)
+	for i := 8; i < len(data); i += 4 {
+		sid += "-" + strconv.FormatUint(uint64(binary.LittleEndian.Uint32(data[i:])), 10)
+	}
+	return sid, nil
+}
+
+func escape(buf *bytes.Buffer, s string) {
+	// EscapeText only fails when writing to the buffer fails
+	_ = xml.EscapeText(buf, []byte(s))
+}
+
+func decodeUTF16(data []byte) string {
+	chars := make([]uint16, len(data)/2)
+	for i := range chars {
+		chars[i] = binary.LittleEndian.Uint16(data[2*i:])
+	}
+	return string(utf16.Decode(chars))
+}
+
+// reader reads little endian values. The first read out of bounds sets its
+// error, the following reads then return zero values.
+type reader struct {
+	data []byte
+	pos  int
+	// embedded is set when reading a fragment embedded in a substitution value
+	embedded bool
+	err      error
+}
+
+func (rd *reader) next(n int) []byte {
+	if rd.err != nil {
+		return nil
+	}
+	if n < 0 || rd.pos < 0 || rd.pos+n > len(rd.data) {
+		rd.err = errOutOfBounds
+		return nil
+	}
+	b := rd.data[rd.pos : rd.pos+n]
+	rd.pos += n
+	return b
+}
+
+func (rd *reader) skip(n int) {
+	rd.next(n)
+}
+
+func (rd *reader) u8() byte {
+	if b := rd.next(1); b != nil {
+		return b[0]
+	}
+	return 0
+}
+
+func (rd *reader) u16() uint16 {
+	if b := rd.next(2); b != nil {
+		return binary.LittleEndian.Uint16(b)
+	}
+	return 0
+}
+
+func (rd *reader) u32() uint32 {
+	if b := rd.next(4); b != nil {
+		return binary.LittleEndian.Uint32(b)
+	}
+	return 0
+}
+
+func (rd *reader) utf16String(chars int) string {
+	return decodeUTF16(rd.next(2 * chars))
+}
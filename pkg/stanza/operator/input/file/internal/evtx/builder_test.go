@@ -0,0 +1,288 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package evtx
+
+import (
+	"encoding/binary"
+	"time"
+	"unicode/utf16"
+)
+
+// node is a node of the binary xml written by the chunk builder.
+type node interface {
+	write(b *chunkBuilder, embedded bool)
+}
+
+type element struct {
+	name     string
+	attrs    []attribute
+	children []node
+}
+
+type attribute struct {
+	name  string
+	value node
+}
+
+type text string
+
+type sub struct {
+	id       uint16
+	optional bool
+}
+
+func (e element) write(b *chunkBuilder, embedded bool) {
+	token := byte(tokenOpenStartElement)
+	if len(e.attrs) > 0 {
+		token |= tokenMoreFlag
+	}
+	b.u8(token)
+	if !embedded {
+		b.u16(0xffff)
+	}
+	b.u32(0) // Size, not used when rendering
+	b.name(e.name)
+	if len(e.attrs) > 0 {
+		b.u32(0)
+		for i, attr := range e.attrs {
+			token = tokenAttribute
+			if i < len(e.attrs)-1 {
+				token |= tokenMoreFlag
+			}
+			b.u8(token)
+			b.name(attr.name)
+			attr.value.write(b, embedded)
+		}
+	}
+	if len(e.children) == 0 {
+		b.u8(tokenCloseEmptyElement)
+		return
+	}
+	b.u8(tokenCloseStartElement)
+	for _, child := range e.children {
+		child.write(b, embedded)
+	}
+	b.u8(tokenEndElement)
+}
+
+func (t text) write(b *chunkBuilder, _ bool) {
+	b.u8(tokenValue)
+	b.u8(typeString)
+	b.u16(uint16(len(utf16.Encode([]rune(string(t))))))
+	b.bytes(utf16String(string(t)))
+}
+
+func (s sub) write(b *chunkBuilder, _ bool) {
+	if s.optional {
+		b.u8(tokenOptionalSubstitution)
+	} else {
+		b.u8(tokenNormalSubstitution)
+	}
+	b.u16(s.id)
+	b.u8(typeString)
+}
+
+// substitutionValue is a value of a template instance. The embedded
+// fragment of binary xml values is written in place of their data.
+type substitutionValue struct {
+	valueType byte
+	data      []byte
+	fragment  node
+}
+
+// chunkBuilder writes the binary xml of a chunk, the same way Windows does:
+// the names and the template definitions are written along their first use.
+type chunkBuilder struct {
+	buf       []byte
+	names     map[string]int
+	templates map[string]int
+}
+
+func newChunkBuilder() *chunkBuilder {
+	b := &chunkBuilder{
+		buf:       make([]byte, chunkHeaderSize),
+		names:     map[string]int{},
+		templates: map[string]int{},
+	}
+	copy(b.buf, chunkMagic)
+	binary.LittleEndian.PutUint32(b.buf[40:], 128)
+	return b
+}
+
+func (b *chunkBuilder) u8(v byte) {
+	b.buf = append(b.buf, v)
+}
+
+func (b *chunkBuilder) u16(v uint16) {
+	b.buf = binary.LittleEndian.AppendUint16(b.buf, v)
+}
+
+func (b *chunkBuilder) u32(v uint32) {
+	b.buf = binary.LittleEndian.AppendUint32(b.buf, v)
+}
+
+func (b *chunkBuilder) u64(v uint64) {
+	b.buf = binary.LittleEndian.AppendUint64(b.buf, v)
+}
+
+func (b *chunkBuilder) bytes(v []byte) {
+	b.buf = append(b.buf, v...)
+}
+
+func (b *chunkBuilder) name(name string) {
+	if offset, ok := b.names[name]; ok {
+		b.u32(uint32(offset))
+		return
+	}
+	offset := len(b.buf) + 4
+	b.names[name] = offset
+	b.u32(uint32(offset))
+	b.u32(0) // Offset of the next name
+	b.u16(0) // Hash
+	b.u16(uint16(len(utf16.Encode([]rune(name)))))
+	b.bytes(utf16String(name))
+	b.u16(0)
+}
+
+func (b *chunkBuilder) fragment(root node, embedded bool) {
+	b.bytes([]byte{tokenFragmentHeader, 1, 1, 0})
+	root.write(b, embedded)
+	b.u8(tokenEOF)
+}
+
+func (b *chunkBuilder) templateInstance(key string, template node, values []substitutionValue) {
+	b.u8(tokenTemplateInstance)
+	b.u8(1)
+	b.u32(uint32(len(b.templates)))
+	if offset, ok := b.templates[key]; ok {
+		b.u32(uint32(offset))
+	} else {
+		offset = len(b.buf) + 4
+		b.templates[key] = offset
+		b.u32(uint32(offset))
+		b.u32(0)                  // Offset of the next template
+		b.bytes(make([]byte, 16)) // Identifier
+		sizeOffset := len(b.buf)
+		b.u32(0)
+		b.fragment(template, false)
+		binary.LittleEndian.PutUint32(b.buf[sizeOffset:], uint32(len(b.buf)-sizeOffset-4))
+	}
+
+	b.u32(uint32(len(values)))
+	descriptors := len(b.buf)
+	for _, v := range values {
+		b.u16(0)
+		b.u8(v.valueType)
+		b.u8(0)
+	}
+	for i, v := range values {
+		start := len(b.buf)
+		if v.fragment != nil {
+			b.fragment(v.fragment, true)
+		} else {
+			b.bytes(v.data)
+		}
+		binary.LittleEndian.PutUint16(b.buf[descriptors+4*i:], uint16(len(b.buf)-start))
+	}
+}
+
+func (b *chunkBuilder) record(id uint64, written time.Time, key string, template node, values []substitutionValue) {
+	start := len(b.buf)
+	b.bytes(recordMagic)
+	b.u32(0)
+	b.u64(id)
+	b.u64(toFileTime(written))
+	b.bytes([]byte{tokenFragmentHeader, 1, 1, 0})
+	b.templateInstance(key, template, values)
+	b.u8(tokenEOF)
+	size := uint32(len(b.buf) - start + 4)
+	b.u32(size)
+	binary.LittleEndian.PutUint32(b.buf[start+4:], size)
+}
+
+// chunk returns the chunk, padded to its size.
+func (b *chunkBuilder) chunk() []byte {
+	binary.LittleEndian.PutUint32(b.buf[48:], uint32(len(b.buf)))
+	chunk := make([]byte, ChunkSize)
+	copy(chunk, b.buf)
+	return chunk
+}
+
+// file returns an evtx file made of the chunks.
+func file(chunks ...[]byte) []byte {
+	header := make([]byte, FileHeaderSize)
+	copy(header, fileMagic)
+	binary.LittleEndian.PutUint32(header[32:], 128)
+	binary.LittleEndian.PutUint16(header[38:], 3)
+	binary.LittleEndian.PutUint16(header[40:], FileHeaderSize)
+	binary.LittleEndian.PutUint16(header[42:], uint16(len(chunks)))
+	data := header
+	for _, chunk := range chunks {
+		data = append(data, chunk...)
+	}
+	return data
+}
+
+func utf16String(s string) []byte {
+	var data []byte
+	for _, c := range utf16.Encode([]rune(s)) {
+		data = binary.LittleEndian.AppendUint16(data, c)
+	}
+	return data
+}
+
+func toFileTime(t time.Time) uint64 {
+	return uint64(t.UnixNano()/100 + 116444736000000000)
+}
+
+func stringValue(s string) substitutionValue {
+	return substitutionValue{valueType: typeString, data: utf16String(s)}
+}
+
+func uint8Value(v uint8) substitutionValue {
+	return substitutionValue{valueType: typeUint8, data: []byte{v}}
+}
+
+func uint16Value(v uint16) substitutionValue {
+	return substitutionValue{valueType: typeUint16, data: binary.LittleEndian.AppendUint16(nil, v)}
+}
+
+func uint32Value(v uint32) substitutionValue {
+	return substitutionValue{valueType: typeUint32, data: binary.LittleEndian.AppendUint32(nil, v)}
+}
+
+func uint64Value(v uint64) substitutionValue {
+	return substitutionValue{valueType: typeUint64, data: binary.LittleEndian.AppendUint64(nil, v)}
+}
+
+func hexInt64Value(v uint64) substitutionValue {
+	return substitutionValue{valueType: typeHexInt64, data: binary.LittleEndian.AppendUint64(nil, v)}
+}
+
+func fileTimeValue(t time.Time) substitutionValue {
+	return substitutionValue{valueType: typeFileTime, data: binary.LittleEndian.AppendUint64(nil, toFileTime(t))}
+}
+
+func guidValue(data1 uint32, data2, data3 uint16, data4 [8]byte) substitutionValue {
+	data := binary.LittleEndian.AppendUint32(nil, data1)
+	data = binary.LittleEndian.AppendUint16(data, data2)
+	data = binary.LittleEndian.AppendUint16(data, data3)
+	return substitutionValue{valueType: typeGUID, data: append(data, data4[:]...)}
+}
+
+func sidValue(authority byte, subAuthorities ...uint32) substitutionValue {
+	data := []byte{1, byte(len(subAuthorities)), 0, 0, 0, 0, 0, authority}
+	for _, s := range subAuthorities {
+		data = binary.LittleEndian.AppendUint32(data, s)
+	}
+	return substitutionValue{valueType: typeSID, data: data}
+}
+
+func nullValue() substitutionValue {
+	return substitutionValue{valueType: typeNull}
+}
+
+func binXMLValue(fragment node) substitutionValue {
+	return substitutionValue{valueType: typeBinXML, fragment: fragment}
+}
@@ -0,0 +1,108 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+// Package evtx reads the events of the Windows XML Event Log (EVTX) files.
+// The events are stored as binary xml, which is rendered back to the xml
+// returned by the Windows APIs.
+// See https://github.com/libyal/libevtx/blob/main/documentation/Windows%20XML%20Event%20Log%20(EVTX).asciidoc
+package evtx // import "github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/operator/input/file/internal/evtx"
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"time"
+)
+
+const (
+	// FileHeaderSize is the size of the header at the start of a file.
+	FileHeaderSize = 4096
+	// ChunkSize is the size of the chunks of events following the file header.
+	ChunkSize = 65536
+
+	chunkHeaderSize  = 512
+	recordHeaderSize = 24
+)
+
+var (
+	fileMagic   = []byte("ElfFile\x00")
+	chunkMagic  = []byte("ElfChnk\x00")
+	recordMagic = []byte("**\x00\x00")
+)
+
+// SplitFunc splits a file into its chunks of events. The file header and the
+// chunks which have not been initialized yet do not produce any token.
+func SplitFunc(data []byte, _ bool) (int, []byte, error) {
+	if bytes.HasPrefix(data, fileMagic) {
+		if len(data) < FileHeaderSize {
+			return 0, nil, nil
+		}
+		return FileHeaderSize, nil, nil
+	}
+	if len(data) < ChunkSize {
+		// A partial chunk is read again once it has been entirely written
+		return 0, nil, nil
+	}
+	if !bytes.HasPrefix(data, chunkMagic) {
+		return ChunkSize, nil, nil
+	}
+	return ChunkSize, data[:ChunkSize], nil
+}
+
+// Record is an event read from a chunk.
+type Record struct {
+	ID uint64
+	// Written is the time the event has been written to the file.
+	Written time.Time
+	// XML is the event rendered as xml.
+	XML []byte
+}
+
+// ParseChunk renders the events of a chunk. The events which cannot be rendered
+// are reported in the error, the other events of the chunk are still returned.
+func ParseChunk(chunk []byte) ([]Record, error) {
+	if len(chunk) < chunkHeaderSize || !bytes.HasPrefix(chunk, chunkMagic) {
+		return nil, errors.New("invalid chunk header")
+	}
+
+	end := int(binary.LittleEndian.Uint32(chunk[48:]))
+	if end < chunkHeaderSize || end > len(chunk) {
+		return nil, fmt.Errorf("invalid end of the records %d", end)
+	}
+
+	var records []Record
+	var errs []error
+	names := map[int]string{}
+	for offset := chunkHeaderSize; offset+recordHeaderSize+4 <= end; {
+		if !bytes.Equal(chunk[offset:offset+4], recordMagic) {
+			errs = append(errs, fmt.Errorf("invalid record header at offset %d", offset))
+			break
+		}
+		size := int(binary.LittleEndian.Uint32(chunk[offset+4:]))
+		if size < recordHeaderSize+4 || offset+size > end {
+			errs = append(errs, fmt.Errorf("invalid record size %d at offset %d", size, offset))
+			break
+		}
+
+		record := Record{
+			ID:      binary.LittleEndian.Uint64(chunk[offset+8:]),
+			Written: fileTime(binary.LittleEndian.Uint64(chunk[offset+16:])),
+		}
+		r := &renderer{chunk: chunk, names: names}
+		if err := r.render(offset+recordHeaderSize, offset+size-4); err != nil {
+			errs = append(errs, fmt.Errorf("render record %d: %w", record.ID, err))
+		} else {
+			record.XML = r.out.Bytes()
+			records = append(records, record)
+		}
+		offset += size
+	}
+	return records, errors.Join(errs...)
+}
+
+// fileTime converts a FILETIME, the number of 100 nanoseconds since 1601, to a time.
+func fileTime(ft uint64) time.Time {
+	const epochDelta = 116444736000000000 // 100 nanoseconds between 1601 and 1970
+	return time.Unix(0, (int64(ft)-epochDelta)*100).UTC()
+}
@@ -0,0 +1,261 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package evtx
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// eventTemplate is the template of the events of the tests. The events with
+// an embedded fragment use it in place of the event data.
+func eventTemplate(embedded bool) node {
+	data := node(element{name: "EventData", children: []node{
+		element{name: "Data", attrs: []attribute{{name: "Name", value: text("Time")}}, children: []node{sub{id: 14}}},
+		element{name: "Data", attrs: []attribute{{name: "Name", value: text("Source")}}, children: []node{sub{id: 15}}},
+	}})
+	if embedded {
+		data = sub{id: 14, optional: true}
+	}
+	return element{
+		name:  "Event",
+		attrs: []attribute{{name: "xmlns", value: text("http://schemas.microsoft.com/win/2004/08/events/event")}},
+		children: []node{
+			element{name: "System", children: []node{
+				element{name: "Provider", attrs: []attribute{
+					{name: "Name", value: sub{id: 0}},
+					{name: "Guid", value: sub{id: 1, optional: true}},
+					{name: "EventSourceName", value: sub{id: 17, optional: true}},
+				}},
+				element{name: "EventID", attrs: []attribute{{name: "Qualifiers", value: sub{id: 2, optional: true}}}, children: []node{sub{id: 3}}},
+				element{name: "Level", children: []node{sub{id: 4}}},
+				element{name: "Task", children: []node{sub{id: 5}}},
+				element{name: "Opcode", children: []node{sub{id: 16}}},
+				element{name: "Keywords", children: []node{sub{id: 6}}},
+				element{name: "TimeCreated", attrs: []attribute{{name: "SystemTime", value: sub{id: 7}}}},
+				element{name: "EventRecordID", children: []node{sub{id: 8}}},
+				element{name: "Execution", attrs: []attribute{
+					{name: "ProcessID", value: sub{id: 9}},
+					{name: "ThreadID", value: sub{id: 10}},
+				}},
+				element{name: "Channel", children: []node{sub{id: 11}}},
+				element{name: "Computer", children: []node{sub{id: 12}}},
+				element{name: "Security", attrs: []attribute{{name: "UserID", value: sub{id: 13, optional: true}}}},
+			}},
+			data,
+		},
+	}
+}
+
+var (
+	firstWritten = time.Date(2022, time.April, 22, 10, 20, 52, 377862500, time.UTC)
+	lastWritten  = time.Date(2023, time.October, 12, 10, 38, 24, 543506200, time.UTC)
+)
+
+// testChunk returns a chunk of three events. The first and the last events
+// share their template, the second has user data embedded in its values.
+func testChunk() []byte {
+	b := newChunkBuilder()
+	b.record(23401, firstWritten, "event", eventTemplate(false), []substitutionValue{
+		stringValue("Microsoft-Windows-Security-SPP"),
+		guidValue(0xE23B33B0, 0xC8C9, 0x472C, [8]byte{0xA5, 0xF9, 0xF2, 0xBD, 0xFE, 0xA0, 0xF1, 0x56}),
+		uint16Value(16384),
+		uint16Value(16384),
+		uint8Value(4),
+		uint16Value(0),
+		hexInt64Value(0x80000000000000),
+		fileTimeValue(firstWritten),
+		uint64Value(23401),
+		uint32Value(0),
+		uint32Value(0),
+		stringValue("Application"),
+		stringValue("computer"),
+		nullValue(),
+		stringValue("2022-04-28T19:48:52Z"),
+		stringValue("RulesEngine"),
+		uint8Value(0),
+		stringValue("Software Protection Platform Service"),
+	})
+	b.record(23402, lastWritten, "user_data", eventTemplate(true), []substitutionValue{
+		stringValue("Microsoft-Windows-Eventlog"),
+		guidValue(0xFC65DDD8, 0xD6EF, 0x4962, [8]byte{0x83, 0xD5, 0x6E, 0x5C, 0xFE, 0x9C, 0xE1, 0x48}),
+		nullValue(),
+		uint16Value(1102),
+		uint8Value(4),
+		uint16Value(104),
+		hexInt64Value(0x4020000000000000),
+		fileTimeValue(lastWritten),
+		uint64Value(2590526),
+		uint32Value(1472),
+		uint32Value(7784),
+		stringValue("Security"),
+		stringValue("test.example.com"),
+		sidValue(5, 18),
+		binXMLValue(element{name: "UserData", children: []node{
+			element{name: "LogFileCleared", children: []node{
+				element{name: "SubjectUserName", children: []node{text("admin & co")}},
+			}},
+		}}),
+		nullValue(),
+		uint8Value(0),
+		nullValue(),
+	})
+	b.record(23403, lastWritten, "event", eventTemplate(false), []substitutionValue{
+		stringValue("Microsoft-Windows-Security-SPP"),
+		nullValue(),
+		nullValue(),
+		uint16Value(1003),
+		uint8Value(2),
+		uint16Value(0),
+		hexInt64Value(0x80000000000000),
+		fileTimeValue(lastWritten),
+		uint64Value(23403),
+		uint32Value(0),
+		uint32Value(0),
+		stringValue("Application"),
+		stringValue("computer"),
+		nullValue(),
+		stringValue(""),
+		stringValue("<RulesEngine>"),
+		uint8Value(0),
+		nullValue(),
+	})
+	return b.chunk()
+}
+
+func TestParseChunk(t *testing.T) {
+	records, err := ParseChunk(testChunk())
+	require.NoError(t, err)
+	require.Equal(t, []Record{
+		{
+			ID:      23401,
+			Written: firstWritten,
+			XML: []byte(`<Event xmlns="http://schemas.microsoft.com/win/2004/08/events/event"><System>` +
+				`<Provider Name="Microsoft-Windows-Security-SPP" Guid="{E23B33B0-C8C9-472C-A5F9-F2BDFEA0F156}" EventSourceName="Software Protection Platform Service"/>` +
+				`<EventID Qualifiers="16384">16384</EventID><Level>4</Level><Task>0</Task><Opcode>0</Opcode><Keywords>0x80000000000000</Keywords>` +
+				`<TimeCreated SystemTime="2022-04-22T10:20:52.3778625Z"/><EventRecordID>23401</EventRecordID>` +
+				`<Execution ProcessID="0" ThreadID="0"/><Channel>Application</Channel><Computer>computer</Computer><Security/></System>` +
+				`<EventData><Data Name="Time">2022-04-28T19:48:52Z</Data><Data Name="Source">RulesEngine</Data></EventData></Event>`),
+		},
+		{
+			ID:      23402,
+			Written: lastWritten,
+			XML: []byte(`<Event xmlns="http://schemas.microsoft.com/win/2004/08/events/event"><System>` +
+				`<Provider Name="Microsoft-Windows-Eventlog" Guid="{FC65DDD8-D6EF-4962-83D5-6E5CFE9CE148}"/>` +
+				`<EventID>1102</EventID><Level>4</Level><Task>104</Task><Opcode>0</Opcode><Keywords>0x4020000000000000</Keywords>` +
+				`<TimeCreated SystemTime="2023-10-12T10:38:24.5435062Z"/><EventRecordID>2590526</EventRecordID>` +
+				`<Execution ProcessID="1472" ThreadID="7784"/><Channel>Security</Channel><Computer>test.example.com</Computer>` +
+				`<Security UserID="S-1-5-18"/></System>` +
+				`<UserData><LogFileCleared><SubjectUserName>admin &amp; co</SubjectUserName></LogFileCleared></UserData></Event>`),
+		},
+		{
+			ID:      23403,
+			Written: lastWritten,
+			XML: []byte(`<Event xmlns="http://schemas.microsoft.com/win/2004/08/events/event"><System>` +
+				`<Provider Name="Microsoft-Windows-Security-SPP"/>` +
+				`<EventID>1003</EventID><Level>2</Level><Task>0</Task><Opcode>0</Opcode><Keywords>0x80000000000000</Keywords>` +
+				`<TimeCreated SystemTime="2023-10-12T10:38:24.5435062Z"/><EventRecordID>23403</EventRecordID>` +
+				`<Execution ProcessID="0" ThreadID="0"/><Channel>Application</Channel><Computer>computer</Computer><Security/></System>` +
+				`<EventData><Data Name="Time"></Data><Data Name="Source">&lt;RulesEngine&gt;</Data></EventData></Event>`),
+		},
+	}, records)
+}
+
+func TestParseChunkErrors(t *testing.T) {
+	valid := testChunk()
+
+	cases := []struct {
+		name    string
+		chunk   func() []byte
+		records int
+		err     string
+	}{
+		{
+			name:  "invalid_header",
+			chunk: func() []byte { return file() },
+			err:   "invalid chunk header",
+		},
+		{
+			name: "invalid_end",
+			chunk: func() []byte {
+				chunk := bytes.Clone(valid)
+				binary.LittleEndian.PutUint32(chunk[48:], ChunkSize+1)
+				return chunk
+			},
+			err: "invalid end of the records 65537",
+		},
+		{
+			name: "invalid_record_size",
+			chunk: func() []byte {
+				chunk := bytes.Clone(valid)
+				binary.LittleEndian.PutUint32(chunk[chunkHeaderSize+4:], ChunkSize)
+				return chunk
+			},
+			err: "invalid record size 65536 at offset 512",
+		},
+		{
+			name: "invalid_substitution",
+			chunk: func() []byte {
+				b := newChunkBuilder()
+				b.record(1, firstWritten, "event", eventTemplate(false), []substitutionValue{stringValue("provider")})
+				b.record(2, firstWritten, "other", element{name: "Event"}, nil)
+				return b.chunk()
+			},
+			records: 1,
+			err:     "render record 1: invalid substitution 1, the template instance has 1 values",
+		},
+		{
+			name: "truncated",
+			chunk: func() []byte {
+				b := newChunkBuilder()
+				b.record(1, firstWritten, "event", element{name: "Event", children: []node{text("event")}}, nil)
+				// Drop the end of the element and of the fragment
+				b.buf = append(b.buf[:len(b.buf)-6], b.buf[len(b.buf)-4:]...)
+				binary.LittleEndian.PutUint32(b.buf[chunkHeaderSize+4:], uint32(len(b.buf)-chunkHeaderSize))
+				return b.chunk()
+			},
+			err: "render record 1: unexpected end of data",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			records, err := ParseChunk(tc.chunk())
+			require.EqualError(t, err, tc.err)
+			require.Len(t, records, tc.records)
+		})
+	}
+}
+
+func TestSplitFunc(t *testing.T) {
+	chunk := testChunk()
+	data := file(chunk, make([]byte, ChunkSize), chunk)
+
+	scanner := bufio.NewScanner(bytes.NewReader(append(data, chunk[:ChunkSize/2]...)))
+	scanner.Buffer(make([]byte, 0, 4096), 2*ChunkSize)
+	scanner.Split(SplitFunc)
+
+	var tokens [][]byte
+	for scanner.Scan() {
+		tokens = append(tokens, scanner.Bytes())
+	}
+	require.NoError(t, scanner.Err())
+	// The uninitialized chunk is skipped and the partial chunk is not read yet
+	require.Equal(t, [][]byte{chunk, chunk}, tokens)
+}
+
+// TestSampleFile ensures that the sample file read by the file input tests
+// contains the test chunk.
+func TestSampleFile(t *testing.T) {
+	data, err := os.ReadFile(filepath.Join("testdata", "sample.evtx"))
+	require.NoError(t, err)
+	require.Equal(t, file(testChunk()), data)
+}
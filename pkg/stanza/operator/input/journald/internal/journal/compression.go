@@ -0,0 +1,109 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package journal // import "github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/operator/input/journald/internal/journal"
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+var errCorruptLZ4 = errors.New("corrupt lz4 data")
+
+type zstdDecoder struct {
+	once    sync.Once
+	decoder *zstd.Decoder
+	err     error
+}
+
+// defaultZstdDecoder is shared by the files, it is created on first use.
+var defaultZstdDecoder = &zstdDecoder{}
+
+func (d *zstdDecoder) decode(data []byte) ([]byte, error) {
+	d.once.Do(func() {
+		d.decoder, d.err = zstd.NewReader(nil, zstd.WithDecoderMaxMemory(maxObjectSize))
+	})
+	if d.err != nil {
+		return nil, d.err
+	}
+	return d.decoder.DecodeAll(data, nil)
+}
+
+// decodeLZ4 decompresses the data of a field compressed by journald: an lz4
+// block, prefixed by its decompressed size.
+func decodeLZ4(data []byte) ([]byte, error) {
+	if len(data) < 8 {
+		return nil, errCorruptLZ4
+	}
+	size := binary.LittleEndian.Uint64(data)
+	if size > maxObjectSize {
+		return nil, fmt.Errorf("invalid lz4 size %d", size)
+	}
+	src := data[8:]
+	dst := make([]byte, 0, size)
+	for len(src) > 0 {
+		token := src[0]
+		src = src[1:]
+
+		literals, n, ok := lz4Length(src, int(token>>4))
+		if !ok {
+			return nil, errCorruptLZ4
+		}
+		src = src[n:]
+		if literals > len(src) || uint64(len(dst)+literals) > size {
+			return nil, errCorruptLZ4
+		}
+		dst = append(dst, src[:literals]...)
+		src = src[literals:]
+		if len(src) == 0 {
+			// The last sequence only holds literals
+			break
+		}
+
+		if len(src) < 2 {
+			return nil, errCorruptLZ4
+		}
+		offset := int(binary.LittleEndian.Uint16(src))
+		src = src[2:]
+		if offset == 0 || offset > len(dst) {
+			return nil, errCorruptLZ4
+		}
+		match, n, ok := lz4Length(src, int(token&0xf))
+		if !ok {
+			return nil, errCorruptLZ4
+		}
+		src = src[n:]
+		match += 4
+		if uint64(len(dst)+match) > size {
+			return nil, errCorruptLZ4
+		}
+		// The match may overlap the bytes it appends
+		start := len(dst) - offset
+		for i := 0; i < match; i++ {
+			dst = append(dst, dst[start+i])
+		}
+	}
+	if uint64(len(dst)) != size {
+		return nil, errCorruptLZ4
+	}
+	return dst, nil
+}
+
+// lz4Length returns a length of a sequence, which continues in the bytes of
+// src when its value in the token is 15, and the number of bytes read.
+func lz4Length(src []byte, length int) (int, int, bool) {
+	if length != 0xf {
+		return length, 0, true
+	}
+	for n, b := range src {
+		length += int(b)
+		if b != 0xff {
+			return length, n + 1, true
+		}
+	}
+	return 0, 0, false
+}
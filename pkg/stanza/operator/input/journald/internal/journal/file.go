@@ -0,0 +1,458 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+// Package journal reads the files of the systemd journal, as documented in
+// https://systemd.io/JOURNAL_FILE_FORMAT/.
+package journal // import "github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/operator/input/journald/internal/journal"
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+)
+
+var signature = []byte("LPKSHHRH")
+
+const (
+	// headerMinSize is the size of the oldest headers, the fields read by
+	// this package are all part of them.
+	headerMinSize = 208
+
+	objectHeaderSize     = 16
+	entryObjectMinSize   = 64
+	entryArrayHeaderSize = 24
+	dataPayloadOffset    = 64
+	// compactDataPayloadOffset is the offset of the payload of data objects
+	// in compact files, which also store the tail of the entry array.
+	compactDataPayloadOffset = 72
+
+	// maxObjectSize bounds the size of the objects read, it is the maximum
+	// size of the data of a field of journald.
+	maxObjectSize = 768 * 1024 * 1024
+)
+
+// Incompatible flags of the header
+const (
+	flagCompressedXZ   = 1 << 0
+	flagCompressedLZ4  = 1 << 1
+	flagKeyedHash      = 1 << 2
+	flagCompressedZSTD = 1 << 3
+	flagCompact        = 1 << 4
+
+	knownFlags = flagCompressedXZ | flagCompressedLZ4 | flagKeyedHash | flagCompressedZSTD | flagCompact
+)
+
+// Object types
+const (
+	objectData       = 1
+	objectEntry      = 3
+	objectEntryArray = 6
+)
+
+// Flags of the objects
+const (
+	objectCompressedXZ   = 1 << 0
+	objectCompressedLZ4  = 1 << 1
+	objectCompressedZSTD = 1 << 2
+)
+
+// ID is a 128 bits identifier of systemd.
+type ID [16]byte
+
+func (id ID) String() string {
+	return hex.EncodeToString(id[:])
+}
+
+// ParseID parses an identifier formatted as 32 hexadecimal characters.
+func ParseID(s string) (ID, error) {
+	var id ID
+	if len(s) != 2*len(id) {
+		return id, fmt.Errorf("invalid id %q", s)
+	}
+	if _, err := hex.Decode(id[:], []byte(s)); err != nil {
+		return id, fmt.Errorf("invalid id %q", s)
+	}
+	return id, nil
+}
+
+type header struct {
+	flags       uint32
+	fileID      ID
+	seqnumID    ID
+	end         uint64
+	entries     uint64
+	entryArrays uint64
+}
+
+// entryArray is an entry array of the chain of a file.
+type entryArray struct {
+	offset   uint64
+	capacity uint64
+}
+
+// File is a journal file. The entries appended to the file after it is
+// opened are read once Refresh is called.
+type File struct {
+	f      io.ReaderAt
+	closer io.Closer
+	header header
+	arrays []entryArray
+	zstd   *zstdDecoder
+}
+
+// OpenFile opens the journal file at the path.
+func OpenFile(path string) (*File, error) {
+	f, err := os.Open(path) // #nosec - the journal files are configured by the user
+	if err != nil {
+		return nil, err
+	}
+	file, err := newFile(f)
+	if err != nil {
+		f.Close()
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	file.closer = f
+	return file, nil
+}
+
+func newFile(r io.ReaderAt) (*File, error) {
+	f := &File{f: r, zstd: defaultZstdDecoder}
+	if err := f.Refresh(); err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+// Refresh reads the header of the file again, to find the entries
+// appended since it was last read.
+func (f *File) Refresh() error {
+	buf := make([]byte, headerMinSize)
+	if _, err := f.f.ReadAt(buf, 0); err != nil {
+		return fmt.Errorf("read header: %w", err)
+	}
+	if !bytes.Equal(buf[:8], signature) {
+		return errors.New("not a journal file")
+	}
+	h := header{flags: binary.LittleEndian.Uint32(buf[12:])}
+	if unknown := h.flags &^ knownFlags; unknown != 0 {
+		return fmt.Errorf("unsupported incompatible flags %#x", unknown)
+	}
+	copy(h.fileID[:], buf[24:])
+	copy(h.seqnumID[:], buf[72:])
+	headerSize := binary.LittleEndian.Uint64(buf[88:])
+	h.end = headerSize + binary.LittleEndian.Uint64(buf[96:])
+	h.entries = binary.LittleEndian.Uint64(buf[152:])
+	h.entryArrays = binary.LittleEndian.Uint64(buf[176:])
+	if headerSize < headerMinSize || h.end < headerSize {
+		return errors.New("invalid header")
+	}
+	if f.header.fileID != (ID{}) && f.header.fileID != h.fileID {
+		return errors.New("the file was replaced")
+	}
+	f.header = h
+	return nil
+}
+
+// ID returns the identifier of the file.
+func (f *File) ID() ID {
+	return f.header.fileID
+}
+
+// Entries returns the number of entries of the file.
+func (f *File) Entries() uint64 {
+	return f.header.entries
+}
+
+// Close closes the file.
+func (f *File) Close() error {
+	if f.closer == nil {
+		return nil
+	}
+	return f.closer.Close()
+}
+
+func (f *File) compact() bool {
+	return f.header.flags&flagCompact != 0
+}
+
+// itemSize is the size of the items of the entries and of the entry arrays.
+func (f *File) itemSize() uint64 {
+	if f.compact() {
+		return 4
+	}
+	return 8
+}
+
+func (f *File) offset(buf []byte) uint64 {
+	if f.compact() {
+		return uint64(binary.LittleEndian.Uint32(buf))
+	}
+	return binary.LittleEndian.Uint64(buf)
+}
+
+// readObject reads the object at the offset, which must be of the type.
+// Only the first size bytes of the object are read when size is not 0.
+func (f *File) readObject(offset uint64, objectType byte, minSize, size uint64) ([]byte, error) {
+	if offset%8 != 0 || offset < headerMinSize || offset+objectHeaderSize > f.header.end {
+		return nil, fmt.Errorf("invalid object offset %d", offset)
+	}
+	var buf [objectHeaderSize]byte
+	if _, err := f.f.ReadAt(buf[:], int64(offset)); err != nil {
+		return nil, fmt.Errorf("read object at offset %d: %w", offset, err)
+	}
+	if buf[0] != objectType {
+		return nil, fmt.Errorf("unexpected object type %d at offset %d", buf[0], offset)
+	}
+	objectSize := binary.LittleEndian.Uint64(buf[8:])
+	if objectSize < minSize || objectSize > maxObjectSize || offset+objectSize > f.header.end {
+		return nil, fmt.Errorf("invalid object size %d at offset %d", objectSize, offset)
+	}
+	if size == 0 || size > objectSize {
+		size = objectSize
+	}
+	object := make([]byte, size)
+	if _, err := f.f.ReadAt(object, int64(offset)); err != nil {
+		return nil, fmt.Errorf("read object at offset %d: %w", offset, err)
+	}
+	return object, nil
+}
+
+// entryOffset returns the offset of the entry of the index, in the chain of
+// entry arrays of the file.
+func (f *File) entryOffset(index uint64) (uint64, error) {
+	if index >= f.header.entries {
+		return 0, fmt.Errorf("invalid entry index %d", index)
+	}
+	i := index
+	for n := 0; ; n++ {
+		if n == len(f.arrays) {
+			if err := f.nextArray(); err != nil {
+				return 0, err
+			}
+		}
+		array := f.arrays[n]
+		if i < array.capacity {
+			buf := make([]byte, f.itemSize())
+			if _, err := f.f.ReadAt(buf, int64(array.offset+entryArrayHeaderSize+i*f.itemSize())); err != nil {
+				return 0, fmt.Errorf("read entry array at offset %d: %w", array.offset, err)
+			}
+			offset := f.offset(buf)
+			if offset == 0 {
+				return 0, fmt.Errorf("missing entry %d", index)
+			}
+			return offset, nil
+		}
+		i -= array.capacity
+	}
+}
+
+// nextArray appends the next entry array of the chain to the arrays.
+func (f *File) nextArray() error {
+	offset := f.header.entryArrays
+	if n := len(f.arrays); n > 0 {
+		// The next array is linked once the previous one is full, read it again
+		object, err := f.readObject(f.arrays[n-1].offset, objectEntryArray, entryArrayHeaderSize, entryArrayHeaderSize)
+		if err != nil {
+			return err
+		}
+		offset = binary.LittleEndian.Uint64(object[16:])
+	}
+	if offset == 0 {
+		return errors.New("missing entry array")
+	}
+	object, err := f.readObject(offset, objectEntryArray, entryArrayHeaderSize, entryArrayHeaderSize)
+	if err != nil {
+		return err
+	}
+	capacity := (binary.LittleEndian.Uint64(object[8:]) - entryArrayHeaderSize) / f.itemSize()
+	if capacity == 0 {
+		return fmt.Errorf("empty entry array at offset %d", offset)
+	}
+	f.arrays = append(f.arrays, entryArray{offset: offset, capacity: capacity})
+	return nil
+}
+
+// Position is the position of an entry in the journal, which orders the
+// entries of several files.
+type Position struct {
+	SeqnumID  ID
+	Seqnum    uint64
+	BootID    ID
+	Monotonic uint64
+	Realtime  uint64
+	XorHash   uint64
+}
+
+// Compare returns -1, 0 or 1 when p is before, the same as or after o. The
+// positions are compared the same way as journalctl: by sequence number when
+// they share their sequence, by monotonic time when they share their boot,
+// and by realtime otherwise.
+func (p Position) Compare(o Position) int {
+	switch {
+	case p.SeqnumID == o.SeqnumID && p.Seqnum != o.Seqnum:
+		return compare(p.Seqnum, o.Seqnum)
+	case p.BootID == o.BootID && p.Monotonic != o.Monotonic:
+		return compare(p.Monotonic, o.Monotonic)
+	case p.Realtime != o.Realtime:
+		return compare(p.Realtime, o.Realtime)
+	default:
+		return compare(p.XorHash, o.XorHash)
+	}
+}
+
+func compare(a, b uint64) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// Cursor returns the cursor of the position, in the format of journalctl.
+func (p Position) Cursor() string {
+	return fmt.Sprintf("s=%s;i=%x;b=%s;m=%x;t=%x;x=%x", p.SeqnumID, p.Seqnum, p.BootID, p.Monotonic, p.Realtime, p.XorHash)
+}
+
+// ParseCursor parses a cursor of journalctl.
+func ParseCursor(cursor string) (Position, error) {
+	var p Position
+	var seen int
+	for _, part := range strings.Split(cursor, ";") {
+		key, value, ok := strings.Cut(part, "=")
+		if !ok {
+			return p, fmt.Errorf("invalid cursor %q", cursor)
+		}
+		var err error
+		switch key {
+		case "s":
+			p.SeqnumID, err = ParseID(value)
+		case "i":
+			p.Seqnum, err = strconv.ParseUint(value, 16, 64)
+		case "b":
+			p.BootID, err = ParseID(value)
+		case "m":
+			p.Monotonic, err = strconv.ParseUint(value, 16, 64)
+		case "t":
+			p.Realtime, err = strconv.ParseUint(value, 16, 64)
+		case "x":
+			p.XorHash, err = strconv.ParseUint(value, 16, 64)
+		default:
+			continue
+		}
+		if err != nil {
+			return p, fmt.Errorf("invalid cursor %q", cursor)
+		}
+		seen++
+	}
+	if seen != 6 {
+		return p, fmt.Errorf("invalid cursor %q", cursor)
+	}
+	return p, nil
+}
+
+// Field is a field of an entry.
+type Field struct {
+	Name  string
+	Value []byte
+}
+
+// Entry is an entry of the journal.
+type Entry struct {
+	Position
+	Fields []Field
+}
+
+// position reads the position of the entry at the offset.
+func (f *File) position(offset uint64) (Position, []byte, error) {
+	object, err := f.readObject(offset, objectEntry, entryObjectMinSize, 0)
+	if err != nil {
+		return Position{}, nil, err
+	}
+	p := Position{
+		SeqnumID:  f.header.seqnumID,
+		Seqnum:    binary.LittleEndian.Uint64(object[16:]),
+		Realtime:  binary.LittleEndian.Uint64(object[24:]),
+		Monotonic: binary.LittleEndian.Uint64(object[32:]),
+		XorHash:   binary.LittleEndian.Uint64(object[56:]),
+	}
+	copy(p.BootID[:], object[40:])
+	return p, object, nil
+}
+
+// Position returns the position of the entry of the index.
+func (f *File) Position(index uint64) (Position, error) {
+	offset, err := f.entryOffset(index)
+	if err != nil {
+		return Position{}, err
+	}
+	p, _, err := f.position(offset)
+	return p, err
+}
+
+// Entry reads the entry of the index.
+func (f *File) Entry(index uint64) (*Entry, error) {
+	offset, err := f.entryOffset(index)
+	if err != nil {
+		return nil, err
+	}
+	p, object, err := f.position(offset)
+	if err != nil {
+		return nil, err
+	}
+
+	itemSize := f.itemSize()
+	if !f.compact() {
+		// The items of regular files also hold the hash of their data
+		itemSize = 16
+	}
+	items := object[entryObjectMinSize:]
+	e := &Entry{Position: p, Fields: make([]Field, 0, len(items)/int(itemSize))}
+	var errs []error
+	for ; len(items) >= int(itemSize); items = items[itemSize:] {
+		field, err := f.field(f.offset(items))
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		e.Fields = append(e.Fields, field)
+	}
+	return e, errors.Join(errs...)
+}
+
+// field reads the field of the data object at the offset.
+func (f *File) field(offset uint64) (Field, error) {
+	payloadOffset := uint64(dataPayloadOffset)
+	if f.compact() {
+		payloadOffset = compactDataPayloadOffset
+	}
+	object, err := f.readObject(offset, objectData, payloadOffset, 0)
+	if err != nil {
+		return Field{}, err
+	}
+	data := object[payloadOffset:]
+	switch flags := object[1]; {
+	case flags&objectCompressedZSTD != 0:
+		data, err = f.zstd.decode(data)
+	case flags&objectCompressedLZ4 != 0:
+		data, err = decodeLZ4(data)
+	case flags&objectCompressedXZ != 0:
+		err = errors.New("xz compression is not supported")
+	}
+	if err != nil {
+		return Field{}, fmt.Errorf("decompress data at offset %d: %w", offset, err)
+	}
+	name, value, ok := bytes.Cut(data, []byte("="))
+	if !ok {
+		return Field{}, fmt.Errorf("invalid data at offset %d", offset)
+	}
+	return Field{Name: string(name), Value: value}, nil
+}
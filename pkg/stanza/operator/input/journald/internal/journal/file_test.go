@@ -0,0 +1,160 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package journal
+
+import (
+	"encoding/binary"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFileEntry(t *testing.T) {
+	f, err := OpenFile(testFiles[0])
+	require.NoError(t, err)
+	defer f.Close()
+
+	// The fourth entry was sent to the native socket of journald
+	e, err := f.Entry(3)
+	require.NoError(t, err)
+	require.Equal(t, "s=9464f8b9451844a4bef6216b00a1ddee;i=4;b=bc718905d3fe45b48f38b6cff1524fb7;m=21b6e5c66;t=65e2d88769522;x=6398b3290a566c47", e.Cursor())
+	require.Contains(t, e.Fields, Field{Name: "MESSAGE", Value: []byte("native message")})
+	require.Contains(t, e.Fields, Field{Name: "CUSTOM_FIELD", Value: []byte("custom value")})
+
+	// The large fields of the seventh entry are compressed
+	e, err = f.Entry(6)
+	require.NoError(t, err)
+	require.Contains(t, e.Fields, Field{Name: "LARGE", Value: []byte(strings.Repeat("x", 2000))})
+	require.Contains(t, e.Fields, Field{Name: "HUGE", Value: []byte(strings.Repeat("y", 5000))})
+
+	_, err = f.Entry(f.Entries())
+	require.EqualError(t, err, "invalid entry index 13")
+}
+
+func TestOpenFileErrors(t *testing.T) {
+	valid, err := os.ReadFile(testFiles[0])
+	require.NoError(t, err)
+
+	cases := []struct {
+		name string
+		data func() []byte
+		err  string
+	}{
+		{
+			name: "not_journal",
+			data: func() []byte { return make([]byte, headerMinSize) },
+			err:  "not a journal file",
+		},
+		{
+			name: "truncated",
+			data: func() []byte { return valid[:100] },
+			err:  "read header: EOF",
+		},
+		{
+			name: "unsupported_flags",
+			data: func() []byte {
+				data := append([]byte{}, valid...)
+				binary.LittleEndian.PutUint32(data[12:], flagCompact|1<<5)
+				return data
+			},
+			err: "unsupported incompatible flags 0x20",
+		},
+		{
+			name: "invalid_header_size",
+			data: func() []byte {
+				data := append([]byte{}, valid...)
+				binary.LittleEndian.PutUint64(data[88:], 16)
+				return data
+			},
+			err: "invalid header",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "system.journal")
+			require.NoError(t, os.WriteFile(path, tc.data(), 0600))
+			_, err := OpenFile(path)
+			require.EqualError(t, err, path+": "+tc.err)
+		})
+	}
+}
+
+func TestFileEntryErrors(t *testing.T) {
+	valid, err := os.ReadFile(testFiles[0])
+	require.NoError(t, err)
+	entryArrays := binary.LittleEndian.Uint64(valid[176:])
+
+	t.Run("invalid_entry_array", func(t *testing.T) {
+		data := append([]byte{}, valid...)
+		binary.LittleEndian.PutUint64(data[176:], 12)
+		f, err := newFile(readerAt(data))
+		require.NoError(t, err)
+		_, err = f.Entry(0)
+		require.EqualError(t, err, "invalid object offset 12")
+	})
+
+	t.Run("invalid_entry", func(t *testing.T) {
+		data := append([]byte{}, valid...)
+		// The first item of the entry array points to the array itself
+		binary.LittleEndian.PutUint32(data[entryArrays+entryArrayHeaderSize:], uint32(entryArrays))
+		f, err := newFile(readerAt(data))
+		require.NoError(t, err)
+		_, err = f.Entry(0)
+		require.ErrorContains(t, err, "unexpected object type 6")
+	})
+}
+
+type readerAt []byte
+
+func (r readerAt) ReadAt(p []byte, off int64) (int, error) {
+	return copy(p, r[off:]), nil
+}
+
+func TestParseCursor(t *testing.T) {
+	cursor := "s=9464f8b9451844a4bef6216b00a1ddee;i=4;b=bc718905d3fe45b48f38b6cff1524fb7;m=21b6e5c66;t=65e2d88769522;x=6398b3290a566c47"
+	p, err := ParseCursor(cursor)
+	require.NoError(t, err)
+	require.Equal(t, uint64(4), p.Seqnum)
+	require.Equal(t, uint64(0x21b6e5c66), p.Monotonic)
+	require.Equal(t, uint64(0x65e2d88769522), p.Realtime)
+	require.Equal(t, cursor, p.Cursor())
+
+	for _, invalid := range []string{
+		"",
+		"s=9464f8b9451844a4bef6216b00a1ddee;i=4",
+		"s=9464f8b9;i=4;b=bc718905d3fe45b48f38b6cff1524fb7;m=21b6e5c66;t=65e2d88769522;x=6398b3290a566c47",
+		"s=9464f8b9451844a4bef6216b00a1ddee;i=z;b=bc718905d3fe45b48f38b6cff1524fb7;m=21b6e5c66;t=65e2d88769522;x=6398b3290a566c47",
+	} {
+		_, err := ParseCursor(invalid)
+		require.EqualError(t, err, "invalid cursor \""+invalid+"\"")
+	}
+}
+
+func TestDecodeLZ4(t *testing.T) {
+	expected := strings.Repeat("a", 17) + "bcdefghijklmnopqrstu"
+	block := []byte{
+		0x1c, 'a', 0x01, 0x00, // A literal and a match of 16 bytes
+		0xf0, 0x05, // The last 20 literals
+	}
+	block = append(block, "bcdefghijklmnopqrstu"...)
+	data := binary.LittleEndian.AppendUint64(nil, uint64(len(expected)))
+
+	decoded, err := decodeLZ4(append(data, block...))
+	require.NoError(t, err)
+	require.Equal(t, expected, string(decoded))
+
+	for name, invalid := range map[string][]byte{
+		"size":      data[:4],
+		"offset":    append(append([]byte{}, data...), 0x1c, 'a', 0x00, 0x00),
+		"truncated": append(append([]byte{}, data...), block[:len(block)-1]...),
+		"too_long":  append(binary.LittleEndian.AppendUint64(nil, 10), block...),
+	} {
+		_, err := decodeLZ4(invalid)
+		require.Error(t, err, name)
+	}
+}
@@ -0,0 +1,273 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package journal // import "github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/operator/input/journald/internal/journal"
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// DefaultDirectories are the directories of the journal files written by
+// journald, when it stores them in memory or on disk.
+var DefaultDirectories = []string{"/run/log/journal", "/var/log/journal"}
+
+type readerFile struct {
+	*File
+	path string
+	info os.FileInfo
+	// next is the index of the next entry to read
+	next uint64
+	head *Position
+}
+
+// Reader reads the entries of several journal files in order, the same way
+// as journalctl. It reads the journal files of directories, and of the
+// subdirectories named after a machine id, or a list of journal files,
+// which may be glob patterns.
+type Reader struct {
+	directories []string
+	paths       []string
+	files       []*readerFile
+	// last is the position of the last entry read, the files opened later
+	// are read from the entry following it.
+	last *Position
+}
+
+// NewReader creates a reader of the journal files of the directories, or
+// of the files matching the paths.
+func NewReader(directories, paths []string) *Reader {
+	return &Reader{directories: directories, paths: paths}
+}
+
+// SeekTail skips the entries of the journal files, to read the entries
+// appended next.
+func (r *Reader) SeekTail() error {
+	err := r.Refresh()
+	for _, f := range r.files {
+		f.next, f.head = f.Entries(), nil
+		if f.next == 0 {
+			continue
+		}
+		p, err := f.Position(f.next - 1)
+		if err != nil {
+			continue
+		}
+		if r.last == nil || p.Compare(*r.last) > 0 {
+			r.last = &p
+		}
+	}
+	return err
+}
+
+// Seek skips the entries of the journal files up to the position, to read
+// the entries following it.
+func (r *Reader) Seek(p Position) error {
+	r.last = &p
+	return r.Refresh()
+}
+
+// Refresh opens the journal files created since the last refresh, closes
+// the ones that were deleted, and finds the entries appended to the others.
+func (r *Reader) Refresh() error {
+	paths, errs := r.find()
+
+	found := make([]*readerFile, 0, len(paths))
+	for _, path := range paths {
+		info, err := os.Stat(path)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if f := r.file(info); f != nil {
+			// The files are renamed when journald rotates them
+			f.path = path
+			if err := f.Refresh(); err != nil {
+				errs = append(errs, fmt.Errorf("%s: %w", path, err))
+			}
+			found = append(found, f)
+			continue
+		}
+		f, err := r.open(path, info)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		found = append(found, f)
+	}
+
+	for _, f := range r.files {
+		if !containsFile(found, f) {
+			f.Close()
+		}
+	}
+	r.files = found
+	return errors.Join(errs...)
+}
+
+// Next returns the next entry of the journal, or nil when all the entries of
+// the files were read. The entries of the files missing fields that can't be
+// read are returned along the error.
+func (r *Reader) Next() (*Entry, error) {
+	var next *readerFile
+	for _, f := range r.files {
+		if f.next >= f.Entries() {
+			continue
+		}
+		if f.head == nil {
+			p, err := f.Position(f.next)
+			if err != nil {
+				f.next++
+				return nil, fmt.Errorf("%s: %w", f.path, err)
+			}
+			f.head = &p
+		}
+		if next == nil || f.head.Compare(*next.head) < 0 {
+			next = f
+		}
+	}
+	if next == nil {
+		return nil, nil
+	}
+
+	e, err := next.Entry(next.next)
+	next.next, next.head = next.next+1, nil
+	if err != nil {
+		err = fmt.Errorf("%s: %w", next.path, err)
+	}
+	if e != nil {
+		r.last = &e.Position
+	}
+	return e, err
+}
+
+// Close closes the journal files.
+func (r *Reader) Close() error {
+	var errs []error
+	for _, f := range r.files {
+		errs = append(errs, f.Close())
+	}
+	r.files = nil
+	return errors.Join(errs...)
+}
+
+func (r *Reader) find() ([]string, []error) {
+	if len(r.paths) > 0 {
+		return globFiles(r.paths)
+	}
+
+	var paths []string
+	var errs []error
+	for _, dir := range r.directories {
+		files, err := findFiles(dir, true)
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			errs = append(errs, err)
+		}
+		paths = append(paths, files...)
+	}
+	return paths, errs
+}
+
+// globFiles expands the glob patterns of the paths, like journalctl --file.
+// A path matching no file is kept, so that it is reported as missing.
+func globFiles(patterns []string) ([]string, []error) {
+	var paths []string
+	var errs []error
+	found := map[string]bool{}
+	for _, pattern := range patterns {
+		matches, err := filepath.Glob(pattern)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", pattern, err))
+			continue
+		}
+		if len(matches) == 0 {
+			matches = []string{pattern}
+		}
+		for _, path := range matches {
+			if !found[path] {
+				found[path] = true
+				paths = append(paths, path)
+			}
+		}
+	}
+	return paths, errs
+}
+
+// findFiles returns the journal files of the directory, and of its
+// subdirectories named after a machine id.
+func findFiles(dir string, machines bool) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var paths []string
+	var errs []error
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() {
+			if _, err := ParseID(name); err != nil || !machines {
+				continue
+			}
+			files, err := findFiles(filepath.Join(dir, name), false)
+			if err != nil {
+				errs = append(errs, err)
+			}
+			paths = append(paths, files...)
+			continue
+		}
+		if strings.HasSuffix(name, ".journal") || strings.HasSuffix(name, ".journal~") {
+			paths = append(paths, filepath.Join(dir, name))
+		}
+	}
+	sort.Strings(paths)
+	return paths, errors.Join(errs...)
+}
+
+func (r *Reader) file(info os.FileInfo) *readerFile {
+	for _, f := range r.files {
+		if os.SameFile(f.info, info) {
+			return f
+		}
+	}
+	return nil
+}
+
+func (r *Reader) open(path string, info os.FileInfo) (*readerFile, error) {
+	file, err := OpenFile(path)
+	if err != nil {
+		return nil, err
+	}
+	f := &readerFile{File: file, path: path, info: info}
+	if r.last == nil {
+		return f, nil
+	}
+
+	var searchErr error
+	f.next = uint64(sort.Search(int(f.Entries()), func(i int) bool {
+		p, err := f.Position(uint64(i))
+		if err != nil {
+			searchErr = err
+			return true
+		}
+		return p.Compare(*r.last) > 0
+	}))
+	if searchErr != nil {
+		file.Close()
+		return nil, fmt.Errorf("%s: %w", path, searchErr)
+	}
+	return f, nil
+}
+
+func containsFile(files []*readerFile, f *readerFile) bool {
+	for _, file := range files {
+		if file == f {
+			return true
+		}
+	}
+	return false
+}
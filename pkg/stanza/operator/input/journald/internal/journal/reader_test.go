@@ -0,0 +1,179 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package journal
+
+import (
+	"bufio"
+	"encoding/binary"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// The journal files of the tests were written by journald, and the output of
+// journalctl for them is in the golden files.
+var (
+	testDir   = filepath.Join("..", "..", "testdata", "journal")
+	testFiles = []string{
+		filepath.Join(testDir, "fed6b2924c424cf1b9a322f606b4de6d", "system@9464f8b9451844a4bef6216b00a1ddee-0000000000000001-00065e2d87822aea.journal"),
+		filepath.Join(testDir, "fed6b2924c424cf1b9a322f606b4de6d", "system@9464f8b9451844a4bef6216b00a1ddee-000000000000000e-00065e2dedd9a5a2.journal"),
+		filepath.Join(testDir, "fed6b2924c424cf1b9a322f606b4de6d", "system.journal"),
+	}
+)
+
+type goldenEntry struct {
+	Cursor   string `json:"__CURSOR"`
+	Realtime string `json:"__REALTIME_TIMESTAMP"`
+}
+
+func readGolden(t *testing.T) []goldenEntry {
+	f, err := os.Open(filepath.Join("..", "..", "testdata", "golden", "all.json"))
+	require.NoError(t, err)
+	defer f.Close()
+
+	var entries []goldenEntry
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var e goldenEntry
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &e))
+		entries = append(entries, e)
+	}
+	require.NoError(t, scanner.Err())
+	return entries
+}
+
+func readAll(t *testing.T, r *Reader) []goldenEntry {
+	var entries []goldenEntry
+	for {
+		e, err := r.Next()
+		require.NoError(t, err)
+		if e == nil {
+			return entries
+		}
+		entries = append(entries, goldenEntry{Cursor: e.Cursor(), Realtime: strconv.FormatUint(e.Realtime, 10)})
+	}
+}
+
+func TestReader(t *testing.T) {
+	golden := readGolden(t)
+
+	t.Run("directory", func(t *testing.T) {
+		r := NewReader([]string{testDir}, nil)
+		defer r.Close()
+		require.NoError(t, r.Refresh())
+		require.Equal(t, golden, readAll(t, r))
+	})
+
+	t.Run("files", func(t *testing.T) {
+		r := NewReader(nil, testFiles)
+		defer r.Close()
+		require.NoError(t, r.Refresh())
+		require.Equal(t, golden, readAll(t, r))
+	})
+
+	t.Run("glob", func(t *testing.T) {
+		r := NewReader(nil, []string{
+			filepath.Join(testDir, "*", "system@*.journal"),
+			filepath.Join(testDir, "*", "*.journal"),
+		})
+		defer r.Close()
+		require.NoError(t, r.Refresh())
+		require.Len(t, r.files, len(testFiles))
+		require.Equal(t, golden, readAll(t, r))
+	})
+
+	t.Run("glob_no_match", func(t *testing.T) {
+		r := NewReader(nil, []string{filepath.Join(t.TempDir(), "*.journal")})
+		defer r.Close()
+		require.ErrorIs(t, r.Refresh(), os.ErrNotExist)
+		require.Empty(t, readAll(t, r))
+	})
+
+	t.Run("bad_pattern", func(t *testing.T) {
+		r := NewReader(nil, []string{"["})
+		defer r.Close()
+		require.ErrorIs(t, r.Refresh(), filepath.ErrBadPattern)
+	})
+
+	t.Run("seek", func(t *testing.T) {
+		position, err := ParseCursor(golden[10].Cursor)
+		require.NoError(t, err)
+
+		r := NewReader([]string{testDir}, nil)
+		defer r.Close()
+		require.NoError(t, r.Seek(position))
+		require.Equal(t, golden[11:], readAll(t, r))
+	})
+
+	t.Run("seek_tail", func(t *testing.T) {
+		r := NewReader([]string{testDir}, nil)
+		defer r.Close()
+		require.NoError(t, r.SeekTail())
+		require.Empty(t, readAll(t, r))
+	})
+
+	t.Run("missing_directory", func(t *testing.T) {
+		r := NewReader([]string{filepath.Join(t.TempDir(), "missing")}, nil)
+		defer r.Close()
+		require.NoError(t, r.Refresh())
+		require.Empty(t, readAll(t, r))
+	})
+}
+
+func TestReaderRotation(t *testing.T) {
+	golden := readGolden(t)
+	dir := t.TempDir()
+	active := filepath.Join(dir, "system.journal")
+
+	copyFile(t, testFiles[0], active)
+	r := NewReader([]string{dir}, nil)
+	defer r.Close()
+	require.NoError(t, r.Refresh())
+	first := readAll(t, r)
+	require.Equal(t, golden[:len(first)], first)
+
+	// journald renames the active file when it rotates it, its entries are
+	// not read again
+	require.NoError(t, os.Rename(active, filepath.Join(dir, "system@1.journal")))
+	copyFile(t, testFiles[1], active)
+	require.NoError(t, r.Refresh())
+	second := readAll(t, r)
+	require.NotEmpty(t, second)
+	require.Equal(t, golden[len(first):len(first)+len(second)], second)
+
+	require.NoError(t, os.Remove(filepath.Join(dir, "system@1.journal")))
+	require.NoError(t, r.Refresh())
+	require.Len(t, r.files, 1)
+}
+
+func TestReaderAppend(t *testing.T) {
+	golden := readGolden(t)
+	path := filepath.Join(t.TempDir(), "system.journal")
+	data, err := os.ReadFile(testFiles[0])
+	require.NoError(t, err)
+	entries := binary.LittleEndian.Uint64(data[152:])
+
+	// Hide the last entries of the file, as if they were not appended yet
+	binary.LittleEndian.PutUint64(data[152:], 2)
+	require.NoError(t, os.WriteFile(path, data, 0600))
+	r := NewReader(nil, []string{path})
+	defer r.Close()
+	require.NoError(t, r.Refresh())
+	require.Equal(t, golden[:2], readAll(t, r))
+
+	binary.LittleEndian.PutUint64(data[152:], entries)
+	require.NoError(t, os.WriteFile(path, data, 0600))
+	require.NoError(t, r.Refresh())
+	require.Equal(t, golden[2:entries], readAll(t, r))
+}
+
+func copyFile(t *testing.T, src, dst string) {
+	data, err := os.ReadFile(src)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(dst, data, 0600))
+}
@@ -31,6 +31,12 @@ import (
 const operatorType = "journald_input"
 const waitDuration = 1 * time.Second
 
+const (
+	readerAuto       = "auto"
+	readerJournalctl = "journalctl"
+	readerNative     = "native"
+)
+
 func init() {
 	operator.Register(operatorType, func() operator.Builder { return NewConfig() })
 }
@@ -46,6 +52,7 @@ func NewConfigWithID(operatorID string) *Config {
 		InputConfig: helper.NewInputConfig(operatorID, operatorType),
 		StartAt:     "end",
 		Priority:    "info",
+		Reader:      readerAuto,
 	}
 }
 
@@ -62,6 +69,7 @@ type Config struct {
 	Identifiers []string      `mapstructure:"identifiers,omitempty"`
 	Grep        string        `mapstructure:"grep,omitempty"`
 	Dmesg       bool          `mapstructure:"dmesg,omitempty"`
+	Reader      string        `mapstructure:"reader,omitempty"`
 }
 
 type MatchConfig map[string]string
@@ -78,6 +86,21 @@ func (c Config) Build(logger *zap.SugaredLogger) (operator.Operator, error) {
 		return nil, err
 	}
 
+	native, err := c.useNativeReader()
+	if err != nil {
+		return nil, err
+	}
+	if native {
+		reader, err := c.buildNativeReader()
+		if err != nil {
+			return nil, err
+		}
+		return &Input{
+			InputOperator: inputOperator,
+			native:        reader,
+		}, nil
+	}
+
 	return &Input{
 		InputOperator: inputOperator,
 		newCmd: func(ctx context.Context, cursor []byte) cmd {
@@ -91,6 +114,22 @@ func (c Config) Build(logger *zap.SugaredLogger) (operator.Operator, error) {
 	}, nil
 }
 
+// useNativeReader returns true if the journal files are read without
+// journalctl, which is the case by default when it isn't in the $PATH.
+func (c Config) useNativeReader() (bool, error) {
+	switch c.Reader {
+	case readerAuto:
+		_, err := exec.LookPath("journalctl")
+		return err != nil, nil
+	case readerJournalctl:
+		return false, nil
+	case readerNative:
+		return true, nil
+	default:
+		return false, fmt.Errorf("invalid value '%s' for parameter 'reader'", c.Reader)
+	}
+}
+
 func (c Config) buildArgs() ([]string, error) {
 	args := make([]string, 0, 10)
 
@@ -193,6 +232,7 @@ type Input struct {
 	helper.InputOperator
 
 	newCmd func(ctx context.Context, cursor []byte) cmd
+	native *nativeReader
 
 	persister operator.Persister
 	json      jsoniter.API
@@ -227,6 +267,10 @@ func (operator *Input) Start(persister operator.Persister) error {
 
 	operator.persister = persister
 
+	if operator.native != nil {
+		return operator.startNative(ctx, cursor)
+	}
+
 	// Start journalctl
 	journal := operator.newCmd(ctx, cursor)
 	stdout, err := journal.StdoutPipe()
@@ -55,6 +55,7 @@ func (f *fakeJournaldCmd) Wait() error {
 func TestInputJournald(t *testing.T) {
 	cfg := NewConfigWithID("my_journald_input")
 	cfg.OutputIDs = []string{"output"}
+	cfg.Reader = readerJournalctl
 
 	op, err := cfg.Build(testutil.Logger(t))
 	require.NoError(t, err)
@@ -226,6 +227,7 @@ func TestBuildConfig(t *testing.T) {
 func TestInputJournaldError(t *testing.T) {
 	cfg := NewConfigWithID("my_journald_input")
 	cfg.OutputIDs = []string{"output"}
+	cfg.Reader = readerJournalctl
 
 	op, err := cfg.Build(testutil.Logger(t))
 	require.NoError(t, err)
@@ -0,0 +1,432 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build linux
+// +build linux
+
+package journald // import "github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/operator/input/journald"
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"go.uber.org/zap"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/operator/input/journald/internal/journal"
+)
+
+const (
+	defaultPollInterval = 200 * time.Millisecond
+
+	// jsonThreshold is the size from which journalctl outputs the fields as
+	// null in JSON.
+	jsonThreshold = 4096
+
+	// coredumpMessageID is the MESSAGE_ID of the entries of systemd-coredump
+	coredumpMessageID = "fc2e22bc6ee647b6b90729ab34a250b1"
+
+	bootIDPath = "/proc/sys/kernel/random/boot_id"
+)
+
+var priorityNames = []string{"emerg", "alert", "crit", "err", "warning", "notice", "info", "debug"}
+
+var unitSuffixes = []string{".service", ".socket", ".target", ".device", ".mount", ".automount", ".swap", ".timer", ".path", ".slice", ".scope"}
+
+// nativeReader reads the journal files without journalctl
+type nativeReader struct {
+	directories  []string
+	files        []string
+	startAt      string
+	filter       *filter
+	pollInterval time.Duration
+}
+
+func (c Config) buildNativeReader() (*nativeReader, error) {
+	f, err := c.buildFilter()
+	if err != nil {
+		return nil, err
+	}
+	r := &nativeReader{
+		directories:  journal.DefaultDirectories,
+		files:        c.Files,
+		startAt:      c.StartAt,
+		filter:       f,
+		pollInterval: defaultPollInterval,
+	}
+	if c.Directory != nil {
+		r.directories = []string{*c.Directory}
+		r.files = nil
+	}
+	return r, nil
+}
+
+// startNative reads the journal files, starting after the cursor if it is set.
+func (operator *Input) startNative(ctx context.Context, cursor []byte) error {
+	if len(operator.native.files) == 0 && len(operator.native.directories) == 1 {
+		if _, err := os.Stat(operator.native.directories[0]); err != nil {
+			return fmt.Errorf("journal directory: %w", err)
+		}
+	}
+
+	r := journal.NewReader(operator.native.directories, operator.native.files)
+	var err error
+	switch {
+	case cursor != nil:
+		var position journal.Position
+		if position, err = journal.ParseCursor(string(cursor)); err != nil {
+			r.Close()
+			return err
+		}
+		err = r.Seek(position)
+	case operator.native.startAt == "beginning":
+		err = r.Refresh()
+	default:
+		err = r.SeekTail()
+	}
+	if err != nil {
+		operator.Warnw("Failed to open journal files", zap.Error(err))
+	}
+
+	operator.wg.Add(1)
+	go func() {
+		defer operator.wg.Done()
+		defer r.Close()
+
+		ticker := time.NewTicker(operator.native.pollInterval)
+		defer ticker.Stop()
+
+		lastErr := fmt.Sprint(err)
+		for {
+			operator.readEntries(ctx, r)
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+
+			// Only log the errors when they change, not at every poll
+			if err := r.Refresh(); fmt.Sprint(err) != lastErr {
+				lastErr = fmt.Sprint(err)
+				if err != nil {
+					operator.Warnw("Failed to open journal files", zap.Error(err))
+				}
+			}
+		}
+	}()
+	return nil
+}
+
+// readEntries writes the entries appended to the journal since it was last read.
+func (operator *Input) readEntries(ctx context.Context, r *journal.Reader) {
+	for ctx.Err() == nil {
+		e, err := r.Next()
+		if err != nil {
+			operator.Warnw("Failed to read journal entry", zap.Error(err))
+		}
+		if e == nil {
+			if err != nil {
+				continue
+			}
+			return
+		}
+
+		fields := newFields(e)
+		if !operator.native.filter.match(e, fields) {
+			continue
+		}
+		cursor := e.Cursor()
+		ent, err := operator.NewEntry(nativeBody(e, fields, cursor))
+		if err != nil {
+			operator.Warnw("Failed to create entry", zap.Error(err))
+			continue
+		}
+		ent.Timestamp = time.Unix(0, int64(e.Realtime)*1000) // in microseconds
+
+		if err := operator.persister.Set(ctx, lastReadCursorKey, []byte(cursor)); err != nil {
+			operator.Warnw("Failed to set offset", zap.Error(err))
+		}
+		operator.Write(ctx, ent)
+	}
+}
+
+// fields are the values of the fields of an entry, by name.
+type fields map[string][][]byte
+
+func newFields(e *journal.Entry) fields {
+	f := make(fields, len(e.Fields))
+	for _, field := range e.Fields {
+		f[field.Name] = append(f[field.Name], field.Value)
+	}
+	return f
+}
+
+func (f fields) has(name, value string) bool {
+	for _, v := range f[name] {
+		if string(v) == value {
+			return true
+		}
+	}
+	return false
+}
+
+// matches returns true if a value of the field matches the glob pattern.
+func (f fields) matches(name, pattern string) bool {
+	for _, v := range f[name] {
+		if ok, _ := path.Match(pattern, string(v)); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// nativeBody returns the body of an entry, in the same format as the JSON
+// output of journalctl.
+func nativeBody(e *journal.Entry, f fields, cursor string) map[string]any {
+	body := make(map[string]any, len(f)+2)
+	for name, values := range f {
+		if name == "_BOOT_ID" {
+			continue
+		}
+		if len(values) == 1 {
+			body[name] = fieldValue(name, values[0])
+			continue
+		}
+		list := make([]any, 0, len(values))
+		for _, v := range values {
+			list = append(list, fieldValue(name, v))
+		}
+		body[name] = list
+	}
+	body["__CURSOR"] = cursor
+	body["__MONOTONIC_TIMESTAMP"] = strconv.FormatUint(e.Monotonic, 10)
+	body["_BOOT_ID"] = e.BootID.String()
+	return body
+}
+
+// fieldValue returns the value of a field the same way as journalctl: null
+// when it is too large, and an array of bytes when it isn't printable.
+func fieldValue(name string, value []byte) any {
+	if len(name)+1+len(value) >= jsonThreshold {
+		return nil
+	}
+	if printable(value) {
+		return string(value)
+	}
+	// The numbers are decoded as float64 from the output of journalctl
+	bytes := make([]any, 0, len(value))
+	for _, b := range value {
+		bytes = append(bytes, float64(b))
+	}
+	return bytes
+}
+
+func printable(value []byte) bool {
+	for len(value) > 0 {
+		r, size := utf8.DecodeRune(value)
+		if r == utf8.RuneError && size <= 1 {
+			return false
+		}
+		if unicode.IsControl(r) && r != '\t' && r != '\n' {
+			return false
+		}
+		value = value[size:]
+	}
+	return true
+}
+
+// filter selects the entries the same way as the options of journalctl:
+// the entries must match all the options, and any of their values.
+type filter struct {
+	priorities  map[string]bool
+	units       []string
+	identifiers []string
+	matches     []MatchConfig
+	grep        *regexp.Regexp
+	// bootID is the boot of the kernel messages read, when it is set
+	bootID string
+}
+
+func (c Config) buildFilter() (*filter, error) {
+	priorities, err := parsePriorities(c.Priority)
+	if err != nil {
+		return nil, err
+	}
+
+	f := &filter{
+		priorities:  priorities,
+		identifiers: c.Identifiers,
+		matches:     c.Matches,
+	}
+
+	for _, unit := range c.Units {
+		unit = mangleUnit(unit)
+		if _, err := path.Match(unit, ""); err != nil {
+			return nil, fmt.Errorf("invalid unit '%s': %w", unit, err)
+		}
+		f.units = append(f.units, unit)
+	}
+
+	if c.Grep != "" {
+		pattern := c.Grep
+		// Like journalctl, the pattern is case insensitive when it has no uppercase characters
+		if strings.ToLower(pattern) == pattern {
+			pattern = "(?i)" + pattern
+		}
+		if f.grep, err = regexp.Compile(pattern); err != nil {
+			return nil, fmt.Errorf("invalid value '%s' for parameter 'grep': %w", c.Grep, err)
+		}
+	}
+
+	if c.Dmesg {
+		bootID, err := os.ReadFile(bootIDPath)
+		if err != nil {
+			return nil, fmt.Errorf("read boot id: %w", err)
+		}
+		f.bootID = strings.ReplaceAll(strings.TrimSpace(string(bootID)), "-", "")
+	}
+	return f, nil
+}
+
+// parsePriorities returns the values of the PRIORITY field of a priority,
+// which selects the priorities up to it, or of a range of priorities. It
+// returns nil when all the priorities are selected.
+func parsePriorities(priority string) (map[string]bool, error) {
+	from, to := "emerg", priority
+	if before, after, ok := strings.Cut(priority, ".."); ok {
+		from, to = before, after
+	}
+	low, err := parsePriority(from)
+	if err != nil {
+		return nil, fmt.Errorf("invalid value '%s' for parameter 'priority'", priority)
+	}
+	high, err := parsePriority(to)
+	if err != nil {
+		return nil, fmt.Errorf("invalid value '%s' for parameter 'priority'", priority)
+	}
+	if low > high {
+		low, high = high, low
+	}
+	if low == 0 && high == len(priorityNames)-1 {
+		// Like journalctl, the entries without priority are read too
+		return nil, nil
+	}
+
+	priorities := make(map[string]bool, high-low+1)
+	for i := low; i <= high; i++ {
+		priorities[strconv.Itoa(i)] = true
+	}
+	return priorities, nil
+}
+
+func parsePriority(priority string) (int, error) {
+	for i, name := range priorityNames {
+		if priority == name {
+			return i, nil
+		}
+	}
+	i, err := strconv.Atoi(priority)
+	if err != nil || i < 0 || i >= len(priorityNames) {
+		return 0, fmt.Errorf("invalid priority '%s'", priority)
+	}
+	return i, nil
+}
+
+// mangleUnit adds the .service suffix to the units without one, like journalctl.
+func mangleUnit(unit string) string {
+	if strings.ContainsAny(unit, "*?[") {
+		return unit
+	}
+	for _, suffix := range unitSuffixes {
+		if strings.HasSuffix(unit, suffix) {
+			return unit
+		}
+	}
+	return unit + ".service"
+}
+
+func (f *filter) match(e *journal.Entry, fields fields) bool {
+	if f.bootID != "" && (!fields.has("_TRANSPORT", "kernel") || e.BootID.String() != f.bootID) {
+		return false
+	}
+	if !f.matchPriority(fields) || !f.matchUnits(fields) || !f.matchIdentifiers(fields) || !f.matchMatches(fields) {
+		return false
+	}
+	if f.grep != nil {
+		for _, message := range fields["MESSAGE"] {
+			if f.grep.Match(message) {
+				return true
+			}
+		}
+		return false
+	}
+	return true
+}
+
+func (f *filter) matchPriority(fields fields) bool {
+	if f.priorities == nil {
+		return true
+	}
+	for _, priority := range fields["PRIORITY"] {
+		if f.priorities[string(priority)] {
+			return true
+		}
+	}
+	return false
+}
+
+// matchUnits matches the entries of the units, and the entries about them
+// logged by systemd, the same way as journalctl.
+func (f *filter) matchUnits(fields fields) bool {
+	if len(f.units) == 0 {
+		return true
+	}
+	for _, unit := range f.units {
+		switch {
+		case fields.matches("_SYSTEMD_UNIT", unit),
+			fields.has("MESSAGE_ID", coredumpMessageID) && fields.has("_UID", "0") && fields.matches("COREDUMP_UNIT", unit),
+			fields.has("_PID", "1") && fields.matches("UNIT", unit),
+			fields.has("_UID", "0") && fields.matches("OBJECT_SYSTEMD_UNIT", unit):
+			return true
+		}
+	}
+	return false
+}
+
+func (f *filter) matchIdentifiers(fields fields) bool {
+	if len(f.identifiers) == 0 {
+		return true
+	}
+	for _, identifier := range f.identifiers {
+		if fields.has("SYSLOG_IDENTIFIER", identifier) {
+			return true
+		}
+	}
+	return false
+}
+
+func (f *filter) matchMatches(fields fields) bool {
+	if len(f.matches) == 0 {
+		return true
+	}
+	for _, match := range f.matches {
+		matched := true
+		for name, value := range match {
+			if !fields.has(name, value) {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			return true
+		}
+	}
+	return false
+}
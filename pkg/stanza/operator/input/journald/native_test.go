@@ -0,0 +1,376 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build linux
+// +build linux
+
+package journald
+
+import (
+	"bufio"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	jsoniter "github.com/json-iterator/go"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/entry"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/operator"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/operator/input/journald/internal/journal"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/testutil"
+)
+
+// The journal files of the tests were written by journald, and the golden
+// files are the output of journalctl for them, with the equivalent options.
+var testJournalDir = filepath.Join("testdata", "journal")
+
+var testJournalFiles = []string{
+	"system@9464f8b9451844a4bef6216b00a1ddee-0000000000000001-00065e2d87822aea.journal",
+	"system@9464f8b9451844a4bef6216b00a1ddee-000000000000000e-00065e2dedd9a5a2.journal",
+	"system.journal",
+}
+
+type goldenEntry struct {
+	timestamp time.Time
+	body      map[string]any
+}
+
+func readGolden(t *testing.T, name string) []goldenEntry {
+	f, err := os.Open(filepath.Join("testdata", "golden", name))
+	require.NoError(t, err)
+	defer f.Close()
+
+	var entries []goldenEntry
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(nil, 1024*1024)
+	for scanner.Scan() {
+		op := &Input{json: jsoniter.ConfigFastest}
+		e, _, err := op.parseJournalEntry(scanner.Bytes())
+		require.NoError(t, err)
+		entries = append(entries, goldenEntry{timestamp: e.Timestamp, body: e.Body.(map[string]any)})
+	}
+	require.NoError(t, scanner.Err())
+	return entries
+}
+
+func startNativeInput(t *testing.T, cfg *Config, persister operator.Persister) (chan *entry.Entry, func()) {
+	cfg.OutputIDs = []string{"output"}
+	cfg.Reader = readerNative
+	op, err := cfg.Build(testutil.Logger(t))
+	require.NoError(t, err)
+	op.(*Input).native.pollInterval = 10 * time.Millisecond
+	if cfg.Dmesg {
+		// The boot of the journal files, rather than the current one
+		op.(*Input).native.filter.bootID = "bc718905d3fe45b48f38b6cff1524fb7"
+	}
+
+	mockOutput := testutil.NewMockOperator("output")
+	received := make(chan *entry.Entry)
+	mockOutput.On("Process", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		received <- args.Get(1).(*entry.Entry)
+	}).Return(nil)
+	require.NoError(t, op.SetOutputs([]operator.Operator{mockOutput}))
+
+	require.NoError(t, op.Start(persister))
+	return received, func() {
+		require.NoError(t, op.Stop())
+	}
+}
+
+func expectEntries(t *testing.T, received chan *entry.Entry, expected []goldenEntry) {
+	for _, e := range expected {
+		select {
+		case r := <-received:
+			require.Equal(t, e.body, r.Body)
+			require.Equal(t, e.timestamp, r.Timestamp)
+		case <-time.After(time.Second):
+			require.FailNow(t, "Timed out waiting for entry to be read")
+		}
+	}
+	select {
+	case r := <-received:
+		require.FailNow(t, "Unexpected entry", r.Body)
+	case <-time.After(100 * time.Millisecond):
+	}
+}
+
+func TestInputJournaldNative(t *testing.T) {
+	testCases := []struct {
+		name   string
+		config func(cfg *Config)
+		golden string
+	}{
+		{
+			name:   "default_priority",
+			config: func(cfg *Config) {},
+			golden: "priority.json",
+		},
+		{
+			name: "all_priorities",
+			config: func(cfg *Config) {
+				cfg.Priority = "debug"
+			},
+			golden: "debug.json",
+		},
+		{
+			name: "priority_range",
+			config: func(cfg *Config) {
+				cfg.Priority = "err..warning"
+			},
+			golden: "priority_range.json",
+		},
+		{
+			name: "files",
+			config: func(cfg *Config) {
+				cfg.Directory = nil
+				for _, file := range testJournalFiles {
+					cfg.Files = append(cfg.Files, filepath.Join(testJournalDir, "fed6b2924c424cf1b9a322f606b4de6d", file))
+				}
+				cfg.Priority = "debug"
+			},
+			golden: "debug.json",
+		},
+		{
+			name: "unit",
+			config: func(cfg *Config) {
+				cfg.Units = []string{"ssh"}
+				cfg.Priority = "debug"
+			},
+			golden: "unit.json",
+		},
+		{
+			name: "unit_coredump",
+			config: func(cfg *Config) {
+				cfg.Units = []string{"cron.service"}
+				cfg.Priority = "debug"
+			},
+			golden: "unit_coredump.json",
+		},
+		{
+			name: "unit_glob",
+			config: func(cfg *Config) {
+				cfg.Units = []string{"ss*"}
+				cfg.Priority = "debug"
+			},
+			golden: "unit_glob.json",
+		},
+		{
+			name: "identifiers",
+			config: func(cfg *Config) {
+				cfg.Identifiers = []string{"myapp", "catapp"}
+				cfg.Priority = "debug"
+			},
+			golden: "identifiers.json",
+		},
+		{
+			name: "grep",
+			config: func(cfg *Config) {
+				cfg.Grep = "case"
+				cfg.Priority = "debug"
+			},
+			golden: "grep.json",
+		},
+		{
+			name: "grep_case_sensitive",
+			config: func(cfg *Config) {
+				cfg.Grep = "CASE"
+				cfg.Priority = "debug"
+			},
+			golden: "grep_case.json",
+		},
+		{
+			name: "matches",
+			config: func(cfg *Config) {
+				cfg.Matches = []MatchConfig{
+					{"SYSLOG_IDENTIFIER": "other"},
+					{"PRIORITY": "3"},
+				}
+				cfg.Priority = "debug"
+			},
+			golden: "matches.json",
+		},
+		{
+			name: "dmesg",
+			config: func(cfg *Config) {
+				cfg.Dmesg = true
+				cfg.Priority = "debug"
+			},
+			golden: "dmesg.json",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			cfg := NewConfigWithID("my_journald_input")
+			cfg.Directory = &testJournalDir
+			cfg.StartAt = "beginning"
+			tc.config(cfg)
+
+			received, stop := startNativeInput(t, cfg, testutil.NewUnscopedMockPersister())
+			defer stop()
+			expectEntries(t, received, readGolden(t, tc.golden))
+		})
+	}
+}
+
+func TestInputJournaldNativeCursor(t *testing.T) {
+	golden := readGolden(t, "debug.json")
+	persister := testutil.NewUnscopedMockPersister()
+
+	cfg := NewConfigWithID("my_journald_input")
+	cfg.Directory = &testJournalDir
+	cfg.Priority = "debug"
+	cfg.StartAt = "beginning"
+	received, stop := startNativeInput(t, cfg, persister)
+	expectEntries(t, received, golden)
+	stop()
+
+	cursor, err := persister.Get(context.Background(), lastReadCursorKey)
+	require.NoError(t, err)
+	require.Equal(t, golden[len(golden)-1].body["__CURSOR"], string(cursor))
+
+	// The entries following the cursor are read after a restart
+	require.NoError(t, persister.Set(context.Background(), lastReadCursorKey, []byte(golden[20].body["__CURSOR"].(string))))
+	received, stop = startNativeInput(t, cfg, persister)
+	defer stop()
+	expectEntries(t, received, golden[21:])
+}
+
+func TestInputJournaldNativeStartAtEnd(t *testing.T) {
+	golden := readGolden(t, "debug.json")
+	machineDir := filepath.Join(testJournalDir, "fed6b2924c424cf1b9a322f606b4de6d")
+	dir := t.TempDir()
+	for _, file := range testJournalFiles[:2] {
+		copyJournalFile(t, filepath.Join(machineDir, file), filepath.Join(dir, file))
+	}
+
+	cfg := NewConfigWithID("my_journald_input")
+	cfg.Directory = &dir
+	cfg.Priority = "debug"
+	received, stop := startNativeInput(t, cfg, testutil.NewUnscopedMockPersister())
+	defer stop()
+	expectEntries(t, received, nil)
+
+	// The entries of the files created after the start are read
+	f, err := journal.OpenFile(filepath.Join(machineDir, testJournalFiles[2]))
+	require.NoError(t, err)
+	entries := int(f.Entries())
+	require.NoError(t, f.Close())
+	copyJournalFile(t, filepath.Join(machineDir, testJournalFiles[2]), filepath.Join(dir, testJournalFiles[2]))
+	expectEntries(t, received, golden[len(golden)-entries:])
+}
+
+func TestInputJournaldNativeMissingDirectory(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "missing")
+	cfg := NewConfigWithID("my_journald_input")
+	cfg.Directory = &dir
+	cfg.Reader = readerNative
+
+	op, err := cfg.Build(testutil.Logger(t))
+	require.NoError(t, err)
+	err = op.Start(testutil.NewUnscopedMockPersister())
+	require.ErrorContains(t, err, "journal directory: stat "+dir)
+	require.NoError(t, op.Stop())
+}
+
+func TestBuildNativeConfig(t *testing.T) {
+	testCases := []struct {
+		name          string
+		config        func(cfg *Config)
+		expectedError string
+	}{
+		{
+			name:   "default",
+			config: func(cfg *Config) {},
+		},
+		{
+			name: "invalid_reader",
+			config: func(cfg *Config) {
+				cfg.Reader = "journald"
+			},
+			expectedError: "invalid value 'journald' for parameter 'reader'",
+		},
+		{
+			name: "invalid_start_at",
+			config: func(cfg *Config) {
+				cfg.StartAt = "middle"
+			},
+			expectedError: "invalid value 'middle' for parameter 'start_at'",
+		},
+		{
+			name: "numeric_priority",
+			config: func(cfg *Config) {
+				cfg.Priority = "0..3"
+			},
+		},
+		{
+			name: "invalid_priority",
+			config: func(cfg *Config) {
+				cfg.Priority = "verbose"
+			},
+			expectedError: "invalid value 'verbose' for parameter 'priority'",
+		},
+		{
+			name: "invalid_priority_range",
+			config: func(cfg *Config) {
+				cfg.Priority = "err..8"
+			},
+			expectedError: "invalid value 'err..8' for parameter 'priority'",
+		},
+		{
+			name: "invalid_unit",
+			config: func(cfg *Config) {
+				cfg.Units = []string{"ssh[.service"}
+			},
+			expectedError: "invalid unit 'ssh[.service'",
+		},
+		{
+			name: "invalid_grep",
+			config: func(cfg *Config) {
+				cfg.Grep = "a("
+			},
+			expectedError: "invalid value 'a(' for parameter 'grep'",
+		},
+		{
+			name: "invalid_match",
+			config: func(cfg *Config) {
+				cfg.Matches = []MatchConfig{{"-SYSTEMD_UNIT": "dbus.service"}}
+			},
+			expectedError: "'-SYSTEMD_UNIT' is not a valid Systemd field name",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			cfg := NewConfigWithID("my_journald_input")
+			cfg.Reader = readerNative
+			tc.config(cfg)
+			_, err := cfg.Build(testutil.Logger(t))
+			if tc.expectedError != "" {
+				require.ErrorContains(t, err, tc.expectedError)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestNativeFieldValue(t *testing.T) {
+	require.Equal(t, "value", fieldValue("NAME", []byte("value")))
+	require.Equal(t, "first\nsecond\tthird", fieldValue("NAME", []byte("first\nsecond\tthird")))
+	require.Equal(t, "héllo ✓", fieldValue("NAME", []byte("héllo ✓")))
+	require.Equal(t, []any{float64(1), float64('a')}, fieldValue("NAME", []byte{1, 'a'}))
+	require.Equal(t, []any{float64(0xff)}, fieldValue("NAME", []byte{0xff}))
+	require.Nil(t, fieldValue("NAME", make([]byte, jsonThreshold-5)))
+	require.NotNil(t, fieldValue("NAME", make([]byte, jsonThreshold-6)))
+}
+
+func copyJournalFile(t *testing.T, src, dst string) {
+	data, err := os.ReadFile(src)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(dst, data, 0600))
+}
@@ -40,6 +40,9 @@ func NewConfig() *Config {
 func NewConfigWithID(operatorID string) *Config {
 	return &Config{
 		InputConfig: helper.NewInputConfig(operatorID, operatorType),
+		BaseConfig: BaseConfig{
+			Framing: split.FramingNewline,
+		},
 	}
 }
 
@@ -53,6 +56,7 @@ type BaseConfig struct {
 	Path        string       `mapstructure:"path"`
 	Permissions uint32       `mapstructure:"mode"`
 	Encoding    string       `mapstructure:"encoding"`
+	Framing     string       `mapstructure:"framing,omitempty"`
 	SplitConfig split.Config `mapstructure:"multiline,omitempty"`
 	TrimConfig  trim.Config  `mapstructure:",squash"`
 	MaxLogSize  int          `mapstructure:"max_log_size"`
@@ -70,6 +74,11 @@ func (c *Config) Build(logger *zap.SugaredLogger) (operator.Operator, error) {
 		return nil, fmt.Errorf("failed to lookup encoding %q: %w", c.Encoding, err)
 	}
 
+	newlineFraming := c.Framing == "" || c.Framing == split.FramingNewline
+	if !newlineFraming && c.SplitConfig != (split.Config{}) {
+		return nil, fmt.Errorf("multiline cannot be used with the %s framing", c.Framing)
+	}
+
 	splitFunc, err := c.SplitConfig.Func(enc, true, DefaultMaxLogSize)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create split function: %w", err)
@@ -80,6 +89,17 @@ func (c *Config) Build(logger *zap.SugaredLogger) (operator.Operator, error) {
 		maxLogSize = DefaultMaxLogSize
 	}
 
+	splitFunc, err = split.FramingFunc(c.Framing, splitFunc, maxLogSize)
+	if err != nil {
+		return nil, err
+	}
+
+	// The tokens of the other framings are read as they were written
+	trimFunc := trim.Nop
+	if newlineFraming {
+		trimFunc = c.TrimConfig.Func()
+	}
+
 	return &Input{
 		InputOperator: inputOperator,
 
@@ -87,7 +107,7 @@ func (c *Config) Build(logger *zap.SugaredLogger) (operator.Operator, error) {
 		path:        c.Path,
 		permissions: c.Permissions,
 		splitFunc:   splitFunc,
-		trimFunc:    c.TrimConfig.Func(),
+		trimFunc:    trimFunc,
 	}, nil
 }
 
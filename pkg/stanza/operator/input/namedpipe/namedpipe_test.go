@@ -19,6 +19,7 @@ import (
 	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/entry"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/operator"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/pipeline"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/split"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/testutil"
 )
 
@@ -147,3 +148,80 @@ func TestPipeWrites(t *testing.T) {
 		}
 	}
 }
+
+// TestPipeWritesFraming writes logs with the other framings to the pipe and verifies that they are received.
+func TestPipeWritesFraming(t *testing.T) {
+	testCases := []struct {
+		name    string
+		framing string
+		data    []byte
+		expect  []string
+	}{
+		{
+			name:    "length_prefixed",
+			framing: split.FramingLengthPrefixed,
+			data:    []byte("\x00\x00\x00\x0a log1\nlog2\x00\x00\x00\x04log3"),
+			expect:  []string{" log1\nlog2", "log3"},
+		},
+		{
+			name:    "json_seq",
+			framing: split.FramingJSONSequence,
+			data:    []byte("\x1e{\"log\":1}\n\x1e{\n  \"log\": 2\n}\n"),
+			expect:  []string{`{"log":1}`, "{\n  \"log\": 2\n}"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			fake := testutil.NewFakeOutput(t)
+
+			conf := NewConfig()
+			conf.Path = filename(t)
+			conf.Permissions = 0666
+			conf.Framing = tc.framing
+			conf.OutputIDs = []string{fake.ID()}
+
+			op, err := conf.Build(zaptest.NewLogger(t).Sugar())
+			require.NoError(t, err)
+
+			p, err := pipeline.NewDirectedPipeline([]operator.Operator{op, fake})
+			require.NoError(t, err)
+
+			require.NoError(t, p.Start(testutil.NewUnscopedMockPersister()))
+			defer func() {
+				require.NoError(t, p.Stop())
+			}()
+
+			pipe, err := os.OpenFile(conf.Path, os.O_WRONLY, 0)
+			require.NoError(t, err)
+			defer func() {
+				require.NoError(t, pipe.Close())
+			}()
+
+			_, err = pipe.Write(tc.data)
+			require.NoError(t, err)
+
+			for _, expect := range tc.expect {
+				select {
+				case e := <-fake.Received:
+					require.Equal(t, expect, e.Body)
+				case <-time.After(time.Second):
+					t.Fatal("timed out waiting for entry")
+				}
+			}
+		})
+	}
+}
+
+func TestBuildFraming(t *testing.T) {
+	conf := NewConfig()
+	conf.Framing = "octet_counting"
+	_, err := conf.Build(zaptest.NewLogger(t).Sugar())
+	require.EqualError(t, err, "invalid framing 'octet_counting'")
+
+	conf = NewConfig()
+	conf.Framing = split.FramingJSONSequence
+	conf.SplitConfig.LineStartPattern = "^{"
+	_, err = conf.Build(zaptest.NewLogger(t).Sugar())
+	require.EqualError(t, err, "multiline cannot be used with the json_seq framing")
+}
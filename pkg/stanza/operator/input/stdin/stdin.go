@@ -15,6 +15,7 @@ import (
 	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/entry"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/operator"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/operator/helper"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/split"
 )
 
 func init() {
@@ -25,12 +26,15 @@ func init() {
 func NewConfig(operatorID string) *Config {
 	return &Config{
 		InputConfig: helper.NewInputConfig(operatorID, "stdin"),
+		Framing:     split.FramingNewline,
 	}
 }
 
 // Config is the configuration of a stdin input operator.
 type Config struct {
 	helper.InputConfig `mapstructure:",squash"`
+	Framing            string `mapstructure:"framing,omitempty"`
+	MaxLogSize         int    `mapstructure:"max_log_size,omitempty"`
 }
 
 // Build will build a stdin input operator.
@@ -40,18 +44,32 @@ func (c *Config) Build(logger *zap.SugaredLogger) (operator.Operator, error) {
 		return nil, err
 	}
 
+	maxLogSize := c.MaxLogSize
+	if maxLogSize == 0 {
+		maxLogSize = bufio.MaxScanTokenSize
+	}
+
+	splitFunc, err := split.FramingFunc(c.Framing, bufio.ScanLines, maxLogSize)
+	if err != nil {
+		return nil, err
+	}
+
 	return &Input{
 		InputOperator: inputOperator,
 		stdin:         os.Stdin,
+		splitFunc:     splitFunc,
+		maxLogSize:    maxLogSize,
 	}, nil
 }
 
 // Input is an operator that reads input from stdin
 type Input struct {
 	helper.InputOperator
-	wg     sync.WaitGroup
-	cancel context.CancelFunc
-	stdin  *os.File
+	wg         sync.WaitGroup
+	cancel     context.CancelFunc
+	stdin      *os.File
+	splitFunc  bufio.SplitFunc
+	maxLogSize int
 }
 
 // Start will start generating log entries.
@@ -70,6 +88,8 @@ func (g *Input) Start(_ operator.Persister) error {
 	}
 
 	scanner := bufio.NewScanner(g.stdin)
+	scanner.Split(g.splitFunc)
+	scanner.Buffer(nil, g.maxLogSize)
 
 	g.wg.Add(1)
 	go func() {
@@ -10,6 +10,7 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/operator"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/split"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/testutil"
 )
 
@@ -39,3 +40,64 @@ func TestStdin(t *testing.T) {
 	w.Close()
 	fake.ExpectBody(t, "test")
 }
+
+func TestStdinFraming(t *testing.T) {
+	testCases := []struct {
+		name    string
+		framing string
+		data    string
+		expect  []string
+	}{
+		{
+			name:    "length_prefixed",
+			framing: split.FramingLengthPrefixed,
+			data:    "\x00\x00\x00\x09log1\nlog2\x00\x00\x00\x04log3",
+			expect:  []string{"log1\nlog2", "log3"},
+		},
+		{
+			name:    "json_seq",
+			framing: split.FramingJSONSequence,
+			data:    "\x1e{\"log\":1}\n\x1e{\n  \"log\": 2\n}\n",
+			expect:  []string{`{"log":1}`, "{\n  \"log\": 2\n}"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			cfg := NewConfig("")
+			cfg.Framing = tc.framing
+			cfg.OutputIDs = []string{"fake"}
+
+			op, err := cfg.Build(testutil.Logger(t))
+			require.NoError(t, err)
+
+			fake := testutil.NewFakeOutput(t)
+			require.NoError(t, op.SetOutputs([]operator.Operator{fake}))
+
+			r, w, err := os.Pipe()
+			require.NoError(t, err)
+
+			stdin := op.(*Input)
+			stdin.stdin = r
+
+			require.NoError(t, stdin.Start(testutil.NewUnscopedMockPersister()))
+			defer func() {
+				require.NoError(t, stdin.Stop())
+			}()
+
+			_, err = w.WriteString(tc.data)
+			require.NoError(t, err)
+			w.Close()
+			for _, expect := range tc.expect {
+				fake.ExpectBody(t, expect)
+			}
+		})
+	}
+}
+
+func TestStdinInvalidFraming(t *testing.T) {
+	cfg := NewConfig("")
+	cfg.Framing = "octet_counting"
+	_, err := cfg.Build(testutil.Logger(t))
+	require.EqualError(t, err, "invalid framing 'octet_counting'")
+}
@@ -89,9 +89,13 @@ func (c Config) Build(logger *zap.SugaredLogger) (operator.Operator, error) {
 		udpInputCfg := udp.NewConfigWithID(inputBase.ID() + "_internal_udp")
 		udpInputCfg.BaseConfig = *c.UDP
 
-		// Octet counting and Non-Transparent-Framing are invalid for UDP connections
-		if syslogParserCfg.EnableOctetCounting || syslogParserCfg.NonTransparentFramingTrailer != nil {
-			return nil, errors.New("octet_counting and non_transparent_framing is not compatible with UDP")
+		// Non-Transparent-Framing is invalid for UDP connections
+		if syslogParserCfg.NonTransparentFramingTrailer != nil {
+			return nil, errors.New("non_transparent_framing is not compatible with UDP")
+		}
+		// Octet counted messages may be sent in datagrams too
+		if syslogParserCfg.EnableOctetCounting {
+			udpInputCfg.SplitFuncBuilder = OctetSplitFuncBuilder
 		}
 
 		udpInput, err := udpInputCfg.Build(logger)
@@ -62,6 +62,7 @@ var (
 			Body: `215 <86>1 2015-08-05T21:58:59.693Z 192.168.2.132 SecureAuth0 23108 ID52020 [SecureAuth@27389 UserHostAddress="192.168.2.132" Realm="SecureAuth0" UserID="Tester2" PEN="27389"] Found the user for retrieving user's profile`,
 		},
 		ValidForTCP: true,
+		ValidForUDP: true,
 	}
 )
 
@@ -164,6 +165,16 @@ func TestSyslogIDs(t *testing.T) {
 	})
 }
 
+func TestUDPNonTransparentFraming(t *testing.T) {
+	trailer := syslog.LFTrailer
+	cfg := basicConfig()
+	cfg.Protocol = syslog.RFC5424
+	cfg.NonTransparentFramingTrailer = &trailer
+
+	_, err := NewConfigWithUDP(&cfg.BaseConfig).Build(testutil.Logger(t))
+	require.EqualError(t, err, "non_transparent_framing is not compatible with UDP")
+}
+
 func NewConfigWithTCP(syslogCfg *syslog.BaseConfig) *Config {
 	cfg := NewConfigWithID("test_syslog")
 	cfg.BaseConfig = *syslogCfg
@@ -6,9 +6,11 @@ package tcp
 import (
 	"path/filepath"
 	"testing"
+	"time"
 
 	"go.opentelemetry.io/collector/config/configtls"
 
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/operator/helper"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/operator/operatortest"
 )
 
@@ -32,6 +34,12 @@ func TestUnmarshal(t *testing.T) {
 					cfg.AddAttributes = true
 					cfg.Encoding = "utf-8"
 					cfg.SplitConfig.LineStartPattern = "ABC"
+					cfg.MaxConnections = 100
+					cfg.IdleTimeout = 5 * time.Minute
+					cfg.RateLimit = &helper.RateLimitConfig{
+						Rate:  1000,
+						Burst: 2000,
+					}
 					cfg.TLS = &configtls.TLSServerSetting{
 						TLSSetting: configtls.TLSSetting{
 							CertFile: "foo",
@@ -9,11 +9,14 @@ import (
 	"context"
 	"crypto/rand"
 	"crypto/tls"
+	"crypto/x509"
+	"errors"
 	"fmt"
 	"io"
 	"net"
 	"strconv"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/jpillora/backoff"
@@ -22,6 +25,7 @@ import (
 	"golang.org/x/text/encoding"
 
 	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/decode"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/entry"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/operator"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/operator/helper"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/split"
@@ -38,6 +42,10 @@ const (
 	// DefaultMaxLogSize is the max buffer sized used
 	// if MaxLogSize is not set
 	DefaultMaxLogSize = 1024 * 1024
+
+	// defaultTLSHandshakeTimeout is the time a client has to complete the TLS
+	// handshake, before its connection is closed
+	defaultTLSHandshakeTimeout = 10 * time.Second
 )
 
 func init() {
@@ -74,6 +82,9 @@ type BaseConfig struct {
 	AddAttributes    bool                        `mapstructure:"add_attributes,omitempty"`
 	OneLogPerPacket  bool                        `mapstructure:"one_log_per_packet,omitempty"`
 	Encoding         string                      `mapstructure:"encoding,omitempty"`
+	MaxConnections   int                         `mapstructure:"max_connections,omitempty"`
+	IdleTimeout      time.Duration               `mapstructure:"idle_timeout,omitempty"`
+	RateLimit        *helper.RateLimitConfig     `mapstructure:"rate_limit,omitempty"`
 	SplitConfig      split.Config                `mapstructure:"multiline,omitempty"`
 	TrimConfig       trim.Config                 `mapstructure:",squash"`
 	SplitFuncBuilder SplitFuncBuilder
@@ -101,6 +112,14 @@ func (c Config) Build(logger *zap.SugaredLogger) (operator.Operator, error) {
 		return nil, fmt.Errorf("invalid value for parameter 'max_log_size', must be equal to or greater than %d bytes", minMaxLogSize)
 	}
 
+	if c.MaxConnections < 0 {
+		return nil, fmt.Errorf("invalid value for parameter 'max_connections', must not be negative")
+	}
+
+	if c.IdleTimeout < 0 {
+		return nil, fmt.Errorf("invalid value for parameter 'idle_timeout', must not be negative")
+	}
+
 	if c.ListenAddress == "" {
 		return nil, fmt.Errorf("missing required parameter 'listen_address'")
 	}
@@ -126,23 +145,35 @@ func (c Config) Build(logger *zap.SugaredLogger) (operator.Operator, error) {
 	}
 	splitFunc = trim.WithFunc(splitFunc, c.TrimConfig.Func())
 
+	var rateLimiter *helper.RateLimiter
+	if c.RateLimit != nil {
+		rateLimiter, err = c.RateLimit.Build()
+		if err != nil {
+			return nil, fmt.Errorf("failed to build rate_limit: %w", err)
+		}
+	}
+
 	var resolver *helper.IPResolver
 	if c.AddAttributes {
 		resolver = helper.NewIPResolver()
 	}
 
 	tcpInput := &Input{
-		InputOperator:   inputOperator,
-		address:         c.ListenAddress,
-		MaxLogSize:      int(c.MaxLogSize),
-		addAttributes:   c.AddAttributes,
-		OneLogPerPacket: c.OneLogPerPacket,
-		encoding:        enc,
-		splitFunc:       splitFunc,
+		InputOperator:    inputOperator,
+		address:          c.ListenAddress,
+		MaxLogSize:       int(c.MaxLogSize),
+		addAttributes:    c.AddAttributes,
+		OneLogPerPacket:  c.OneLogPerPacket,
+		maxConnections:   c.MaxConnections,
+		idleTimeout:      c.IdleTimeout,
+		handshakeTimeout: defaultTLSHandshakeTimeout,
+		encoding:         enc,
+		splitFunc:        splitFunc,
 		backoff: backoff.Backoff{
 			Max: 3 * time.Second,
 		},
-		resolver: resolver,
+		resolver:    resolver,
+		rateLimiter: rateLimiter,
 	}
 
 	if c.TLS != nil {
@@ -162,16 +193,21 @@ type Input struct {
 	MaxLogSize      int
 	addAttributes   bool
 	OneLogPerPacket bool
-
-	listener net.Listener
-	cancel   context.CancelFunc
-	wg       sync.WaitGroup
-	tls      *tls.Config
-	backoff  backoff.Backoff
-
-	encoding  encoding.Encoding
-	splitFunc bufio.SplitFunc
-	resolver  *helper.IPResolver
+	maxConnections  int
+	idleTimeout     time.Duration
+
+	listener         net.Listener
+	connections      atomic.Int64
+	cancel           context.CancelFunc
+	wg               sync.WaitGroup
+	tls              *tls.Config
+	handshakeTimeout time.Duration
+	backoff          backoff.Backoff
+
+	encoding    encoding.Encoding
+	splitFunc   bufio.SplitFunc
+	resolver    *helper.IPResolver
+	rateLimiter *helper.RateLimiter
 }
 
 // Start will start listening for log entries over tcp.
@@ -229,6 +265,15 @@ func (t *Input) goListen(ctx context.Context) {
 			}
 			t.backoff.Reset()
 
+			if t.maxConnections > 0 && t.connections.Load() >= int64(t.maxConnections) {
+				t.Warnw("Rejecting connection, max_connections reached", zap.String("remote_addr", conn.RemoteAddr().String()))
+				if err := conn.Close(); err != nil {
+					t.Errorf("Failed to close connection: %s", err)
+				}
+				continue
+			}
+			t.connections.Add(1)
+
 			t.Debugf("Received connection: %s", conn.RemoteAddr().String())
 			subctx, cancel := context.WithCancel(ctx)
 			t.goHandleClose(subctx, conn)
@@ -248,6 +293,7 @@ func (t *Input) goHandleClose(ctx context.Context, conn net.Conn) {
 		if err := conn.Close(); err != nil {
 			t.Errorf("Failed to close connection: %s", err)
 		}
+		t.connections.Add(-1)
 	}()
 }
 
@@ -259,36 +305,103 @@ func (t *Input) goHandleMessages(ctx context.Context, conn net.Conn, cancel cont
 		defer t.wg.Done()
 		defer cancel()
 
+		peerCert, err := t.peerCertificate(conn)
+		if err != nil {
+			t.Errorw("TLS handshake error", zap.Error(err))
+			return
+		}
+
+		var reader io.Reader = conn
+		if t.idleTimeout > 0 {
+			reader = &idleTimeoutReader{conn: conn, timeout: t.idleTimeout}
+		}
+
 		dec := decode.New(t.encoding)
 		if t.OneLogPerPacket {
 			var buf bytes.Buffer
-			_, err := io.Copy(&buf, conn)
-			if err != nil {
+			_, err := io.Copy(&buf, reader)
+			if isTimeout(err) {
+				t.Debugf("Closing idle connection: %s", conn.RemoteAddr().String())
+			} else if err != nil {
 				t.Errorw("IO copy net connection buffer error", zap.Error(err))
 			}
 			log := truncateMaxLog(buf.Bytes(), t.MaxLogSize)
-			t.handleMessage(ctx, conn, dec, log)
+			t.handleMessage(ctx, conn, peerCert, dec, log)
 			return
 		}
 
 		buf := make([]byte, 0, t.MaxLogSize)
 
-		scanner := bufio.NewScanner(conn)
+		scanner := bufio.NewScanner(reader)
 		scanner.Buffer(buf, t.MaxLogSize)
 
 		scanner.Split(t.splitFunc)
 
 		for scanner.Scan() {
-			t.handleMessage(ctx, conn, dec, scanner.Bytes())
+			t.handleMessage(ctx, conn, peerCert, dec, scanner.Bytes())
 		}
 
-		if err := scanner.Err(); err != nil {
+		if err := scanner.Err(); isTimeout(err) {
+			t.Debugf("Closing idle connection: %s", conn.RemoteAddr().String())
+		} else if err != nil {
 			t.Errorw("Scanner error", zap.Error(err))
 		}
 	}()
 }
 
-func (t *Input) handleMessage(ctx context.Context, conn net.Conn, dec *decode.Decoder, log []byte) {
+// peerCertificate completes the TLS handshake of the connection, and returns
+// the certificate of the client, if any. The handshake fails if the client
+// doesn't complete it within the handshake timeout.
+func (t *Input) peerCertificate(conn net.Conn) (*x509.Certificate, error) {
+	tlsConn, ok := conn.(*tls.Conn)
+	if !ok {
+		return nil, nil
+	}
+	if err := tlsConn.SetDeadline(time.Now().Add(t.handshakeTimeout)); err != nil {
+		return nil, err
+	}
+	if err := tlsConn.Handshake(); err != nil {
+		return nil, err
+	}
+	if err := tlsConn.SetDeadline(time.Time{}); err != nil {
+		return nil, err
+	}
+	if certs := tlsConn.ConnectionState().PeerCertificates; len(certs) > 0 {
+		return certs[0], nil
+	}
+	return nil, nil
+}
+
+// idleTimeoutReader reads from a connection, failing when no data is received
+// within the timeout.
+type idleTimeoutReader struct {
+	conn    net.Conn
+	timeout time.Duration
+}
+
+func (r *idleTimeoutReader) Read(p []byte) (int, error) {
+	if err := r.conn.SetReadDeadline(time.Now().Add(r.timeout)); err != nil {
+		return 0, err
+	}
+	return r.conn.Read(p)
+}
+
+// isTimeout reports whether the error is a timeout of a connection.
+func isTimeout(err error) bool {
+	var netErr net.Error
+	return errors.As(err, &netErr) && netErr.Timeout()
+}
+
+func (t *Input) handleMessage(ctx context.Context, conn net.Conn, peerCert *x509.Certificate, dec *decode.Decoder, log []byte) {
+	if t.rateLimiter != nil {
+		if addr, ok := conn.RemoteAddr().(*net.TCPAddr); ok && !t.rateLimiter.Allow(addr.IP.String()) {
+			if dropped, report := t.rateLimiter.Drop(); report {
+				t.Warnw("Dropped entries, rate_limit exceeded", zap.Int("dropped", dropped), zap.String("remote_addr", addr.String()))
+			}
+			return
+		}
+	}
+
 	decoded, err := dec.Decode(log)
 	if err != nil {
 		t.Errorw("Failed to decode data", zap.Error(err))
@@ -316,11 +429,41 @@ func (t *Input) handleMessage(ctx context.Context, conn net.Conn, dec *decode.De
 			entry.AddAttribute("net.host.port", strconv.FormatInt(int64(addr.Port), 10))
 			entry.AddAttribute("net.host.name", t.resolver.GetHostFromIP(ip))
 		}
+
+		if peerCert != nil {
+			addPeerCertificateAttributes(entry, peerCert)
+		}
 	}
 
 	t.Write(ctx, entry)
 }
 
+// addPeerCertificateAttributes adds the subject and the subject alternative
+// names of the certificate of the client.
+func addPeerCertificateAttributes(e *entry.Entry, cert *x509.Certificate) {
+	e.AddAttribute("tls.client.subject", cert.Subject.String())
+	if cert.Subject.CommonName != "" {
+		e.AddAttribute("tls.client.common_name", cert.Subject.CommonName)
+	}
+
+	var names []any
+	for _, name := range cert.DNSNames {
+		names = append(names, name)
+	}
+	for _, ip := range cert.IPAddresses {
+		names = append(names, ip.String())
+	}
+	for _, email := range cert.EmailAddresses {
+		names = append(names, email)
+	}
+	for _, uri := range cert.URIs {
+		names = append(names, uri.String())
+	}
+	if len(names) > 0 {
+		e.Attributes["tls.client.san"] = names
+	}
+}
+
 func truncateMaxLog(data []byte, maxLogSize int) (token []byte) {
 	if len(data) >= maxLogSize {
 		return data[:maxLogSize]
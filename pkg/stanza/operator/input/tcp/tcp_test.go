@@ -4,10 +4,19 @@
 package tcp
 
 import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	cryptorand "crypto/rand"
 	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io"
+	"math/big"
 	"math/rand"
 	"net"
 	"os"
+	"path/filepath"
 	"strconv"
 	"testing"
 	"time"
@@ -18,6 +27,7 @@ import (
 
 	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/entry"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/operator"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/operator/helper"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/testutil"
 )
 
@@ -335,6 +345,46 @@ func TestBuild(t *testing.T) {
 			},
 			true,
 		},
+		{
+			"max-connections-negative",
+			Config{
+				BaseConfig: BaseConfig{
+					ListenAddress:  "10.0.0.1:9000",
+					MaxConnections: -1,
+				},
+			},
+			true,
+		},
+		{
+			"idle-timeout-negative",
+			Config{
+				BaseConfig: BaseConfig{
+					ListenAddress: "10.0.0.1:9000",
+					IdleTimeout:   -time.Second,
+				},
+			},
+			true,
+		},
+		{
+			"rate-limit-valid",
+			Config{
+				BaseConfig: BaseConfig{
+					ListenAddress: "10.0.0.1:9000",
+					RateLimit:     &helper.RateLimitConfig{Rate: 100},
+				},
+			},
+			false,
+		},
+		{
+			"rate-limit-without-rate",
+			Config{
+				BaseConfig: BaseConfig{
+					ListenAddress: "10.0.0.1:9000",
+					RateLimit:     &helper.RateLimitConfig{Burst: 100},
+				},
+			},
+			true,
+		},
 	}
 
 	for _, tc := range cases {
@@ -343,6 +393,9 @@ func TestBuild(t *testing.T) {
 			cfg.ListenAddress = tc.inputBody.ListenAddress
 			cfg.MaxLogSize = tc.inputBody.MaxLogSize
 			cfg.TLS = tc.inputBody.TLS
+			cfg.MaxConnections = tc.inputBody.MaxConnections
+			cfg.RateLimit = tc.inputBody.RateLimit
+			cfg.IdleTimeout = tc.inputBody.IdleTimeout
 			_, err := cfg.Build(testutil.Logger(t))
 			if tc.expectErr {
 				require.Error(t, err)
@@ -368,6 +421,230 @@ func TestTLSTCPInput(t *testing.T) {
 	t.Run("CarriageReturn", tlsInputTest([]byte("message\r\n"), []string{"message"}))
 }
 
+func startTestInput(t *testing.T, cfg *Config) (*Input, chan *entry.Entry) {
+	op, err := cfg.Build(testutil.Logger(t))
+	require.NoError(t, err)
+
+	fakeOutput := testutil.NewFakeOutput(t)
+	tcpInput := op.(*Input)
+	tcpInput.InputOperator.OutputOperators = []operator.Operator{fakeOutput}
+
+	require.NoError(t, tcpInput.Start(testutil.NewUnscopedMockPersister()))
+	t.Cleanup(func() {
+		require.NoError(t, tcpInput.Stop(), "expected to stop tcp input operator without error")
+	})
+	return tcpInput, fakeOutput.Received
+}
+
+func TestMaxConnections(t *testing.T) {
+	cfg := NewConfigWithID("test_id")
+	cfg.ListenAddress = "127.0.0.1:0"
+	cfg.MaxConnections = 1
+	tcpInput, received := startTestInput(t, cfg)
+
+	first, err := net.Dial("tcp", tcpInput.listener.Addr().String())
+	require.NoError(t, err)
+	_, err = first.Write([]byte("first\n"))
+	require.NoError(t, err)
+	select {
+	case e := <-received:
+		require.Equal(t, "first", e.Body)
+	case <-time.After(time.Second):
+		require.FailNow(t, "Timed out waiting for message to be written")
+	}
+
+	// The connections over the limit are closed
+	second, err := net.Dial("tcp", tcpInput.listener.Addr().String())
+	require.NoError(t, err)
+	defer second.Close()
+	require.NoError(t, second.SetReadDeadline(time.Now().Add(time.Second)))
+	_, err = second.Read(make([]byte, 1))
+	require.ErrorIs(t, err, io.EOF)
+
+	// A connection is accepted again once the first one is closed
+	require.NoError(t, first.Close())
+	require.Eventually(t, func() bool {
+		return tcpInput.connections.Load() == 0
+	}, time.Second, 10*time.Millisecond)
+	third, err := net.Dial("tcp", tcpInput.listener.Addr().String())
+	require.NoError(t, err)
+	defer third.Close()
+	_, err = third.Write([]byte("third\n"))
+	require.NoError(t, err)
+	select {
+	case e := <-received:
+		require.Equal(t, "third", e.Body)
+	case <-time.After(time.Second):
+		require.FailNow(t, "Timed out waiting for message to be written")
+	}
+}
+
+func TestRateLimit(t *testing.T) {
+	cfg := NewConfigWithID("test_id")
+	cfg.ListenAddress = "127.0.0.1:0"
+	cfg.RateLimit = &helper.RateLimitConfig{Rate: 0.001, Burst: 2}
+	tcpInput, received := startTestInput(t, cfg)
+
+	conn, err := net.Dial("tcp", tcpInput.listener.Addr().String())
+	require.NoError(t, err)
+	defer conn.Close()
+	_, err = conn.Write([]byte("message1\nmessage2\nmessage3\n"))
+	require.NoError(t, err)
+
+	for _, expected := range []string{"message1", "message2"} {
+		select {
+		case e := <-received:
+			require.Equal(t, expected, e.Body)
+		case <-time.After(time.Second):
+			require.FailNow(t, "Timed out waiting for message to be written")
+		}
+	}
+	select {
+	case e := <-received:
+		require.FailNow(t, "Unexpected entry: %s", e)
+	case <-time.After(100 * time.Millisecond):
+	}
+}
+
+func TestIdleTimeout(t *testing.T) {
+	cfg := NewConfigWithID("test_id")
+	cfg.ListenAddress = "127.0.0.1:0"
+	cfg.IdleTimeout = 200 * time.Millisecond
+	tcpInput, received := startTestInput(t, cfg)
+
+	conn, err := net.Dial("tcp", tcpInput.listener.Addr().String())
+	require.NoError(t, err)
+	defer conn.Close()
+	_, err = conn.Write([]byte("message\n"))
+	require.NoError(t, err)
+	select {
+	case e := <-received:
+		require.Equal(t, "message", e.Body)
+	case <-time.After(time.Second):
+		require.FailNow(t, "Timed out waiting for message to be written")
+	}
+
+	// The connection is closed once it didn't receive data within the timeout
+	require.NoError(t, conn.SetReadDeadline(time.Now().Add(5*time.Second)))
+	_, err = conn.Read(make([]byte, 1))
+	require.ErrorIs(t, err, io.EOF)
+	require.Eventually(t, func() bool {
+		return tcpInput.connections.Load() == 0
+	}, time.Second, 10*time.Millisecond)
+}
+
+func TestTLSHandshakeTimeout(t *testing.T) {
+	dir := t.TempDir()
+	certFile := filepath.Join(dir, "test.crt")
+	keyFile := filepath.Join(dir, "test.key")
+	require.NoError(t, os.WriteFile(certFile, []byte(testTLSCertificate+"\n"), 0600))
+	require.NoError(t, os.WriteFile(keyFile, []byte(testTLSPrivateKey+"\n"), 0600))
+
+	cfg := NewConfigWithID("test_id")
+	cfg.ListenAddress = "127.0.0.1:0"
+	cfg.TLS = &configtls.TLSServerSetting{
+		TLSSetting: configtls.TLSSetting{
+			CertFile: certFile,
+			KeyFile:  keyFile,
+		},
+	}
+	op, err := cfg.Build(testutil.Logger(t))
+	require.NoError(t, err)
+	tcpInput := op.(*Input)
+	tcpInput.handshakeTimeout = 200 * time.Millisecond
+	tcpInput.InputOperator.OutputOperators = []operator.Operator{testutil.NewFakeOutput(t)}
+	require.NoError(t, tcpInput.Start(testutil.NewUnscopedMockPersister()))
+	defer func() {
+		require.NoError(t, tcpInput.Stop())
+	}()
+
+	// A client which doesn't start the handshake is disconnected
+	conn, err := net.Dial("tcp", tcpInput.listener.Addr().String())
+	require.NoError(t, err)
+	defer conn.Close()
+	require.NoError(t, conn.SetReadDeadline(time.Now().Add(5*time.Second)))
+	_, err = conn.Read(make([]byte, 1))
+	require.ErrorIs(t, err, io.EOF)
+}
+
+func TestTLSClientCertificateAttributes(t *testing.T) {
+	dir := t.TempDir()
+	certFile := filepath.Join(dir, "test.crt")
+	keyFile := filepath.Join(dir, "test.key")
+	require.NoError(t, os.WriteFile(certFile, []byte(testTLSCertificate+"\n"), 0600))
+	require.NoError(t, os.WriteFile(keyFile, []byte(testTLSPrivateKey+"\n"), 0600))
+	clientCert, clientCAFile := writeClientCertificate(t, dir)
+
+	cfg := NewConfigWithID("test_id")
+	cfg.ListenAddress = "127.0.0.1:0"
+	cfg.AddAttributes = true
+	cfg.TLS = &configtls.TLSServerSetting{
+		TLSSetting: configtls.TLSSetting{
+			CertFile: certFile,
+			KeyFile:  keyFile,
+		},
+		ClientCAFile: clientCAFile,
+	}
+	tcpInput, received := startTestInput(t, cfg)
+
+	conn, err := tls.Dial("tcp", tcpInput.listener.Addr().String(), &tls.Config{
+		InsecureSkipVerify: true, // #nosec G402 - the server certificate is self-signed
+		Certificates:       []tls.Certificate{clientCert},
+	})
+	require.NoError(t, err)
+	defer conn.Close()
+	_, err = conn.Write([]byte("message\n"))
+	require.NoError(t, err)
+
+	select {
+	case e := <-received:
+		require.Equal(t, "message", e.Body)
+		require.Equal(t, "CN=client.example.com,O=Example", e.Attributes["tls.client.subject"])
+		require.Equal(t, "client.example.com", e.Attributes["tls.client.common_name"])
+		require.Equal(t, []any{"client.example.com", "10.0.0.1", "ops@example.com"}, e.Attributes["tls.client.san"])
+		require.Equal(t, "127.0.0.1", e.Attributes["net.peer.ip"])
+	case <-time.After(time.Second):
+		require.FailNow(t, "Timed out waiting for message to be written")
+	}
+
+	// Clients without a certificate are rejected
+	noCertConn, err := tls.Dial("tcp", tcpInput.listener.Addr().String(), &tls.Config{
+		InsecureSkipVerify: true, // #nosec G402 - the server certificate is self-signed
+	})
+	if err == nil {
+		defer noCertConn.Close()
+		require.NoError(t, noCertConn.SetReadDeadline(time.Now().Add(time.Second)))
+		_, err = noCertConn.Read(make([]byte, 1))
+	}
+	require.Error(t, err)
+}
+
+// writeClientCertificate creates a self-signed client certificate, and writes
+// it as the CA file of the client certificates.
+func writeClientCertificate(t *testing.T, dir string) (tls.Certificate, string) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), cryptorand.Reader)
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "client.example.com", Organization: []string{"Example"}},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+		DNSNames:              []string{"client.example.com"},
+		IPAddresses:           []net.IP{net.IPv4(10, 0, 0, 1)},
+		EmailAddresses:        []string{"ops@example.com"},
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+	}
+	der, err := x509.CreateCertificate(cryptorand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+
+	caFile := filepath.Join(dir, "client-ca.crt")
+	require.NoError(t, os.WriteFile(caFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0600))
+	return tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key}, caFile
+}
+
 func TestFailToBind(t *testing.T) {
 	ip := "localhost"
 	port := 0
@@ -7,6 +7,7 @@ import (
 	"path/filepath"
 	"testing"
 
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/operator/helper"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/operator/operatortest"
 )
 
@@ -30,6 +31,10 @@ func TestUnmarshal(t *testing.T) {
 					cfg.Encoding = "utf-8"
 					cfg.SplitConfig.LineStartPattern = "ABC"
 					cfg.SplitConfig.LineEndPattern = ""
+					cfg.RateLimit = &helper.RateLimitConfig{
+						Rate:  1000,
+						Burst: 2000,
+					}
 					return cfg
 				}(),
 			},
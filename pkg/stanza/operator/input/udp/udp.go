@@ -70,13 +70,21 @@ type AsyncConfig struct {
 
 // BaseConfig is the details configuration of a udp input operator.
 type BaseConfig struct {
-	ListenAddress   string       `mapstructure:"listen_address,omitempty"`
-	OneLogPerPacket bool         `mapstructure:"one_log_per_packet,omitempty"`
-	AddAttributes   bool         `mapstructure:"add_attributes,omitempty"`
-	Encoding        string       `mapstructure:"encoding,omitempty"`
-	SplitConfig     split.Config `mapstructure:"multiline,omitempty"`
-	TrimConfig      trim.Config  `mapstructure:",squash"`
-	AsyncConfig     *AsyncConfig `mapstructure:"async,omitempty"`
+	ListenAddress    string                  `mapstructure:"listen_address,omitempty"`
+	OneLogPerPacket  bool                    `mapstructure:"one_log_per_packet,omitempty"`
+	AddAttributes    bool                    `mapstructure:"add_attributes,omitempty"`
+	Encoding         string                  `mapstructure:"encoding,omitempty"`
+	SplitConfig      split.Config            `mapstructure:"multiline,omitempty"`
+	TrimConfig       trim.Config             `mapstructure:",squash"`
+	AsyncConfig      *AsyncConfig            `mapstructure:"async,omitempty"`
+	RateLimit        *helper.RateLimitConfig `mapstructure:"rate_limit,omitempty"`
+	SplitFuncBuilder SplitFuncBuilder
+}
+
+type SplitFuncBuilder func(enc encoding.Encoding) (bufio.SplitFunc, error)
+
+func (c Config) defaultSplitFuncBuilder(enc encoding.Encoding) (bufio.SplitFunc, error) {
+	return c.SplitConfig.Func(enc, true, MaxUDPSize)
 }
 
 // Build will build a udp input operator.
@@ -100,13 +108,25 @@ func (c Config) Build(logger *zap.SugaredLogger) (operator.Operator, error) {
 		return nil, err
 	}
 
+	if c.SplitFuncBuilder == nil {
+		c.SplitFuncBuilder = c.defaultSplitFuncBuilder
+	}
+
 	// Build split func
-	splitFunc, err := c.SplitConfig.Func(enc, true, MaxUDPSize)
+	splitFunc, err := c.SplitFuncBuilder(enc)
 	if err != nil {
 		return nil, err
 	}
 	splitFunc = trim.WithFunc(splitFunc, c.TrimConfig.Func())
 
+	var rateLimiter *helper.RateLimiter
+	if c.RateLimit != nil {
+		rateLimiter, err = c.RateLimit.Build()
+		if err != nil {
+			return nil, fmt.Errorf("failed to build rate_limit: %w", err)
+		}
+	}
+
 	var resolver *helper.IPResolver
 	if c.AddAttributes {
 		resolver = helper.NewIPResolver()
@@ -132,6 +152,7 @@ func (c Config) Build(logger *zap.SugaredLogger) (operator.Operator, error) {
 		encoding:        enc,
 		splitFunc:       splitFunc,
 		resolver:        resolver,
+		rateLimiter:     rateLimiter,
 		OneLogPerPacket: c.OneLogPerPacket,
 		AsyncConfig:     c.AsyncConfig,
 	}
@@ -162,9 +183,10 @@ type Input struct {
 	wg         sync.WaitGroup
 	wgReader   sync.WaitGroup
 
-	encoding  encoding.Encoding
-	splitFunc bufio.SplitFunc
-	resolver  *helper.IPResolver
+	encoding    encoding.Encoding
+	splitFunc   bufio.SplitFunc
+	resolver    *helper.IPResolver
+	rateLimiter *helper.RateLimiter
 
 	messageQueue   chan messageAndAddress
 	readBufferPool sync.Pool
@@ -315,6 +337,15 @@ func truncateMaxLog(data []byte) (token []byte) {
 }
 
 func (u *Input) handleMessage(ctx context.Context, remoteAddr net.Addr, dec *decode.Decoder, log []byte) {
+	if u.rateLimiter != nil {
+		if addr, ok := remoteAddr.(*net.UDPAddr); ok && !u.rateLimiter.Allow(addr.IP.String()) {
+			if dropped, report := u.rateLimiter.Drop(); report {
+				u.Warnw("Dropped entries, rate_limit exceeded", zap.Int("dropped", dropped), zap.String("remote_addr", addr.String()))
+			}
+			return
+		}
+	}
+
 	decoded := log
 	if u.encoding != encoding.Nop {
 		var err error
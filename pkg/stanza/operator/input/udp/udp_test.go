@@ -15,6 +15,7 @@ import (
 
 	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/entry"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/operator"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/operator/helper"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/testutil"
 )
 
@@ -158,6 +159,47 @@ func TestInputAttributes(t *testing.T) {
 	t.Run("NewlineInMessage", udpInputAttributesTest([]byte("message1\nmessage2\n"), []string{"message1\nmessage2"}))
 }
 
+func TestRateLimit(t *testing.T) {
+	cfg := NewConfigWithID("test_input")
+	cfg.ListenAddress = "127.0.0.1:0"
+	cfg.RateLimit = &helper.RateLimitConfig{Rate: 0.001, Burst: 2}
+
+	op, err := cfg.Build(testutil.Logger(t))
+	require.NoError(t, err)
+
+	fakeOutput := testutil.NewFakeOutput(t)
+	udpInput := op.(*Input)
+	udpInput.InputOperator.OutputOperators = []operator.Operator{fakeOutput}
+
+	require.NoError(t, udpInput.Start(testutil.NewUnscopedMockPersister()))
+	defer func() {
+		require.NoError(t, udpInput.Stop(), "expected to stop udp input operator without error")
+	}()
+
+	conn, err := net.Dial("udp", udpInput.connection.LocalAddr().String())
+	require.NoError(t, err)
+	defer conn.Close()
+
+	for _, message := range []string{"message1", "message2", "message3"} {
+		_, err = conn.Write([]byte(message))
+		require.NoError(t, err)
+	}
+
+	for _, expectedBody := range []string{"message1", "message2"} {
+		select {
+		case e := <-fakeOutput.Received:
+			require.Equal(t, expectedBody, e.Body)
+		case <-time.After(time.Second):
+			require.FailNow(t, "Timed out waiting for message to be written")
+		}
+	}
+	select {
+	case e := <-fakeOutput.Received:
+		require.FailNow(t, "Unexpected entry: %s", e)
+	case <-time.After(100 * time.Millisecond):
+	}
+}
+
 func TestFailToBind(t *testing.T) {
 	ip := "localhost"
 	port := 0
@@ -40,7 +40,7 @@ func (e *Event) RenderSimple(buffer Buffer) (EventXML, error) {
 		return EventXML{}, fmt.Errorf("failed to read bytes from buffer: %w", err)
 	}
 
-	return unmarshalEventXML(bytes)
+	return UnmarshalEventXML(bytes)
 }
 
 // RenderFormatted will render the event as EventXML with formatted info.
@@ -69,7 +69,7 @@ func (e *Event) RenderFormatted(buffer Buffer, publisher Publisher) (EventXML, e
 		return EventXML{}, fmt.Errorf("failed to read bytes from buffer: %w", err)
 	}
 
-	return unmarshalEventXML(bytes)
+	return UnmarshalEventXML(bytes)
 }
 
 // Close will close the event handle.
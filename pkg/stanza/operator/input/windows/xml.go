@@ -35,12 +35,28 @@ type EventXML struct {
 
 // parseTimestamp will parse the timestamp of the event.
 func (e *EventXML) parseTimestamp() time.Time {
-	if timestamp, err := time.Parse(time.RFC3339Nano, e.TimeCreated.SystemTime); err == nil {
+	if timestamp, ok := e.Timestamp(); ok {
 		return timestamp
 	}
 	return time.Now()
 }
 
+// Timestamp returns the creation time of the event, or false if it is missing or invalid.
+func (e *EventXML) Timestamp() (time.Time, bool) {
+	timestamp, err := time.Parse(time.RFC3339Nano, e.TimeCreated.SystemTime)
+	return timestamp, err == nil
+}
+
+// Severity returns the severity of the event.
+func (e *EventXML) Severity() entry.Severity {
+	return e.parseRenderedSeverity()
+}
+
+// Body returns the body of the event, as set by the windows_eventlog_input operator.
+func (e *EventXML) Body() map[string]any {
+	return e.parseBody()
+}
+
 // parseRenderedSeverity will parse the severity of the event.
 func (e *EventXML) parseRenderedSeverity() entry.Severity {
 	switch e.RenderedLevel {
@@ -175,8 +191,9 @@ func parseEventData(eventData EventData) map[string]any {
 	return outputMap
 }
 
-// unmarshalEventXML will unmarshal EventXML from xml bytes.
-func unmarshalEventXML(bytes []byte) (EventXML, error) {
+// UnmarshalEventXML will unmarshal EventXML from xml bytes.
+// The xml can be rendered by the Windows APIs, forwarded or read from an evtx file.
+func UnmarshalEventXML(bytes []byte) (EventXML, error) {
 	var eventXML EventXML
 	if err := xml.Unmarshal(bytes, &eventXML); err != nil {
 		return EventXML{}, fmt.Errorf("failed to unmarshal xml bytes into event: %w (%s)", err, string(bytes))
@@ -0,0 +1,277 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package container // import "github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/operator/parser/container"
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	jsoniter "github.com/json-iterator/go"
+	"go.uber.org/zap"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/attrs"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/entry"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/operator"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/operator/helper"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/operator/transformer/recombine"
+)
+
+const (
+	operatorType = "container"
+
+	dockerFormat     = "docker"
+	crioFormat       = "crio"
+	containerdFormat = "containerd"
+
+	// The attributes set by the parser.
+	iostreamAttribute = "log.iostream"
+	logtagAttribute   = "logtag"
+
+	// The log tags of the CRI format, the docker format is given the same tags.
+	partialLogtag = "P"
+	fullLogtag    = "F"
+
+	// The fields of the parsed log, which are removed once moved to the timestamp and the body.
+	timeField = "time"
+	logField  = "log"
+)
+
+var (
+	criLineMatcher  = regexp.MustCompile(`^(?P<time>[^ ]+) (?P<stream>stdout|stderr) (?P<logtag>[^ ]*) ?(?P<log>.*)$`)
+	filePathMatcher = regexp.MustCompile(`^.*/(?P<namespace>[^_/]+)_(?P<pod_name>[^_/]+)_(?P<uid>[a-f0-9\-]+)/(?P<container_name>[^._/]+)/(?P<restart_count>\d+)\.log$`)
+)
+
+func init() {
+	operator.Register(operatorType, func() operator.Builder { return NewConfig() })
+}
+
+// NewConfig creates a new container parser config with default values
+func NewConfig() *Config {
+	return NewConfigWithID(operatorType)
+}
+
+// NewConfigWithID creates a new container parser config with default values
+func NewConfigWithID(operatorID string) *Config {
+	return &Config{
+		ParserConfig:            helper.NewParserConfig(operatorID, operatorType),
+		AddMetadataFromFilePath: true,
+	}
+}
+
+// Config is the configuration of a container parser operator.
+type Config struct {
+	helper.ParserConfig `mapstructure:",squash"`
+
+	// Format is the format of the container runtime logs. The format is detected for each entry if empty.
+	Format string `mapstructure:"format"`
+
+	// AddMetadataFromFilePath sets the Kubernetes metadata found in the log file path as resource attributes.
+	AddMetadataFromFilePath bool `mapstructure:"add_metadata_from_filepath"`
+
+	// MaxLogSize is the maximum size of a log recombined from partial lines.
+	MaxLogSize helper.ByteSize `mapstructure:"max_log_size,omitempty"`
+}
+
+// Build will build a container parser operator.
+func (c Config) Build(logger *zap.SugaredLogger) (operator.Operator, error) {
+	switch c.Format {
+	case "", dockerFormat, crioFormat, containerdFormat:
+	default:
+		return nil, fmt.Errorf("invalid format '%s', must be one of '%s', '%s' or '%s'", c.Format, dockerFormat, crioFormat, containerdFormat)
+	}
+
+	if c.ParseTo.String() != entry.NewAttributeField().String() {
+		return nil, errors.New("parse_to is not supported by the container parser, the parsed fields are set as attributes")
+	}
+
+	if c.ParserConfig.TimeParser == nil {
+		parseFromField := entry.NewAttributeField(timeField)
+		c.ParserConfig.TimeParser = &helper.TimeParser{
+			ParseFrom:  &parseFromField,
+			LayoutType: helper.GotimeKey,
+			Layout:     time.RFC3339Nano,
+		}
+	}
+	if c.ParserConfig.BodyField == nil {
+		bodyField := entry.NewAttributeField(logField)
+		c.ParserConfig.BodyField = &bodyField
+	}
+
+	parserOperator, err := c.ParserConfig.Build(logger)
+	if err != nil {
+		return nil, err
+	}
+
+	// The partial lines are recombined by an internal recombine operator,
+	// which sends the entries to the outputs of the parser.
+	recombineConfig := recombine.NewConfigWithID(c.OperatorID + "_recombine")
+	recombineConfig.OutputIDs = c.OutputIDs
+	recombineConfig.IsLastEntry = fmt.Sprintf("attributes.%s != '%s'", logtagAttribute, partialLogtag)
+	recombineConfig.CombineField = entry.NewBodyField()
+	recombineConfig.CombineWith = ""
+	recombineConfig.OverwriteWith = "newest"
+	recombineConfig.SourceIdentifier = entry.NewAttributeField(attrs.LogFilePath)
+	recombineConfig.MaxLogSize = c.MaxLogSize
+	recombineOperator, err := recombineConfig.Build(logger)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build the recombine operator: %w", err)
+	}
+
+	return &Parser{
+		ParserOperator:          parserOperator,
+		recombine:               recombineOperator,
+		format:                  c.Format,
+		addMetadataFromFilePath: c.AddMetadataFromFilePath,
+		json:                    jsoniter.ConfigFastest,
+	}, nil
+}
+
+// Parser is an operator that parses the logs of container runtimes.
+type Parser struct {
+	helper.ParserOperator
+	recombine               operator.Operator
+	format                  string
+	addMetadataFromFilePath bool
+	json                    jsoniter.API
+}
+
+// Start will start the internal recombine operator.
+func (p *Parser) Start(persister operator.Persister) error {
+	return p.recombine.Start(persister)
+}
+
+// Stop will flush the partial lines and stop the internal recombine operator.
+func (p *Parser) Stop() error {
+	return p.recombine.Stop()
+}
+
+// SetOutputs will set the outputs of the parser and of the internal recombine operator.
+func (p *Parser) SetOutputs(operators []operator.Operator) error {
+	if err := p.ParserOperator.SetOutputs(operators); err != nil {
+		return err
+	}
+	return p.recombine.SetOutputs(operators)
+}
+
+// SetOutputIDs will set the output IDs of the parser and of the internal recombine operator.
+func (p *Parser) SetOutputIDs(opIDs []string) {
+	p.ParserOperator.SetOutputIDs(opIDs)
+	p.recombine.SetOutputIDs(opIDs)
+}
+
+// Process will parse an entry of a container runtime log, and recombine partial lines.
+func (p *Parser) Process(ctx context.Context, e *entry.Entry) error {
+	skip, err := p.Skip(ctx, e)
+	if err != nil {
+		return p.HandleEntryError(ctx, e, err)
+	}
+	if skip {
+		p.Write(ctx, e)
+		return nil
+	}
+
+	if err = p.ParseWith(ctx, e, p.parse); err != nil {
+		return err
+	}
+	e.Delete(entry.NewAttributeField(timeField))
+	e.Delete(entry.NewAttributeField(logField))
+
+	if p.addMetadataFromFilePath {
+		if err = extractMetadataFromFilePath(e); err != nil {
+			return p.HandleEntryError(ctx, e, err)
+		}
+	}
+
+	return p.recombine.Process(ctx, e)
+}
+
+// parse will parse a line of a container runtime log.
+func (p *Parser) parse(value any) (any, error) {
+	line, ok := value.(string)
+	if !ok {
+		return nil, fmt.Errorf("type '%T' cannot be parsed as a container log", value)
+	}
+
+	format := p.format
+	if format == "" {
+		format = detectFormat(line)
+	}
+	if format == dockerFormat {
+		return p.parseDocker(line)
+	}
+	return parseCRI(line)
+}
+
+// detectFormat returns the format of the line, the docker format being JSON.
+func detectFormat(line string) string {
+	if strings.HasPrefix(line, "{") {
+		return dockerFormat
+	}
+	return crioFormat
+}
+
+// parseDocker parses a line of the json-file logging driver of docker, where the
+// partial lines are the ones without a trailing newline.
+func (p *Parser) parseDocker(line string) (map[string]any, error) {
+	var parsed struct {
+		Log    string `json:"log"`
+		Stream string `json:"stream"`
+		Time   string `json:"time"`
+	}
+	if err := p.json.UnmarshalFromString(line, &parsed); err != nil {
+		return nil, fmt.Errorf("failed to parse docker log: %w", err)
+	}
+
+	logtag := partialLogtag
+	if strings.HasSuffix(parsed.Log, "\n") {
+		logtag = fullLogtag
+	}
+	return map[string]any{
+		timeField:         parsed.Time,
+		logField:          strings.TrimSuffix(parsed.Log, "\n"),
+		iostreamAttribute: parsed.Stream,
+		logtagAttribute:   logtag,
+	}, nil
+}
+
+// parseCRI parses a line of the CRI logging format, used by CRI-O and containerd.
+func parseCRI(line string) (map[string]any, error) {
+	matches := criLineMatcher.FindStringSubmatch(line)
+	if matches == nil {
+		return nil, errors.New("failed to parse CRI log: the line does not match the CRI format")
+	}
+	return map[string]any{
+		timeField:         matches[criLineMatcher.SubexpIndex("time")],
+		logField:          matches[criLineMatcher.SubexpIndex("log")],
+		iostreamAttribute: matches[criLineMatcher.SubexpIndex("stream")],
+		logtagAttribute:   matches[criLineMatcher.SubexpIndex("logtag")],
+	}, nil
+}
+
+// extractMetadataFromFilePath sets the Kubernetes metadata found in the path of a
+// /var/log/pods/<namespace>_<pod_name>_<pod_uid>/<container_name>/<restart_count>.log file.
+func extractMetadataFromFilePath(e *entry.Entry) error {
+	var path string
+	if err := e.Read(entry.NewAttributeField(attrs.LogFilePath), &path); err != nil {
+		return fmt.Errorf("failed to read the %s attribute, include_file_path must be enabled: %w", attrs.LogFilePath, err)
+	}
+	matches := filePathMatcher.FindStringSubmatch(path)
+	if matches == nil {
+		return fmt.Errorf("failed to extract metadata from the file path '%s'", path)
+	}
+
+	if e.Resource == nil {
+		e.Resource = map[string]any{}
+	}
+	e.Resource["k8s.namespace.name"] = matches[filePathMatcher.SubexpIndex("namespace")]
+	e.Resource["k8s.pod.name"] = matches[filePathMatcher.SubexpIndex("pod_name")]
+	e.Resource["k8s.pod.uid"] = matches[filePathMatcher.SubexpIndex("uid")]
+	e.Resource["k8s.container.name"] = matches[filePathMatcher.SubexpIndex("container_name")]
+	e.Resource["k8s.container.restart_count"] = matches[filePathMatcher.SubexpIndex("restart_count")]
+	return nil
+}
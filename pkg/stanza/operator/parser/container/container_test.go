@@ -0,0 +1,260 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package container
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/entry"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/operator"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/testutil"
+)
+
+const testFilePath = "/var/log/pods/kube-system_kube-proxy-8x6z2_49cc7c1fd3702c40b2686ea7486091d6/kube-proxy/1.log"
+
+func newTestParser(t *testing.T, cfg *Config) (operator.Operator, *testutil.FakeOutput) {
+	cfg.OutputIDs = []string{"fake"}
+	op, err := cfg.Build(testutil.Logger(t))
+	require.NoError(t, err)
+
+	fake := testutil.NewFakeOutput(t)
+	require.NoError(t, op.SetOutputs([]operator.Operator{fake}))
+	require.NoError(t, op.Start(testutil.NewUnscopedMockPersister()))
+	t.Cleanup(func() {
+		require.NoError(t, op.Stop())
+	})
+	return op, fake
+}
+
+func newTestEntry(body string) *entry.Entry {
+	e := entry.New()
+	e.Body = body
+	e.Attributes = map[string]any{"log.file.path": testFilePath}
+	return e
+}
+
+func receiveEntry(t *testing.T, fake *testutil.FakeOutput) *entry.Entry {
+	select {
+	case e := <-fake.Received:
+		return e
+	case <-time.After(time.Second):
+		require.FailNow(t, "Timed out waiting for entry")
+		return nil
+	}
+}
+
+func TestParser(t *testing.T) {
+	expectedResource := map[string]any{
+		"k8s.namespace.name":          "kube-system",
+		"k8s.pod.name":                "kube-proxy-8x6z2",
+		"k8s.pod.uid":                 "49cc7c1fd3702c40b2686ea7486091d6",
+		"k8s.container.name":          "kube-proxy",
+		"k8s.container.restart_count": "1",
+	}
+
+	cases := []struct {
+		name         string
+		format       string
+		body         string
+		expectedBody string
+		expectedTime time.Time
+		stream       string
+	}{
+		{
+			name:         "docker",
+			body:         `{"log":"INFO: log line here\n","stream":"stdout","time":"2029-03-30T08:31:20.545192187Z"}`,
+			expectedBody: "INFO: log line here",
+			expectedTime: time.Date(2029, time.March, 30, 8, 31, 20, 545192187, time.UTC),
+			stream:       "stdout",
+		},
+		{
+			name:         "crio",
+			body:         "2024-04-13T07:59:37.505201169-05:00 stderr F standard error line",
+			expectedBody: "standard error line",
+			expectedTime: time.Date(2024, time.April, 13, 12, 59, 37, 505201169, time.UTC),
+			stream:       "stderr",
+		},
+		{
+			name:         "containerd",
+			body:         "2023-06-22T10:10:38.803151283Z stdout F INFO: log line here",
+			expectedBody: "INFO: log line here",
+			expectedTime: time.Date(2023, time.June, 22, 10, 10, 38, 803151283, time.UTC),
+			stream:       "stdout",
+		},
+		{
+			name:         "containerd_empty_line",
+			body:         "2023-06-22T10:10:38.803151283Z stdout F",
+			expectedBody: "",
+			expectedTime: time.Date(2023, time.June, 22, 10, 10, 38, 803151283, time.UTC),
+			stream:       "stdout",
+		},
+		{
+			name:         "explicit_format",
+			format:       "docker",
+			body:         `{"log":"INFO: log line here\n","stream":"stderr","time":"2029-03-30T08:31:20.545192187Z"}`,
+			expectedBody: "INFO: log line here",
+			expectedTime: time.Date(2029, time.March, 30, 8, 31, 20, 545192187, time.UTC),
+			stream:       "stderr",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			cfg := NewConfig()
+			cfg.Format = tc.format
+			op, fake := newTestParser(t, cfg)
+
+			require.NoError(t, op.Process(context.Background(), newTestEntry(tc.body)))
+
+			e := receiveEntry(t, fake)
+			require.Equal(t, tc.expectedBody, e.Body)
+			require.True(t, tc.expectedTime.Equal(e.Timestamp), "expected %v, got %v", tc.expectedTime, e.Timestamp)
+			require.Equal(t, map[string]any{
+				"log.file.path": testFilePath,
+				"log.iostream":  tc.stream,
+				"logtag":        "F",
+			}, e.Attributes)
+			require.Equal(t, expectedResource, e.Resource)
+		})
+	}
+}
+
+func TestParserRecombine(t *testing.T) {
+	cases := []struct {
+		name  string
+		lines []string
+	}{
+		{
+			name: "cri",
+			lines: []string{
+				"2023-06-22T10:10:38.803151283Z stdout P first part, ",
+				"2023-06-22T10:10:38.803151284Z stdout P second part, ",
+				"2023-06-22T10:10:38.803151285Z stdout F last part",
+			},
+		},
+		{
+			name: "docker",
+			lines: []string{
+				`{"log":"first part, ","stream":"stdout","time":"2023-06-22T10:10:38.803151283Z"}`,
+				`{"log":"second part, ","stream":"stdout","time":"2023-06-22T10:10:38.803151284Z"}`,
+				`{"log":"last part\n","stream":"stdout","time":"2023-06-22T10:10:38.803151285Z"}`,
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			op, fake := newTestParser(t, NewConfig())
+
+			for _, line := range tc.lines {
+				require.NoError(t, op.Process(context.Background(), newTestEntry(line)))
+			}
+
+			e := receiveEntry(t, fake)
+			require.Equal(t, "first part, second part, last part", e.Body)
+			require.Equal(t, "F", e.Attributes["logtag"])
+			require.True(t, time.Date(2023, time.June, 22, 10, 10, 38, 803151285, time.UTC).Equal(e.Timestamp))
+			fake.ExpectNoEntry(t, 100*time.Millisecond)
+		})
+	}
+}
+
+func TestParserRecombineSources(t *testing.T) {
+	op, fake := newTestParser(t, NewConfig())
+
+	otherFilePath := "/var/log/pods/default_nginx-5d8d7f9d8c-2xk7p_a7d3c6a1-0f6b-4f8e-9d3c-2b1e5f6a7c8d/nginx/0.log"
+	partial := newTestEntry("2023-06-22T10:10:38.803151283Z stdout P partial ")
+	other := newTestEntry("2023-06-22T10:10:38.803151284Z stdout F other file")
+	other.Attributes["log.file.path"] = otherFilePath
+	last := newTestEntry("2023-06-22T10:10:38.803151285Z stdout F line")
+
+	require.NoError(t, op.Process(context.Background(), partial))
+	require.NoError(t, op.Process(context.Background(), other))
+	require.NoError(t, op.Process(context.Background(), last))
+
+	e := receiveEntry(t, fake)
+	require.Equal(t, "other file", e.Body)
+	require.Equal(t, "nginx", e.Resource["k8s.container.name"])
+	require.Equal(t, "a7d3c6a1-0f6b-4f8e-9d3c-2b1e5f6a7c8d", e.Resource["k8s.pod.uid"])
+	require.Equal(t, "partial line", receiveEntry(t, fake).Body)
+}
+
+func TestParserWithoutMetadata(t *testing.T) {
+	cfg := NewConfig()
+	cfg.AddMetadataFromFilePath = false
+	op, fake := newTestParser(t, cfg)
+
+	e := entry.New()
+	e.Body = "2023-06-22T10:10:38.803151283Z stdout F line"
+	require.NoError(t, op.Process(context.Background(), e))
+
+	e = receiveEntry(t, fake)
+	require.Equal(t, "line", e.Body)
+	require.Empty(t, e.Resource)
+}
+
+func TestParserErrors(t *testing.T) {
+	cases := []struct {
+		name     string
+		body     any
+		filePath string
+		err      string
+	}{
+		{
+			name:     "invalid_cri",
+			body:     "2023-06-22T10:10:38.803151283Z stdin F line",
+			filePath: testFilePath,
+			err:      "failed to parse CRI log: the line does not match the CRI format",
+		},
+		{
+			name:     "invalid_docker",
+			body:     `{"log":"line\n",`,
+			filePath: testFilePath,
+			err:      "failed to parse docker log",
+		},
+		{
+			name:     "invalid_type",
+			body:     map[string]any{"log": "line"},
+			filePath: testFilePath,
+			err:      "type 'map[string]interface {}' cannot be parsed as a container log",
+		},
+		{
+			name:     "invalid_file_path",
+			body:     "2023-06-22T10:10:38.803151283Z stdout F line",
+			filePath: "/var/log/containers/nginx.log",
+			err:      "failed to extract metadata from the file path '/var/log/containers/nginx.log'",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			op, fake := newTestParser(t, NewConfig())
+
+			e := entry.New()
+			e.Body = tc.body
+			e.Attributes = map[string]any{"log.file.path": tc.filePath}
+			err := op.Process(context.Background(), e)
+			require.ErrorContains(t, err, tc.err)
+
+			// The entry is sent as is with the default on_error.
+			require.NotNil(t, receiveEntry(t, fake))
+		})
+	}
+}
+
+func TestBuildErrors(t *testing.T) {
+	cfg := NewConfig()
+	cfg.Format = "podman"
+	_, err := cfg.Build(testutil.Logger(t))
+	require.EqualError(t, err, "invalid format 'podman', must be one of 'docker', 'crio' or 'containerd'")
+
+	cfg = NewConfig()
+	cfg.ParseTo = entry.RootableField{Field: entry.NewBodyField()}
+	_, err = cfg.Build(testutil.Logger(t))
+	require.EqualError(t, err, "parse_to is not supported by the container parser, the parsed fields are set as attributes")
+}
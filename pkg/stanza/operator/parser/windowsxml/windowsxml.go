@@ -0,0 +1,93 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package windowsxml // import "github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/operator/parser/windowsxml"
+
+import (
+	"context"
+	"fmt"
+
+	"go.uber.org/zap"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/entry"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/operator"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/operator/helper"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/operator/input/windows"
+)
+
+const operatorType = "windows_xml_parser"
+
+func init() {
+	operator.Register(operatorType, func() operator.Builder { return NewConfig() })
+}
+
+// NewConfig creates a new Windows Event XML parser config with default values
+func NewConfig() *Config {
+	return NewConfigWithID(operatorType)
+}
+
+// NewConfigWithID creates a new Windows Event XML parser config with default values
+func NewConfigWithID(operatorID string) *Config {
+	cfg := &Config{
+		ParserConfig: helper.NewParserConfig(operatorID, operatorType),
+	}
+	// The event replaces the body, as with the windows_eventlog_input operator
+	cfg.ParseTo = entry.RootableField{Field: entry.NewBodyField()}
+	return cfg
+}
+
+// Config is the configuration of a Windows Event XML parser operator.
+type Config struct {
+	helper.ParserConfig `mapstructure:",squash"`
+}
+
+// Build will build a Windows Event XML parser operator.
+func (c Config) Build(logger *zap.SugaredLogger) (operator.Operator, error) {
+	parserOperator, err := c.ParserConfig.Build(logger)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Parser{
+		ParserOperator: parserOperator,
+	}, nil
+}
+
+// Parser is an operator that parses Windows Event XML.
+type Parser struct {
+	helper.ParserOperator
+}
+
+// Process will parse an entry for Windows Event XML.
+// The timestamp and severity of the entry are set from the event.
+func (p *Parser) Process(ctx context.Context, ent *entry.Entry) error {
+	return p.ParserOperator.ProcessWith(ctx, ent, func(value any) (any, error) {
+		event, err := parse(value)
+		if err != nil {
+			return nil, err
+		}
+		if timestamp, ok := event.Timestamp(); ok {
+			ent.Timestamp = timestamp
+		}
+		ent.Severity = event.Severity()
+		return event.Body(), nil
+	})
+}
+
+// parse will parse a value as Windows Event XML.
+func parse(value any) (*windows.EventXML, error) {
+	var event windows.EventXML
+	var err error
+	switch m := value.(type) {
+	case string:
+		event, err = windows.UnmarshalEventXML([]byte(m))
+	case []byte:
+		event, err = windows.UnmarshalEventXML(m)
+	default:
+		return nil, fmt.Errorf("type %T cannot be parsed as Windows Event XML", value)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &event, nil
+}
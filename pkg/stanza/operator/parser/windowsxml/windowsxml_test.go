@@ -0,0 +1,170 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package windowsxml
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/entry"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/operator"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/testutil"
+)
+
+func newTestParser(t *testing.T, cfg *Config) (operator.Operator, *testutil.FakeOutput) {
+	cfg.OutputIDs = []string{"fake"}
+	op, err := cfg.Build(testutil.Logger(t))
+	require.NoError(t, err)
+
+	fake := testutil.NewFakeOutput(t)
+	require.NoError(t, op.SetOutputs([]operator.Operator{fake}))
+	return op, fake
+}
+
+func receiveEntry(t *testing.T, fake *testutil.FakeOutput) *entry.Entry {
+	select {
+	case e := <-fake.Received:
+		return e
+	case <-time.After(time.Second):
+		require.FailNow(t, "Timed out waiting for entry")
+		return nil
+	}
+}
+
+func expectedBody() map[string]any {
+	return map[string]any{
+		"event_id": map[string]any{
+			"qualifiers": uint16(16384),
+			"id":         uint32(16384),
+		},
+		"provider": map[string]any{
+			"name":         "Microsoft-Windows-Security-SPP",
+			"guid":         "{E23B33B0-C8C9-472C-A5F9-F2BDFEA0F156}",
+			"event_source": "Software Protection Platform Service",
+		},
+		"system_time": "2022-04-22T10:20:52.3778625Z",
+		"computer":    "computer",
+		"channel":     "Application",
+		"record_id":   uint64(23401),
+		"level":       "4",
+		"message":     "",
+		"task":        "0",
+		"opcode":      "0",
+		"keywords":    []string{"0x80000000000000"},
+		"event_data": map[string]any{
+			"data": []any{
+				map[string]any{"Time": "2022-04-28T19:48:52Z"},
+				map[string]any{"Source": "RulesEngine"},
+			},
+		},
+		"execution": map[string]any{
+			"process_id": uint(0),
+			"thread_id":  uint(0),
+		},
+	}
+}
+
+func TestParser(t *testing.T) {
+	data, err := os.ReadFile(filepath.Join("testdata", "xmlSample.xml"))
+	require.NoError(t, err)
+
+	cases := []struct {
+		name string
+		body any
+	}{
+		{
+			name: "string",
+			body: string(data),
+		},
+		{
+			name: "bytes",
+			body: data,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			op, fake := newTestParser(t, NewConfig())
+
+			e := entry.New()
+			e.Body = tc.body
+			require.NoError(t, op.Process(context.Background(), e))
+
+			e = receiveEntry(t, fake)
+			require.Equal(t, expectedBody(), e.Body)
+			require.Equal(t, time.Date(2022, time.April, 22, 10, 20, 52, 377862500, time.UTC), e.Timestamp.UTC())
+			require.Equal(t, entry.Info, e.Severity)
+		})
+	}
+}
+
+func TestParserParseTo(t *testing.T) {
+	data, err := os.ReadFile(filepath.Join("testdata", "xmlSample.xml"))
+	require.NoError(t, err)
+
+	cfg := NewConfig()
+	cfg.ParseFrom = entry.NewAttributeField("xml")
+	cfg.ParseTo = entry.RootableField{Field: entry.NewAttributeField("event")}
+	op, fake := newTestParser(t, cfg)
+
+	e := entry.New()
+	e.Body = "forwarded event"
+	e.Attributes = map[string]any{"xml": string(data)}
+	require.NoError(t, op.Process(context.Background(), e))
+
+	e = receiveEntry(t, fake)
+	require.Equal(t, "forwarded event", e.Body)
+	require.Equal(t, expectedBody(), e.Attributes["event"])
+}
+
+func TestParserInvalidTimestamp(t *testing.T) {
+	op, fake := newTestParser(t, NewConfig())
+
+	timestamp := time.Date(2023, time.January, 1, 0, 0, 0, 0, time.UTC)
+	e := entry.New()
+	e.Timestamp = timestamp
+	e.Body = `<Event><System><Level>2</Level><TimeCreated SystemTime="yesterday"/></System></Event>`
+	require.NoError(t, op.Process(context.Background(), e))
+
+	e = receiveEntry(t, fake)
+	require.Equal(t, timestamp, e.Timestamp)
+	require.Equal(t, entry.Error, e.Severity)
+}
+
+func TestParserErrors(t *testing.T) {
+	cases := []struct {
+		name string
+		body any
+		err  string
+	}{
+		{
+			name: "invalid_xml",
+			body: "<Event><System>",
+			err:  "failed to unmarshal xml bytes into event",
+		},
+		{
+			name: "invalid_type",
+			body: map[string]any{"xml": "<Event/>"},
+			err:  "type map[string]interface {} cannot be parsed as Windows Event XML",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			op, fake := newTestParser(t, NewConfig())
+
+			e := entry.New()
+			e.Body = tc.body
+			require.ErrorContains(t, op.Process(context.Background(), e), tc.err)
+
+			// The entry is sent as is with the default on_error.
+			require.Equal(t, tc.body, receiveEntry(t, fake).Body)
+		})
+	}
+}
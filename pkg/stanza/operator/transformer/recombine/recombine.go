@@ -17,6 +17,7 @@ import (
 	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/entry"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/operator"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/operator/helper"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/split"
 )
 
 const (
@@ -51,6 +52,7 @@ type Config struct {
 	helper.TransformerConfig `mapstructure:",squash"`
 	IsFirstEntry             string          `mapstructure:"is_first_entry"`
 	IsLastEntry              string          `mapstructure:"is_last_entry"`
+	Preset                   string          `mapstructure:"preset"`
 	MaxBatchSize             int             `mapstructure:"max_batch_size"`
 	CombineField             entry.Field     `mapstructure:"combine_field"`
 	CombineWith              string          `mapstructure:"combine_with"`
@@ -72,15 +74,31 @@ func (c *Config) Build(logger *zap.SugaredLogger) (operator.Operator, error) {
 		return nil, fmt.Errorf("only one of is_first_entry and is_last_entry can be set")
 	}
 
-	if c.IsLastEntry == "" && c.IsFirstEntry == "" {
-		return nil, fmt.Errorf("one of is_first_entry and is_last_entry must be set")
+	isFirstEntry := c.IsFirstEntry
+	if c.Preset != "" {
+		if c.IsLastEntry != "" || c.IsFirstEntry != "" {
+			return nil, fmt.Errorf("preset cannot be set with is_first_entry or is_last_entry")
+		}
+		if c.CombineField.FieldInterface == nil {
+			return nil, fmt.Errorf("missing required argument 'combine_field'")
+		}
+		re, err := split.PresetLineContinuationRegex(c.Preset)
+		if err != nil {
+			return nil, err
+		}
+		// An entry is the first one of a log unless it continues the previous entry.
+		isFirstEntry = fmt.Sprintf("not (%s matches %q)", c.CombineField.String(), re.String())
+	}
+
+	if c.IsLastEntry == "" && isFirstEntry == "" {
+		return nil, fmt.Errorf("one of is_first_entry, is_last_entry and preset must be set")
 	}
 
 	var matchesFirst bool
 	var prog *vm.Program
-	if c.IsFirstEntry != "" {
+	if isFirstEntry != "" {
 		matchesFirst = true
-		prog, err = helper.ExprCompileBool(c.IsFirstEntry)
+		prog, err = helper.ExprCompileBool(isFirstEntry)
 		if err != nil {
 			return nil, fmt.Errorf("failed to compile is_first_entry: %w", err)
 		}
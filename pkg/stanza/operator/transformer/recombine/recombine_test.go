@@ -6,6 +6,8 @@ package recombine
 import (
 	"context"
 	"fmt"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 	"time"
@@ -15,6 +17,7 @@ import (
 	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/entry"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/operator"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/operator/helper"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/split"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/testutil"
 )
 
@@ -618,6 +621,69 @@ func BenchmarkRecombineLimitTrigger(b *testing.B) {
 
 }
 
+func TestPresets(t *testing.T) {
+	for _, preset := range split.Presets() {
+		t.Run(preset, func(t *testing.T) {
+			// The corpus of the split package separates the expected logs with a line of scissors
+			corpus, err := os.ReadFile(filepath.Join("..", "..", "..", "split", "testdata", "presets", preset+".log"))
+			require.NoError(t, err)
+			logs := strings.Split(strings.TrimSuffix(string(corpus), "\n"), "\n---8<---\n")
+
+			cfg := NewConfig()
+			cfg.CombineField = entry.NewBodyField()
+			cfg.Preset = preset
+			cfg.OutputIDs = []string{"fake"}
+			op, err := cfg.Build(testutil.Logger(t))
+			require.NoError(t, err)
+			recombine := op.(*Transformer)
+
+			fake := testutil.NewFakeOutput(t)
+			require.NoError(t, recombine.SetOutputs([]operator.Operator{fake}))
+
+			ctx := context.Background()
+			for _, log := range logs {
+				for _, line := range strings.Split(log, "\n") {
+					e := entry.New()
+					e.Body = line
+					require.NoError(t, recombine.Process(ctx, e))
+				}
+			}
+			// Flush the last log as the force flush period would
+			require.NoError(t, recombine.flushSource(DefaultSourceIdentifier, true))
+
+			for _, log := range logs {
+				select {
+				case e := <-fake.Received:
+					require.Equal(t, log, e.Body)
+				case <-time.After(time.Second):
+					require.FailNow(t, "Timed out waiting for entry")
+				}
+			}
+			fake.ExpectNoEntry(t, 100*time.Millisecond)
+		})
+	}
+}
+
+func TestPresetBuildErrors(t *testing.T) {
+	cfg := NewConfig()
+	cfg.CombineField = entry.NewBodyField()
+	cfg.Preset = split.PresetJava
+	cfg.IsFirstEntry = MatchAll
+	_, err := cfg.Build(testutil.Logger(t))
+	require.EqualError(t, err, "preset cannot be set with is_first_entry or is_last_entry")
+
+	cfg = NewConfig()
+	cfg.Preset = split.PresetJava
+	_, err = cfg.Build(testutil.Logger(t))
+	require.EqualError(t, err, "missing required argument 'combine_field'")
+
+	cfg = NewConfig()
+	cfg.CombineField = entry.NewBodyField()
+	cfg.Preset = "cobol"
+	_, err = cfg.Build(testutil.Logger(t))
+	require.EqualError(t, err, "unknown preset 'cobol', must be one of dotnet, go, java, nodejs, python, ruby")
+}
+
 func TestTimeout(t *testing.T) {
 	t.Parallel()
 
@@ -0,0 +1,121 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package split // import "github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/split"
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// Framings of the tokens of a stream
+const (
+	// FramingNewline splits the tokens by newline, or as configured by the split config.
+	FramingNewline = "newline"
+	// FramingLengthPrefixed reads the tokens prefixed by their length, as a 32 bits big endian integer.
+	FramingLengthPrefixed = "length_prefixed"
+	// FramingJSONSequence reads the JSON texts of a JSON text sequence, as defined by RFC 7464.
+	FramingJSONSequence = "json_seq"
+)
+
+const (
+	lengthPrefixSize = 4
+	recordSeparator  = 0x1e
+)
+
+var errTruncatedFrame = errors.New("truncated frame")
+
+// FramingFunc returns the split func of the framing. The newline split func
+// is used for the newline framing, which is the framing by default.
+func FramingFunc(framing string, newline bufio.SplitFunc, maxLogSize int) (bufio.SplitFunc, error) {
+	switch framing {
+	case "", FramingNewline:
+		return newline, nil
+	case FramingLengthPrefixed:
+		return LengthPrefixedSplitFunc(maxLogSize), nil
+	case FramingJSONSequence:
+		return JSONSequenceSplitFunc(maxLogSize), nil
+	default:
+		return nil, fmt.Errorf("invalid framing '%s'", framing)
+	}
+}
+
+// LengthPrefixedSplitFunc splits the frames prefixed by their length, as a
+// 32 bits big endian integer. The frames larger than maxLogSize, including
+// their prefix, are truncated.
+func LengthPrefixedSplitFunc(maxLogSize int) bufio.SplitFunc {
+	// skip is the size of the end of the truncated frame, that is skipped
+	var skip int
+	return func(data []byte, atEOF bool) (advance int, token []byte, err error) {
+		if skip > 0 {
+			advance = len(data)
+			if advance > skip {
+				advance = skip
+			}
+			skip -= advance
+			return advance, nil, nil
+		}
+
+		if len(data) < lengthPrefixSize {
+			if atEOF && len(data) > 0 {
+				return 0, nil, errTruncatedFrame
+			}
+			return 0, nil, nil
+		}
+
+		end := lengthPrefixSize + int(binary.BigEndian.Uint32(data))
+		switch {
+		case end <= len(data):
+			return end, data[lengthPrefixSize:end], nil
+		case len(data) >= maxLogSize:
+			skip = end - len(data)
+			return len(data), data[lengthPrefixSize:], nil
+		case atEOF:
+			return 0, nil, errTruncatedFrame
+		default:
+			return 0, nil, nil
+		}
+	}
+}
+
+// JSONSequenceSplitFunc splits the JSON texts of a JSON text sequence, which
+// start with a record separator and end with a newline. The JSON texts larger
+// than maxLogSize are truncated, and the bytes out of the records are skipped.
+func JSONSequenceSplitFunc(maxLogSize int) bufio.SplitFunc {
+	return func(data []byte, atEOF bool) (advance int, token []byte, err error) {
+		start := bytes.IndexByte(data, recordSeparator)
+		switch {
+		case start < 0:
+			return len(data), nil, nil
+		case start > 0:
+			return start, nil, nil
+		}
+
+		// The record ends with the next record separator, or with a newline
+		// after a complete JSON text when no other record is written yet
+		end := bytes.IndexByte(data[1:], recordSeparator) + 1
+		switch {
+		case end > 0:
+		case atEOF:
+			end = len(data)
+		default:
+			if newline := bytes.LastIndexByte(data, '\n'); newline > 0 && json.Valid(data[1:newline]) {
+				end = newline + 1
+				break
+			}
+			if len(data) < maxLogSize {
+				return 0, nil, nil
+			}
+			end = len(data)
+		}
+
+		if record := bytes.TrimSpace(data[1:end]); len(record) > 0 {
+			return end, record, nil
+		}
+		return end, nil, nil
+	}
+}
@@ -0,0 +1,183 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package split
+
+import (
+	"bufio"
+	"encoding/binary"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/split/splittest"
+)
+
+func TestFramingFunc(t *testing.T) {
+	for _, framing := range []string{"", FramingNewline, FramingLengthPrefixed, FramingJSONSequence} {
+		f, err := FramingFunc(framing, bufio.ScanLines, 100)
+		assert.NoError(t, err, framing)
+		assert.NotNil(t, f, framing)
+	}
+
+	_, err := FramingFunc("octet_counting", bufio.ScanLines, 100)
+	assert.EqualError(t, err, "invalid framing 'octet_counting'")
+}
+
+func frame(token string) []byte {
+	return append(binary.BigEndian.AppendUint32(nil, uint32(len(token))), token...)
+}
+
+func frames(tokens ...string) []byte {
+	var data []byte
+	for _, token := range tokens {
+		data = append(data, frame(token)...)
+	}
+	return data
+}
+
+func TestLengthPrefixedSplitFunc(t *testing.T) {
+	testCases := []struct {
+		name  string
+		input []byte
+		steps []splittest.Step
+	}{
+		{
+			name:  "OneFrame",
+			input: frames("log1"),
+			steps: []splittest.Step{
+				splittest.ExpectAdvanceToken(8, "log1"),
+			},
+		},
+		{
+			name:  "TwoFrames",
+			input: frames("log1", "log with\nnewline"),
+			steps: []splittest.Step{
+				splittest.ExpectAdvanceToken(8, "log1"),
+				splittest.ExpectAdvanceToken(20, "log with\nnewline"),
+			},
+		},
+		{
+			name:  "EmptyFrame",
+			input: frames("", "log1"),
+			steps: []splittest.Step{
+				splittest.ExpectAdvanceToken(4, ""),
+				splittest.ExpectAdvanceToken(8, "log1"),
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, splittest.New(LengthPrefixedSplitFunc(100), tc.input, tc.steps...))
+	}
+
+	t.Run("Truncated", func(t *testing.T) {
+		splitFunc := LengthPrefixedSplitFunc(100)
+		_, _, err := splitFunc([]byte{0, 0}, true)
+		assert.EqualError(t, err, "truncated frame")
+		_, _, err = splitFunc(frame("log1")[:6], true)
+		assert.EqualError(t, err, "truncated frame")
+	})
+
+	t.Run("TooLong", func(t *testing.T) {
+		splitFunc := LengthPrefixedSplitFunc(10)
+		data := frames("abcdefghijklmnop", "log1")
+
+		advance, token, err := splitFunc(data[:10], false)
+		require.NoError(t, err)
+		assert.Equal(t, 10, advance)
+		assert.Equal(t, []byte("abcdef"), token)
+
+		// The end of the frame is skipped
+		advance, token, err = splitFunc(data[10:14], false)
+		require.NoError(t, err)
+		assert.Equal(t, 4, advance)
+		assert.Nil(t, token)
+
+		advance, token, err = splitFunc(data[14:], false)
+		require.NoError(t, err)
+		assert.Equal(t, 6, advance)
+		assert.Nil(t, token)
+
+		advance, token, err = splitFunc(data[20:], false)
+		require.NoError(t, err)
+		assert.Equal(t, 8, advance)
+		assert.Equal(t, []byte("log1"), token)
+	})
+}
+
+func TestJSONSequenceSplitFunc(t *testing.T) {
+	testCases := []struct {
+		name  string
+		input []byte
+		steps []splittest.Step
+	}{
+		{
+			name:  "OneRecord",
+			input: []byte("\x1e{\"a\":1}\n"),
+			steps: []splittest.Step{
+				splittest.ExpectAdvanceToken(9, `{"a":1}`),
+			},
+		},
+		{
+			name:  "TwoRecords",
+			input: []byte("\x1e{\"a\":1}\n\x1e[1,2]\n"),
+			steps: []splittest.Step{
+				splittest.ExpectAdvanceToken(9, `{"a":1}`),
+				splittest.ExpectAdvanceToken(7, `[1,2]`),
+			},
+		},
+		{
+			name:  "MultilineRecord",
+			input: []byte("\x1e{\n  \"a\": 1,\n  \"b\": 2\n}\n"),
+			steps: []splittest.Step{
+				splittest.ExpectAdvanceToken(24, "{\n  \"a\": 1,\n  \"b\": 2\n}"),
+			},
+		},
+		{
+			name:  "RecordWithoutNewline",
+			input: []byte("\x1e\"log1\"\x1e\"log2\""),
+			steps: []splittest.Step{
+				splittest.ExpectAdvanceToken(7, `"log1"`),
+				splittest.ExpectAdvanceToken(7, `"log2"`),
+			},
+		},
+		{
+			name:  "InvalidRecord",
+			input: []byte("\x1e{\"a\":\n\x1e{\"b\":2}\n"),
+			steps: []splittest.Step{
+				splittest.ExpectAdvanceToken(7, `{"a":`),
+				splittest.ExpectAdvanceToken(9, `{"b":2}`),
+			},
+		},
+		{
+			name:  "EmptyRecord",
+			input: []byte("\x1e\n\x1e{\"a\":1}\n"),
+			steps: []splittest.Step{
+				splittest.ExpectAdvanceNil(2),
+				splittest.ExpectAdvanceToken(9, `{"a":1}`),
+			},
+		},
+		{
+			name:  "BytesBeforeRecord",
+			input: []byte("x\x1e{\"a\":1}\n"),
+			steps: []splittest.Step{
+				splittest.ExpectAdvanceNil(1),
+				splittest.ExpectAdvanceToken(9, `{"a":1}`),
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, splittest.New(JSONSequenceSplitFunc(100), tc.input, tc.steps...))
+	}
+
+	t.Run("TooLong", func(t *testing.T) {
+		splitFunc := JSONSequenceSplitFunc(10)
+		advance, token, err := splitFunc([]byte("\x1e[1,2,3,4,"), false)
+		require.NoError(t, err)
+		assert.Equal(t, 10, advance)
+		assert.Equal(t, []byte("[1,2,3,4,"), token)
+	})
+}
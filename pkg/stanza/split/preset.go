@@ -0,0 +1,202 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package split // import "github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/split"
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"golang.org/x/text/encoding"
+	"golang.org/x/text/encoding/unicode"
+)
+
+// The presets recognizing the stack traces of common runtimes.
+const (
+	PresetJava   = "java"
+	PresetPython = "python"
+	PresetGo     = "go"
+	PresetDotnet = "dotnet"
+	PresetRuby   = "ruby"
+	PresetNodejs = "nodejs"
+)
+
+// presetContinuationPatterns are the patterns of the lines which continue the
+// previous line of a log, such as the frames and causes of a stack trace. The
+// blank lines are continuations when they are followed by one of these lines.
+var presetContinuationPatterns = map[string][]string{
+	PresetJava: {
+		// Frames: "	at com.example.Foo.bar(Foo.java:10)"
+		`\s+at \S`,
+		// Elided frames: "	... 12 more", "	... 12 common frames omitted"
+		`\s*\.\.\. \d+ (?:more|common frames omitted)`,
+		// Causes: "Caused by: java.io.IOException: Broken pipe", "	Suppressed: ..."
+		`\s*(?:Caused by|Suppressed|Wrapped by): `,
+		// Exception logged after the message: "java.lang.IllegalStateException: message"
+		`(?:[a-zA-Z_$][\w$]*\.)+[\w$]*(?:Exception|Error|Throwable)(?::.*)?$`,
+	},
+	PresetPython: {
+		`Traceback \(most recent call last\):$`,
+		// Frames, source lines, caret markers and exception groups are indented
+		`\s+\S`,
+		`(?:During handling of the above exception, another exception occurred|The above exception was the direct cause of the following exception):$`,
+		// Exception: "ValueError: invalid literal", "requests.exceptions.ConnectionError: ..."
+		`(?:[a-zA-Z_]\w*\.)*\w*(?:Error|Exception|Warning|Exit|Interrupt|Iteration|Group)(?::.*)?$`,
+	},
+	PresetGo: {
+		`goroutine \d+ \[[^\]]+\]:$`,
+		// Functions, qualified by their package: "main.main()", "panic({0x6b5ba0?, 0x9b4a30?})",
+		// "net/http.(*conn).serve(0xc000128000, {0x6f8f38, 0xc0000a6000})"
+		`(?:[\w./-]+\.\S+|panic)\(.*\)$`,
+		// Locations: "	/usr/local/go/src/net/http/server.go:2009 +0x645"
+		`\s+\S+\.go:\d+(?: \+0x[0-9a-f]+)?$`,
+		`created by \S`,
+		`\[signal `,
+		`\.\.\.additional frames elided\.\.\.$`,
+	},
+	PresetDotnet: {
+		// Frames, inner exceptions and the messages of the console logger are indented:
+		// "   at Program.Main(String[] args) in C:\src\Program.cs:line 10", " ---> System.Exception: inner"
+		`\s+\S`,
+		`--- End of (?:inner exception stack trace|stack trace from previous location)`,
+		// Exception logged after the message: "System.InvalidOperationException: message"
+		`(?:[A-Za-z_]\w*\.)+\w*(?:Exception|Error)(?::.*)?$`,
+	},
+	PresetRuby: {
+		// Frames of uncaught exceptions: "	from app.rb:7:in `<main>'"
+		`\s+from \S+:\d+:in `,
+		// Frames of logged backtraces: "app/controllers/users_controller.rb:5:in `show'",
+		// "actionpack (7.0.4) lib/abstract_controller/base.rb:215:in `process_action'"
+		"(?:\\S+ \\([^)]+\\) )?\\S+\\.rb:\\d+:in [`'][^`']*'$",
+		`\s*\.\.\. \d+ levels\.\.\.$`,
+	},
+	PresetNodejs: {
+		// Frames, causes and the properties of errors are indented:
+		// "    at Object.<anonymous> (/app/index.js:1:7)", "  [cause]: Error: timeout", "  code: 'ECONNREFUSED'"
+		`\s+\S`,
+		// End of the properties of errors
+		`}$`,
+	},
+}
+
+// presetsWithBlankLines are the presets whose stack traces contain blank lines, such as
+// the ones between chained Python exceptions or between the goroutines of a Go panic.
+var presetsWithBlankLines = map[string]bool{
+	PresetPython: true,
+	PresetGo:     true,
+}
+
+// Presets returns the names of the supported presets.
+func Presets() []string {
+	presets := make([]string, 0, len(presetContinuationPatterns))
+	for preset := range presetContinuationPatterns {
+		presets = append(presets, preset)
+	}
+	sort.Strings(presets)
+	return presets
+}
+
+// PresetContinuationRegex returns a regex matching the lines which continue the previous
+// line of a log, according to the stack trace formats of the preset.
+func PresetContinuationRegex(preset string) (*regexp.Regexp, error) {
+	patterns, ok := presetContinuationPatterns[preset]
+	if !ok {
+		return nil, fmt.Errorf("unknown preset '%s', must be one of %s", preset, strings.Join(Presets(), ", "))
+	}
+	return regexp.MustCompile(`^(?:` + strings.Join(patterns, "|") + `)`), nil
+}
+
+// PresetLineContinuationRegex returns a regex matching the lines which continue the previous
+// line of a log, for the callers matching each line on its own. As they can't look at the lines
+// following the blank lines, the blank lines continue the logs of the presets whose stack traces
+// contain them.
+func PresetLineContinuationRegex(preset string) (*regexp.Regexp, error) {
+	re, err := PresetContinuationRegex(preset)
+	if err != nil || !presetsWithBlankLines[preset] {
+		return re, err
+	}
+	return regexp.MustCompile(`^$|` + re.String()), nil
+}
+
+// ContinuationSplitFunc creates a bufio.SplitFunc that splits an incoming stream into
+// tokens made of a line followed by all the lines matching the continuation regex,
+// and the blank lines followed by such lines. The newline ending a token is not
+// included in it. A token is ended before the line which would make it larger than
+// maxLogSize, unless maxLogSize is 0.
+func ContinuationSplitFunc(re *regexp.Regexp, enc encoding.Encoding, flushAtEOF bool, maxLogSize int) (bufio.SplitFunc, error) {
+	newline, err := encodedNewline(enc)
+	if err != nil {
+		return nil, err
+	}
+
+	carriageReturn, err := encodedCarriageReturn(enc)
+	if err != nil {
+		return nil, err
+	}
+
+	dropCR := func(data []byte) []byte {
+		return bytes.TrimSuffix(data, carriageReturn)
+	}
+	continues := func(line []byte) bool {
+		line = dropCR(line)
+		if len(line) == 0 {
+			return false
+		}
+		if enc != unicode.UTF8 {
+			decoded, err := enc.NewDecoder().Bytes(line)
+			if err != nil {
+				return false
+			}
+			line = decoded
+		}
+		return re.Match(line)
+	}
+
+	return func(data []byte, atEOF bool) (advance int, token []byte, err error) {
+		firstEnd := bytes.Index(data, newline)
+		if firstEnd < 0 {
+			// Flush if no more data is expected
+			if len(data) != 0 && atEOF && flushAtEOF {
+				return len(data), data, nil
+			}
+			return 0, nil, nil // read more data and try again.
+		}
+
+		// end is the end of the token, and next the lines after it. The blank lines
+		// are skipped until a line tells whether they continue the token.
+		end := firstEnd
+		nextStart := end + len(newline)
+		for {
+			next := data[nextStart:]
+			lineEnd := bytes.Index(next, newline)
+			if lineEnd < 0 {
+				// The next line is incomplete, so it is not known yet whether it continues the token
+				if atEOF && flushAtEOF {
+					if continues(next) {
+						return len(data), data, nil
+					}
+					return end + len(newline), dropCR(data[:end]), nil
+				}
+				if maxLogSize > 0 && len(data) >= maxLogSize {
+					// The token can't grow any further
+					return end + len(newline), dropCR(data[:end]), nil
+				}
+				return 0, nil, nil // read more data and try again.
+			}
+
+			if len(dropCR(next[:lineEnd])) == 0 {
+				nextStart += lineEnd + len(newline)
+				continue
+			}
+			if !continues(next[:lineEnd]) || (maxLogSize > 0 && nextStart+lineEnd > maxLogSize) {
+				return end + len(newline), dropCR(data[:end]), nil
+			}
+			end = nextStart + lineEnd
+			nextStart = end + len(newline)
+		}
+	}, nil
+}
@@ -0,0 +1,207 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package split
+
+import (
+	"bufio"
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/text/encoding"
+	"golang.org/x/text/encoding/unicode"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/split/splittest"
+)
+
+// corpusSeparator separates the expected logs in the corpus files.
+const corpusSeparator = "---8<---\n"
+
+func TestPresetsCorpus(t *testing.T) {
+	for _, preset := range Presets() {
+		t.Run(preset, func(t *testing.T) {
+			corpus, err := os.ReadFile(filepath.Join("testdata", "presets", preset+".log"))
+			require.NoError(t, err)
+
+			var expected []string
+			for _, log := range strings.Split(string(corpus), corpusSeparator) {
+				expected = append(expected, strings.TrimSuffix(log, "\n"))
+			}
+
+			splitFunc, err := Config{Preset: preset}.Func(unicode.UTF8, true, 0)
+			require.NoError(t, err)
+
+			scanner := bufio.NewScanner(bytes.NewReader([]byte(strings.ReplaceAll(string(corpus), corpusSeparator, ""))))
+			scanner.Split(splitFunc)
+			var actual []string
+			for scanner.Scan() {
+				actual = append(actual, scanner.Text())
+			}
+			require.NoError(t, scanner.Err())
+			assert.Equal(t, expected, actual)
+		})
+	}
+}
+
+func TestPresetConfigFunc(t *testing.T) {
+	t.Run("UnknownPreset", func(t *testing.T) {
+		cfg := Config{Preset: "cobol"}
+		_, err := cfg.Func(unicode.UTF8, false, 100)
+		assert.EqualError(t, err, "unknown preset 'cobol', must be one of dotnet, go, java, nodejs, python, ruby")
+	})
+
+	t.Run("PresetWithPattern", func(t *testing.T) {
+		cfg := Config{Preset: PresetJava, LineStartPattern: "foo"}
+		_, err := cfg.Func(unicode.UTF8, false, 100)
+		assert.EqualError(t, err, "preset cannot be set with line_start_pattern or line_end_pattern")
+	})
+
+	t.Run("NopEncodingError", func(t *testing.T) {
+		cfg := Config{Preset: PresetJava}
+		_, err := cfg.Func(encoding.Nop, false, 100)
+		assert.EqualError(t, err, "preset should not be set when using nop encoding")
+	})
+}
+
+func TestContinuationSplitFunc(t *testing.T) {
+	re, err := PresetContinuationRegex(PresetJava)
+	require.NoError(t, err)
+
+	testCases := []struct {
+		name       string
+		flushAtEOF bool
+		maxLogSize int
+		input      []byte
+		steps      []splittest.Step
+	}{
+		{
+			name:  "Empty",
+			input: []byte{},
+		},
+		{
+			name:  "IncompleteLine",
+			input: []byte("log1"),
+		},
+		{
+			name:       "IncompleteLineFlushAtEOF",
+			flushAtEOF: true,
+			input:      []byte("log1"),
+			steps: []splittest.Step{
+				splittest.ExpectToken("log1"),
+			},
+		},
+		{
+			name:  "SingleLines",
+			input: []byte("log1\nlog2\nlog3\n"),
+			steps: []splittest.Step{
+				splittest.ExpectAdvanceToken(len("log1\n"), "log1"),
+				splittest.ExpectAdvanceToken(len("log2\n"), "log2"),
+			},
+		},
+		{
+			name:  "StackTrace",
+			input: []byte("log1\njava.lang.Exception: boom\n\tat Foo.bar(Foo.java:1)\nlog2\n"),
+			steps: []splittest.Step{
+				splittest.ExpectAdvanceToken(len("log1\njava.lang.Exception: boom\n\tat Foo.bar(Foo.java:1)\n"), "log1\njava.lang.Exception: boom\n\tat Foo.bar(Foo.java:1)"),
+			},
+		},
+		{
+			name:       "StackTraceFlushAtEOF",
+			flushAtEOF: true,
+			input:      []byte("log1\n\tat Foo.bar(Foo.java:1)\n\tat Foo.baz(Foo.java:2)"),
+			steps: []splittest.Step{
+				splittest.ExpectToken("log1\n\tat Foo.bar(Foo.java:1)\n\tat Foo.baz(Foo.java:2)"),
+			},
+		},
+		{
+			name:  "BlankLinesFollowedByContinuation",
+			input: []byte("log1\n\n\n\tat Foo.bar(Foo.java:1)\nlog2\n"),
+			steps: []splittest.Step{
+				splittest.ExpectAdvanceToken(len("log1\n\n\n\tat Foo.bar(Foo.java:1)\n"), "log1\n\n\n\tat Foo.bar(Foo.java:1)"),
+			},
+		},
+		{
+			name:  "BlankLinesEndingLog",
+			input: []byte("log1\n\tat Foo.bar(Foo.java:1)\n\nlog2\n"),
+			steps: []splittest.Step{
+				splittest.ExpectAdvanceToken(len("log1\n\tat Foo.bar(Foo.java:1)\n"), "log1\n\tat Foo.bar(Foo.java:1)"),
+				splittest.ExpectAdvanceToken(len("\n"), ""),
+			},
+		},
+		{
+			name:       "BlankLinesAtEOF",
+			flushAtEOF: true,
+			input:      []byte("log1\n\n"),
+			steps: []splittest.Step{
+				splittest.ExpectAdvanceToken(len("log1\n"), "log1"),
+				splittest.ExpectAdvanceToken(len("\n"), ""),
+			},
+		},
+		{
+			name:       "MaxLogSize",
+			maxLogSize: len("log1\n\tat Foo.bar(Foo.java:1)\n"),
+			input:      []byte("log1\n\tat Foo.bar(Foo.java:1)\n\tat Foo.baz(Foo.java:2)\nlog2\n"),
+			steps: []splittest.Step{
+				splittest.ExpectAdvanceToken(len("log1\n\tat Foo.bar(Foo.java:1)\n"), "log1\n\tat Foo.bar(Foo.java:1)"),
+				splittest.ExpectAdvanceToken(len("\tat Foo.baz(Foo.java:2)\n"), "\tat Foo.baz(Foo.java:2)"),
+			},
+		},
+		{
+			name:       "CarriageReturns",
+			flushAtEOF: true,
+			input:      []byte("log1\r\n\tat Foo.bar(Foo.java:1)\r\nlog2\r\n"),
+			steps: []splittest.Step{
+				splittest.ExpectAdvanceToken(len("log1\r\n\tat Foo.bar(Foo.java:1)\r\n"), "log1\r\n\tat Foo.bar(Foo.java:1)"),
+				splittest.ExpectAdvanceToken(len("log2\r\n"), "log2"),
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		splitFunc, err := ContinuationSplitFunc(re, unicode.UTF8, tc.flushAtEOF, tc.maxLogSize)
+		require.NoError(t, err)
+		t.Run(tc.name, splittest.New(splitFunc, tc.input, tc.steps...))
+	}
+}
+
+func TestContinuationSplitFuncEncoding(t *testing.T) {
+	re, err := PresetContinuationRegex(PresetJava)
+	require.NoError(t, err)
+
+	enc := unicode.UTF16(unicode.LittleEndian, unicode.IgnoreBOM)
+	splitFunc, err := ContinuationSplitFunc(re, enc, false, 0)
+	require.NoError(t, err)
+
+	input, err := enc.NewEncoder().Bytes([]byte("log1\n\tat Foo.bar(Foo.java:1)\nlog2\n"))
+	require.NoError(t, err)
+	advance, token, err := splitFunc(input, false)
+	require.NoError(t, err)
+	decoded, err := enc.NewDecoder().Bytes(token)
+	require.NoError(t, err)
+	assert.Equal(t, "log1\n\tat Foo.bar(Foo.java:1)", string(decoded))
+	assert.Equal(t, len(token)+2, advance)
+}
+
+func TestPresetLineContinuationRegex(t *testing.T) {
+	// The blank lines only continue the logs of the presets whose stack traces contain them
+	for _, preset := range Presets() {
+		lineRe, err := PresetLineContinuationRegex(preset)
+		require.NoError(t, err)
+		re, err := PresetContinuationRegex(preset)
+		require.NoError(t, err)
+		assert.False(t, re.MatchString(""), preset)
+		assert.Equal(t, preset == PresetPython || preset == PresetGo, lineRe.MatchString(""), preset)
+	}
+
+	// The Go functions are qualified by their package
+	re, err := PresetContinuationRegex(PresetGo)
+	require.NoError(t, err)
+	assert.True(t, re.MatchString("main.(*worker).run(0x0)"))
+	assert.True(t, re.MatchString("panic({0x6b5ba0?, 0x9b4a30?})"))
+	assert.False(t, re.MatchString("retry(3)"))
+}
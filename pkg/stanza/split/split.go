@@ -17,6 +17,7 @@ type Config struct {
 	LineStartPattern string `mapstructure:"line_start_pattern"`
 	LineEndPattern   string `mapstructure:"line_end_pattern"`
 	OmitPattern      bool   `mapstructure:"omit_pattern"`
+	Preset           string `mapstructure:"preset"`
 }
 
 // Func will return a bufio.SplitFunc based on the config
@@ -28,9 +29,23 @@ func (c Config) Func(enc encoding.Encoding, flushAtEOF bool, maxLogSize int) (bu
 		if c.LineStartPattern != "" {
 			return nil, fmt.Errorf("line_start_pattern should not be set when using nop encoding")
 		}
+		if c.Preset != "" {
+			return nil, fmt.Errorf("preset should not be set when using nop encoding")
+		}
 		return NoSplitFunc(maxLogSize), nil
 	}
 
+	if c.Preset != "" {
+		if c.LineEndPattern != "" || c.LineStartPattern != "" {
+			return nil, fmt.Errorf("preset cannot be set with line_start_pattern or line_end_pattern")
+		}
+		re, err := PresetContinuationRegex(c.Preset)
+		if err != nil {
+			return nil, err
+		}
+		return ContinuationSplitFunc(re, enc, flushAtEOF, maxLogSize)
+	}
+
 	if c.LineEndPattern == "" && c.LineStartPattern == "" {
 		return NewlineSplitFunc(enc, flushAtEOF)
 	}
@@ -0,0 +1,204 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package prometheusremotewrite // import "github.com/open-telemetry/opentelemetry-collector-contrib/pkg/translator/prometheusremotewrite"
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/prometheus/prometheus/prompb"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.uber.org/multierr"
+
+	prometheustranslator "github.com/open-telemetry/opentelemetry-collector-contrib/pkg/translator/prometheus"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/translator/prometheusremotewrite/writev2"
+)
+
+// FromMetricsV2 converts pmetric.Metrics to prometheus remote write 2.0 format. The labels, help and units of the
+// series are interned in the returned symbols table. The metadata and created timestamps are set on every series
+// instead of being sent as separate metadata and _created series, so Settings.ExportCreatedMetric is ignored.
+func FromMetricsV2(md pmetric.Metrics, settings Settings) (tsMap map[string]*writev2.TimeSeries, symbolsTable writev2.SymbolsTable, errs error) {
+	settings.ExportCreatedMetric = false
+	c := &prometheusConverterV2{
+		series:  make(map[string]*writev2.TimeSeries),
+		symbols: writev2.NewSymbolTable(),
+	}
+
+	resourceMetricsSlice := md.ResourceMetrics()
+	for i := 0; i < resourceMetricsSlice.Len(); i++ {
+		resourceMetrics := resourceMetricsSlice.At(i)
+		resource := resourceMetrics.Resource()
+		scopeMetricsSlice := resourceMetrics.ScopeMetrics()
+		// keep track of the most recent timestamp in the ResourceMetrics for
+		// use with the "target" info metric
+		var mostRecentTimestamp pcommon.Timestamp
+		for j := 0; j < scopeMetricsSlice.Len(); j++ {
+			metricSlice := scopeMetricsSlice.At(j).Metrics()
+			for k := 0; k < metricSlice.Len(); k++ {
+				metric := metricSlice.At(k)
+				mostRecentTimestamp = maxTimestamp(mostRecentTimestamp, mostRecentTimestampInMetric(metric))
+
+				if !isValidAggregationTemporality(metric) {
+					errs = multierr.Append(errs, fmt.Errorf("invalid temporality and type combination for metric %q", metric.Name()))
+					continue
+				}
+				errs = multierr.Append(errs, c.addMetric(metric, resource, settings))
+			}
+		}
+
+		series := make(map[string]*prompb.TimeSeries)
+		addResourceTargetInfo(resource, settings, mostRecentTimestamp, series)
+		c.addSeries(series, writev2.Metadata{Type: writev2.MetricTypeGauge}, 0)
+	}
+
+	return c.series, c.symbols, errs
+}
+
+// prometheusConverterV2 converts the series of the 1.0 format to the 2.0 format.
+type prometheusConverterV2 struct {
+	series  map[string]*writev2.TimeSeries
+	symbols writev2.SymbolsTable
+}
+
+// addMetric converts every data point of metric, the series of a data point
+// are converted with the 1.0 helpers and then symbolized.
+func (c *prometheusConverterV2) addMetric(metric pmetric.Metric, resource pcommon.Resource, settings Settings) error {
+	metadata := writev2.Metadata{
+		// the 1.0 and 2.0 metric types share the same values
+		Type:    writev2.MetricType(otelMetricTypeToPromMetricType(metric)),
+		HelpRef: c.symbols.Symbolize(metric.Description()),
+		UnitRef: c.symbols.Symbolize(metric.Unit()),
+	}
+
+	var errs error
+	//exhaustive:enforce
+	switch metric.Type() {
+	case pmetric.MetricTypeGauge:
+		dataPoints := metric.Gauge().DataPoints()
+		if dataPoints.Len() == 0 {
+			errs = multierr.Append(errs, fmt.Errorf("empty data points. %s is dropped", metric.Name()))
+		}
+		for x := 0; x < dataPoints.Len(); x++ {
+			series := make(map[string]*prompb.TimeSeries)
+			addSingleGaugeNumberDataPoint(dataPoints.At(x), resource, metric, settings, series)
+			c.addSeries(series, metadata, 0)
+		}
+	case pmetric.MetricTypeSum:
+		dataPoints := metric.Sum().DataPoints()
+		if dataPoints.Len() == 0 {
+			errs = multierr.Append(errs, fmt.Errorf("empty data points. %s is dropped", metric.Name()))
+		}
+		for x := 0; x < dataPoints.Len(); x++ {
+			series := make(map[string]*prompb.TimeSeries)
+			addSingleSumNumberDataPoint(dataPoints.At(x), resource, metric, settings, series)
+			var createdTimestamp pcommon.Timestamp
+			if metric.Sum().IsMonotonic() {
+				createdTimestamp = dataPoints.At(x).StartTimestamp()
+			}
+			c.addSeries(series, metadata, createdTimestamp)
+		}
+	case pmetric.MetricTypeHistogram:
+		dataPoints := metric.Histogram().DataPoints()
+		if dataPoints.Len() == 0 {
+			errs = multierr.Append(errs, fmt.Errorf("empty data points. %s is dropped", metric.Name()))
+		}
+		for x := 0; x < dataPoints.Len(); x++ {
+			series := make(map[string]*prompb.TimeSeries)
+			addSingleHistogramDataPoint(dataPoints.At(x), resource, metric, settings, series)
+			c.addSeries(series, metadata, dataPoints.At(x).StartTimestamp())
+		}
+	case pmetric.MetricTypeExponentialHistogram:
+		dataPoints := metric.ExponentialHistogram().DataPoints()
+		if dataPoints.Len() == 0 {
+			errs = multierr.Append(errs, fmt.Errorf("empty data points. %s is dropped", metric.Name()))
+		}
+		name := prometheustranslator.BuildCompliantName(metric, settings.Namespace, settings.AddMetricSuffixes)
+		for x := 0; x < dataPoints.Len(); x++ {
+			series := make(map[string]*prompb.TimeSeries)
+			errs = multierr.Append(errs, addSingleExponentialHistogramDataPoint(name, dataPoints.At(x), resource, settings, series))
+			c.addSeries(series, metadata, dataPoints.At(x).StartTimestamp())
+		}
+	case pmetric.MetricTypeSummary:
+		dataPoints := metric.Summary().DataPoints()
+		if dataPoints.Len() == 0 {
+			errs = multierr.Append(errs, fmt.Errorf("empty data points. %s is dropped", metric.Name()))
+		}
+		for x := 0; x < dataPoints.Len(); x++ {
+			series := make(map[string]*prompb.TimeSeries)
+			addSingleSummaryDataPoint(dataPoints.At(x), resource, metric, settings, series)
+			c.addSeries(series, metadata, dataPoints.At(x).StartTimestamp())
+		}
+	default:
+		errs = multierr.Append(errs, errors.New("unsupported metric type"))
+	}
+	return errs
+}
+
+// addSeries symbolizes the series and merges them with the already converted series sharing the same signature.
+func (c *prometheusConverterV2) addSeries(series map[string]*prompb.TimeSeries, metadata writev2.Metadata, createdTimestamp pcommon.Timestamp) {
+	for sig, ts := range series {
+		v2, ok := c.series[sig]
+		if !ok {
+			v2 = &writev2.TimeSeries{
+				LabelsRefs: c.symbols.SymbolizeLabels(ts.Labels, nil),
+				Metadata:   metadata,
+			}
+			if createdTimestamp != 0 {
+				v2.CreatedTimestamp = convertTimeStamp(createdTimestamp)
+			}
+			c.series[sig] = v2
+		}
+		for _, sample := range ts.Samples {
+			v2.Samples = append(v2.Samples, writev2.Sample{Value: sample.Value, Timestamp: sample.Timestamp})
+		}
+		for i := range ts.Histograms {
+			v2.Histograms = append(v2.Histograms, toHistogramV2(&ts.Histograms[i]))
+		}
+		for _, exemplar := range ts.Exemplars {
+			v2.Exemplars = append(v2.Exemplars, writev2.Exemplar{
+				LabelsRefs: c.symbols.SymbolizeLabels(exemplar.Labels, nil),
+				Value:      exemplar.Value,
+				Timestamp:  exemplar.Timestamp,
+			})
+		}
+	}
+}
+
+// toHistogramV2 converts a native histogram of the 1.0 format to the 2.0 format.
+func toHistogramV2(h *prompb.Histogram) writev2.Histogram {
+	v2 := writev2.Histogram{
+		Sum:            h.Sum,
+		Schema:         h.Schema,
+		ZeroThreshold:  h.ZeroThreshold,
+		NegativeSpans:  toBucketSpansV2(h.NegativeSpans),
+		NegativeDeltas: h.NegativeDeltas,
+		NegativeCounts: h.NegativeCounts,
+		PositiveSpans:  toBucketSpansV2(h.PositiveSpans),
+		PositiveDeltas: h.PositiveDeltas,
+		PositiveCounts: h.PositiveCounts,
+		ResetHint:      writev2.ResetHint(h.ResetHint),
+		Timestamp:      h.Timestamp,
+	}
+	if _, ok := h.Count.(*prompb.Histogram_CountFloat); ok {
+		v2.IsFloat = true
+		v2.CountFloat = h.GetCountFloat()
+		v2.ZeroCountFloat = h.GetZeroCountFloat()
+	} else {
+		v2.CountInt = h.GetCountInt()
+		v2.ZeroCountInt = h.GetZeroCountInt()
+	}
+	return v2
+}
+
+func toBucketSpansV2(spans []prompb.BucketSpan) []writev2.BucketSpan {
+	if len(spans) == 0 {
+		return nil
+	}
+	v2 := make([]writev2.BucketSpan, 0, len(spans))
+	for _, span := range spans {
+		v2 = append(v2, writev2.BucketSpan{Offset: span.Offset, Length: span.Length})
+	}
+	return v2
+}
@@ -0,0 +1,162 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package prometheusremotewrite
+
+import (
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/prometheus/prometheus/prompb"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/translator/prometheusremotewrite/writev2"
+)
+
+func TestFromMetricsV2(t *testing.T) {
+	start := time.Date(2023, 12, 1, 10, 0, 0, 0, time.UTC)
+	ts := start.Add(time.Minute)
+
+	md := pmetric.NewMetrics()
+	rm := md.ResourceMetrics().AppendEmpty()
+	rm.Resource().Attributes().PutStr("service.name", "api")
+	rm.Resource().Attributes().PutStr("host.name", "web-1")
+	metrics := rm.ScopeMetrics().AppendEmpty().Metrics()
+
+	counter := metrics.AppendEmpty()
+	counter.SetName("http.requests")
+	counter.SetDescription("Number of requests")
+	counter.SetUnit("1")
+	sum := counter.SetEmptySum()
+	sum.SetIsMonotonic(true)
+	sum.SetAggregationTemporality(pmetric.AggregationTemporalityCumulative)
+	for i, v := range []int64{3, 5} {
+		dp := sum.DataPoints().AppendEmpty()
+		dp.SetStartTimestamp(pcommon.NewTimestampFromTime(start))
+		dp.SetTimestamp(pcommon.NewTimestampFromTime(ts.Add(time.Duration(i) * time.Second)))
+		dp.SetIntValue(v)
+	}
+
+	gauge := metrics.AppendEmpty()
+	gauge.SetName("temperature")
+	dp := gauge.SetEmptyGauge().DataPoints().AppendEmpty()
+	dp.SetTimestamp(pcommon.NewTimestampFromTime(ts))
+	dp.SetDoubleValue(21.5)
+	exemplar := dp.Exemplars().AppendEmpty()
+	exemplar.SetTimestamp(pcommon.NewTimestampFromTime(ts))
+	exemplar.SetDoubleValue(22)
+	exemplar.SetTraceID([16]byte{1})
+
+	histogram := metrics.AppendEmpty()
+	histogram.SetName("latency")
+	expHistogram := histogram.SetEmptyExponentialHistogram()
+	expHistogram.SetAggregationTemporality(pmetric.AggregationTemporalityCumulative)
+	hdp := expHistogram.DataPoints().AppendEmpty()
+	hdp.SetStartTimestamp(pcommon.NewTimestampFromTime(start))
+	hdp.SetTimestamp(pcommon.NewTimestampFromTime(ts))
+	hdp.SetCount(3)
+	hdp.SetSum(6)
+	hdp.SetScale(1)
+	hdp.Positive().SetOffset(0)
+	hdp.Positive().BucketCounts().FromRaw([]uint64{1, 2})
+
+	tsMap, symbols, err := FromMetricsV2(md, Settings{ExportCreatedMetric: true})
+	require.NoError(t, err)
+
+	series := map[string]*writev2.TimeSeries{}
+	for _, s := range tsMap {
+		series[seriesName(s, symbols.Symbols())] = s
+	}
+	require.Len(t, series, 4)
+
+	requests := series["http_requests"]
+	require.NotNil(t, requests)
+	assert.Equal(t, []writev2.Sample{
+		{Value: 3, Timestamp: ts.UnixMilli()},
+		{Value: 5, Timestamp: ts.Add(time.Second).UnixMilli()},
+	}, requests.Samples)
+	assert.Equal(t, start.UnixMilli(), requests.CreatedTimestamp)
+	assert.Equal(t, writev2.MetricTypeCounter, requests.Metadata.Type)
+	assert.Equal(t, "Number of requests", symbols.Symbols()[requests.Metadata.HelpRef])
+	assert.Equal(t, "1", symbols.Symbols()[requests.Metadata.UnitRef])
+	assert.Equal(t, []prompb.Label{
+		{Name: "__name__", Value: "http_requests"},
+		{Name: "job", Value: "api"},
+	}, sortedLabels(requests.LabelsRefs, symbols.Symbols()))
+
+	temperature := series["temperature"]
+	require.NotNil(t, temperature)
+	assert.Equal(t, writev2.MetricTypeGauge, temperature.Metadata.Type)
+	assert.Equal(t, uint32(0), temperature.Metadata.HelpRef)
+	assert.Zero(t, temperature.CreatedTimestamp)
+	require.Len(t, temperature.Exemplars, 1)
+	assert.Equal(t, 22.0, temperature.Exemplars[0].Value)
+	assert.Equal(t, []prompb.Label{
+		{Name: "trace_id", Value: "01000000000000000000000000000000"},
+	}, writev2.DesymbolizeLabels(temperature.Exemplars[0].LabelsRefs, symbols.Symbols()))
+
+	latency := series["latency"]
+	require.NotNil(t, latency)
+	assert.Equal(t, writev2.MetricTypeHistogram, latency.Metadata.Type)
+	assert.Equal(t, start.UnixMilli(), latency.CreatedTimestamp)
+	require.Len(t, latency.Histograms, 1)
+	assert.Equal(t, uint64(3), latency.Histograms[0].CountInt)
+	assert.Equal(t, 6.0, latency.Histograms[0].Sum)
+	assert.Equal(t, int32(1), latency.Histograms[0].Schema)
+	assert.Equal(t, []writev2.BucketSpan{{Offset: 1, Length: 2}}, latency.Histograms[0].PositiveSpans)
+	assert.Equal(t, []int64{1, 1}, latency.Histograms[0].PositiveDeltas)
+
+	targetInfo := series["target_info"]
+	require.NotNil(t, targetInfo)
+	assert.Equal(t, writev2.MetricTypeGauge, targetInfo.Metadata.Type)
+
+	for name := range series {
+		assert.NotContains(t, name, "_created")
+	}
+}
+
+func TestFromMetricsV2_ClassicHistogram(t *testing.T) {
+	md := pmetric.NewMetrics()
+	metric := md.ResourceMetrics().AppendEmpty().ScopeMetrics().AppendEmpty().Metrics().AppendEmpty()
+	metric.SetName("duration")
+	histogram := metric.SetEmptyHistogram()
+	histogram.SetAggregationTemporality(pmetric.AggregationTemporalityCumulative)
+	dp := histogram.DataPoints().AppendEmpty()
+	dp.SetStartTimestamp(pcommon.Timestamp(time.Second))
+	dp.SetTimestamp(pcommon.Timestamp(2 * time.Second))
+	dp.SetCount(2)
+	dp.SetSum(3)
+	dp.ExplicitBounds().FromRaw([]float64{1})
+	dp.BucketCounts().FromRaw([]uint64{1, 1})
+
+	tsMap, symbols, err := FromMetricsV2(md, Settings{})
+	require.NoError(t, err)
+
+	var names []string
+	for _, s := range tsMap {
+		names = append(names, seriesName(s, symbols.Symbols()))
+		assert.Equal(t, writev2.MetricTypeHistogram, s.Metadata.Type)
+		assert.Equal(t, int64(1000), s.CreatedTimestamp)
+	}
+	sort.Strings(names)
+	assert.Equal(t, []string{"duration_bucket", "duration_bucket", "duration_count", "duration_sum"}, names)
+}
+
+func seriesName(ts *writev2.TimeSeries, symbols []string) string {
+	for _, l := range writev2.DesymbolizeLabels(ts.LabelsRefs, symbols) {
+		if l.Name == nameStr {
+			return l.Value
+		}
+	}
+	return ""
+}
+
+func sortedLabels(refs []uint32, symbols []string) []prompb.Label {
+	labels := writev2.DesymbolizeLabels(refs, symbols)
+	sort.Sort(ByLabelName(labels))
+	return labels
+}
@@ -15,8 +15,8 @@ import (
 	prometheustranslator "github.com/open-telemetry/opentelemetry-collector-contrib/pkg/translator/prometheus"
 )
 
-// addSingleSumNumberDataPoint converts the Gauge metric data point to a
-// Prometheus time series with samples and labels. The result is stored in the
+// addSingleGaugeNumberDataPoint converts the Gauge metric data point to a
+// Prometheus time series with samples, labels and exemplars. The result is stored in the
 // series map.
 func addSingleGaugeNumberDataPoint(
 	pt pmetric.NumberDataPoint,
@@ -45,7 +45,12 @@ func addSingleGaugeNumberDataPoint(
 	if pt.Flags().NoRecordedValue() {
 		sample.Value = math.Float64frombits(value.StaleNaN)
 	}
-	addSample(series, sample, labels, metric.Type().String())
+	sig := addSample(series, sample, labels, metric.Type().String())
+
+	if ts, ok := series[sig]; sig != "" && ok {
+		exemplars := getPromExemplars[pmetric.NumberDataPoint](pt)
+		ts.Exemplars = append(ts.Exemplars, exemplars...)
+	}
 }
 
 // addSingleSumNumberDataPoint converts the Sum metric data point to a Prometheus
@@ -0,0 +1,385 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package writev2 // import "github.com/open-telemetry/opentelemetry-collector-contrib/pkg/translator/prometheusremotewrite/writev2"
+
+import (
+	"errors"
+	"fmt"
+	"math"
+
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+var errInvalidMessage = errors.New("invalid remote write 2.0 message")
+
+// Marshal encodes the request in the protobuf wire format.
+func (r *Request) Marshal() ([]byte, error) {
+	var b []byte
+	for _, s := range r.Symbols {
+		b = protowire.AppendTag(b, 4, protowire.BytesType)
+		b = protowire.AppendString(b, s)
+	}
+	for i := range r.Timeseries {
+		b = appendMessage(b, 5, r.Timeseries[i].appendTo(nil))
+	}
+	return b, nil
+}
+
+// Size returns the encoded size of the series.
+func (ts *TimeSeries) Size() int {
+	return len(ts.appendTo(nil))
+}
+
+func (ts *TimeSeries) appendTo(b []byte) []byte {
+	b = appendPackedVarints(b, 1, ts.LabelsRefs)
+	for _, s := range ts.Samples {
+		b = appendMessage(b, 2, s.appendTo(nil))
+	}
+	for i := range ts.Histograms {
+		b = appendMessage(b, 3, ts.Histograms[i].appendTo(nil))
+	}
+	for i := range ts.Exemplars {
+		b = appendMessage(b, 4, ts.Exemplars[i].appendTo(nil))
+	}
+	if ts.Metadata != (Metadata{}) {
+		b = appendMessage(b, 5, ts.Metadata.appendTo(nil))
+	}
+	b = appendVarint(b, 6, uint64(ts.CreatedTimestamp))
+	return b
+}
+
+func (s Sample) appendTo(b []byte) []byte {
+	b = appendDouble(b, 1, s.Value)
+	b = appendVarint(b, 2, uint64(s.Timestamp))
+	return b
+}
+
+func (e *Exemplar) appendTo(b []byte) []byte {
+	b = appendPackedVarints(b, 1, e.LabelsRefs)
+	b = appendDouble(b, 2, e.Value)
+	b = appendVarint(b, 3, uint64(e.Timestamp))
+	return b
+}
+
+func (m Metadata) appendTo(b []byte) []byte {
+	b = appendVarint(b, 1, uint64(m.Type))
+	b = appendVarint(b, 3, uint64(m.HelpRef))
+	b = appendVarint(b, 4, uint64(m.UnitRef))
+	return b
+}
+
+func (h *Histogram) appendTo(b []byte) []byte {
+	// count and zero_count are oneof fields, they are written even when zero.
+	if h.IsFloat {
+		b = protowire.AppendTag(b, 2, protowire.Fixed64Type)
+		b = protowire.AppendFixed64(b, math.Float64bits(h.CountFloat))
+	} else {
+		b = protowire.AppendTag(b, 1, protowire.VarintType)
+		b = protowire.AppendVarint(b, h.CountInt)
+	}
+	b = appendDouble(b, 3, h.Sum)
+	b = appendVarint(b, 4, protowire.EncodeZigZag(int64(h.Schema)))
+	b = appendDouble(b, 5, h.ZeroThreshold)
+	if h.IsFloat {
+		b = protowire.AppendTag(b, 7, protowire.Fixed64Type)
+		b = protowire.AppendFixed64(b, math.Float64bits(h.ZeroCountFloat))
+	} else {
+		b = protowire.AppendTag(b, 6, protowire.VarintType)
+		b = protowire.AppendVarint(b, h.ZeroCountInt)
+	}
+	for _, span := range h.NegativeSpans {
+		b = appendMessage(b, 8, span.appendTo(nil))
+	}
+	b = appendPackedZigZags(b, 9, h.NegativeDeltas)
+	b = appendPackedDoubles(b, 10, h.NegativeCounts)
+	for _, span := range h.PositiveSpans {
+		b = appendMessage(b, 11, span.appendTo(nil))
+	}
+	b = appendPackedZigZags(b, 12, h.PositiveDeltas)
+	b = appendPackedDoubles(b, 13, h.PositiveCounts)
+	b = appendVarint(b, 14, uint64(h.ResetHint))
+	b = appendVarint(b, 15, uint64(h.Timestamp))
+	return b
+}
+
+func (s BucketSpan) appendTo(b []byte) []byte {
+	b = appendVarint(b, 1, protowire.EncodeZigZag(int64(s.Offset)))
+	b = appendVarint(b, 2, uint64(s.Length))
+	return b
+}
+
+// appendVarint appends a varint field, omitting the zero value.
+func appendVarint(b []byte, num protowire.Number, v uint64) []byte {
+	if v == 0 {
+		return b
+	}
+	b = protowire.AppendTag(b, num, protowire.VarintType)
+	return protowire.AppendVarint(b, v)
+}
+
+// appendDouble appends a double field, omitting the zero value.
+func appendDouble(b []byte, num protowire.Number, v float64) []byte {
+	bits := math.Float64bits(v)
+	if bits == 0 {
+		return b
+	}
+	b = protowire.AppendTag(b, num, protowire.Fixed64Type)
+	return protowire.AppendFixed64(b, bits)
+}
+
+func appendMessage(b []byte, num protowire.Number, msg []byte) []byte {
+	b = protowire.AppendTag(b, num, protowire.BytesType)
+	return protowire.AppendBytes(b, msg)
+}
+
+func appendPackedVarints(b []byte, num protowire.Number, vs []uint32) []byte {
+	if len(vs) == 0 {
+		return b
+	}
+	var packed []byte
+	for _, v := range vs {
+		packed = protowire.AppendVarint(packed, uint64(v))
+	}
+	return appendMessage(b, num, packed)
+}
+
+func appendPackedZigZags(b []byte, num protowire.Number, vs []int64) []byte {
+	if len(vs) == 0 {
+		return b
+	}
+	var packed []byte
+	for _, v := range vs {
+		packed = protowire.AppendVarint(packed, protowire.EncodeZigZag(v))
+	}
+	return appendMessage(b, num, packed)
+}
+
+func appendPackedDoubles(b []byte, num protowire.Number, vs []float64) []byte {
+	if len(vs) == 0 {
+		return b
+	}
+	packed := make([]byte, 0, 8*len(vs))
+	for _, v := range vs {
+		packed = protowire.AppendFixed64(packed, math.Float64bits(v))
+	}
+	return appendMessage(b, num, packed)
+}
+
+// Unmarshal decodes a request encoded in the protobuf wire format.
+func (r *Request) Unmarshal(b []byte) error {
+	*r = Request{}
+	return consumeFields(b, func(num protowire.Number, typ protowire.Type, b []byte) (int, error) {
+		switch {
+		case num == 4 && typ == protowire.BytesType:
+			v, n := protowire.ConsumeString(b)
+			r.Symbols = append(r.Symbols, v)
+			return n, nil
+		case num == 5 && typ == protowire.BytesType:
+			var ts TimeSeries
+			return consumeMessage(b, ts.unmarshal, func() { r.Timeseries = append(r.Timeseries, ts) })
+		}
+		return -1, nil
+	})
+}
+
+func (ts *TimeSeries) unmarshal(b []byte) error {
+	return consumeFields(b, func(num protowire.Number, typ protowire.Type, b []byte) (int, error) {
+		switch num {
+		case 1:
+			return consumeVarints(b, typ, func(v uint64) { ts.LabelsRefs = append(ts.LabelsRefs, uint32(v)) })
+		case 2:
+			var s Sample
+			return consumeMessage(b, s.unmarshal, func() { ts.Samples = append(ts.Samples, s) })
+		case 3:
+			var h Histogram
+			return consumeMessage(b, h.unmarshal, func() { ts.Histograms = append(ts.Histograms, h) })
+		case 4:
+			var e Exemplar
+			return consumeMessage(b, e.unmarshal, func() { ts.Exemplars = append(ts.Exemplars, e) })
+		case 5:
+			return consumeMessage(b, ts.Metadata.unmarshal, func() {})
+		case 6:
+			return consumeVarints(b, typ, func(v uint64) { ts.CreatedTimestamp = int64(v) })
+		}
+		return -1, nil
+	})
+}
+
+func (s *Sample) unmarshal(b []byte) error {
+	return consumeFields(b, func(num protowire.Number, typ protowire.Type, b []byte) (int, error) {
+		switch num {
+		case 1:
+			return consumeDoubles(b, typ, func(v float64) { s.Value = v })
+		case 2:
+			return consumeVarints(b, typ, func(v uint64) { s.Timestamp = int64(v) })
+		}
+		return -1, nil
+	})
+}
+
+func (e *Exemplar) unmarshal(b []byte) error {
+	return consumeFields(b, func(num protowire.Number, typ protowire.Type, b []byte) (int, error) {
+		switch num {
+		case 1:
+			return consumeVarints(b, typ, func(v uint64) { e.LabelsRefs = append(e.LabelsRefs, uint32(v)) })
+		case 2:
+			return consumeDoubles(b, typ, func(v float64) { e.Value = v })
+		case 3:
+			return consumeVarints(b, typ, func(v uint64) { e.Timestamp = int64(v) })
+		}
+		return -1, nil
+	})
+}
+
+func (m *Metadata) unmarshal(b []byte) error {
+	return consumeFields(b, func(num protowire.Number, typ protowire.Type, b []byte) (int, error) {
+		switch num {
+		case 1:
+			return consumeVarints(b, typ, func(v uint64) { m.Type = MetricType(v) })
+		case 3:
+			return consumeVarints(b, typ, func(v uint64) { m.HelpRef = uint32(v) })
+		case 4:
+			return consumeVarints(b, typ, func(v uint64) { m.UnitRef = uint32(v) })
+		}
+		return -1, nil
+	})
+}
+
+func (h *Histogram) unmarshal(b []byte) error {
+	return consumeFields(b, func(num protowire.Number, typ protowire.Type, b []byte) (int, error) {
+		switch num {
+		case 1:
+			return consumeVarints(b, typ, func(v uint64) { h.CountInt = v })
+		case 2:
+			h.IsFloat = true
+			return consumeDoubles(b, typ, func(v float64) { h.CountFloat = v })
+		case 3:
+			return consumeDoubles(b, typ, func(v float64) { h.Sum = v })
+		case 4:
+			return consumeVarints(b, typ, func(v uint64) { h.Schema = int32(protowire.DecodeZigZag(v)) })
+		case 5:
+			return consumeDoubles(b, typ, func(v float64) { h.ZeroThreshold = v })
+		case 6:
+			return consumeVarints(b, typ, func(v uint64) { h.ZeroCountInt = v })
+		case 7:
+			h.IsFloat = true
+			return consumeDoubles(b, typ, func(v float64) { h.ZeroCountFloat = v })
+		case 8:
+			var s BucketSpan
+			return consumeMessage(b, s.unmarshal, func() { h.NegativeSpans = append(h.NegativeSpans, s) })
+		case 9:
+			return consumeVarints(b, typ, func(v uint64) { h.NegativeDeltas = append(h.NegativeDeltas, protowire.DecodeZigZag(v)) })
+		case 10:
+			return consumeDoubles(b, typ, func(v float64) { h.NegativeCounts = append(h.NegativeCounts, v) })
+		case 11:
+			var s BucketSpan
+			return consumeMessage(b, s.unmarshal, func() { h.PositiveSpans = append(h.PositiveSpans, s) })
+		case 12:
+			return consumeVarints(b, typ, func(v uint64) { h.PositiveDeltas = append(h.PositiveDeltas, protowire.DecodeZigZag(v)) })
+		case 13:
+			return consumeDoubles(b, typ, func(v float64) { h.PositiveCounts = append(h.PositiveCounts, v) })
+		case 14:
+			return consumeVarints(b, typ, func(v uint64) { h.ResetHint = ResetHint(v) })
+		case 15:
+			return consumeVarints(b, typ, func(v uint64) { h.Timestamp = int64(v) })
+		}
+		return -1, nil
+	})
+}
+
+func (s *BucketSpan) unmarshal(b []byte) error {
+	return consumeFields(b, func(num protowire.Number, typ protowire.Type, b []byte) (int, error) {
+		switch num {
+		case 1:
+			return consumeVarints(b, typ, func(v uint64) { s.Offset = int32(protowire.DecodeZigZag(v)) })
+		case 2:
+			return consumeVarints(b, typ, func(v uint64) { s.Length = uint32(v) })
+		}
+		return -1, nil
+	})
+}
+
+// consumeFields calls field for every field of the message. field returns the
+// length of the consumed value, or -1 to skip unknown fields.
+func consumeFields(b []byte, field func(num protowire.Number, typ protowire.Type, b []byte) (int, error)) error {
+	for len(b) > 0 {
+		num, typ, n := protowire.ConsumeTag(b)
+		if n < 0 {
+			return fmt.Errorf("%w: %v", errInvalidMessage, protowire.ParseError(n))
+		}
+		b = b[n:]
+		n, err := field(num, typ, b)
+		if err != nil {
+			return err
+		}
+		if n == -1 {
+			n = protowire.ConsumeFieldValue(num, typ, b)
+		}
+		if n < 0 {
+			return fmt.Errorf("%w: field %d: %v", errInvalidMessage, num, protowire.ParseError(n))
+		}
+		b = b[n:]
+	}
+	return nil
+}
+
+func consumeMessage(b []byte, unmarshal func([]byte) error, done func()) (int, error) {
+	v, n := protowire.ConsumeBytes(b)
+	if n < 0 {
+		return n, nil
+	}
+	if err := unmarshal(v); err != nil {
+		return n, err
+	}
+	done()
+	return n, nil
+}
+
+// consumeVarints consumes a varint field, packed or not.
+func consumeVarints(b []byte, typ protowire.Type, add func(uint64)) (int, error) {
+	switch typ {
+	case protowire.VarintType:
+		v, n := protowire.ConsumeVarint(b)
+		if n >= 0 {
+			add(v)
+		}
+		return n, nil
+	case protowire.BytesType:
+		packed, n := protowire.ConsumeBytes(b)
+		for len(packed) > 0 {
+			v, m := protowire.ConsumeVarint(packed)
+			if m < 0 {
+				return m, nil
+			}
+			add(v)
+			packed = packed[m:]
+		}
+		return n, nil
+	}
+	return -1, nil
+}
+
+// consumeDoubles consumes a double field, packed or not.
+func consumeDoubles(b []byte, typ protowire.Type, add func(float64)) (int, error) {
+	switch typ {
+	case protowire.Fixed64Type:
+		v, n := protowire.ConsumeFixed64(b)
+		if n >= 0 {
+			add(math.Float64frombits(v))
+		}
+		return n, nil
+	case protowire.BytesType:
+		packed, n := protowire.ConsumeBytes(b)
+		for len(packed) > 0 {
+			v, m := protowire.ConsumeFixed64(packed)
+			if m < 0 {
+				return m, nil
+			}
+			add(math.Float64frombits(v))
+			packed = packed[m:]
+		}
+		return n, nil
+	}
+	return -1, nil
+}
@@ -0,0 +1,107 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package writev2
+
+import (
+	"math"
+	"testing"
+
+	"github.com/prometheus/prometheus/prompb"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRequest_MarshalUnmarshal(t *testing.T) {
+	req := &Request{
+		Symbols: []string{"", "__name__", "http_requests_total", "job", "api", "trace_id", "abc", "Number of requests", "1"},
+		Timeseries: []TimeSeries{
+			{
+				LabelsRefs: []uint32{1, 2, 3, 4},
+				Samples: []Sample{
+					{Value: 1, Timestamp: 1000},
+					{Value: 0, Timestamp: 2000},
+				},
+				Exemplars: []Exemplar{
+					{LabelsRefs: []uint32{5, 6}, Value: 1, Timestamp: 1000},
+				},
+				Metadata:         Metadata{Type: MetricTypeCounter, HelpRef: 7, UnitRef: 8},
+				CreatedTimestamp: 500,
+			},
+			{
+				LabelsRefs: []uint32{1, 2},
+				Histograms: []Histogram{
+					{
+						CountInt:       5,
+						Sum:            -1.5,
+						Schema:         -2,
+						ZeroThreshold:  1e-128,
+						ZeroCountInt:   0,
+						NegativeSpans:  []BucketSpan{{Offset: -3, Length: 1}},
+						NegativeDeltas: []int64{2},
+						PositiveSpans:  []BucketSpan{{Offset: 1, Length: 2}},
+						PositiveDeltas: []int64{1, -1},
+						ResetHint:      ResetHintGauge,
+						Timestamp:      3000,
+					},
+					{
+						IsFloat:        true,
+						CountFloat:     2.5,
+						ZeroCountFloat: 0.5,
+						PositiveSpans:  []BucketSpan{{Offset: 0, Length: 1}},
+						PositiveCounts: []float64{2},
+						Timestamp:      4000,
+					},
+				},
+			},
+		},
+	}
+
+	data, err := req.Marshal()
+	require.NoError(t, err)
+
+	var got Request
+	require.NoError(t, got.Unmarshal(data))
+	assert.Equal(t, *req, got)
+}
+
+func TestRequest_UnmarshalInvalid(t *testing.T) {
+	var req Request
+	assert.ErrorIs(t, req.Unmarshal([]byte{0x2a, 0x05, 0x01}), errInvalidMessage)
+}
+
+// The samples and histograms of the 1.0 and 2.0 formats share the same field numbers.
+func TestSample_CompatibleWithPrompb(t *testing.T) {
+	data := Sample{Value: math.Pi, Timestamp: 42}.appendTo(nil)
+
+	var sample prompb.Sample
+	require.NoError(t, sample.Unmarshal(data))
+	assert.Equal(t, prompb.Sample{Value: math.Pi, Timestamp: 42}, sample)
+}
+
+func TestHistogram_CompatibleWithPrompb(t *testing.T) {
+	h := Histogram{
+		CountInt:       10,
+		Sum:            12.5,
+		Schema:         3,
+		ZeroThreshold:  0.001,
+		ZeroCountInt:   1,
+		PositiveSpans:  []BucketSpan{{Offset: -2, Length: 3}},
+		PositiveDeltas: []int64{4, -2, 1},
+		ResetHint:      ResetHintNo,
+		Timestamp:      1234,
+	}
+	data := h.appendTo(nil)
+
+	var expected prompb.Histogram
+	require.NoError(t, expected.Unmarshal(data))
+	assert.Equal(t, uint64(10), expected.GetCountInt())
+	assert.Equal(t, 12.5, expected.Sum)
+	assert.Equal(t, int32(3), expected.Schema)
+	assert.Equal(t, 0.001, expected.ZeroThreshold)
+	assert.Equal(t, uint64(1), expected.GetZeroCountInt())
+	assert.Equal(t, []prompb.BucketSpan{{Offset: -2, Length: 3}}, expected.PositiveSpans)
+	assert.Equal(t, []int64{4, -2, 1}, expected.PositiveDeltas)
+	assert.Equal(t, prompb.Histogram_NO, expected.ResetHint)
+	assert.Equal(t, int64(1234), expected.Timestamp)
+}
@@ -0,0 +1,55 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package writev2 // import "github.com/open-telemetry/opentelemetry-collector-contrib/pkg/translator/prometheusremotewrite/writev2"
+
+import (
+	"github.com/prometheus/prometheus/prompb"
+)
+
+// SymbolsTable interns the strings of a request.
+type SymbolsTable struct {
+	strings    []string
+	symbolsMap map[string]uint32
+}
+
+// NewSymbolTable returns a symbols table holding the empty string at index 0, as required by the protocol.
+func NewSymbolTable() SymbolsTable {
+	return SymbolsTable{
+		strings:    []string{""},
+		symbolsMap: map[string]uint32{"": 0},
+	}
+}
+
+// Symbolize interns str and returns its reference.
+func (t *SymbolsTable) Symbolize(str string) uint32 {
+	if ref, ok := t.symbolsMap[str]; ok {
+		return ref
+	}
+	ref := uint32(len(t.strings))
+	t.strings = append(t.strings, str)
+	t.symbolsMap[str] = ref
+	return ref
+}
+
+// SymbolizeLabels interns the names and values of labels and appends their references to buf.
+func (t *SymbolsTable) SymbolizeLabels(labels []prompb.Label, buf []uint32) []uint32 {
+	for _, l := range labels {
+		buf = append(buf, t.Symbolize(l.Name), t.Symbolize(l.Value))
+	}
+	return buf
+}
+
+// Symbols returns the interned strings, indexed by their reference.
+func (t *SymbolsTable) Symbols() []string {
+	return t.strings
+}
+
+// DesymbolizeLabels resolves the label references against symbols.
+func DesymbolizeLabels(refs []uint32, symbols []string) []prompb.Label {
+	labels := make([]prompb.Label, 0, len(refs)/2)
+	for i := 0; i+1 < len(refs); i += 2 {
+		labels = append(labels, prompb.Label{Name: symbols[refs[i]], Value: symbols[refs[i+1]]})
+	}
+	return labels
+}
@@ -0,0 +1,27 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package writev2
+
+import (
+	"testing"
+
+	"github.com/prometheus/prometheus/prompb"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSymbolsTable(t *testing.T) {
+	table := NewSymbolTable()
+	assert.Equal(t, uint32(0), table.Symbolize(""))
+	assert.Equal(t, uint32(1), table.Symbolize("job"))
+	assert.Equal(t, uint32(1), table.Symbolize("job"))
+
+	labels := []prompb.Label{
+		{Name: "__name__", Value: "up"},
+		{Name: "job", Value: "api"},
+	}
+	refs := table.SymbolizeLabels(labels, nil)
+	assert.Equal(t, []uint32{2, 3, 1, 4}, refs)
+	assert.Equal(t, []string{"", "job", "__name__", "up", "api"}, table.Symbols())
+	assert.Equal(t, labels, DesymbolizeLabels(refs, table.Symbols()))
+}
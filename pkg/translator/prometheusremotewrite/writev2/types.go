@@ -0,0 +1,111 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+// Package writev2 implements the messages of the Prometheus remote write 2.0
+// protocol, see https://prometheus.io/docs/specs/remote_write_spec_2_0/.
+// The messages are encoded following io/prometheus/write/v2/types.proto.
+package writev2 // import "github.com/open-telemetry/opentelemetry-collector-contrib/pkg/translator/prometheusremotewrite/writev2"
+
+const (
+	// ContentType is the content type of remote write 2.0 requests.
+	ContentType = "application/x-protobuf;proto=io.prometheus.write.v2.Request"
+	// Version is the value of the X-Prometheus-Remote-Write-Version header of remote write 2.0 requests.
+	Version = "2.0.0"
+)
+
+// Request is the remote write 2.0 request. All the strings of the request,
+// label names and values, help and units, are interned in Symbols and
+// referenced by their index.
+type Request struct {
+	// Symbols is the symbols table, the first symbol is always the empty string.
+	Symbols    []string
+	Timeseries []TimeSeries
+}
+
+// TimeSeries is a series of samples or histograms sharing the same labels.
+type TimeSeries struct {
+	// LabelsRefs are the pairs of label name and value references in the symbols table.
+	LabelsRefs []uint32
+	Samples    []Sample
+	Histograms []Histogram
+	Exemplars  []Exemplar
+	Metadata   Metadata
+	// CreatedTimestamp is the time in ms the series was created or reset at, 0 if unknown.
+	CreatedTimestamp int64
+}
+
+// Sample is a float sample.
+type Sample struct {
+	Value float64
+	// Timestamp is the time in ms since epoch.
+	Timestamp int64
+}
+
+// Exemplar is an exemplar of a series.
+type Exemplar struct {
+	LabelsRefs []uint32
+	Value      float64
+	// Timestamp is the time in ms since epoch.
+	Timestamp int64
+}
+
+// MetricType is the type of the metric a series belongs to.
+type MetricType int32
+
+const (
+	MetricTypeUnspecified    MetricType = 0
+	MetricTypeCounter        MetricType = 1
+	MetricTypeGauge          MetricType = 2
+	MetricTypeHistogram      MetricType = 3
+	MetricTypeGaugeHistogram MetricType = 4
+	MetricTypeSummary        MetricType = 5
+	MetricTypeInfo           MetricType = 6
+	MetricTypeStateset       MetricType = 7
+)
+
+// Metadata is the metadata of the metric a series belongs to.
+type Metadata struct {
+	Type MetricType
+	// HelpRef and UnitRef are references in the symbols table, 0 for none.
+	HelpRef uint32
+	UnitRef uint32
+}
+
+// ResetHint tells whether a histogram is a reset of the series.
+type ResetHint int32
+
+const (
+	ResetHintUnknown ResetHint = 0
+	ResetHintYes     ResetHint = 1
+	ResetHintNo      ResetHint = 2
+	ResetHintGauge   ResetHint = 3
+)
+
+// Histogram is a native histogram sample. Integer histograms use the *Int
+// counts and the deltas, float histograms the *Float counts and the counts.
+type Histogram struct {
+	CountInt       uint64
+	CountFloat     float64
+	Sum            float64
+	Schema         int32
+	ZeroThreshold  float64
+	ZeroCountInt   uint64
+	ZeroCountFloat float64
+	NegativeSpans  []BucketSpan
+	NegativeDeltas []int64
+	NegativeCounts []float64
+	PositiveSpans  []BucketSpan
+	PositiveDeltas []int64
+	PositiveCounts []float64
+	ResetHint      ResetHint
+	// Timestamp is the time in ms since epoch.
+	Timestamp int64
+	// IsFloat tells whether the counts are float counts.
+	IsFloat bool
+}
+
+// BucketSpan is a run of consecutive buckets.
+type BucketSpan struct {
+	Offset int32
+	Length uint32
+}
@@ -0,0 +1,59 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package recordingrulesprocessor // import "github.com/open-telemetry/opentelemetry-collector-contrib/processor/recordingrulesprocessor"
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"go.opentelemetry.io/collector/component"
+)
+
+// Config defines the configuration for the processor.
+type Config struct {
+	// RuleFiles is the list of Prometheus rule files to load, in the same format
+	// as the `rule_files` of the Prometheus configuration. Globs are supported.
+	RuleFiles []string `mapstructure:"rule_files"`
+
+	// EvaluationInterval is how often the rule groups are evaluated. The `interval`
+	// of a rule group overrides it.
+	EvaluationInterval time.Duration `mapstructure:"evaluation_interval"`
+
+	// Window is how long the received samples are kept in memory to evaluate the rules.
+	// It must cover the lookback delta and the longest range selector of the rules.
+	Window time.Duration `mapstructure:"window"`
+
+	// LookbackDelta is the maximum time a sample is considered by an instant selector.
+	LookbackDelta time.Duration `mapstructure:"lookback_delta"`
+
+	// DropSourceMetrics drops the received metrics which are selected by the rules,
+	// so that only the results of the rules are forwarded.
+	DropSourceMetrics bool `mapstructure:"drop_source_metrics"`
+
+	// AddMetricSuffixes controls whether the type and unit suffixes are added to the
+	// names of the received metrics, as done by the Prometheus exporters. The rules
+	// must select the metrics by these names.
+	AddMetricSuffixes bool `mapstructure:"add_metric_suffixes"`
+}
+
+var _ component.Config = (*Config)(nil)
+
+// Validate checks whether the input configuration has all of the required fields for the processor.
+// An error is returned if there are any invalid inputs.
+func (config *Config) Validate() error {
+	if len(config.RuleFiles) == 0 {
+		return errors.New("rule_files must not be empty")
+	}
+	if config.EvaluationInterval <= 0 {
+		return errors.New("evaluation_interval must be positive")
+	}
+	if config.LookbackDelta <= 0 {
+		return errors.New("lookback_delta must be positive")
+	}
+	if config.Window < config.LookbackDelta {
+		return fmt.Errorf("window must be at least the lookback_delta (%v)", config.LookbackDelta)
+	}
+	return nil
+}
@@ -0,0 +1,78 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package recordingrulesprocessor
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/confmap/confmaptest"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/processor/recordingrulesprocessor/internal/metadata"
+)
+
+func TestLoadConfig(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		id           component.ID
+		expected     component.Config
+		errorMessage string
+	}{
+		{
+			id: component.NewIDWithName(metadata.Type, ""),
+			expected: &Config{
+				RuleFiles:          []string{"testdata/rules.yaml"},
+				EvaluationInterval: time.Minute,
+				Window:             10 * time.Minute,
+				LookbackDelta:      5 * time.Minute,
+				AddMetricSuffixes:  true,
+			},
+		},
+		{
+			id: component.NewIDWithName(metadata.Type, "custom"),
+			expected: &Config{
+				RuleFiles:          []string{"testdata/*.rules.yaml", "testdata/rules.yaml"},
+				EvaluationInterval: 30 * time.Second,
+				Window:             15 * time.Minute,
+				LookbackDelta:      2 * time.Minute,
+				DropSourceMetrics:  true,
+				AddMetricSuffixes:  false,
+			},
+		},
+		{
+			id:           component.NewIDWithName(metadata.Type, "no_rule_files"),
+			errorMessage: "rule_files must not be empty",
+		},
+		{
+			id:           component.NewIDWithName(metadata.Type, "small_window"),
+			errorMessage: "window must be at least the lookback_delta (5m0s)",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.id.String(), func(t *testing.T) {
+			cm, err := confmaptest.LoadConf(filepath.Join("testdata", "config.yaml"))
+			require.NoError(t, err)
+
+			factory := NewFactory()
+			cfg := factory.CreateDefaultConfig()
+
+			sub, err := cm.Sub(tt.id.String())
+			require.NoError(t, err)
+			require.NoError(t, component.UnmarshalConfig(sub, cfg))
+
+			if tt.expected == nil {
+				assert.EqualError(t, component.ValidateConfig(cfg), tt.errorMessage)
+				return
+			}
+			assert.NoError(t, component.ValidateConfig(cfg))
+			assert.Equal(t, tt.expected, cfg)
+		})
+	}
+}
@@ -0,0 +1,8 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+//go:generate mdatagen metadata.yaml
+
+// Package recordingrulesprocessor implements a processor which evaluates
+// Prometheus recording rules over the metrics flowing through it.
+package recordingrulesprocessor // import "github.com/open-telemetry/opentelemetry-collector-contrib/processor/recordingrulesprocessor"
@@ -0,0 +1,84 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package recordingrulesprocessor
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/consumer/consumertest"
+	"go.opentelemetry.io/collector/processor/processortest"
+)
+
+func TestType(t *testing.T) {
+	factory := NewFactory()
+	pType := factory.Type()
+	assert.Equal(t, pType, component.Type("recordingrules"))
+}
+
+func TestCreateDefaultConfig(t *testing.T) {
+	factory := NewFactory()
+	cfg := factory.CreateDefaultConfig()
+	assert.Equal(t, cfg, &Config{
+		EvaluationInterval: time.Minute,
+		Window:             10 * time.Minute,
+		LookbackDelta:      5 * time.Minute,
+		AddMetricSuffixes:  true,
+	})
+	assert.NoError(t, componenttest.CheckConfigStruct(cfg))
+}
+
+func TestCreateProcessors(t *testing.T) {
+	factory := NewFactory()
+	cfg := factory.CreateDefaultConfig().(*Config)
+	cfg.RuleFiles = []string{filepath.Join("testdata", "rules.yaml")}
+
+	tp, err := factory.CreateTracesProcessor(context.Background(), processortest.NewNopCreateSettings(), cfg, consumertest.NewNop())
+	// Not implemented error
+	assert.Error(t, err)
+	assert.Nil(t, tp)
+
+	mp, err := factory.CreateMetricsProcessor(context.Background(), processortest.NewNopCreateSettings(), cfg, consumertest.NewNop())
+	require.NoError(t, err)
+	require.NotNil(t, mp)
+	require.NoError(t, mp.Start(context.Background(), componenttest.NewNopHost()))
+	assert.NoError(t, mp.Shutdown(context.Background()))
+}
+
+func TestCreateProcessorInvalidRuleFiles(t *testing.T) {
+	tests := []struct {
+		name         string
+		ruleFiles    []string
+		errorMessage string
+	}{
+		{
+			name:         "no_files",
+			ruleFiles:    []string{filepath.Join("testdata", "missing*.yaml")},
+			errorMessage: "no rule groups found in rule_files",
+		},
+		{
+			name:         "alerting_rules",
+			ruleFiles:    []string{filepath.Join("testdata", "alerting_rules.yaml")},
+			errorMessage: `failed to load rule file "testdata/alerting_rules.yaml": group "alerts": alerting rule "HighRequestRate" is not supported`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			factory := NewFactory()
+			cfg := factory.CreateDefaultConfig().(*Config)
+			cfg.RuleFiles = tt.ruleFiles
+
+			mp, err := factory.CreateMetricsProcessor(context.Background(), processortest.NewNopCreateSettings(), cfg, consumertest.NewNop())
+			assert.EqualError(t, err, tt.errorMessage)
+			assert.Nil(t, mp)
+		})
+	}
+}
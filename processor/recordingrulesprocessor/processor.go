@@ -0,0 +1,208 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package recordingrulesprocessor // import "github.com/open-telemetry/opentelemetry-collector-contrib/processor/recordingrulesprocessor"
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prometheus/prometheus/promql"
+	"github.com/prometheus/prometheus/rules"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.opentelemetry.io/collector/processor/processorhelper"
+	"go.uber.org/zap"
+
+	prometheustranslator "github.com/open-telemetry/opentelemetry-collector-contrib/pkg/translator/prometheus"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/translator/prometheusremotewrite"
+)
+
+const (
+	scopeName = "recordingrulesprocessor"
+
+	// maxSamples is the maximum number of samples a single rule evaluation can load, as in Prometheus.
+	maxSamples = 50000000
+)
+
+type recordingRulesProcessor struct {
+	config         *Config
+	logger         *zap.Logger
+	nextConsumer   consumer.Metrics
+	groups         []*ruleGroup
+	sourceMatchers []*labels.Matcher
+	storage        *memStorage
+	queryFunc      rules.QueryFunc
+	settings       prometheusremotewrite.Settings
+	cancelFunc     context.CancelFunc
+	wg             sync.WaitGroup
+}
+
+func newRecordingRulesProcessor(config *Config, logger *zap.Logger, nextConsumer consumer.Metrics) (*recordingRulesProcessor, error) {
+	groups, err := loadRuleGroups(config.RuleFiles, config.EvaluationInterval)
+	if err != nil {
+		return nil, err
+	}
+
+	storage := newMemStorage()
+	engine := promql.NewEngine(promql.EngineOpts{
+		MaxSamples:    maxSamples,
+		Timeout:       config.EvaluationInterval,
+		LookbackDelta: config.LookbackDelta,
+		NoStepSubqueryIntervalFn: func(int64) int64 {
+			return config.EvaluationInterval.Milliseconds()
+		},
+	})
+
+	return &recordingRulesProcessor{
+		config:         config,
+		logger:         logger,
+		nextConsumer:   nextConsumer,
+		groups:         groups,
+		sourceMatchers: sourceMetricMatchers(groups),
+		storage:        storage,
+		queryFunc:      rules.EngineQueryFunc(engine, storage),
+		settings: prometheusremotewrite.Settings{
+			DisableTargetInfo: true,
+			AddMetricSuffixes: config.AddMetricSuffixes,
+		},
+	}, nil
+}
+
+func (rrp *recordingRulesProcessor) start(context.Context, component.Host) error {
+	ctx, cancel := context.WithCancel(context.Background())
+	rrp.cancelFunc = cancel
+	for _, group := range rrp.groups {
+		rrp.wg.Add(1)
+		go rrp.runGroup(ctx, group)
+	}
+	return nil
+}
+
+func (rrp *recordingRulesProcessor) shutdown(context.Context) error {
+	if rrp.cancelFunc != nil {
+		rrp.cancelFunc()
+	}
+	rrp.wg.Wait()
+	return nil
+}
+
+// runGroup evaluates the rule group at its interval until the context is cancelled.
+func (rrp *recordingRulesProcessor) runGroup(ctx context.Context, group *ruleGroup) {
+	defer rrp.wg.Done()
+
+	ticker := time.NewTicker(group.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			rrp.evaluateGroup(ctx, group, time.Now())
+		}
+	}
+}
+
+// evaluateGroup evaluates the rules of the group at the given time, and sends their results to the next consumer.
+func (rrp *recordingRulesProcessor) evaluateGroup(ctx context.Context, group *ruleGroup, ts time.Time) {
+	rrp.storage.truncate(ts.Add(-rrp.config.Window).UnixMilli())
+
+	md := pmetric.NewMetrics()
+	sm := md.ResourceMetrics().AppendEmpty().ScopeMetrics().AppendEmpty()
+	sm.Scope().SetName(scopeName)
+
+	for _, rule := range group.rules {
+		vector, err := rule.Eval(ctx, ts, rrp.queryFunc, nil, group.limit)
+		if err != nil {
+			rrp.logger.Warn("Failed to evaluate recording rule",
+				zap.String("group", group.name), zap.String("rule", rule.Name()), zap.Error(err))
+			continue
+		}
+		if len(vector) == 0 {
+			continue
+		}
+
+		metric := sm.Metrics().AppendEmpty()
+		metric.SetName(rule.Name())
+		dps := metric.SetEmptyGauge().DataPoints()
+		for _, s := range vector {
+			if s.H != nil {
+				continue
+			}
+			// The results are stored, so that the following rules can use them.
+			rrp.storage.append(s.Metric, s.T, s.F)
+
+			dp := dps.AppendEmpty()
+			dp.SetTimestamp(pcommon.NewTimestampFromTime(time.UnixMilli(s.T)))
+			dp.SetDoubleValue(s.F)
+			s.Metric.Range(func(l labels.Label) {
+				if l.Name != labels.MetricName {
+					dp.Attributes().PutStr(l.Name, l.Value)
+				}
+			})
+		}
+	}
+
+	if md.DataPointCount() == 0 {
+		return
+	}
+	if err := rrp.nextConsumer.ConsumeMetrics(ctx, md); err != nil {
+		rrp.logger.Error("Failed to send the results of the recording rules",
+			zap.String("group", group.name), zap.Error(err))
+	}
+}
+
+// processMetrics implements the ProcessMetricsFunc type.
+func (rrp *recordingRulesProcessor) processMetrics(_ context.Context, md pmetric.Metrics) (pmetric.Metrics, error) {
+	tsMap, err := prometheusremotewrite.FromMetrics(md, rrp.settings)
+	if err != nil {
+		rrp.logger.Debug("Failed to convert some metrics", zap.Error(err))
+	}
+	for _, ts := range tsMap {
+		builder := labels.NewScratchBuilder(len(ts.Labels))
+		for _, l := range ts.Labels {
+			builder.Add(l.Name, l.Value)
+		}
+		builder.Sort()
+		lset := builder.Labels()
+		for _, s := range ts.Samples {
+			rrp.storage.append(lset, s.Timestamp, s.Value)
+		}
+	}
+
+	if !rrp.config.DropSourceMetrics {
+		return md, nil
+	}
+	md.ResourceMetrics().RemoveIf(func(rm pmetric.ResourceMetrics) bool {
+		rm.ScopeMetrics().RemoveIf(func(sm pmetric.ScopeMetrics) bool {
+			sm.Metrics().RemoveIf(rrp.isSourceMetric)
+			return sm.Metrics().Len() == 0
+		})
+		return rm.ScopeMetrics().Len() == 0
+	})
+	if md.ResourceMetrics().Len() == 0 {
+		return md, processorhelper.ErrSkipProcessingData
+	}
+	return md, nil
+}
+
+// isSourceMetric returns whether the metric is selected by any of the rules.
+func (rrp *recordingRulesProcessor) isSourceMetric(metric pmetric.Metric) bool {
+	name := prometheustranslator.BuildCompliantName(metric, "", rrp.config.AddMetricSuffixes)
+	names := []string{name}
+	if metric.Type() == pmetric.MetricTypeHistogram || metric.Type() == pmetric.MetricTypeSummary {
+		names = append(names, name+"_bucket", name+"_sum", name+"_count")
+	}
+	for _, m := range rrp.sourceMatchers {
+		for _, n := range names {
+			if m.Matches(n) {
+				return true
+			}
+		}
+	}
+	return false
+}
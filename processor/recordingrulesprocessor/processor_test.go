@@ -0,0 +1,181 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package recordingrulesprocessor
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/consumer/consumertest"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.opentelemetry.io/collector/processor/processorhelper"
+	"go.uber.org/zap"
+)
+
+const testRules = `groups:
+  - name: http
+    rules:
+      - record: job:http_requests:sum
+        expr: sum by (job) (http_requests_total)
+      - record: job:http_requests:sum_doubled
+        expr: job:http_requests:sum * 2
+        labels:
+          source: recording_rule
+`
+
+func newTestProcessor(t *testing.T, dropSourceMetrics bool) (*recordingRulesProcessor, *consumertest.MetricsSink) {
+	rulesFile := filepath.Join(t.TempDir(), "rules.yaml")
+	require.NoError(t, os.WriteFile(rulesFile, []byte(testRules), 0600))
+
+	cfg := createDefaultConfig().(*Config)
+	cfg.RuleFiles = []string{rulesFile}
+	cfg.DropSourceMetrics = dropSourceMetrics
+	require.NoError(t, cfg.Validate())
+
+	sink := new(consumertest.MetricsSink)
+	rrp, err := newRecordingRulesProcessor(cfg, zap.NewNop(), sink)
+	require.NoError(t, err)
+	return rrp, sink
+}
+
+func generateTestMetrics(ts time.Time) pmetric.Metrics {
+	md := pmetric.NewMetrics()
+	rm := md.ResourceMetrics().AppendEmpty()
+	rm.Resource().Attributes().PutStr("service.name", "api")
+	sm := rm.ScopeMetrics().AppendEmpty()
+
+	requests := sm.Metrics().AppendEmpty()
+	requests.SetName("http.requests")
+	sum := requests.SetEmptySum()
+	sum.SetIsMonotonic(true)
+	sum.SetAggregationTemporality(pmetric.AggregationTemporalityCumulative)
+	for method, value := range map[string]float64{"GET": 10, "POST": 5} {
+		dp := sum.DataPoints().AppendEmpty()
+		dp.SetTimestamp(pcommon.NewTimestampFromTime(ts))
+		dp.SetDoubleValue(value)
+		dp.Attributes().PutStr("method", method)
+	}
+
+	usage := sm.Metrics().AppendEmpty()
+	usage.SetName("cpu_usage")
+	dp := usage.SetEmptyGauge().DataPoints().AppendEmpty()
+	dp.SetTimestamp(pcommon.NewTimestampFromTime(ts))
+	dp.SetDoubleValue(0.5)
+	return md
+}
+
+func TestProcessorEvaluateRules(t *testing.T) {
+	rrp, sink := newTestProcessor(t, false)
+
+	now := time.Now().Truncate(time.Millisecond)
+	md, err := rrp.processMetrics(context.Background(), generateTestMetrics(now.Add(-30*time.Second)))
+	require.NoError(t, err)
+	assert.Equal(t, 2, md.ResourceMetrics().At(0).ScopeMetrics().At(0).Metrics().Len())
+
+	rrp.evaluateGroup(context.Background(), rrp.groups[0], now)
+
+	require.Len(t, sink.AllMetrics(), 1)
+	sm := sink.AllMetrics()[0].ResourceMetrics().At(0).ScopeMetrics().At(0)
+	assert.Equal(t, scopeName, sm.Scope().Name())
+	require.Equal(t, 2, sm.Metrics().Len())
+
+	expected := []struct {
+		name       string
+		value      float64
+		attributes map[string]any
+	}{
+		{name: "job:http_requests:sum", value: 15, attributes: map[string]any{"job": "api"}},
+		{name: "job:http_requests:sum_doubled", value: 30, attributes: map[string]any{"job": "api", "source": "recording_rule"}},
+	}
+	for i, e := range expected {
+		metric := sm.Metrics().At(i)
+		assert.Equal(t, e.name, metric.Name())
+		require.Equal(t, pmetric.MetricTypeGauge, metric.Type())
+		require.Equal(t, 1, metric.Gauge().DataPoints().Len())
+		dp := metric.Gauge().DataPoints().At(0)
+		assert.Equal(t, e.value, dp.DoubleValue())
+		assert.Equal(t, pcommon.NewTimestampFromTime(now), dp.Timestamp())
+		assert.Equal(t, e.attributes, dp.Attributes().AsRaw())
+	}
+}
+
+func TestProcessorNoResults(t *testing.T) {
+	rrp, sink := newTestProcessor(t, false)
+
+	// The samples are outside of the lookback delta.
+	now := time.Now()
+	_, err := rrp.processMetrics(context.Background(), generateTestMetrics(now.Add(-6*time.Minute)))
+	require.NoError(t, err)
+
+	rrp.evaluateGroup(context.Background(), rrp.groups[0], now)
+	assert.Empty(t, sink.AllMetrics())
+}
+
+func TestProcessorWindow(t *testing.T) {
+	rrp, _ := newTestProcessor(t, false)
+
+	now := time.Now()
+	_, err := rrp.processMetrics(context.Background(), generateTestMetrics(now.Add(-20*time.Minute)))
+	require.NoError(t, err)
+	assert.Len(t, rrp.storage.series, 3)
+
+	rrp.evaluateGroup(context.Background(), rrp.groups[0], now)
+	assert.Empty(t, rrp.storage.series)
+}
+
+func TestProcessorDropSourceMetrics(t *testing.T) {
+	rrp, sink := newTestProcessor(t, true)
+
+	now := time.Now()
+	md, err := rrp.processMetrics(context.Background(), generateTestMetrics(now.Add(-30*time.Second)))
+	require.NoError(t, err)
+	metrics := md.ResourceMetrics().At(0).ScopeMetrics().At(0).Metrics()
+	require.Equal(t, 1, metrics.Len())
+	assert.Equal(t, "cpu_usage", metrics.At(0).Name())
+
+	// The dropped metrics are still used by the rules.
+	rrp.evaluateGroup(context.Background(), rrp.groups[0], now)
+	assert.Equal(t, 2, sink.DataPointCount())
+
+	md = generateTestMetrics(now)
+	md.ResourceMetrics().At(0).ScopeMetrics().At(0).Metrics().RemoveIf(func(m pmetric.Metric) bool {
+		return m.Name() == "cpu_usage"
+	})
+	_, err = rrp.processMetrics(context.Background(), md)
+	assert.ErrorIs(t, err, processorhelper.ErrSkipProcessingData)
+}
+
+func TestMemStorageAppend(t *testing.T) {
+	s := newMemStorage()
+	lset := labels.FromStrings(labels.MetricName, "up", "job", "api")
+	s.append(lset, 20, 2)
+	s.append(lset, 10, 1)
+	s.append(lset, 30, 3)
+	s.append(lset, 20, 4)
+	assert.Equal(t, []sample{{t: 10, f: 1}, {t: 20, f: 4}, {t: 30, f: 3}}, s.series[string(lset.Bytes(nil))].samples)
+
+	s.truncate(20)
+	assert.Equal(t, []sample{{t: 20, f: 4}, {t: 30, f: 3}}, s.series[string(lset.Bytes(nil))].samples)
+
+	q, err := s.Querier(25, 40)
+	require.NoError(t, err)
+	set := q.Select(context.Background(), true, nil, labels.MustNewMatcher(labels.MatchEqual, "job", "api"))
+	require.True(t, set.Next())
+	assert.Equal(t, lset, set.At().Labels())
+	assert.False(t, set.Next())
+
+	values, _, err := q.LabelValues(context.Background(), "job")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"api"}, values)
+	names, _, err := q.LabelNames(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, []string{labels.MetricName, "job"}, names)
+}
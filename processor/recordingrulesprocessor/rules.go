@@ -0,0 +1,95 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package recordingrulesprocessor // import "github.com/open-telemetry/opentelemetry-collector-contrib/processor/recordingrulesprocessor"
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"time"
+
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prometheus/prometheus/model/rulefmt"
+	"github.com/prometheus/prometheus/promql/parser"
+	"github.com/prometheus/prometheus/rules"
+)
+
+// ruleGroup is a group of recording rules which are evaluated sequentially at the same interval,
+// so that a rule can use the results of the rules preceding it.
+type ruleGroup struct {
+	name     string
+	interval time.Duration
+	limit    int
+	rules    []*rules.RecordingRule
+}
+
+// loadRuleGroups loads the recording rules of the rule files matching the given patterns.
+func loadRuleGroups(patterns []string, defaultInterval time.Duration) ([]*ruleGroup, error) {
+	var groups []*ruleGroup
+	for _, pattern := range patterns {
+		files, err := filepath.Glob(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid rule file pattern %q: %w", pattern, err)
+		}
+		for _, file := range files {
+			ruleGroups, errs := rulefmt.ParseFile(file)
+			if len(errs) > 0 {
+				return nil, fmt.Errorf("failed to load rule file %q: %w", file, errors.Join(errs...))
+			}
+			for _, rg := range ruleGroups.Groups {
+				group, err := newRuleGroup(rg, defaultInterval)
+				if err != nil {
+					return nil, fmt.Errorf("failed to load rule file %q: %w", file, err)
+				}
+				groups = append(groups, group)
+			}
+		}
+	}
+	if len(groups) == 0 {
+		return nil, errors.New("no rule groups found in rule_files")
+	}
+	return groups, nil
+}
+
+func newRuleGroup(rg rulefmt.RuleGroup, defaultInterval time.Duration) (*ruleGroup, error) {
+	group := &ruleGroup{
+		name:     rg.Name,
+		interval: time.Duration(rg.Interval),
+		limit:    rg.Limit,
+	}
+	if group.interval == 0 {
+		group.interval = defaultInterval
+	}
+	for _, r := range rg.Rules {
+		if r.Alert.Value != "" {
+			return nil, fmt.Errorf("group %q: alerting rule %q is not supported", rg.Name, r.Alert.Value)
+		}
+		expr, err := parser.ParseExpr(r.Expr.Value)
+		if err != nil {
+			return nil, fmt.Errorf("group %q: rule %q: %w", rg.Name, r.Record.Value, err)
+		}
+		group.rules = append(group.rules, rules.NewRecordingRule(r.Record.Value, expr, labels.FromMap(r.Labels)))
+	}
+	return group, nil
+}
+
+// sourceMetricMatchers returns the metric name matchers of the selectors of the rules.
+func sourceMetricMatchers(groups []*ruleGroup) []*labels.Matcher {
+	var matchers []*labels.Matcher
+	for _, group := range groups {
+		for _, rule := range group.rules {
+			parser.Inspect(rule.Query(), func(node parser.Node, _ []parser.Node) error {
+				if vs, ok := node.(*parser.VectorSelector); ok {
+					for _, m := range vs.LabelMatchers {
+						if m.Name == labels.MetricName {
+							matchers = append(matchers, m)
+						}
+					}
+				}
+				return nil
+			})
+		}
+	}
+	return matchers
+}
@@ -0,0 +1,198 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package recordingrulesprocessor // import "github.com/open-telemetry/opentelemetry-collector-contrib/processor/recordingrulesprocessor"
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"github.com/prometheus/prometheus/model/histogram"
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prometheus/prometheus/storage"
+	"github.com/prometheus/prometheus/tsdb/chunkenc"
+	"github.com/prometheus/prometheus/tsdb/chunks"
+	"github.com/prometheus/prometheus/util/annotations"
+)
+
+// memStorage keeps the float samples of the series in memory, so that they can be queried by the PromQL engine.
+type memStorage struct {
+	mu     sync.RWMutex
+	series map[string]*memSeries
+	buf    []byte
+}
+
+type memSeries struct {
+	lset    labels.Labels
+	samples []sample
+}
+
+var _ storage.Queryable = (*memStorage)(nil)
+
+func newMemStorage() *memStorage {
+	return &memStorage{series: map[string]*memSeries{}}
+}
+
+// append adds a sample to the series with the given labels. A sample with the same timestamp
+// as an existing one replaces it.
+func (s *memStorage) append(lset labels.Labels, t int64, f float64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.buf = lset.Bytes(s.buf)
+	series, ok := s.series[string(s.buf)]
+	if !ok {
+		series = &memSeries{lset: lset}
+		s.series[string(s.buf)] = series
+	}
+
+	n := len(series.samples)
+	if n == 0 || series.samples[n-1].t < t {
+		series.samples = append(series.samples, sample{t: t, f: f})
+		return
+	}
+	i := sort.Search(n, func(i int) bool { return series.samples[i].t >= t })
+	if series.samples[i].t == t {
+		series.samples[i].f = f
+		return
+	}
+	series.samples = append(series.samples, sample{})
+	copy(series.samples[i+1:], series.samples[i:])
+	series.samples[i] = sample{t: t, f: f}
+}
+
+// truncate removes the samples older than mint, and the series left without samples.
+func (s *memStorage) truncate(mint int64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for key, series := range s.series {
+		i := sort.Search(len(series.samples), func(i int) bool { return series.samples[i].t >= mint })
+		if i == len(series.samples) {
+			delete(s.series, key)
+			continue
+		}
+		series.samples = series.samples[i:]
+	}
+}
+
+func (s *memStorage) Querier(mint, maxt int64) (storage.Querier, error) {
+	return &memQuerier{storage: s, mint: mint, maxt: maxt}, nil
+}
+
+type memQuerier struct {
+	storage    *memStorage
+	mint, maxt int64
+}
+
+func (q *memQuerier) Select(_ context.Context, sortSeries bool, _ *storage.SelectHints, matchers ...*labels.Matcher) storage.SeriesSet {
+	q.storage.mu.RLock()
+	defer q.storage.mu.RUnlock()
+
+	var result []storage.Series
+	for _, series := range q.storage.series {
+		if !matchLabels(series.lset, matchers) {
+			continue
+		}
+		var samples []chunks.Sample
+		for _, s := range series.samples {
+			if s.t >= q.mint && s.t <= q.maxt {
+				samples = append(samples, s)
+			}
+		}
+		if len(samples) > 0 {
+			result = append(result, storage.NewListSeries(series.lset, samples))
+		}
+	}
+	if sortSeries {
+		sort.Slice(result, func(i, j int) bool {
+			return labels.Compare(result[i].Labels(), result[j].Labels()) < 0
+		})
+	}
+	return &seriesSet{series: result, index: -1}
+}
+
+func (q *memQuerier) LabelValues(_ context.Context, name string, matchers ...*labels.Matcher) ([]string, annotations.Annotations, error) {
+	q.storage.mu.RLock()
+	defer q.storage.mu.RUnlock()
+
+	values := map[string]struct{}{}
+	for _, series := range q.storage.series {
+		if value := series.lset.Get(name); value != "" && matchLabels(series.lset, matchers) {
+			values[value] = struct{}{}
+		}
+	}
+	return sortedKeys(values), nil, nil
+}
+
+func (q *memQuerier) LabelNames(_ context.Context, matchers ...*labels.Matcher) ([]string, annotations.Annotations, error) {
+	q.storage.mu.RLock()
+	defer q.storage.mu.RUnlock()
+
+	names := map[string]struct{}{}
+	for _, series := range q.storage.series {
+		if matchLabels(series.lset, matchers) {
+			series.lset.Range(func(l labels.Label) {
+				names[l.Name] = struct{}{}
+			})
+		}
+	}
+	return sortedKeys(names), nil, nil
+}
+
+func (q *memQuerier) Close() error {
+	return nil
+}
+
+func matchLabels(lset labels.Labels, matchers []*labels.Matcher) bool {
+	for _, m := range matchers {
+		if !m.Matches(lset.Get(m.Name)) {
+			return false
+		}
+	}
+	return true
+}
+
+func sortedKeys(m map[string]struct{}) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+type seriesSet struct {
+	series []storage.Series
+	index  int
+}
+
+func (s *seriesSet) Next() bool {
+	s.index++
+	return s.index < len(s.series)
+}
+
+func (s *seriesSet) At() storage.Series {
+	return s.series[s.index]
+}
+
+func (s *seriesSet) Err() error {
+	return nil
+}
+
+func (s *seriesSet) Warnings() annotations.Annotations {
+	return nil
+}
+
+// sample is a float sample, only float samples are supported.
+type sample struct {
+	t int64
+	f float64
+}
+
+func (s sample) T() int64                      { return s.t }
+func (s sample) F() float64                    { return s.f }
+func (s sample) H() *histogram.Histogram       { return nil }
+func (s sample) FH() *histogram.FloatHistogram { return nil }
+func (s sample) Type() chunkenc.ValueType      { return chunkenc.ValFloat }
@@ -4,6 +4,7 @@
 package kafkareceiver // import "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/kafkareceiver"
 
 import (
+	"errors"
 	"time"
 
 	"go.opentelemetry.io/collector/component"
@@ -32,6 +33,29 @@ type MessageMarking struct {
 	OnError bool `mapstructure:"on_error"`
 }
 
+// ErrorBackOff configures the retries of messages that fail to be consumed by
+// the next consumer with a non-permanent error.
+type ErrorBackOff struct {
+	// Whether or not to retry non-permanent errors (default disabled).
+	Enabled bool `mapstructure:"enabled"`
+	// The time to wait after the first failure before retrying (default 500ms).
+	InitialInterval time.Duration `mapstructure:"initial_interval"`
+	// The upper bound on the time between retries (default 10s).
+	MaxInterval time.Duration `mapstructure:"max_interval"`
+	// The maximum time spent retrying a message, after which it is handled as
+	// failed. Zero means no limit (default 1m).
+	MaxElapsedTime time.Duration `mapstructure:"max_elapsed_time"`
+}
+
+// DeadLetter configures the topic where messages that cannot be processed are
+// published to.
+type DeadLetter struct {
+	// The name of the kafka topic failed messages are published to. Messages
+	// published to this topic are marked and consumption continues with the
+	// next message. Disabled if empty.
+	Topic string `mapstructure:"topic"`
+}
+
 type HeaderExtraction struct {
 	ExtractHeaders bool     `mapstructure:"extract_headers"`
 	Headers        []string `mapstructure:"headers"`
@@ -74,6 +98,12 @@ type Config struct {
 
 	// Extract headers from kafka records
 	HeaderExtraction HeaderExtraction `mapstructure:"header_extraction"`
+
+	// Controls the retries of messages that fail with non-permanent errors
+	ErrorBackOff ErrorBackOff `mapstructure:"error_backoff"`
+
+	// Publishes the messages that cannot be processed to a dead-letter topic
+	DeadLetter DeadLetter `mapstructure:"dead_letter"`
 }
 
 const (
@@ -85,5 +115,19 @@ var _ component.Config = (*Config)(nil)
 
 // Validate checks the receiver configuration is valid
 func (cfg *Config) Validate() error {
+	if cfg.DeadLetter.Topic != "" && cfg.DeadLetter.Topic == cfg.Topic {
+		return errors.New("dead_letter.topic must be different from topic")
+	}
+	if cfg.ErrorBackOff.Enabled {
+		if cfg.ErrorBackOff.InitialInterval <= 0 {
+			return errors.New("error_backoff.initial_interval must be positive")
+		}
+		if cfg.ErrorBackOff.MaxInterval < cfg.ErrorBackOff.InitialInterval {
+			return errors.New("error_backoff.max_interval must not be lower than error_backoff.initial_interval")
+		}
+		if cfg.ErrorBackOff.MaxElapsedTime < 0 {
+			return errors.New("error_backoff.max_elapsed_time must not be negative")
+		}
+	}
 	return nil
 }
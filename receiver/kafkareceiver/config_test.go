@@ -60,6 +60,11 @@ func TestLoadConfig(t *testing.T) {
 					Enable:   true,
 					Interval: 1 * time.Second,
 				},
+				ErrorBackOff: ErrorBackOff{
+					InitialInterval: 500 * time.Millisecond,
+					MaxInterval:     10 * time.Second,
+					MaxElapsedTime:  time.Minute,
+				},
 			},
 		},
 		{
@@ -92,6 +97,18 @@ func TestLoadConfig(t *testing.T) {
 					Enable:   true,
 					Interval: 1 * time.Second,
 				},
+				MessageMarking: MessageMarking{
+					After: true,
+				},
+				ErrorBackOff: ErrorBackOff{
+					Enabled:         true,
+					InitialInterval: time.Second,
+					MaxInterval:     30 * time.Second,
+					MaxElapsedTime:  5 * time.Minute,
+				},
+				DeadLetter: DeadLetter{
+					Topic: "logs-dead-letter",
+				},
 			},
 		},
 	}
@@ -110,3 +127,58 @@ func TestLoadConfig(t *testing.T) {
 		})
 	}
 }
+
+func TestValidateConfig(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(cfg *Config)
+		err    string
+	}{
+		{
+			name: "default",
+		},
+		{
+			name: "dead letter topic same as topic",
+			modify: func(cfg *Config) {
+				cfg.DeadLetter.Topic = cfg.Topic
+			},
+			err: "dead_letter.topic must be different from topic",
+		},
+		{
+			name: "zero initial interval",
+			modify: func(cfg *Config) {
+				cfg.ErrorBackOff.Enabled = true
+				cfg.ErrorBackOff.InitialInterval = 0
+			},
+			err: "error_backoff.initial_interval must be positive",
+		},
+		{
+			name: "max interval lower than initial interval",
+			modify: func(cfg *Config) {
+				cfg.ErrorBackOff.Enabled = true
+				cfg.ErrorBackOff.MaxInterval = time.Millisecond
+			},
+			err: "error_backoff.max_interval must not be lower than error_backoff.initial_interval",
+		},
+		{
+			name: "invalid backoff disabled",
+			modify: func(cfg *Config) {
+				cfg.ErrorBackOff.InitialInterval = 0
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := createDefaultConfig().(*Config)
+			if tt.modify != nil {
+				tt.modify(cfg)
+			}
+			err := cfg.Validate()
+			if tt.err == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, tt.err)
+			}
+		})
+	}
+}
@@ -0,0 +1,160 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package kafkareceiver // import "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/kafkareceiver"
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/IBM/sarama"
+	"github.com/cenkalti/backoff/v4"
+	"go.opencensus.io/stats"
+	"go.opencensus.io/tag"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/consumer/consumererror"
+	"go.uber.org/zap"
+)
+
+// Headers added to the messages published to the dead-letter topic.
+const (
+	deadLetterHeaderError     = "otel-dead-letter-error"
+	deadLetterHeaderTopic     = "otel-dead-letter-topic"
+	deadLetterHeaderPartition = "otel-dead-letter-partition"
+	deadLetterHeaderOffset    = "otel-dead-letter-offset"
+)
+
+// deadLetterPublisher republishes the messages that cannot be processed to
+// the dead-letter topic.
+type deadLetterPublisher struct {
+	id       component.ID
+	producer sarama.SyncProducer
+	topic    string
+}
+
+// newDeadLetterPublisher creates a publisher for the configured dead-letter
+// topic, it returns nil if no topic is configured.
+func newDeadLetterPublisher(config Config, id component.ID, c *sarama.Config) (*deadLetterPublisher, error) {
+	if config.DeadLetter.Topic == "" {
+		return nil, nil
+	}
+	producerConfig := *c
+	producerConfig.Producer.Return.Successes = true
+	producerConfig.Producer.Return.Errors = true
+	producerConfig.Producer.RequiredAcks = sarama.WaitForAll
+	producer, err := sarama.NewSyncProducer(config.Brokers, &producerConfig)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create dead-letter producer: %w", err)
+	}
+	return &deadLetterPublisher{
+		id:       id,
+		producer: producer,
+		topic:    config.DeadLetter.Topic,
+	}, nil
+}
+
+// publish sends message to the dead-letter topic, keeping its key and headers
+// and adding headers describing the error and the original location of the message.
+func (p *deadLetterPublisher) publish(ctx context.Context, message *sarama.ConsumerMessage, cause error) error {
+	headers := make([]sarama.RecordHeader, 0, len(message.Headers)+4)
+	for _, h := range message.Headers {
+		if h != nil {
+			headers = append(headers, *h)
+		}
+	}
+	headers = append(headers,
+		sarama.RecordHeader{Key: []byte(deadLetterHeaderError), Value: []byte(cause.Error())},
+		sarama.RecordHeader{Key: []byte(deadLetterHeaderTopic), Value: []byte(message.Topic)},
+		sarama.RecordHeader{Key: []byte(deadLetterHeaderPartition), Value: []byte(strconv.FormatInt(int64(message.Partition), 10))},
+		sarama.RecordHeader{Key: []byte(deadLetterHeaderOffset), Value: []byte(strconv.FormatInt(message.Offset, 10))},
+	)
+	msg := &sarama.ProducerMessage{
+		Topic:   p.topic,
+		Value:   sarama.ByteEncoder(message.Value),
+		Headers: headers,
+	}
+	if message.Key != nil {
+		msg.Key = sarama.ByteEncoder(message.Key)
+	}
+	if _, _, err := p.producer.SendMessage(msg); err != nil {
+		return err
+	}
+	_ = stats.RecordWithTags(ctx, []tag.Mutator{tag.Upsert(tagInstanceName, p.id.String())}, statDeadLetterMessages.M(1))
+	return nil
+}
+
+func (p *deadLetterPublisher) close() error {
+	if p == nil {
+		return nil
+	}
+	return p.producer.Close()
+}
+
+// handleMessageError handles a message that could not be unmarshaled or
+// consumed. When a dead-letter topic is configured the message is published
+// to it and marked, and nil is returned so consumption continues with the next
+// message. Otherwise the error is returned, marking the message only if
+// configured to do so on errors.
+func handleMessageError(session sarama.ConsumerGroupSession, message *sarama.ConsumerMessage, err error, marking MessageMarking, deadLetter *deadLetterPublisher, logger *zap.Logger) error {
+	// Messages are not dead-lettered when the session is done, so they are
+	// redelivered to the next owner of the partition.
+	if deadLetter == nil || session.Context().Err() != nil {
+		if marking.After && marking.OnError {
+			session.MarkMessage(message, "")
+		}
+		return err
+	}
+	if dlErr := deadLetter.publish(session.Context(), message, err); dlErr != nil {
+		logger.Error("failed to publish message to dead-letter topic", zap.Error(dlErr))
+		return fmt.Errorf("%w; failed to publish message to dead-letter topic: %w", err, dlErr)
+	}
+	logger.Warn("message published to dead-letter topic",
+		zap.Error(err),
+		zap.String("topic", message.Topic),
+		zap.Int32("partition", message.Partition),
+		zap.Int64("offset", message.Offset))
+	if marking.After {
+		session.MarkMessage(message, "")
+	}
+	return nil
+}
+
+// consumeWithBackOff calls consume, retrying it with an exponential backoff
+// while it fails with non-permanent errors, if enabled. It returns the last
+// error if retries are exhausted or the context is done.
+func consumeWithBackOff(ctx context.Context, cfg ErrorBackOff, consume func() error) error {
+	err := consume()
+	if err == nil || !cfg.Enabled || consumererror.IsPermanent(err) {
+		return err
+	}
+
+	expBackOff := backoff.ExponentialBackOff{
+		InitialInterval:     cfg.InitialInterval,
+		RandomizationFactor: backoff.DefaultRandomizationFactor,
+		Multiplier:          backoff.DefaultMultiplier,
+		MaxInterval:         cfg.MaxInterval,
+		MaxElapsedTime:      cfg.MaxElapsedTime,
+		Stop:                backoff.Stop,
+		Clock:               backoff.SystemClock,
+	}
+	expBackOff.Reset()
+	for {
+		wait := expBackOff.NextBackOff()
+		if wait == backoff.Stop {
+			return err
+		}
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return err
+		case <-timer.C:
+		}
+		err = consume()
+		if err == nil || consumererror.IsPermanent(err) {
+			return err
+		}
+	}
+}
@@ -0,0 +1,321 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package kafkareceiver
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/IBM/sarama"
+	"github.com/IBM/sarama/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/consumer/consumererror"
+	"go.opentelemetry.io/collector/consumer/consumertest"
+	"go.opentelemetry.io/collector/pdata/plog"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+	"go.opentelemetry.io/collector/receiver/receiverhelper"
+	"go.opentelemetry.io/collector/receiver/receivertest"
+	"go.uber.org/zap"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/kafkareceiver/internal/metadata"
+)
+
+func TestNewDeadLetterPublisher_disabled(t *testing.T) {
+	p, err := newDeadLetterPublisher(*createDefaultConfig().(*Config), component.NewID(metadata.Type), sarama.NewConfig())
+	require.NoError(t, err)
+	assert.Nil(t, p)
+	assert.NoError(t, p.close())
+}
+
+func TestDeadLetterPublisher_mockBroker(t *testing.T) {
+	broker := sarama.NewMockBroker(t, 1)
+	defer broker.Close()
+	broker.SetHandlerByMap(map[string]sarama.MockResponse{
+		"MetadataRequest": sarama.NewMockMetadataResponse(t).
+			SetBroker(broker.Addr(), broker.BrokerID()).
+			SetLeader("dead-letter", 0, broker.BrokerID()),
+		"ProduceRequest": sarama.NewMockProduceResponse(t),
+	})
+
+	cfg := createDefaultConfig().(*Config)
+	cfg.Brokers = []string{broker.Addr()}
+	cfg.DeadLetter.Topic = "dead-letter"
+	p, err := newDeadLetterPublisher(*cfg, component.NewID(metadata.Type), sarama.NewConfig())
+	require.NoError(t, err)
+	require.NotNil(t, p)
+
+	err = p.publish(context.Background(), &sarama.ConsumerMessage{Topic: "otlp_spans", Value: []byte("invalid")}, errors.New("failed to unmarshal"))
+	require.NoError(t, err)
+	require.NoError(t, p.close())
+
+	var produced int
+	for _, rr := range broker.History() {
+		if _, ok := rr.Request.(*sarama.ProduceRequest); ok {
+			produced++
+		}
+	}
+	assert.Equal(t, 1, produced)
+}
+
+func TestDeadLetterPublisher_publish(t *testing.T) {
+	producer := mocks.NewSyncProducer(t, nil)
+	producer.ExpectSendMessageWithMessageCheckerFunctionAndSucceed(func(msg *sarama.ProducerMessage) error {
+		assert.Equal(t, "dead-letter", msg.Topic)
+		assert.Equal(t, sarama.ByteEncoder("key"), msg.Key)
+		assert.Equal(t, sarama.ByteEncoder("value"), msg.Value)
+		assert.Equal(t, []sarama.RecordHeader{
+			{Key: []byte("tenant"), Value: []byte("a")},
+			{Key: []byte(deadLetterHeaderError), Value: []byte("failed to unmarshal")},
+			{Key: []byte(deadLetterHeaderTopic), Value: []byte("otlp_spans")},
+			{Key: []byte(deadLetterHeaderPartition), Value: []byte("3")},
+			{Key: []byte(deadLetterHeaderOffset), Value: []byte("42")},
+		}, msg.Headers)
+		return nil
+	})
+	p := &deadLetterPublisher{producer: producer, topic: "dead-letter"}
+	err := p.publish(context.Background(), &sarama.ConsumerMessage{
+		Topic:     "otlp_spans",
+		Partition: 3,
+		Offset:    42,
+		Key:       []byte("key"),
+		Value:     []byte("value"),
+		Headers:   []*sarama.RecordHeader{{Key: []byte("tenant"), Value: []byte("a")}},
+	}, errors.New("failed to unmarshal"))
+	require.NoError(t, err)
+	require.NoError(t, p.close())
+}
+
+func TestHandleMessageError(t *testing.T) {
+	cause := errors.New("failed to consume")
+	tests := []struct {
+		name       string
+		marking    MessageMarking
+		deadLetter func(*mocks.SyncProducer)
+		err        string
+		marked     bool
+	}{
+		{
+			name: "no dead letter",
+			err:  "failed to consume",
+		},
+		{
+			name:    "no dead letter, mark on error",
+			marking: MessageMarking{After: true, OnError: true},
+			err:     "failed to consume",
+			marked:  true,
+		},
+		{
+			name:    "dead letter",
+			marking: MessageMarking{After: true},
+			deadLetter: func(p *mocks.SyncProducer) {
+				p.ExpectSendMessageAndSucceed()
+			},
+			marked: true,
+		},
+		{
+			name:    "dead letter fails",
+			marking: MessageMarking{After: true},
+			deadLetter: func(p *mocks.SyncProducer) {
+				p.ExpectSendMessageAndFail(sarama.ErrOutOfBrokers)
+			},
+			err: "failed to consume; failed to publish message to dead-letter topic: kafka: client has run out of available brokers to talk to",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var deadLetter *deadLetterPublisher
+			if tt.deadLetter != nil {
+				producer := mocks.NewSyncProducer(t, nil)
+				tt.deadLetter(producer)
+				deadLetter = &deadLetterPublisher{producer: producer, topic: "dead-letter"}
+				defer func() { require.NoError(t, producer.Close()) }()
+			}
+			session := &markingConsumerGroupSession{testConsumerGroupSession: testConsumerGroupSession{ctx: context.Background()}}
+			err := handleMessageError(session, &sarama.ConsumerMessage{Offset: 1}, cause, tt.marking, deadLetter, zap.NewNop())
+			if tt.err == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, tt.err)
+			}
+			assert.Equal(t, tt.marked, len(session.markedOffsets()) == 1)
+		})
+	}
+}
+
+func TestConsumeWithBackOff(t *testing.T) {
+	transient := errors.New("transient")
+	backOff := ErrorBackOff{
+		Enabled:         true,
+		InitialInterval: time.Millisecond,
+		MaxInterval:     time.Millisecond,
+		MaxElapsedTime:  time.Second,
+	}
+
+	t.Run("retries until success", func(t *testing.T) {
+		calls := 0
+		err := consumeWithBackOff(context.Background(), backOff, func() error {
+			calls++
+			if calls < 3 {
+				return transient
+			}
+			return nil
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, 3, calls)
+	})
+
+	t.Run("permanent errors are not retried", func(t *testing.T) {
+		calls := 0
+		err := consumeWithBackOff(context.Background(), backOff, func() error {
+			calls++
+			return consumererror.NewPermanent(transient)
+		})
+		assert.True(t, consumererror.IsPermanent(err))
+		assert.Equal(t, 1, calls)
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		calls := 0
+		err := consumeWithBackOff(context.Background(), ErrorBackOff{}, func() error {
+			calls++
+			return transient
+		})
+		assert.ErrorIs(t, err, transient)
+		assert.Equal(t, 1, calls)
+	})
+
+	t.Run("context done", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		calls := 0
+		err := consumeWithBackOff(ctx, ErrorBackOff{Enabled: true, InitialInterval: time.Hour, MaxInterval: time.Hour}, func() error {
+			calls++
+			return transient
+		})
+		assert.ErrorIs(t, err, transient)
+		assert.Equal(t, 1, calls)
+	})
+}
+
+func TestTracesConsumerGroupHandler_deadLetter(t *testing.T) {
+	obsrecv, err := receiverhelper.NewObsReport(receiverhelper.ObsReportSettings{ReceiverCreateSettings: receivertest.NewNopCreateSettings()})
+	require.NoError(t, err)
+	producer := mocks.NewSyncProducer(t, nil)
+	producer.ExpectSendMessageWithCheckerFunctionAndSucceed(func(val []byte) error {
+		assert.Equal(t, []byte("!@#"), val)
+		return nil
+	})
+	sink := &consumertest.TracesSink{}
+	c := tracesConsumerGroupHandler{
+		unmarshaler:     newPdataTracesUnmarshaler(&ptrace.ProtoUnmarshaler{}, defaultEncoding),
+		logger:          zap.NewNop(),
+		ready:           make(chan bool),
+		nextConsumer:    sink,
+		obsrecv:         obsrecv,
+		headerExtractor: &nopHeaderExtractor{},
+		messageMarking:  MessageMarking{After: true},
+		deadLetter:      &deadLetterPublisher{producer: producer, topic: "dead-letter"},
+	}
+
+	session := &markingConsumerGroupSession{testConsumerGroupSession: testConsumerGroupSession{ctx: context.Background()}}
+	wg := sync.WaitGroup{}
+	wg.Add(1)
+	groupClaim := &testConsumerGroupClaim{
+		messageChan: make(chan *sarama.ConsumerMessage),
+	}
+	go func() {
+		assert.NoError(t, c.ConsumeClaim(session, groupClaim))
+		wg.Done()
+	}()
+
+	bts, err := (&ptrace.ProtoMarshaler{}).MarshalTraces(ptrace.NewTraces())
+	require.NoError(t, err)
+	groupClaim.messageChan <- &sarama.ConsumerMessage{Offset: 1, Value: []byte("!@#")}
+	groupClaim.messageChan <- &sarama.ConsumerMessage{Offset: 2, Value: bts}
+	close(groupClaim.messageChan)
+	wg.Wait()
+
+	assert.Equal(t, []int64{1, 2}, session.markedOffsets())
+	assert.Len(t, sink.AllTraces(), 1)
+	require.NoError(t, producer.Close())
+}
+
+func TestLogsConsumerGroupHandler_errorBackOff(t *testing.T) {
+	obsrecv, err := receiverhelper.NewObsReport(receiverhelper.ObsReportSettings{ReceiverCreateSettings: receivertest.NewNopCreateSettings()})
+	require.NoError(t, err)
+	next := &flakyLogsConsumer{failures: 2}
+	c := logsConsumerGroupHandler{
+		unmarshaler:     newTextLogsUnmarshaler(),
+		logger:          zap.NewNop(),
+		ready:           make(chan bool),
+		nextConsumer:    next,
+		obsrecv:         obsrecv,
+		headerExtractor: &nopHeaderExtractor{},
+		messageMarking:  MessageMarking{After: true},
+		errorBackOff: ErrorBackOff{
+			Enabled:         true,
+			InitialInterval: time.Millisecond,
+			MaxInterval:     time.Millisecond,
+		},
+	}
+	c.unmarshaler, err = c.unmarshaler.(LogsUnmarshalerWithEnc).WithEnc("utf-8")
+	require.NoError(t, err)
+
+	session := &markingConsumerGroupSession{testConsumerGroupSession: testConsumerGroupSession{ctx: context.Background()}}
+	wg := sync.WaitGroup{}
+	wg.Add(1)
+	groupClaim := &testConsumerGroupClaim{
+		messageChan: make(chan *sarama.ConsumerMessage),
+	}
+	go func() {
+		assert.NoError(t, c.ConsumeClaim(session, groupClaim))
+		wg.Done()
+	}()
+
+	groupClaim.messageChan <- &sarama.ConsumerMessage{Offset: 7, Value: []byte("hello")}
+	close(groupClaim.messageChan)
+	wg.Wait()
+
+	assert.Equal(t, 3, next.calls)
+	assert.Equal(t, []int64{7}, session.markedOffsets())
+}
+
+// markingConsumerGroupSession records the offsets of the marked messages.
+type markingConsumerGroupSession struct {
+	testConsumerGroupSession
+	mu     sync.Mutex
+	marked []int64
+}
+
+func (s *markingConsumerGroupSession) MarkMessage(msg *sarama.ConsumerMessage, _ string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.marked = append(s.marked, msg.Offset)
+}
+
+func (s *markingConsumerGroupSession) markedOffsets() []int64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.marked
+}
+
+// flakyLogsConsumer fails with a non-permanent error the first failures calls.
+type flakyLogsConsumer struct {
+	consumertest.LogsSink
+	failures int
+	calls    int
+}
+
+func (c *flakyLogsConsumer) ConsumeLogs(ctx context.Context, ld plog.Logs) error {
+	c.calls++
+	if c.calls <= c.failures {
+		return errors.New("temporarily unavailable")
+	}
+	return c.LogsSink.ConsumeLogs(ctx, ld)
+}
@@ -0,0 +1,75 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package kafkareceiver // import "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/kafkareceiver"
+
+import (
+	"fmt"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/pdata/plog"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+)
+
+// loadEncodingExtension looks up the extension referenced by encoding in the host
+// and returns it as T. An error is returned if encoding is not a valid component ID,
+// if no such extension is configured or if it does not implement T.
+func loadEncodingExtension[T any](host component.Host, encoding string) (T, error) {
+	var zero T
+	var id component.ID
+	if err := id.UnmarshalText([]byte(encoding)); err != nil {
+		return zero, err
+	}
+	ext, ok := host.GetExtensions()[id]
+	if !ok {
+		return zero, fmt.Errorf("unknown encoding extension %q", encoding)
+	}
+	unmarshaler, ok := ext.(T)
+	if !ok {
+		return zero, fmt.Errorf("extension %q is not an unmarshaler for this signal", encoding)
+	}
+	return unmarshaler, nil
+}
+
+// loadTracesUnmarshaler returns the unmarshaler for the configured encoding. Encoding
+// extensions take precedence over the built-in unmarshalers. The error of the
+// lookup of the extension is returned when there is no built-in unmarshaler.
+func loadTracesUnmarshaler(host component.Host, encoding string, builtin TracesUnmarshaler) (TracesUnmarshaler, error) {
+	unmarshaler, err := loadEncodingExtension[ptrace.Unmarshaler](host, encoding)
+	if err == nil {
+		return newPdataTracesUnmarshaler(unmarshaler, encoding), nil
+	}
+	if builtin == nil {
+		return nil, fmt.Errorf("%w: %w", errUnrecognizedEncoding, err)
+	}
+	return builtin, nil
+}
+
+// loadMetricsUnmarshaler returns the unmarshaler for the configured encoding. Encoding
+// extensions take precedence over the built-in unmarshalers. The error of the
+// lookup of the extension is returned when there is no built-in unmarshaler.
+func loadMetricsUnmarshaler(host component.Host, encoding string, builtin MetricsUnmarshaler) (MetricsUnmarshaler, error) {
+	unmarshaler, err := loadEncodingExtension[pmetric.Unmarshaler](host, encoding)
+	if err == nil {
+		return newPdataMetricsUnmarshaler(unmarshaler, encoding), nil
+	}
+	if builtin == nil {
+		return nil, fmt.Errorf("%w: %w", errUnrecognizedEncoding, err)
+	}
+	return builtin, nil
+}
+
+// loadLogsUnmarshaler returns the unmarshaler for the configured encoding. Encoding
+// extensions take precedence over the built-in unmarshalers. The error of the
+// lookup of the extension is returned when there is no built-in unmarshaler.
+func loadLogsUnmarshaler(host component.Host, encoding string, builtin LogsUnmarshaler) (LogsUnmarshaler, error) {
+	unmarshaler, err := loadEncodingExtension[plog.Unmarshaler](host, encoding)
+	if err == nil {
+		return newPdataLogsUnmarshaler(unmarshaler, encoding), nil
+	}
+	if builtin == nil {
+		return nil, fmt.Errorf("%w: %w", errUnrecognizedEncoding, err)
+	}
+	return builtin, nil
+}
@@ -0,0 +1,124 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package kafkareceiver
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/consumer/consumertest"
+	"go.opentelemetry.io/collector/pdata/plog"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+	"go.opentelemetry.io/collector/receiver/receivertest"
+)
+
+type testEncodingExtension struct {
+	component.StartFunc
+	component.ShutdownFunc
+}
+
+func (testEncodingExtension) UnmarshalTraces(buf []byte) (ptrace.Traces, error) {
+	return (&ptrace.JSONUnmarshaler{}).UnmarshalTraces(buf)
+}
+
+func (testEncodingExtension) UnmarshalMetrics(buf []byte) (pmetric.Metrics, error) {
+	return (&pmetric.JSONUnmarshaler{}).UnmarshalMetrics(buf)
+}
+
+func (testEncodingExtension) UnmarshalLogs(buf []byte) (plog.Logs, error) {
+	return (&plog.JSONUnmarshaler{}).UnmarshalLogs(buf)
+}
+
+type testNopExtension struct {
+	component.StartFunc
+	component.ShutdownFunc
+}
+
+type testExtensionHost struct {
+	component.Host
+	extensions map[component.ID]component.Component
+}
+
+func (h testExtensionHost) GetExtensions() map[component.ID]component.Component {
+	return h.extensions
+}
+
+func newTestExtensionHost(id component.ID, ext component.Component) component.Host {
+	return testExtensionHost{
+		Host:       componenttest.NewNopHost(),
+		extensions: map[component.ID]component.Component{id: ext},
+	}
+}
+
+func TestLoadTracesUnmarshaler(t *testing.T) {
+	id := component.NewIDWithName("otlp_encoding", "json")
+	host := newTestExtensionHost(id, &testEncodingExtension{})
+
+	unmarshaler, err := loadTracesUnmarshaler(host, id.String(), nil)
+	require.NoError(t, err)
+	assert.Equal(t, "otlp_encoding/json", unmarshaler.Encoding())
+
+	td := ptrace.NewTraces()
+	td.ResourceSpans().AppendEmpty().ScopeSpans().AppendEmpty().Spans().AppendEmpty().SetName("span")
+	buf, err := (&ptrace.JSONMarshaler{}).MarshalTraces(td)
+	require.NoError(t, err)
+	got, err := unmarshaler.Unmarshal(buf)
+	require.NoError(t, err)
+	assert.Equal(t, td, got)
+
+	builtin := defaultTracesUnmarshalers()[defaultEncoding]
+	unmarshaler, err = loadTracesUnmarshaler(host, defaultEncoding, builtin)
+	require.NoError(t, err)
+	assert.Equal(t, builtin, unmarshaler)
+
+	_, err = loadTracesUnmarshaler(host, "otlp_encoding/missing", nil)
+	assert.ErrorIs(t, err, errUnrecognizedEncoding)
+	assert.EqualError(t, err, `unrecognized encoding: unknown encoding extension "otlp_encoding/missing"`)
+}
+
+func TestLoadMetricsUnmarshaler(t *testing.T) {
+	id := component.NewID("otlp_encoding")
+	host := newTestExtensionHost(id, &testEncodingExtension{})
+
+	unmarshaler, err := loadMetricsUnmarshaler(host, id.String(), nil)
+	require.NoError(t, err)
+	assert.Equal(t, "otlp_encoding", unmarshaler.Encoding())
+
+	_, err = loadMetricsUnmarshaler(host, "foo", nil)
+	assert.ErrorIs(t, err, errUnrecognizedEncoding)
+	assert.EqualError(t, err, `unrecognized encoding: unknown encoding extension "foo"`)
+}
+
+func TestLoadLogsUnmarshaler(t *testing.T) {
+	id := component.NewID("text_encoding")
+	host := newTestExtensionHost(id, &testEncodingExtension{})
+
+	unmarshaler, err := loadLogsUnmarshaler(host, id.String(), nil)
+	require.NoError(t, err)
+	assert.Equal(t, "text_encoding", unmarshaler.Encoding())
+
+	// extensions that don't implement the signal are reported.
+	host = newTestExtensionHost(id, &testNopExtension{})
+	_, err = loadLogsUnmarshaler(host, id.String(), nil)
+	assert.ErrorIs(t, err, errUnrecognizedEncoding)
+	assert.EqualError(t, err, `unrecognized encoding: extension "`+id.String()+`" is not an unmarshaler for this signal`)
+}
+
+func TestLogsReceiverStart_encodingExtension(t *testing.T) {
+	id := component.NewID("otlp_encoding")
+	c := kafkaLogsConsumer{
+		nextConsumer:  consumertest.NewNop(),
+		settings:      receivertest.NewNopCreateSettings(),
+		consumerGroup: &testConsumerGroup{},
+		encoding:      id.String(),
+	}
+	require.NoError(t, c.Start(context.Background(), newTestExtensionHost(id, &testEncodingExtension{})))
+	assert.Equal(t, id.String(), c.unmarshaler.Encoding())
+	require.NoError(t, c.Shutdown(context.Background()))
+}
@@ -35,6 +35,10 @@ const (
 	defaultAutoCommitEnable = true
 	// default from sarama.NewConfig()
 	defaultAutoCommitInterval = 1 * time.Second
+
+	defaultErrorBackOffInitialInterval = 500 * time.Millisecond
+	defaultErrorBackOffMaxInterval     = 10 * time.Second
+	defaultErrorBackOffMaxElapsedTime  = time.Minute
 )
 
 // FactoryOption applies changes to kafkaExporterFactory.
@@ -114,6 +118,12 @@ func createDefaultConfig() component.Config {
 		HeaderExtraction: HeaderExtraction{
 			ExtractHeaders: false,
 		},
+		ErrorBackOff: ErrorBackOff{
+			Enabled:         false,
+			InitialInterval: defaultErrorBackOffInitialInterval,
+			MaxInterval:     defaultErrorBackOffMaxInterval,
+			MaxElapsedTime:  defaultErrorBackOffMaxElapsedTime,
+		},
 	}
 }
 
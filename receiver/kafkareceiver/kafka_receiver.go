@@ -5,6 +5,7 @@ package kafkareceiver // import "github.com/open-telemetry/opentelemetry-collect
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"strings"
 	"sync"
@@ -35,6 +36,7 @@ type kafkaTracesConsumer struct {
 	topics            []string
 	cancelConsumeLoop context.CancelFunc
 	unmarshaler       TracesUnmarshaler
+	encoding          string
 
 	settings receiver.CreateSettings
 
@@ -42,6 +44,8 @@ type kafkaTracesConsumer struct {
 	messageMarking    MessageMarking
 	headerExtraction  bool
 	headers           []string
+	errorBackOff      ErrorBackOff
+	deadLetter        *deadLetterPublisher
 }
 
 // kafkaMetricsConsumer uses sarama to consume and handle messages from kafka.
@@ -51,6 +55,7 @@ type kafkaMetricsConsumer struct {
 	topics            []string
 	cancelConsumeLoop context.CancelFunc
 	unmarshaler       MetricsUnmarshaler
+	encoding          string
 
 	settings receiver.CreateSettings
 
@@ -58,6 +63,8 @@ type kafkaMetricsConsumer struct {
 	messageMarking    MessageMarking
 	headerExtraction  bool
 	headers           []string
+	errorBackOff      ErrorBackOff
+	deadLetter        *deadLetterPublisher
 }
 
 // kafkaLogsConsumer uses sarama to consume and handle messages from kafka.
@@ -67,6 +74,7 @@ type kafkaLogsConsumer struct {
 	topics            []string
 	cancelConsumeLoop context.CancelFunc
 	unmarshaler       LogsUnmarshaler
+	encoding          string
 
 	settings receiver.CreateSettings
 
@@ -74,6 +82,8 @@ type kafkaLogsConsumer struct {
 	messageMarking    MessageMarking
 	headerExtraction  bool
 	headers           []string
+	errorBackOff      ErrorBackOff
+	deadLetter        *deadLetterPublisher
 }
 
 var _ receiver.Traces = (*kafkaTracesConsumer)(nil)
@@ -81,10 +91,9 @@ var _ receiver.Metrics = (*kafkaMetricsConsumer)(nil)
 var _ receiver.Logs = (*kafkaLogsConsumer)(nil)
 
 func newTracesReceiver(config Config, set receiver.CreateSettings, unmarshalers map[string]TracesUnmarshaler, nextConsumer consumer.Traces) (*kafkaTracesConsumer, error) {
+	// The unmarshaler may be nil here if the encoding refers to an encoding
+	// extension, it is resolved when the receiver is started.
 	unmarshaler := unmarshalers[config.Encoding]
-	if unmarshaler == nil {
-		return nil, errUnrecognizedEncoding
-	}
 
 	c := sarama.NewConfig()
 	c.ClientID = config.ClientID
@@ -111,24 +120,36 @@ func newTracesReceiver(config Config, set receiver.CreateSettings, unmarshalers
 	if err := kafka.ConfigureAuthentication(config.Authentication, c); err != nil {
 		return nil, err
 	}
-	client, err := sarama.NewConsumerGroup(config.Brokers, config.GroupID, c)
+	deadLetter, err := newDeadLetterPublisher(config, set.ID, c)
 	if err != nil {
 		return nil, err
 	}
+	client, err := sarama.NewConsumerGroup(config.Brokers, config.GroupID, c)
+	if err != nil {
+		return nil, errors.Join(err, deadLetter.close())
+	}
 	return &kafkaTracesConsumer{
 		consumerGroup:     client,
 		topics:            []string{config.Topic},
 		nextConsumer:      nextConsumer,
 		unmarshaler:       unmarshaler,
+		encoding:          config.Encoding,
 		settings:          set,
 		autocommitEnabled: config.AutoCommit.Enable,
 		messageMarking:    config.MessageMarking,
 		headerExtraction:  config.HeaderExtraction.ExtractHeaders,
 		headers:           config.HeaderExtraction.Headers,
+		errorBackOff:      config.ErrorBackOff,
+		deadLetter:        deadLetter,
 	}, nil
 }
 
 func (c *kafkaTracesConsumer) Start(_ context.Context, host component.Host) error {
+	unmarshaler, err := loadTracesUnmarshaler(host, c.encoding, c.unmarshaler)
+	if err != nil {
+		return err
+	}
+	c.unmarshaler = unmarshaler
 	ctx, cancel := context.WithCancel(context.Background())
 	c.cancelConsumeLoop = cancel
 	obsrecv, err := receiverhelper.NewObsReport(receiverhelper.ObsReportSettings{
@@ -148,6 +169,8 @@ func (c *kafkaTracesConsumer) Start(_ context.Context, host component.Host) erro
 		autocommitEnabled: c.autocommitEnabled,
 		messageMarking:    c.messageMarking,
 		headerExtractor:   &nopHeaderExtractor{},
+		errorBackOff:      c.errorBackOff,
+		deadLetter:        c.deadLetter,
 	}
 	if c.headerExtraction {
 		consumerGroup.headerExtractor = &headerExtractor{
@@ -182,14 +205,13 @@ func (c *kafkaTracesConsumer) consumeLoop(ctx context.Context, handler sarama.Co
 
 func (c *kafkaTracesConsumer) Shutdown(context.Context) error {
 	c.cancelConsumeLoop()
-	return c.consumerGroup.Close()
+	return errors.Join(c.consumerGroup.Close(), c.deadLetter.close())
 }
 
 func newMetricsReceiver(config Config, set receiver.CreateSettings, unmarshalers map[string]MetricsUnmarshaler, nextConsumer consumer.Metrics) (*kafkaMetricsConsumer, error) {
+	// The unmarshaler may be nil here if the encoding refers to an encoding
+	// extension, it is resolved when the receiver is started.
 	unmarshaler := unmarshalers[config.Encoding]
-	if unmarshaler == nil {
-		return nil, errUnrecognizedEncoding
-	}
 
 	c := sarama.NewConfig()
 	c.ClientID = config.ClientID
@@ -213,24 +235,36 @@ func newMetricsReceiver(config Config, set receiver.CreateSettings, unmarshalers
 	if err := kafka.ConfigureAuthentication(config.Authentication, c); err != nil {
 		return nil, err
 	}
-	client, err := sarama.NewConsumerGroup(config.Brokers, config.GroupID, c)
+	deadLetter, err := newDeadLetterPublisher(config, set.ID, c)
 	if err != nil {
 		return nil, err
 	}
+	client, err := sarama.NewConsumerGroup(config.Brokers, config.GroupID, c)
+	if err != nil {
+		return nil, errors.Join(err, deadLetter.close())
+	}
 	return &kafkaMetricsConsumer{
 		consumerGroup:     client,
 		topics:            []string{config.Topic},
 		nextConsumer:      nextConsumer,
 		unmarshaler:       unmarshaler,
+		encoding:          config.Encoding,
 		settings:          set,
 		autocommitEnabled: config.AutoCommit.Enable,
 		messageMarking:    config.MessageMarking,
 		headerExtraction:  config.HeaderExtraction.ExtractHeaders,
 		headers:           config.HeaderExtraction.Headers,
+		errorBackOff:      config.ErrorBackOff,
+		deadLetter:        deadLetter,
 	}, nil
 }
 
 func (c *kafkaMetricsConsumer) Start(_ context.Context, host component.Host) error {
+	unmarshaler, err := loadMetricsUnmarshaler(host, c.encoding, c.unmarshaler)
+	if err != nil {
+		return err
+	}
+	c.unmarshaler = unmarshaler
 	ctx, cancel := context.WithCancel(context.Background())
 	c.cancelConsumeLoop = cancel
 	obsrecv, err := receiverhelper.NewObsReport(receiverhelper.ObsReportSettings{
@@ -250,6 +284,8 @@ func (c *kafkaMetricsConsumer) Start(_ context.Context, host component.Host) err
 		autocommitEnabled: c.autocommitEnabled,
 		messageMarking:    c.messageMarking,
 		headerExtractor:   &nopHeaderExtractor{},
+		errorBackOff:      c.errorBackOff,
+		deadLetter:        c.deadLetter,
 	}
 	if c.headerExtraction {
 		metricsConsumerGroup.headerExtractor = &headerExtractor{
@@ -284,7 +320,7 @@ func (c *kafkaMetricsConsumer) consumeLoop(ctx context.Context, handler sarama.C
 
 func (c *kafkaMetricsConsumer) Shutdown(context.Context) error {
 	c.cancelConsumeLoop()
-	return c.consumerGroup.Close()
+	return errors.Join(c.consumerGroup.Close(), c.deadLetter.close())
 }
 
 func newLogsReceiver(config Config, set receiver.CreateSettings, unmarshalers map[string]LogsUnmarshaler, nextConsumer consumer.Logs) (*kafkaLogsConsumer, error) {
@@ -300,8 +336,10 @@ func newLogsReceiver(config Config, set receiver.CreateSettings, unmarshalers ma
 	} else {
 		return nil, err
 	}
+	// The unmarshaler may be nil here if the encoding refers to an encoding
+	// extension, it is resolved when the receiver is started.
 	unmarshaler, err := getLogsUnmarshaler(config.Encoding, unmarshalers)
-	if err != nil {
+	if err != nil && !errors.Is(err, errUnrecognizedEncoding) {
 		return nil, err
 	}
 	if config.ProtocolVersion != "" {
@@ -315,20 +353,27 @@ func newLogsReceiver(config Config, set receiver.CreateSettings, unmarshalers ma
 	if err = kafka.ConfigureAuthentication(config.Authentication, c); err != nil {
 		return nil, err
 	}
-	client, err := sarama.NewConsumerGroup(config.Brokers, config.GroupID, c)
+	deadLetter, err := newDeadLetterPublisher(config, set.ID, c)
 	if err != nil {
 		return nil, err
 	}
+	client, err := sarama.NewConsumerGroup(config.Brokers, config.GroupID, c)
+	if err != nil {
+		return nil, errors.Join(err, deadLetter.close())
+	}
 	return &kafkaLogsConsumer{
 		consumerGroup:     client,
 		topics:            []string{config.Topic},
 		nextConsumer:      nextConsumer,
 		unmarshaler:       unmarshaler,
+		encoding:          config.Encoding,
 		settings:          set,
 		autocommitEnabled: config.AutoCommit.Enable,
 		messageMarking:    config.MessageMarking,
 		headerExtraction:  config.HeaderExtraction.ExtractHeaders,
 		headers:           config.HeaderExtraction.Headers,
+		errorBackOff:      config.ErrorBackOff,
+		deadLetter:        deadLetter,
 	}, nil
 }
 
@@ -360,6 +405,11 @@ func getLogsUnmarshaler(encoding string, unmarshalers map[string]LogsUnmarshaler
 }
 
 func (c *kafkaLogsConsumer) Start(_ context.Context, host component.Host) error {
+	unmarshaler, err := loadLogsUnmarshaler(host, c.encoding, c.unmarshaler)
+	if err != nil {
+		return err
+	}
+	c.unmarshaler = unmarshaler
 	ctx, cancel := context.WithCancel(context.Background())
 	c.cancelConsumeLoop = cancel
 	obsrecv, err := receiverhelper.NewObsReport(receiverhelper.ObsReportSettings{
@@ -380,6 +430,8 @@ func (c *kafkaLogsConsumer) Start(_ context.Context, host component.Host) error
 		autocommitEnabled: c.autocommitEnabled,
 		messageMarking:    c.messageMarking,
 		headerExtractor:   &nopHeaderExtractor{},
+		errorBackOff:      c.errorBackOff,
+		deadLetter:        c.deadLetter,
 	}
 	if c.headerExtraction {
 		logsConsumerGroup.headerExtractor = &headerExtractor{
@@ -414,7 +466,7 @@ func (c *kafkaLogsConsumer) consumeLoop(ctx context.Context, handler sarama.Cons
 
 func (c *kafkaLogsConsumer) Shutdown(context.Context) error {
 	c.cancelConsumeLoop()
-	return c.consumerGroup.Close()
+	return errors.Join(c.consumerGroup.Close(), c.deadLetter.close())
 }
 
 type tracesConsumerGroupHandler struct {
@@ -431,6 +483,8 @@ type tracesConsumerGroupHandler struct {
 	autocommitEnabled bool
 	messageMarking    MessageMarking
 	headerExtractor   HeaderExtractor
+	errorBackOff      ErrorBackOff
+	deadLetter        *deadLetterPublisher
 }
 
 type metricsConsumerGroupHandler struct {
@@ -447,6 +501,8 @@ type metricsConsumerGroupHandler struct {
 	autocommitEnabled bool
 	messageMarking    MessageMarking
 	headerExtractor   HeaderExtractor
+	errorBackOff      ErrorBackOff
+	deadLetter        *deadLetterPublisher
 }
 
 type logsConsumerGroupHandler struct {
@@ -463,6 +519,8 @@ type logsConsumerGroupHandler struct {
 	autocommitEnabled bool
 	messageMarking    MessageMarking
 	headerExtractor   HeaderExtractor
+	errorBackOff      ErrorBackOff
+	deadLetter        *deadLetterPublisher
 }
 
 var _ sarama.ConsumerGroupHandler = (*tracesConsumerGroupHandler)(nil)
@@ -513,23 +571,26 @@ func (c *tracesConsumerGroupHandler) ConsumeClaim(session sarama.ConsumerGroupSe
 			traces, err := c.unmarshaler.Unmarshal(message.Value)
 			if err != nil {
 				c.logger.Error("failed to unmarshal message", zap.Error(err))
-				if c.messageMarking.After && c.messageMarking.OnError {
-					session.MarkMessage(message, "")
+				if err = handleMessageError(session, message, err, c.messageMarking, c.deadLetter, c.logger); err != nil {
+					return err
+				}
+				if !c.autocommitEnabled {
+					session.Commit()
 				}
-				return err
+				continue
 			}
 
 			c.headerExtractor.extractHeadersTraces(traces, message)
 			spanCount := traces.SpanCount()
-			err = c.nextConsumer.ConsumeTraces(session.Context(), traces)
+			err = consumeWithBackOff(session.Context(), c.errorBackOff, func() error {
+				return c.nextConsumer.ConsumeTraces(session.Context(), traces)
+			})
 			c.obsrecv.EndTracesOp(ctx, c.unmarshaler.Encoding(), spanCount, err)
 			if err != nil {
-				if c.messageMarking.After && c.messageMarking.OnError {
-					session.MarkMessage(message, "")
+				if err = handleMessageError(session, message, err, c.messageMarking, c.deadLetter, c.logger); err != nil {
+					return err
 				}
-				return err
-			}
-			if c.messageMarking.After {
+			} else if c.messageMarking.After {
 				session.MarkMessage(message, "")
 			}
 			if !c.autocommitEnabled {
@@ -589,23 +650,26 @@ func (c *metricsConsumerGroupHandler) ConsumeClaim(session sarama.ConsumerGroupS
 			metrics, err := c.unmarshaler.Unmarshal(message.Value)
 			if err != nil {
 				c.logger.Error("failed to unmarshal message", zap.Error(err))
-				if c.messageMarking.After && c.messageMarking.OnError {
-					session.MarkMessage(message, "")
+				if err = handleMessageError(session, message, err, c.messageMarking, c.deadLetter, c.logger); err != nil {
+					return err
+				}
+				if !c.autocommitEnabled {
+					session.Commit()
 				}
-				return err
+				continue
 			}
 			c.headerExtractor.extractHeadersMetrics(metrics, message)
 
 			dataPointCount := metrics.DataPointCount()
-			err = c.nextConsumer.ConsumeMetrics(session.Context(), metrics)
+			err = consumeWithBackOff(session.Context(), c.errorBackOff, func() error {
+				return c.nextConsumer.ConsumeMetrics(session.Context(), metrics)
+			})
 			c.obsrecv.EndMetricsOp(ctx, c.unmarshaler.Encoding(), dataPointCount, err)
 			if err != nil {
-				if c.messageMarking.After && c.messageMarking.OnError {
-					session.MarkMessage(message, "")
+				if err = handleMessageError(session, message, err, c.messageMarking, c.deadLetter, c.logger); err != nil {
+					return err
 				}
-				return err
-			}
-			if c.messageMarking.After {
+			} else if c.messageMarking.After {
 				session.MarkMessage(message, "")
 			}
 			if !c.autocommitEnabled {
@@ -670,22 +734,25 @@ func (c *logsConsumerGroupHandler) ConsumeClaim(session sarama.ConsumerGroupSess
 			logs, err := c.unmarshaler.Unmarshal(message.Value)
 			if err != nil {
 				c.logger.Error("failed to unmarshal message", zap.Error(err))
-				if c.messageMarking.After && c.messageMarking.OnError {
-					session.MarkMessage(message, "")
+				if err = handleMessageError(session, message, err, c.messageMarking, c.deadLetter, c.logger); err != nil {
+					return err
+				}
+				if !c.autocommitEnabled {
+					session.Commit()
 				}
-				return err
+				continue
 			}
 			c.headerExtractor.extractHeadersLogs(logs, message)
 			logRecordCount := logs.LogRecordCount()
-			err = c.nextConsumer.ConsumeLogs(session.Context(), logs)
+			err = consumeWithBackOff(session.Context(), c.errorBackOff, func() error {
+				return c.nextConsumer.ConsumeLogs(session.Context(), logs)
+			})
 			c.obsrecv.EndLogsOp(ctx, c.unmarshaler.Encoding(), logRecordCount, err)
 			if err != nil {
-				if c.messageMarking.After && c.messageMarking.OnError {
-					session.MarkMessage(message, "")
+				if err = handleMessageError(session, message, err, c.messageMarking, c.deadLetter, c.logger); err != nil {
+					return err
 				}
-				return err
-			}
-			if c.messageMarking.After {
+			} else if c.messageMarking.After {
 				session.MarkMessage(message, "")
 			}
 			if !c.autocommitEnabled {
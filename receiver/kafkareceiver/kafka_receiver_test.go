@@ -43,14 +43,16 @@ func TestNewTracesReceiver_version_err(t *testing.T) {
 	assert.Nil(t, r)
 }
 
-func TestNewTracesReceiver_encoding_err(t *testing.T) {
-	c := Config{
-		Encoding: "foo",
+func TestTracesReceiver_encoding_err(t *testing.T) {
+	c := kafkaTracesConsumer{
+		nextConsumer:  consumertest.NewNop(),
+		settings:      receivertest.NewNopCreateSettings(),
+		consumerGroup: &testConsumerGroup{},
+		encoding:      "foo",
 	}
-	r, err := newTracesReceiver(c, receivertest.NewNopCreateSettings(), defaultTracesUnmarshalers(), consumertest.NewNop())
-	require.Error(t, err)
-	assert.Nil(t, r)
-	assert.EqualError(t, err, errUnrecognizedEncoding.Error())
+	err := c.Start(context.Background(), componenttest.NewNopHost())
+	assert.ErrorIs(t, err, errUnrecognizedEncoding)
+	assert.EqualError(t, err, `unrecognized encoding: unknown encoding extension "foo"`)
 }
 
 func TestNewTracesReceiver_err_auth_type(t *testing.T) {
@@ -90,6 +92,7 @@ func TestTracesReceiverStart(t *testing.T) {
 		nextConsumer:  consumertest.NewNop(),
 		settings:      receivertest.NewNopCreateSettings(),
 		consumerGroup: &testConsumerGroup{},
+		unmarshaler:   defaultTracesUnmarshalers()[defaultEncoding],
 	}
 
 	require.NoError(t, c.Start(context.Background(), componenttest.NewNopHost()))
@@ -122,6 +125,7 @@ func TestTracesReceiver_error(t *testing.T) {
 		nextConsumer:  consumertest.NewNop(),
 		settings:      settings,
 		consumerGroup: &testConsumerGroup{err: expectedErr},
+		unmarshaler:   defaultTracesUnmarshalers()[defaultEncoding],
 	}
 
 	require.NoError(t, c.Start(context.Background(), componenttest.NewNopHost()))
@@ -304,14 +308,16 @@ func TestNewMetricsReceiver_version_err(t *testing.T) {
 	assert.Nil(t, r)
 }
 
-func TestNewMetricsReceiver_encoding_err(t *testing.T) {
-	c := Config{
-		Encoding: "foo",
+func TestMetricsReceiver_encoding_err(t *testing.T) {
+	c := kafkaMetricsConsumer{
+		nextConsumer:  consumertest.NewNop(),
+		settings:      receivertest.NewNopCreateSettings(),
+		consumerGroup: &testConsumerGroup{},
+		encoding:      "foo",
 	}
-	r, err := newMetricsReceiver(c, receivertest.NewNopCreateSettings(), defaultMetricsUnmarshalers(), consumertest.NewNop())
-	require.Error(t, err)
-	assert.Nil(t, r)
-	assert.EqualError(t, err, errUnrecognizedEncoding.Error())
+	err := c.Start(context.Background(), componenttest.NewNopHost())
+	assert.ErrorIs(t, err, errUnrecognizedEncoding)
+	assert.EqualError(t, err, `unrecognized encoding: unknown encoding extension "foo"`)
 }
 
 func TestNewMetricsExporter_err_auth_type(t *testing.T) {
@@ -351,6 +357,7 @@ func TestMetricsReceiverStart(t *testing.T) {
 		nextConsumer:  consumertest.NewNop(),
 		settings:      receivertest.NewNopCreateSettings(),
 		consumerGroup: &testConsumerGroup{},
+		unmarshaler:   defaultMetricsUnmarshalers()[defaultEncoding],
 	}
 
 	require.NoError(t, c.Start(context.Background(), componenttest.NewNopHost()))
@@ -383,6 +390,7 @@ func TestMetricsReceiver_error(t *testing.T) {
 		nextConsumer:  consumertest.NewNop(),
 		settings:      settings,
 		consumerGroup: &testConsumerGroup{err: expectedErr},
+		unmarshaler:   defaultMetricsUnmarshalers()[defaultEncoding],
 	}
 
 	require.NoError(t, c.Start(context.Background(), componenttest.NewNopHost()))
@@ -563,14 +571,16 @@ func TestNewLogsReceiver_version_err(t *testing.T) {
 	assert.Nil(t, r)
 }
 
-func TestNewLogsReceiver_encoding_err(t *testing.T) {
-	c := Config{
-		Encoding: "foo",
+func TestLogsReceiver_encoding_err(t *testing.T) {
+	c := kafkaLogsConsumer{
+		nextConsumer:  consumertest.NewNop(),
+		settings:      receivertest.NewNopCreateSettings(),
+		consumerGroup: &testConsumerGroup{},
+		encoding:      "foo",
 	}
-	r, err := newLogsReceiver(c, receivertest.NewNopCreateSettings(), defaultLogsUnmarshalers(), consumertest.NewNop())
-	require.Error(t, err)
-	assert.Nil(t, r)
-	assert.EqualError(t, err, errUnrecognizedEncoding.Error())
+	err := c.Start(context.Background(), componenttest.NewNopHost())
+	assert.ErrorIs(t, err, errUnrecognizedEncoding)
+	assert.EqualError(t, err, `unrecognized encoding: unknown encoding extension "foo"`)
 }
 
 func TestNewLogsExporter_err_auth_type(t *testing.T) {
@@ -610,6 +620,7 @@ func TestLogsReceiverStart(t *testing.T) {
 		nextConsumer:  consumertest.NewNop(),
 		settings:      receivertest.NewNopCreateSettings(),
 		consumerGroup: &testConsumerGroup{},
+		unmarshaler:   defaultLogsUnmarshalers()[defaultEncoding],
 	}
 
 	require.NoError(t, c.Start(context.Background(), componenttest.NewNopHost()))
@@ -642,6 +653,7 @@ func TestLogsReceiver_error(t *testing.T) {
 		nextConsumer:  consumertest.NewNop(),
 		settings:      settings,
 		consumerGroup: &testConsumerGroup{err: expectedErr},
+		unmarshaler:   defaultLogsUnmarshalers()[defaultEncoding],
 	}
 
 	require.NoError(t, c.Start(context.Background(), componenttest.NewNopHost()))
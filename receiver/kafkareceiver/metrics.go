@@ -18,6 +18,8 @@ var (
 
 	statPartitionStart = stats.Int64("kafka_receiver_partition_start", "Number of started partitions", stats.UnitDimensionless)
 	statPartitionClose = stats.Int64("kafka_receiver_partition_close", "Number of finished partitions", stats.UnitDimensionless)
+
+	statDeadLetterMessages = stats.Int64("kafka_receiver_dead_letter_messages", "Number of messages published to the dead-letter topic", stats.UnitDimensionless)
 )
 
 // metricViews return metric views for Kafka receiver.
@@ -64,11 +66,20 @@ func metricViews() []*view.View {
 		Aggregation: view.Sum(),
 	}
 
+	countDeadLetterMessages := &view.View{
+		Name:        statDeadLetterMessages.Name(),
+		Measure:     statDeadLetterMessages,
+		Description: statDeadLetterMessages.Description(),
+		TagKeys:     tagKeys,
+		Aggregation: view.Sum(),
+	}
+
 	return []*view.View{
 		countMessages,
 		lastValueOffset,
 		lastValueOffsetLag,
 		countPartitionStart,
 		countPartitionClose,
+		countDeadLetterMessages,
 	}
 }
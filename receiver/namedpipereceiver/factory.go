@@ -0,0 +1,13 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package namedpipereceiver // import "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/namedpipereceiver"
+
+import (
+	"go.opentelemetry.io/collector/receiver"
+)
+
+// NewFactory creates a factory for named pipe receiver
+func NewFactory() receiver.Factory {
+	return newFactoryAdapter()
+}
@@ -0,0 +1,57 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build linux
+// +build linux
+
+package namedpipereceiver // import "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/namedpipereceiver"
+
+import (
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/receiver"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/adapter"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/operator"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/operator/input/namedpipe"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/namedpipereceiver/internal/metadata"
+)
+
+// newFactoryAdapter creates a factory for named pipe receiver
+func newFactoryAdapter() receiver.Factory {
+	return adapter.NewFactory(ReceiverType{}, metadata.LogsStability)
+}
+
+// ReceiverType implements adapter.LogReceiverType
+// to create a named pipe receiver
+type ReceiverType struct{}
+
+// Type is the receiver type
+func (f ReceiverType) Type() component.Type {
+	return metadata.Type
+}
+
+// CreateDefaultConfig creates a config with type and version
+func (f ReceiverType) CreateDefaultConfig() component.Config {
+	return &NamedPipeConfig{
+		BaseConfig: adapter.BaseConfig{
+			Operators: []operator.Config{},
+		},
+		InputConfig: *namedpipe.NewConfig(),
+	}
+}
+
+// BaseConfig gets the base config from config, for now
+func (f ReceiverType) BaseConfig(cfg component.Config) adapter.BaseConfig {
+	return cfg.(*NamedPipeConfig).BaseConfig
+}
+
+// NamedPipeConfig defines configuration for the named pipe receiver
+type NamedPipeConfig struct {
+	InputConfig        namedpipe.Config `mapstructure:",squash"`
+	adapter.BaseConfig `mapstructure:",squash"`
+}
+
+// InputConfig unmarshals the input operator
+func (f ReceiverType) InputConfig(cfg component.Config) operator.Config {
+	return operator.NewConfig(&cfg.(*NamedPipeConfig).InputConfig)
+}
@@ -0,0 +1,49 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build !linux
+// +build !linux
+
+package namedpipereceiver // import "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/namedpipereceiver"
+
+import (
+	"context"
+	"fmt"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/receiver"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/adapter"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/operator"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/namedpipereceiver/internal/metadata"
+)
+
+// newFactoryAdapter creates a dummy factory.
+func newFactoryAdapter() receiver.Factory {
+	return receiver.NewFactory(
+		metadata.Type,
+		createDefaultConfig,
+		receiver.WithLogs(createLogsReceiver, metadata.LogsStability))
+}
+
+type NamedPipeConfig struct {
+	adapter.BaseConfig `mapstructure:",squash"`
+}
+
+func createDefaultConfig() component.Config {
+	return &NamedPipeConfig{
+		BaseConfig: adapter.BaseConfig{
+			Operators: []operator.Config{},
+		},
+	}
+}
+
+func createLogsReceiver(
+	_ context.Context,
+	_ receiver.CreateSettings,
+	_ component.Config,
+	_ consumer.Logs,
+) (receiver.Logs, error) {
+	return nil, fmt.Errorf("named pipes are only supported on linux")
+}
@@ -0,0 +1,114 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build linux
+// +build linux
+
+package namedpipereceiver
+
+import (
+	"context"
+	"encoding/binary"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/confmap/confmaptest"
+	"go.opentelemetry.io/collector/consumer/consumertest"
+	"go.opentelemetry.io/collector/receiver/receivertest"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/adapter"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/operator"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/operator/input/namedpipe"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/split"
+)
+
+func TestNamedPipe(t *testing.T) {
+	cfg := testdataConfigYaml(filepath.Join(t.TempDir(), "logs.pipe"))
+
+	f := NewFactory()
+	sink := new(consumertest.LogsSink)
+	rcvr, err := f.CreateLogsReceiver(context.Background(), receivertest.NewNopCreateSettings(), cfg, sink)
+	require.NoError(t, err)
+	require.NoError(t, rcvr.Start(context.Background(), componenttest.NewNopHost()))
+
+	pipe, err := os.OpenFile(cfg.InputConfig.Path, os.O_WRONLY, 0)
+	require.NoError(t, err)
+
+	expectedLogs := []string{"first log", "second log\nwith a newline"}
+	for _, log := range expectedLogs {
+		_, err = pipe.Write(append(binary.BigEndian.AppendUint32(nil, uint32(len(log))), log...))
+		require.NoError(t, err)
+	}
+	require.NoError(t, pipe.Close())
+
+	require.Eventually(t, expectNLogs(sink, len(expectedLogs)), 2*time.Second, time.Millisecond)
+	require.NoError(t, rcvr.Shutdown(context.Background()))
+
+	var bodies []string
+	for _, logs := range sink.AllLogs() {
+		records := logs.ResourceLogs().At(0).ScopeLogs().At(0).LogRecords()
+		for i := 0; i < records.Len(); i++ {
+			bodies = append(bodies, records.At(i).Body().Str())
+		}
+	}
+	assert.Equal(t, expectedLogs, bodies)
+}
+
+func TestLoadConfig(t *testing.T) {
+	cm, err := confmaptest.LoadConf(filepath.Join("testdata", "config.yaml"))
+	require.NoError(t, err)
+	factory := NewFactory()
+	cfg := factory.CreateDefaultConfig()
+
+	sub, err := cm.Sub("namedpipe")
+	require.NoError(t, err)
+	require.NoError(t, component.UnmarshalConfig(sub, cfg))
+
+	assert.NoError(t, component.ValidateConfig(cfg))
+	assert.Equal(t, testdataConfigYaml("/var/run/app/logs.pipe"), cfg)
+}
+
+func testdataConfigYaml(path string) *NamedPipeConfig {
+	return &NamedPipeConfig{
+		BaseConfig: adapter.BaseConfig{
+			Operators: []operator.Config{},
+		},
+		InputConfig: func() namedpipe.Config {
+			c := namedpipe.NewConfig()
+			c.Path = path
+			c.Permissions = 0600
+			c.Framing = split.FramingLengthPrefixed
+			return *c
+		}(),
+	}
+}
+
+func TestDecodeInputConfigFailure(t *testing.T) {
+	sink := new(consumertest.LogsSink)
+	factory := NewFactory()
+	badCfg := &NamedPipeConfig{
+		BaseConfig: adapter.BaseConfig{
+			Operators: []operator.Config{},
+		},
+		InputConfig: func() namedpipe.Config {
+			c := namedpipe.NewConfig()
+			c.Framing = "fake"
+			return *c
+		}(),
+	}
+	receiver, err := factory.CreateLogsReceiver(context.Background(), receivertest.NewNopCreateSettings(), badCfg, sink)
+	require.Error(t, err, "receiver creation should fail if input config isn't valid")
+	require.Nil(t, receiver, "receiver creation should fail if input config isn't valid")
+}
+
+func expectNLogs(sink *consumertest.LogsSink, expected int) func() bool {
+	return func() bool {
+		return sink.LogRecordCount() == expected
+	}
+}
@@ -16,6 +16,7 @@ import (
 
 	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/adapter"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/fileconsumer"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/fileconsumer/emit"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/otlpjsonfilereceiver/internal/metadata"
 )
 
@@ -85,6 +86,7 @@ func createLogsReceiver(_ context.Context, settings receiver.CreateSettings, con
 				err = logs.ConsumeLogs(ctx, l)
 			}
 			obsrecv.EndLogsOp(ctx, metadata.Type, logRecordCount, err)
+			ack(ctx, err)
 		}
 		return nil
 	})
@@ -117,6 +119,7 @@ func createMetricsReceiver(_ context.Context, settings receiver.CreateSettings,
 				err = metrics.ConsumeMetrics(ctx, m)
 			}
 			obsrecv.EndMetricsOp(ctx, metadata.Type, m.MetricCount(), err)
+			ack(ctx, err)
 		}
 		return nil
 	})
@@ -149,6 +152,7 @@ func createTracesReceiver(_ context.Context, settings receiver.CreateSettings, c
 				err = traces.ConsumeTraces(ctx, t)
 			}
 			obsrecv.EndTracesOp(ctx, metadata.Type, t.SpanCount(), err)
+			ack(ctx, err)
 		}
 		return nil
 	})
@@ -158,3 +162,13 @@ func createTracesReceiver(_ context.Context, settings receiver.CreateSettings, c
 
 	return &otlpjsonfilereceiver{input: input, id: settings.ID, storageID: cfg.StorageID}, nil
 }
+
+// ack acknowledges the delivery of the token emitted with the context, so that the
+// checkpoints of the files advance when `at_least_once` is enabled. Tokens which
+// cannot be unmarshaled are never delivered, so they are not tracked.
+func ack(ctx context.Context, err error) {
+	if a := emit.AckFromContext(ctx); a != nil {
+		a.Track()
+		a.Done(err)
+	}
+}
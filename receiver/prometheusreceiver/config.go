@@ -17,6 +17,7 @@ import (
 	promHTTP "github.com/prometheus/prometheus/discovery/http"
 	"github.com/prometheus/prometheus/discovery/kubernetes"
 	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/config/confighttp"
 	"go.opentelemetry.io/collector/confmap"
 	"go.uber.org/zap"
 	"gopkg.in/yaml.v2"
@@ -49,6 +50,14 @@ type Config struct {
 
 	TargetAllocator *targetAllocator `mapstructure:"target_allocator"`
 
+	// ReloadInterval is the interval at which the files matched by scrape_config_files and rule_files are checked
+	// for changes. When they changed, the scrape jobs or the rule files are reloaded without restarting the
+	// receiver. 0 disables the reload.
+	ReloadInterval time.Duration `mapstructure:"reload_interval"`
+
+	// ScrapeConfigProvider discovers scrape jobs over HTTP, in addition to the ones of the scrape config.
+	ScrapeConfigProvider *scrapeConfigProvider `mapstructure:"scrape_config_provider"`
+
 	// ConfigPlaceholder is just an entry to make the configuration pass a check
 	// that requires that all keys present in the config actually exist on the
 	// structure, ie.: it will error if an unknown key is present.
@@ -60,9 +69,9 @@ type Config struct {
 }
 
 type targetAllocator struct {
-	Endpoint    string        `mapstructure:"endpoint"`
-	Interval    time.Duration `mapstructure:"interval"`
-	CollectorID string        `mapstructure:"collector_id"`
+	confighttp.HTTPClientSettings `mapstructure:",squash"`
+	Interval                      time.Duration `mapstructure:"interval"`
+	CollectorID                   string        `mapstructure:"collector_id"`
 	// ConfigPlaceholder is just an entry to make the configuration pass a check
 	// that requires that all keys present in the config actually exist on the
 	// structure, ie.: it will error if an unknown key is present.
@@ -70,6 +79,14 @@ type targetAllocator struct {
 	HTTPSDConfig      *promHTTP.SDConfig `mapstructure:"-"`
 }
 
+// scrapeConfigProvider is a generic HTTP endpoint returning the scrape configs keyed by job name, in the same
+// format as the /scrape_configs endpoint of the target allocator. The targets of the jobs are discovered with
+// the service discovery configs of the returned scrape configs.
+type scrapeConfigProvider struct {
+	confighttp.HTTPClientSettings `mapstructure:",squash"`
+	Interval                      time.Duration `mapstructure:"interval"`
+}
+
 var _ component.Config = (*Config)(nil)
 var _ confmap.Unmarshaler = (*Config)(nil)
 
@@ -108,28 +125,36 @@ func (cfg *Config) Validate() error {
 			return err
 		}
 	}
+
+	if cfg.ReloadInterval < 0 {
+		return errors.New("reload_interval can't be negative")
+	}
+
+	if cfg.ScrapeConfigProvider != nil {
+		err := cfg.validateScrapeConfigProviderConfig()
+		if err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
 func (cfg *Config) validatePromConfig(promConfig *promconfig.Config) error {
-	if len(promConfig.ScrapeConfigs) == 0 && cfg.TargetAllocator == nil {
-		return errors.New("no Prometheus scrape_configs or target_allocator set")
+	if len(promConfig.ScrapeConfigs) == 0 && len(promConfig.ScrapeConfigFiles) == 0 && cfg.TargetAllocator == nil && cfg.ScrapeConfigProvider == nil {
+		return errors.New("no Prometheus scrape_configs, scrape_config_files, scrape_config_provider or target_allocator set")
 	}
 
 	// Reject features that Prometheus supports but that the receiver doesn't support:
 	// See:
 	// * https://github.com/open-telemetry/opentelemetry-collector/issues/3863
 	// * https://github.com/open-telemetry/wg-prometheus/issues/3
-	unsupportedFeatures := make([]string, 0, 4)
+	unsupportedFeatures := make([]string, 0, 3)
 	if len(promConfig.RemoteWriteConfigs) != 0 {
 		unsupportedFeatures = append(unsupportedFeatures, "remote_write")
 	}
 	if len(promConfig.RemoteReadConfigs) != 0 {
 		unsupportedFeatures = append(unsupportedFeatures, "remote_read")
 	}
-	if len(promConfig.RuleFiles) != 0 {
-		unsupportedFeatures = append(unsupportedFeatures, "rule_files")
-	}
 	if len(promConfig.AlertingConfig.AlertRelabelConfigs) != 0 {
 		unsupportedFeatures = append(unsupportedFeatures, "alert_config.relabel_configs")
 	}
@@ -182,6 +207,19 @@ func (cfg *Config) validateTargetAllocatorConfig() error {
 	return nil
 }
 
+func (cfg *Config) validateScrapeConfigProviderConfig() error {
+	if cfg.TargetAllocator != nil {
+		return errors.New("scrape_config_provider can't be used together with target_allocator")
+	}
+	if _, err := url.ParseRequestURI(cfg.ScrapeConfigProvider.Endpoint); err != nil {
+		return fmt.Errorf("scrape_config_provider endpoint is not valid: %s", cfg.ScrapeConfigProvider.Endpoint)
+	}
+	if cfg.ScrapeConfigProvider.Interval <= 0 {
+		return errors.New("scrape_config_provider interval must be positive")
+	}
+	return nil
+}
+
 // Unmarshal a config.Parser into the config struct.
 func (cfg *Config) Unmarshal(componentParser *confmap.Conf) error {
 	if componentParser == nil {
@@ -99,6 +99,65 @@ func TestLoadTargetAllocatorConfig(t *testing.T) {
 	assert.Equal(t, promModel.Duration(5*time.Second), r2.PrometheusConfig.ScrapeConfigs[0].ScrapeInterval)
 }
 
+func TestLoadScrapeConfigReloadConfig(t *testing.T) {
+	cm, err := confmaptest.LoadConf(filepath.Join("testdata", "config_reload.yaml"))
+	require.NoError(t, err)
+	factory := NewFactory()
+	cfg := factory.CreateDefaultConfig()
+
+	sub, err := cm.Sub(component.NewIDWithName(metadata.Type, "").String())
+	require.NoError(t, err)
+	require.NoError(t, component.UnmarshalConfig(sub, cfg))
+	require.NoError(t, component.ValidateConfig(cfg))
+
+	r0 := cfg.(*Config)
+	assert.Equal(t, 10*time.Second, r0.ReloadInterval)
+	assert.Equal(t, "http://localhost:8080/scrape_configs", r0.ScrapeConfigProvider.Endpoint)
+	assert.Equal(t, time.Minute, r0.ScrapeConfigProvider.Interval)
+	assert.Equal(t, []string{"./testdata/scrape_configs/*.yaml"}, r0.PrometheusConfig.ScrapeConfigFiles)
+
+	scrapeConfigs, err := r0.PrometheusConfig.GetScrapeConfigs()
+	require.NoError(t, err)
+	require.Len(t, scrapeConfigs, 1)
+	assert.Equal(t, "from_file", scrapeConfigs[0].JobName)
+}
+
+func TestScrapeConfigReloadInvalidConfig(t *testing.T) {
+	cm, err := confmaptest.LoadConf(filepath.Join("testdata", "config_reload.yaml"))
+	require.NoError(t, err)
+
+	tests := []struct {
+		name       string
+		wantErrMsg string
+	}{
+		{
+			name:       "negative_reload_interval",
+			wantErrMsg: "reload_interval can't be negative",
+		},
+		{
+			name:       "invalid_provider_endpoint",
+			wantErrMsg: "scrape_config_provider endpoint is not valid: localhost",
+		},
+		{
+			name:       "missing_provider_interval",
+			wantErrMsg: "scrape_config_provider interval must be positive",
+		},
+		{
+			name:       "provider_with_target_allocator",
+			wantErrMsg: "scrape_config_provider can't be used together with target_allocator",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := NewFactory().CreateDefaultConfig()
+			sub, err := cm.Sub(component.NewIDWithName(metadata.Type, tt.name).String())
+			require.NoError(t, err)
+			require.NoError(t, component.UnmarshalConfig(sub, cfg))
+			assert.EqualError(t, component.ValidateConfig(cfg), tt.wantErrMsg)
+		})
+	}
+}
+
 func TestLoadConfigFailsOnUnknownSection(t *testing.T) {
 	cm, err := confmaptest.LoadConf(filepath.Join("testdata", "invalid-config-section.yaml"))
 	require.NoError(t, err)
@@ -162,8 +221,7 @@ func TestRejectUnsupportedPrometheusFeatures(t *testing.T) {
         alert_config.alertmanagers
         alert_config.relabel_configs
         remote_read
-        remote_write
-        rule_files`
+        remote_write`
 
 	gotErrMsg := strings.ReplaceAll(err.Error(), "\t", strings.Repeat(" ", 8))
 	require.Equal(t, wantErrMsg, gotErrMsg)
@@ -37,6 +37,7 @@ func createDefaultConfig() component.Config {
 		PrometheusConfig: &promconfig.Config{
 			GlobalConfig: promconfig.DefaultGlobalConfig,
 		},
+		ReloadInterval: defaultReloadInterval,
 	}
 }
 
@@ -12,7 +12,9 @@ import (
 	"net/http"
 	"net/url"
 	"os"
+	"path/filepath"
 	"regexp"
+	"sort"
 	"sync"
 	"time"
 
@@ -23,6 +25,7 @@ import (
 	"github.com/prometheus/prometheus/config"
 	"github.com/prometheus/prometheus/discovery"
 	promHTTP "github.com/prometheus/prometheus/discovery/http"
+	"github.com/prometheus/prometheus/model/rulefmt"
 	"github.com/prometheus/prometheus/scrape"
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/consumer"
@@ -34,8 +37,9 @@ import (
 )
 
 const (
-	defaultGCInterval = 2 * time.Minute
-	gcIntervalDelta   = 1 * time.Minute
+	defaultGCInterval     = 2 * time.Minute
+	gcIntervalDelta       = 1 * time.Minute
+	defaultReloadInterval = 30 * time.Second
 )
 
 // pReceiver is the type that provides Prometheus scraper/receiver functionality.
@@ -44,12 +48,18 @@ type pReceiver struct {
 	consumer            consumer.Metrics
 	cancelFunc          context.CancelFunc
 	targetAllocatorStop chan struct{}
+	reloadStop          chan struct{}
 	configLoaded        chan struct{}
 	loadConfigOnce      sync.Once
 
 	settings         receiver.CreateSettings
 	scrapeManager    *scrape.Manager
 	discoveryManager *discovery.Manager
+	// scrapeConfigsClient retrieves the scrape configs of the target allocator or of the scrape config provider.
+	scrapeConfigsClient *http.Client
+	// rulesMu guards ruleGroups, the rule groups of the files matched by rule_files as last loaded.
+	rulesMu    sync.Mutex
+	ruleGroups []rulefmt.RuleGroup
 }
 
 // New creates a new prometheus.Receiver reference.
@@ -60,6 +70,7 @@ func newPrometheusReceiver(set receiver.CreateSettings, cfg *Config, next consum
 		settings:            set,
 		configLoaded:        make(chan struct{}),
 		targetAllocatorStop: make(chan struct{}),
+		reloadStop:          make(chan struct{}),
 	}
 	return pr
 }
@@ -75,7 +86,13 @@ func (r *pReceiver) Start(_ context.Context, host component.Host) error {
 	// add scrape configs defined by the collector configs
 	baseCfg := r.cfg.PrometheusConfig
 
-	err := r.initPrometheusComponents(discoveryCtx, host, logger)
+	err := r.loadRules(baseCfg.RuleFiles)
+	if err != nil {
+		r.settings.Logger.Error("Failed to load rule files", zap.Error(err))
+		return err
+	}
+
+	err = r.initPrometheusComponents(discoveryCtx, host, logger)
 	if err != nil {
 		r.settings.Logger.Error("Failed to initPrometheusComponents Prometheus components", zap.Error(err))
 		return err
@@ -89,12 +106,17 @@ func (r *pReceiver) Start(_ context.Context, host component.Host) error {
 
 	allocConf := r.cfg.TargetAllocator
 	if allocConf != nil {
-		err = r.startTargetAllocator(allocConf, baseCfg)
+		err = r.startTargetAllocator(host, allocConf, baseCfg)
 		if err != nil {
 			return err
 		}
 	}
 
+	err = r.startScrapeConfigReload(host, baseCfg)
+	if err != nil {
+		return err
+	}
+
 	r.loadConfigOnce.Do(func() {
 		close(r.configLoaded)
 	})
@@ -102,8 +124,13 @@ func (r *pReceiver) Start(_ context.Context, host component.Host) error {
 	return nil
 }
 
-func (r *pReceiver) startTargetAllocator(allocConf *targetAllocator, baseCfg *config.Config) error {
+func (r *pReceiver) startTargetAllocator(host component.Host, allocConf *targetAllocator, baseCfg *config.Config) error {
 	r.settings.Logger.Info("Starting target allocator discovery")
+	var err error
+	r.scrapeConfigsClient, err = allocConf.ToClient(host, r.settings.TelemetrySettings)
+	if err != nil {
+		return err
+	}
 	// immediately sync jobs, not waiting for the first tick
 	savedHash, err := r.syncTargetAllocator(uint64(0), allocConf, baseCfg)
 	if err != nil {
@@ -197,11 +224,27 @@ func (r *pReceiver) getScrapeConfigsResponse(baseURL string) (map[string]*config
 		return nil, err
 	}
 
-	resp, err := http.Get(scrapeConfigsURL) //nolint
+	resp, err := r.scrapeConfigsClient.Get(scrapeConfigsURL)
+	if err != nil {
+		return nil, err
+	}
+	return r.readScrapeConfigsResponse(resp)
+}
+
+// getProviderScrapeConfigs retrieves the scrape configs keyed by job name from the scrape config provider.
+func (r *pReceiver) getProviderScrapeConfigs(endpoint string) (map[string]*config.ScrapeConfig, error) {
+	resp, err := r.scrapeConfigsClient.Get(endpoint)
 	if err != nil {
 		return nil, err
 	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		_ = resp.Body.Close()
+		return nil, fmt.Errorf("scrape config provider returned status %d", resp.StatusCode)
+	}
+	return r.readScrapeConfigsResponse(resp)
+}
 
+func (r *pReceiver) readScrapeConfigsResponse(resp *http.Response) (map[string]*config.ScrapeConfig, error) {
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
 		return nil, err
@@ -220,13 +263,219 @@ func (r *pReceiver) getScrapeConfigsResponse(baseURL string) (map[string]*config
 	return jobToScrapeConfig, nil
 }
 
+// startScrapeConfigReload reloads the scrape jobs when the files matched by scrape_config_files, or the jobs
+// returned by the scrape config provider, change. The scrape and discovery managers are updated in place.
+// The rule files are reloaded when the files matched by rule_files change.
+func (r *pReceiver) startScrapeConfigReload(host component.Host, baseCfg *config.Config) error {
+	provider := r.cfg.ScrapeConfigProvider
+	// The target allocator replaces the scrape configs of baseCfg, so the files aren't reloaded along with it.
+	watchFiles := r.cfg.ReloadInterval > 0 && len(baseCfg.ScrapeConfigFiles) > 0 && r.cfg.TargetAllocator == nil
+	watchRules := r.cfg.ReloadInterval > 0 && len(baseCfg.RuleFiles) > 0
+	if provider == nil && !watchFiles && !watchRules {
+		return nil
+	}
+
+	// The scrape configs of baseCfg were just applied, and the rule files just loaded.
+	files, err := readFiles(baseCfg.ScrapeConfigFiles)
+	if err != nil {
+		return err
+	}
+	savedHash, err := hashScrapeConfigs(files, nil)
+	if err != nil {
+		return err
+	}
+	ruleFiles, err := readFiles(baseCfg.RuleFiles)
+	if err != nil {
+		return err
+	}
+	savedRulesHash, err := hashstructure.Hash(ruleFiles, hashstructure.FormatV2, nil)
+	if err != nil {
+		return err
+	}
+
+	var providerJobs map[string]*config.ScrapeConfig
+	if provider != nil {
+		r.settings.Logger.Info("Starting scrape config provider discovery")
+		r.scrapeConfigsClient, err = provider.ToClient(host, r.settings.TelemetrySettings)
+		if err != nil {
+			return err
+		}
+		// immediately retrieve the jobs, not waiting for the first tick
+		providerJobs, err = r.getProviderScrapeConfigs(provider.Endpoint)
+		if err != nil {
+			return err
+		}
+		savedHash, err = r.reloadScrapeConfigs(savedHash, baseCfg, providerJobs)
+		if err != nil {
+			return err
+		}
+	}
+
+	go func() {
+		var filesTick, providerTick <-chan time.Time
+		if watchFiles || watchRules {
+			filesTicker := time.NewTicker(r.cfg.ReloadInterval)
+			defer filesTicker.Stop()
+			filesTick = filesTicker.C
+		}
+		if provider != nil {
+			providerTicker := time.NewTicker(provider.Interval)
+			defer providerTicker.Stop()
+			providerTick = providerTicker.C
+		}
+		for {
+			select {
+			case <-filesTick:
+				if watchRules {
+					hash, reloadErr := r.reloadRules(savedRulesHash, baseCfg)
+					if reloadErr != nil {
+						r.settings.Logger.Error("Failed to reload rule files", zap.Error(reloadErr))
+					}
+					// Invalid rules are only reported once, until they change again.
+					savedRulesHash = hash
+				}
+				if !watchFiles {
+					continue
+				}
+			case <-providerTick:
+				jobs, fetchErr := r.getProviderScrapeConfigs(provider.Endpoint)
+				if fetchErr != nil {
+					r.settings.Logger.Error("Failed to retrieve scrape configs from the scrape config provider", zap.Error(fetchErr))
+					continue
+				}
+				providerJobs = jobs
+			case <-r.reloadStop:
+				r.settings.Logger.Info("Stopping scrape config reload")
+				return
+			}
+
+			hash, reloadErr := r.reloadScrapeConfigs(savedHash, baseCfg, providerJobs)
+			if reloadErr != nil {
+				r.settings.Logger.Error("Failed to reload scrape configuration", zap.Error(reloadErr))
+			}
+			// An invalid configuration is only reported once, until it changes again.
+			savedHash = hash
+		}
+	}()
+	return nil
+}
+
+// reloadRules loads the files matched by rule_files in place of the current ones, if the hash of the files
+// doesn't match compareHash. It returns the hash of the files.
+func (r *pReceiver) reloadRules(compareHash uint64, baseCfg *config.Config) (uint64, error) {
+	files, err := readFiles(baseCfg.RuleFiles)
+	if err != nil {
+		return compareHash, err
+	}
+	hash, err := hashstructure.Hash(files, hashstructure.FormatV2, nil)
+	if err != nil {
+		return compareHash, err
+	}
+	if hash == compareHash {
+		// no update needed
+		return hash, nil
+	}
+
+	r.settings.Logger.Info("Reloading rule files")
+	return hash, r.loadRules(baseCfg.RuleFiles)
+}
+
+// loadRules parses the files matched by the rule_files patterns. The current rule groups are kept if a file is
+// invalid.
+func (r *pReceiver) loadRules(patterns []string) error {
+	var ruleGroups []rulefmt.RuleGroup
+	for _, pattern := range patterns {
+		matches, err := filepath.Glob(pattern)
+		if err != nil {
+			return fmt.Errorf("error retrieving rule files for %q: %w", pattern, err)
+		}
+		for _, filename := range matches {
+			rgs, errs := rulefmt.ParseFile(filename)
+			if len(errs) > 0 {
+				return fmt.Errorf("error loading rule file %q: %w", filename, errors.Join(errs...))
+			}
+			ruleGroups = append(ruleGroups, rgs.Groups...)
+		}
+	}
+
+	r.rulesMu.Lock()
+	defer r.rulesMu.Unlock()
+	r.ruleGroups = ruleGroups
+	return nil
+}
+
+// reloadScrapeConfigs applies the scrape configs of baseCfg, of its scrape_config_files and the ones retrieved from
+// the scrape config provider, if their hash doesn't match compareHash. It returns the hash of the scrape configs.
+func (r *pReceiver) reloadScrapeConfigs(compareHash uint64, baseCfg *config.Config, providerJobs map[string]*config.ScrapeConfig) (uint64, error) {
+	files, err := readFiles(baseCfg.ScrapeConfigFiles)
+	if err != nil {
+		return compareHash, err
+	}
+	hash, err := hashScrapeConfigs(files, providerJobs)
+	if err != nil {
+		return compareHash, err
+	}
+	if hash == compareHash {
+		// no update needed
+		return hash, nil
+	}
+
+	cfg := *baseCfg
+	cfg.ScrapeConfigs = make([]*config.ScrapeConfig, 0, len(baseCfg.ScrapeConfigs)+len(providerJobs))
+	cfg.ScrapeConfigs = append(cfg.ScrapeConfigs, baseCfg.ScrapeConfigs...)
+	jobNames := make([]string, 0, len(providerJobs))
+	for jobName := range providerJobs {
+		jobNames = append(jobNames, jobName)
+	}
+	sort.Strings(jobNames)
+	for _, jobName := range jobNames {
+		// Copy the scrape config, applying it sets its defaults, which would change its hash.
+		scrapeConfig := *providerJobs[jobName]
+		cfg.ScrapeConfigs = append(cfg.ScrapeConfigs, &scrapeConfig)
+	}
+
+	r.settings.Logger.Info("Reloading scrape configuration")
+	return hash, r.applyCfg(&cfg)
+}
+
+// readFiles reads the files matched by the patterns, keyed by file name.
+func readFiles(patterns []string) (map[string]string, error) {
+	files := make(map[string]string)
+	for _, pattern := range patterns {
+		matches, err := filepath.Glob(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("error retrieving files for %q: %w", pattern, err)
+		}
+		for _, filename := range matches {
+			content, err := os.ReadFile(filename)
+			if err != nil {
+				return nil, err
+			}
+			files[filename] = string(content)
+		}
+	}
+	return files, nil
+}
+
+func hashScrapeConfigs(files map[string]string, providerJobs map[string]*config.ScrapeConfig) (uint64, error) {
+	return hashstructure.Hash(struct {
+		Files map[string]string
+		Jobs  map[string]*config.ScrapeConfig
+	}{files, providerJobs}, hashstructure.FormatV2, nil)
+}
+
 func (r *pReceiver) applyCfg(cfg *config.Config) error {
 	if err := r.scrapeManager.ApplyConfig(cfg); err != nil {
 		return err
 	}
 
+	// The scrape configs include the ones of the scrape_config_files.
+	scrapeConfigs, err := cfg.GetScrapeConfigs()
+	if err != nil {
+		return err
+	}
 	discoveryCfg := make(map[string]discovery.Configs)
-	for _, scrapeConfig := range cfg.ScrapeConfigs {
+	for _, scrapeConfig := range scrapeConfigs {
 		discoveryCfg[scrapeConfig.JobName] = scrapeConfig.ServiceDiscoveryConfigs
 		r.settings.Logger.Info("Scrape job added", zap.String("jobName", scrapeConfig.JobName))
 	}
@@ -313,5 +562,6 @@ func (r *pReceiver) Shutdown(context.Context) error {
 		r.scrapeManager.Stop()
 	}
 	close(r.targetAllocatorStop)
+	close(r.reloadStop)
 	return nil
 }
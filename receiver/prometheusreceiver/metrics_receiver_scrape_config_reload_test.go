@@ -0,0 +1,265 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package prometheusreceiver
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/prometheus/common/model"
+	"github.com/prometheus/prometheus/discovery"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/config/confighttp"
+	"go.opentelemetry.io/collector/consumer/consumertest"
+	"go.opentelemetry.io/collector/receiver/receivertest"
+)
+
+func TestScrapeConfigFilesReload(t *testing.T) {
+	dir := t.TempDir()
+	jobsFile := filepath.Join(dir, "jobs.yaml")
+	writeScrapeConfigFile(t, jobsFile, "job_a", "localhost:9001")
+
+	cfg := createDefaultConfig().(*Config)
+	cfg.ReloadInterval = 50 * time.Millisecond
+	cfg.PrometheusConfig.ScrapeConfigFiles = []string{filepath.Join(dir, "*.yaml")}
+	require.NoError(t, cfg.Validate())
+
+	receiver := newTestReceiver(t, cfg)
+
+	// The jobs of the scrape_config_files are discovered.
+	assert.Equal(t, []string{"localhost:9001"}, discoveredStaticTargets(receiver))
+
+	writeScrapeConfigFile(t, jobsFile, "job_b", "localhost:9002")
+	assert.Eventually(t, func() bool {
+		targets := discoveredStaticTargets(receiver)
+		return len(targets) == 1 && targets[0] == "localhost:9002"
+	}, 10*time.Second, 50*time.Millisecond)
+
+	// A second file adds its jobs to the ones of the first file.
+	writeScrapeConfigFile(t, filepath.Join(dir, "more_jobs.yaml"), "job_c", "localhost:9003")
+	assert.Eventually(t, func() bool {
+		targets := discoveredStaticTargets(receiver)
+		return len(targets) == 2 && targets[0] == "localhost:9002" && targets[1] == "localhost:9003"
+	}, 10*time.Second, 50*time.Millisecond)
+}
+
+func TestScrapeConfigProvider(t *testing.T) {
+	var mu sync.Mutex
+	response := `{"job_a": {"job_name": "job_a", "static_configs": [{"targets": ["localhost:9001"]}]}}`
+	status := http.StatusOK
+	stub := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		assert.Equal(t, "/scrape_configs", r.URL.Path)
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		_, _ = w.Write([]byte(response))
+	}))
+	defer stub.Close()
+
+	cfg := createDefaultConfig().(*Config)
+	cfg.ScrapeConfigProvider = &scrapeConfigProvider{
+		HTTPClientSettings: confighttp.HTTPClientSettings{Endpoint: stub.URL + "/scrape_configs"},
+		Interval:           50 * time.Millisecond,
+	}
+	cfg.PrometheusConfig.ScrapeConfigs = nil
+	require.NoError(t, cfg.Validate())
+
+	receiver := newTestReceiver(t, cfg)
+
+	// The jobs are retrieved before the receiver is started.
+	assert.Equal(t, []string{"localhost:9001"}, discoveredStaticTargets(receiver))
+
+	mu.Lock()
+	response = `{"job_b": {"job_name": "job_b", "static_configs": [{"targets": ["localhost:9002"]}]}}`
+	mu.Unlock()
+	assert.Eventually(t, func() bool {
+		targets := discoveredStaticTargets(receiver)
+		return len(targets) == 1 && targets[0] == "localhost:9002"
+	}, 10*time.Second, 50*time.Millisecond)
+
+	// Failed retrievals keep the current jobs.
+	mu.Lock()
+	status = http.StatusInternalServerError
+	response = `{}`
+	mu.Unlock()
+	time.Sleep(200 * time.Millisecond)
+	assert.Equal(t, []string{"localhost:9002"}, discoveredStaticTargets(receiver))
+}
+
+func TestScrapeConfigProviderUnavailable(t *testing.T) {
+	stub := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer stub.Close()
+
+	cfg := createDefaultConfig().(*Config)
+	cfg.ScrapeConfigProvider = &scrapeConfigProvider{
+		HTTPClientSettings: confighttp.HTTPClientSettings{Endpoint: stub.URL},
+		Interval:           time.Minute,
+	}
+
+	receiver := newPrometheusReceiver(receivertest.NewNopCreateSettings(), cfg, new(consumertest.MetricsSink))
+	assert.EqualError(t, receiver.Start(context.Background(), componenttest.NewNopHost()), "scrape config provider returned status 404")
+	require.NoError(t, receiver.Shutdown(context.Background()))
+}
+
+func TestFileSDConfigsReload(t *testing.T) {
+	dir := t.TempDir()
+	targetsFile := filepath.Join(dir, "targets.json")
+	writeFile(t, targetsFile, `[{"targets": ["localhost:9001"]}]`)
+	writeFile(t, filepath.Join(dir, "jobs.yaml"), fmt.Sprintf(`scrape_configs:
+  - job_name: file_sd
+    file_sd_configs:
+      - files: ['%s']
+        refresh_interval: 1s
+`, targetsFile))
+
+	cfg := createDefaultConfig().(*Config)
+	cfg.PrometheusConfig.ScrapeConfigFiles = []string{filepath.Join(dir, "jobs.yaml")}
+	receiver := newTestReceiver(t, cfg)
+
+	// The targets of the file are scraped, and updated when the file changes.
+	assert.Eventually(t, func() bool {
+		return assert.ObjectsAreEqual([]string{"localhost:9001"}, activeTargets(receiver))
+	}, 30*time.Second, 100*time.Millisecond)
+
+	writeFile(t, targetsFile, `[{"targets": ["localhost:9002", "localhost:9003"]}]`)
+	assert.Eventually(t, func() bool {
+		return assert.ObjectsAreEqual([]string{"localhost:9002", "localhost:9003"}, activeTargets(receiver))
+	}, 30*time.Second, 100*time.Millisecond)
+}
+
+func TestRuleFilesReload(t *testing.T) {
+	dir := t.TempDir()
+	writeScrapeConfigFile(t, filepath.Join(dir, "jobs.yaml"), "job_a", "localhost:9001")
+	rulesFile := filepath.Join(dir, "rules.yaml")
+	writeRuleFile(t, rulesFile, "job:up:sum", "sum by (job) (up)")
+
+	cfg := createDefaultConfig().(*Config)
+	cfg.ReloadInterval = 50 * time.Millisecond
+	cfg.PrometheusConfig.ScrapeConfigFiles = []string{filepath.Join(dir, "jobs.yaml")}
+	cfg.PrometheusConfig.RuleFiles = []string{filepath.Join(dir, "*.rules"), rulesFile}
+	require.NoError(t, cfg.Validate())
+	receiver := newTestReceiver(t, cfg)
+
+	// The rules are loaded from the start, and replaced when the rule files change.
+	assert.Equal(t, []string{"job:up:sum"}, recordedRules(receiver))
+
+	writeRuleFile(t, rulesFile, "job:up:count", "count by (job) (up)")
+	assert.Eventually(t, func() bool {
+		return assert.ObjectsAreEqual([]string{"job:up:count"}, recordedRules(receiver))
+	}, 10*time.Second, 50*time.Millisecond)
+
+	// Invalid rules keep the current ones.
+	writeFile(t, rulesFile, "groups: [{name: invalid, rules: [{record: invalid, expr: 'sum('}]}]")
+	time.Sleep(200 * time.Millisecond)
+	assert.Equal(t, []string{"job:up:count"}, recordedRules(receiver))
+
+	// A file matched by another pattern is loaded along with the first one.
+	writeRuleFile(t, filepath.Join(dir, "more.rules"), "job:up:max", "max by (job) (up)")
+	writeRuleFile(t, rulesFile, "job:up:min", "min by (job) (up)")
+	assert.Eventually(t, func() bool {
+		return assert.ObjectsAreEqual([]string{"job:up:max", "job:up:min"}, recordedRules(receiver))
+	}, 10*time.Second, 50*time.Millisecond)
+}
+
+func TestInvalidRuleFiles(t *testing.T) {
+	dir := t.TempDir()
+	rulesFile := filepath.Join(dir, "rules.yaml")
+	writeFile(t, rulesFile, "groups: [{name: invalid, rules: [{record: invalid, expr: 'sum('}]}]")
+
+	cfg := createDefaultConfig().(*Config)
+	cfg.PrometheusConfig.RuleFiles = []string{rulesFile}
+	receiver := newPrometheusReceiver(receivertest.NewNopCreateSettings(), cfg, new(consumertest.MetricsSink))
+	assert.ErrorContains(t, receiver.Start(context.Background(), componenttest.NewNopHost()), "error loading rule file")
+}
+
+// newTestReceiver starts a receiver, and checks that its scrape manager is stopped along with it.
+func newTestReceiver(t *testing.T, cfg *Config) *pReceiver {
+	receiver := newPrometheusReceiver(receivertest.NewNopCreateSettings(), cfg, new(consumertest.MetricsSink))
+	require.NoError(t, receiver.Start(context.Background(), componenttest.NewNopHost()))
+	t.Cleanup(func() {
+		require.NoError(t, receiver.Shutdown(context.Background()))
+		assert.Empty(t, flattenTargets(receiver.scrapeManager.TargetsAll()))
+	})
+	return receiver
+}
+
+// recordedRules returns the sorted names of the recording rules loaded from the rule files.
+func recordedRules(r *pReceiver) []string {
+	r.rulesMu.Lock()
+	defer r.rulesMu.Unlock()
+	var rules []string
+	for _, group := range r.ruleGroups {
+		for _, rule := range group.Rules {
+			rules = append(rules, rule.Record.Value)
+		}
+	}
+	sort.Strings(rules)
+	return rules
+}
+
+// activeTargets returns the sorted addresses of the active targets of the scrape manager.
+func activeTargets(r *pReceiver) []string {
+	var targets []string
+	for _, target := range flattenTargets(r.scrapeManager.TargetsActive()) {
+		targets = append(targets, target.URL().Host)
+	}
+	sort.Strings(targets)
+	return targets
+}
+
+func writeRuleFile(t *testing.T, path string, record string, expr string) {
+	writeFile(t, path, fmt.Sprintf(`groups:
+  - name: test
+    rules:
+      - record: %s
+        expr: %s
+`, record, expr))
+}
+
+func writeScrapeConfigFile(t *testing.T, path string, jobName string, target string) {
+	content := fmt.Sprintf(`scrape_configs:
+  - job_name: %s
+    static_configs:
+      - targets: ['%s']
+`, jobName, target)
+	writeFile(t, path, content)
+}
+
+func writeFile(t *testing.T, path string, content string) {
+	// Write then rename, so that the reload never reads a partially written file.
+	tmp := path + ".tmp"
+	require.NoError(t, os.WriteFile(tmp, []byte(content), 0600))
+	require.NoError(t, os.Rename(tmp, path))
+}
+
+// discoveredStaticTargets returns the sorted targets of the static configs of the discovery manager.
+func discoveredStaticTargets(r *pReceiver) []string {
+	var targets []string
+	for _, provider := range r.discoveryManager.Providers() {
+		staticConfig, ok := provider.Config().(discovery.StaticConfig)
+		if !ok {
+			continue
+		}
+		for _, group := range staticConfig {
+			for _, target := range group.Targets {
+				targets = append(targets, string(target[model.AddressLabel]))
+			}
+		}
+	}
+	sort.Strings(targets)
+	return targets
+}
@@ -21,6 +21,9 @@ type Config struct {
 	EnableMetricType      bool                             `mapstructure:"enable_metric_type"`
 	IsMonotonicCounter    bool                             `mapstructure:"is_monotonic_counter"`
 	TimerHistogramMapping []protocol.TimerHistogramMapping `mapstructure:"timer_histogram_mapping"`
+	// SeriesLimit is the maximum number of series aggregated per aggregation interval,
+	// the samples of any further series are aggregated in an overflow series. 0 means no limit.
+	SeriesLimit int `mapstructure:"series_limit"`
 }
 
 func (c *Config) Validate() error {
@@ -30,6 +33,10 @@ func (c *Config) Validate() error {
 		errs = multierr.Append(errs, fmt.Errorf("aggregation_interval must be a positive duration"))
 	}
 
+	if c.SeriesLimit < 0 {
+		errs = multierr.Append(errs, fmt.Errorf("series_limit must not be negative"))
+	}
+
 	var TimerHistogramMappingMissingObjectName bool
 	for _, eachMap := range c.TimerHistogramMapping {
 
@@ -65,6 +72,9 @@ func (c *Config) Validate() error {
 			if eachMap.Histogram.MaxSize != 0 && (eachMap.Histogram.MaxSize < structure.MinSize || eachMap.Histogram.MaxSize > structure.MaximumMaxSize) {
 				errs = multierr.Append(errs, fmt.Errorf("histogram max_size out of range: %v", eachMap.Histogram.MaxSize))
 			}
+			if eachMap.Histogram.Explicit != nil {
+				errs = multierr.Append(errs, validateExplicitHistogram(eachMap.Histogram))
+			}
 		} else {
 			// Non-histogram observer w/ histogram config
 			var empty protocol.HistogramConfig
@@ -80,3 +90,19 @@ func (c *Config) Validate() error {
 
 	return errs
 }
+
+func validateExplicitHistogram(cfg protocol.HistogramConfig) error {
+	if cfg.MaxSize != 0 {
+		return fmt.Errorf("histogram max_size cannot be used with explicit buckets")
+	}
+	buckets := cfg.Explicit.Buckets
+	if len(buckets) == 0 {
+		return fmt.Errorf("histogram explicit buckets must not be empty")
+	}
+	for i := 1; i < len(buckets); i++ {
+		if buckets[i] <= buckets[i-1] {
+			return fmt.Errorf("histogram explicit buckets must be in increasing order: %v", buckets)
+		}
+	}
+	return nil
+}
@@ -42,6 +42,7 @@ func TestLoadConfig(t *testing.T) {
 					Transport: "custom_transport",
 				},
 				AggregationInterval: 70 * time.Second,
+				SeriesLimit:         1000,
 				TimerHistogramMapping: []protocol.TimerHistogramMapping{
 					{
 						StatsdType:   "histogram",
@@ -61,6 +62,15 @@ func TestLoadConfig(t *testing.T) {
 							MaxSize: 170,
 						},
 					},
+					{
+						StatsdType:   "timer",
+						ObserverType: "histogram",
+						Histogram: protocol.HistogramConfig{
+							Explicit: &protocol.ExplicitHistogramConfig{
+								Buckets: []float64{5, 10, 25, 50, 100},
+							},
+						},
+					},
 				},
 			},
 		},
@@ -172,6 +182,79 @@ func TestValidate(t *testing.T) {
 			},
 			expectedErr: "aggregation_interval must be a positive duration",
 		},
+		{
+			name: "negativeSeriesLimit",
+			cfg: &Config{
+				AggregationInterval: 10,
+				SeriesLimit:         -1,
+			},
+			expectedErr: "series_limit must not be negative",
+		},
+		{
+			name: "explicitHistogramWithGaugeObserver",
+			cfg: &Config{
+				AggregationInterval: 10,
+				TimerHistogramMapping: []protocol.TimerHistogramMapping{
+					{
+						StatsdType:   "timing",
+						ObserverType: "gauge",
+						Histogram: protocol.HistogramConfig{
+							Explicit: &protocol.ExplicitHistogramConfig{Buckets: []float64{1}},
+						},
+					},
+				},
+			},
+			expectedErr: "histogram configuration requires observer_type: histogram",
+		},
+		{
+			name: "explicitHistogramWithMaxSize",
+			cfg: &Config{
+				AggregationInterval: 10,
+				TimerHistogramMapping: []protocol.TimerHistogramMapping{
+					{
+						StatsdType:   "timing",
+						ObserverType: "histogram",
+						Histogram: protocol.HistogramConfig{
+							MaxSize:  100,
+							Explicit: &protocol.ExplicitHistogramConfig{Buckets: []float64{1}},
+						},
+					},
+				},
+			},
+			expectedErr: "histogram max_size cannot be used with explicit buckets",
+		},
+		{
+			name: "emptyExplicitBuckets",
+			cfg: &Config{
+				AggregationInterval: 10,
+				TimerHistogramMapping: []protocol.TimerHistogramMapping{
+					{
+						StatsdType:   "timing",
+						ObserverType: "histogram",
+						Histogram: protocol.HistogramConfig{
+							Explicit: &protocol.ExplicitHistogramConfig{},
+						},
+					},
+				},
+			},
+			expectedErr: "histogram explicit buckets must not be empty",
+		},
+		{
+			name: "unsortedExplicitBuckets",
+			cfg: &Config{
+				AggregationInterval: 10,
+				TimerHistogramMapping: []protocol.TimerHistogramMapping{
+					{
+						StatsdType:   "timing",
+						ObserverType: "histogram",
+						Histogram: protocol.HistogramConfig{
+							Explicit: &protocol.ExplicitHistogramConfig{Buckets: []float64{10, 5}},
+						},
+					},
+				},
+			},
+			expectedErr: "histogram explicit buckets must be in increasing order: [10 5]",
+		},
 	}
 
 	for _, test := range tests {
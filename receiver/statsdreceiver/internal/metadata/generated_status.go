@@ -8,5 +8,6 @@ import (
 
 const (
 	Type             = "statsd"
+	LogsStability    = component.StabilityLevelAlpha
 	MetricsStability = component.StabilityLevelBeta
 )
@@ -0,0 +1,233 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package protocol // import "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/statsdreceiver/internal/protocol"
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/plog"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// DogStatsD extensions, see https://docs.datadoghq.com/developers/dogstatsd/datagram_shell.
+const (
+	eventPrefix        = "_e{"
+	serviceCheckPrefix = "_sc|"
+
+	containerIDPrefix = "c:"
+	timestampPrefix   = "T"
+
+	// Prefixes of the origin detection values sent in the container ID field.
+	originContainerIDPrefix = "ci-"
+	originInodePrefix       = "in-"
+
+	attrContainerID  = "container.id"
+	attrCgroupInode  = "dogstatsd.origin.cgroup_inode"
+	attrHostName     = "host.name"
+	attrType         = "dogstatsd.type"
+	attrEventTitle   = "dogstatsd.event.title"
+	attrEventPrio    = "dogstatsd.event.priority"
+	attrEventAlert   = "dogstatsd.event.alert_type"
+	attrEventAggKey  = "dogstatsd.event.aggregation_key"
+	attrEventSource  = "dogstatsd.event.source_type"
+	attrCheckName    = "dogstatsd.service_check.name"
+	attrCheckStatus  = "dogstatsd.service_check.status"
+	typeEvent        = "event"
+	typeServiceCheck = "service_check"
+
+	defaultEventPriority  = "normal"
+	defaultEventAlertType = "info"
+)
+
+var serviceCheckStatuses = []struct {
+	name     string
+	severity plog.SeverityNumber
+}{
+	{"ok", plog.SeverityNumberInfo},
+	{"warning", plog.SeverityNumberWarn},
+	{"critical", plog.SeverityNumberError},
+	{"unknown", plog.SeverityNumberUnspecified},
+}
+
+// parseOrigin maps the value of the container ID field, which is either a
+// container ID or an origin detection value, to an attribute.
+func parseOrigin(value string) attribute.KeyValue {
+	switch {
+	case strings.HasPrefix(value, originContainerIDPrefix):
+		return attribute.String(attrContainerID, strings.TrimPrefix(value, originContainerIDPrefix))
+	case strings.HasPrefix(value, originInodePrefix):
+		return attribute.String(attrCgroupInode, strings.TrimPrefix(value, originInodePrefix))
+	}
+	return attribute.String(attrContainerID, value)
+}
+
+func parseUnixTimestamp(value string) (time.Time, error) {
+	seconds, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("parse timestamp: %s", value)
+	}
+	return time.Unix(seconds, 0), nil
+}
+
+// parseEvent parses an event of the form
+// _e{<TITLE_LENGTH>,<TEXT_LENGTH>}:<TITLE>|<TEXT>|d:<TIMESTAMP>|h:<HOSTNAME>|p:<PRIORITY>|t:<ALERT_TYPE>|k:<KEY>|s:<SOURCE>|#<TAGS>|c:<CONTAINER>
+// into a log record whose body is the event text.
+func parseEvent(line string, now time.Time) (plog.LogRecord, error) {
+	record := plog.NewLogRecord()
+
+	header, rest, ok := strings.Cut(strings.TrimPrefix(line, eventPrefix), "}:")
+	if !ok {
+		return record, fmt.Errorf("invalid event format: %s", line)
+	}
+	titleLenStr, textLenStr, ok := strings.Cut(header, ",")
+	if !ok {
+		return record, fmt.Errorf("invalid event format: %s", line)
+	}
+	titleLen, err := strconv.Atoi(titleLenStr)
+	if err != nil || titleLen <= 0 {
+		return record, fmt.Errorf("invalid event title length: %s", titleLenStr)
+	}
+	textLen, err := strconv.Atoi(textLenStr)
+	if err != nil || textLen < 0 {
+		return record, fmt.Errorf("invalid event text length: %s", textLenStr)
+	}
+	if len(rest) < titleLen+1+textLen || rest[titleLen] != '|' {
+		return record, fmt.Errorf("event title and text do not match their lengths: %s", line)
+	}
+	title := rest[:titleLen]
+	text := rest[titleLen+1 : titleLen+1+textLen]
+	fields := rest[titleLen+1+textLen:]
+	if fields != "" && fields[0] != '|' {
+		return record, fmt.Errorf("event title and text do not match their lengths: %s", line)
+	}
+
+	attrs := record.Attributes()
+	attrs.PutStr(attrType, typeEvent)
+	attrs.PutStr(attrEventTitle, unescapeNewlines(title))
+	priority := defaultEventPriority
+	alertType := defaultEventAlertType
+	for _, part := range splitFields(fields) {
+		switch {
+		case strings.HasPrefix(part, "d:"):
+			timestamp, err := parseUnixTimestamp(strings.TrimPrefix(part, "d:"))
+			if err != nil {
+				return record, err
+			}
+			record.SetTimestamp(pcommon.NewTimestampFromTime(timestamp))
+		case strings.HasPrefix(part, "h:"):
+			attrs.PutStr(attrHostName, strings.TrimPrefix(part, "h:"))
+		case strings.HasPrefix(part, "p:"):
+			priority = strings.TrimPrefix(part, "p:")
+		case strings.HasPrefix(part, "t:"):
+			alertType = strings.TrimPrefix(part, "t:")
+		case strings.HasPrefix(part, "k:"):
+			attrs.PutStr(attrEventAggKey, strings.TrimPrefix(part, "k:"))
+		case strings.HasPrefix(part, "s:"):
+			attrs.PutStr(attrEventSource, strings.TrimPrefix(part, "s:"))
+		default:
+			if err := parseCommonField(part, attrs); err != nil {
+				return record, err
+			}
+		}
+	}
+	attrs.PutStr(attrEventPrio, priority)
+	attrs.PutStr(attrEventAlert, alertType)
+
+	record.Body().SetStr(unescapeNewlines(text))
+	record.SetSeverityText(alertType)
+	switch alertType {
+	case "error":
+		record.SetSeverityNumber(plog.SeverityNumberError)
+	case "warning":
+		record.SetSeverityNumber(plog.SeverityNumberWarn)
+	default:
+		record.SetSeverityNumber(plog.SeverityNumberInfo)
+	}
+	record.SetObservedTimestamp(pcommon.NewTimestampFromTime(now))
+	return record, nil
+}
+
+// parseServiceCheck parses a service check of the form
+// _sc|<NAME>|<STATUS>|d:<TIMESTAMP>|h:<HOSTNAME>|#<TAGS>|c:<CONTAINER>|m:<MESSAGE>
+// into a log record whose body is the message.
+func parseServiceCheck(line string, now time.Time) (plog.LogRecord, error) {
+	record := plog.NewLogRecord()
+
+	// The message is the last field and may contain the field separator.
+	rest, message, hasMessage := strings.Cut(strings.TrimPrefix(line, serviceCheckPrefix), "|m:")
+	parts := strings.Split(rest, "|")
+	if len(parts) < 2 || parts[0] == "" {
+		return record, fmt.Errorf("invalid service check format: %s", line)
+	}
+	status, err := strconv.Atoi(parts[1])
+	if err != nil || status < 0 || status >= len(serviceCheckStatuses) {
+		return record, fmt.Errorf("invalid service check status: %s", parts[1])
+	}
+
+	attrs := record.Attributes()
+	attrs.PutStr(attrType, typeServiceCheck)
+	attrs.PutStr(attrCheckName, parts[0])
+	attrs.PutStr(attrCheckStatus, serviceCheckStatuses[status].name)
+	for _, part := range parts[2:] {
+		switch {
+		case strings.HasPrefix(part, "d:"):
+			timestamp, err := parseUnixTimestamp(strings.TrimPrefix(part, "d:"))
+			if err != nil {
+				return record, err
+			}
+			record.SetTimestamp(pcommon.NewTimestampFromTime(timestamp))
+		case strings.HasPrefix(part, "h:"):
+			attrs.PutStr(attrHostName, strings.TrimPrefix(part, "h:"))
+		default:
+			if err := parseCommonField(part, attrs); err != nil {
+				return record, err
+			}
+		}
+	}
+
+	if hasMessage {
+		record.Body().SetStr(unescapeNewlines(message))
+	}
+	record.SetSeverityText(strings.ToUpper(serviceCheckStatuses[status].name))
+	record.SetSeverityNumber(serviceCheckStatuses[status].severity)
+	record.SetObservedTimestamp(pcommon.NewTimestampFromTime(now))
+	return record, nil
+}
+
+// parseCommonField parses the tags and container ID fields shared by events and service checks.
+func parseCommonField(part string, attrs pcommon.Map) error {
+	var kvs []attribute.KeyValue
+	switch {
+	case strings.HasPrefix(part, "#"):
+		tags, err := parseTags(strings.TrimPrefix(part, "#"))
+		if err != nil {
+			return err
+		}
+		kvs = tags
+	case strings.HasPrefix(part, containerIDPrefix):
+		kvs = append(kvs, parseOrigin(strings.TrimPrefix(part, containerIDPrefix)))
+	default:
+		return fmt.Errorf("unrecognized message part: %s", part)
+	}
+	for _, kv := range kvs {
+		attrs.PutStr(string(kv.Key), kv.Value.AsString())
+	}
+	return nil
+}
+
+func splitFields(fields string) []string {
+	if fields == "" {
+		return nil
+	}
+	return strings.Split(strings.TrimPrefix(fields, "|"), "|")
+}
+
+// unescapeNewlines restores the line breaks DogStatsD clients escape as \n.
+func unescapeNewlines(s string) string {
+	return strings.ReplaceAll(s, `\n`, "\n")
+}
@@ -0,0 +1,217 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package protocol
+
+import (
+	"errors"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/plog"
+)
+
+func Test_ParseEvent(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	tests := []struct {
+		name     string
+		input    string
+		err      error
+		severity plog.SeverityNumber
+		body     string
+		attrs    map[string]any
+		time     time.Time
+	}{
+		{
+			name:     "minimal event",
+			input:    "_e{5,4}:title|text",
+			severity: plog.SeverityNumberInfo,
+			body:     "text",
+			attrs: map[string]any{
+				"dogstatsd.type":             "event",
+				"dogstatsd.event.title":      "title",
+				"dogstatsd.event.priority":   "normal",
+				"dogstatsd.event.alert_type": "info",
+			},
+		},
+		{
+			name:     "event with all fields",
+			input:    `_e{10,12}:deployment|line1\nline2|d:1656581400|h:web-1|p:low|t:error|k:deploy|s:jenkins|#env:prod,team:web|c:ci-abc123`,
+			severity: plog.SeverityNumberError,
+			body:     "line1\nline2",
+			time:     time.Unix(1656581400, 0),
+			attrs: map[string]any{
+				"dogstatsd.type":                  "event",
+				"dogstatsd.event.title":           "deployment",
+				"dogstatsd.event.priority":        "low",
+				"dogstatsd.event.alert_type":      "error",
+				"dogstatsd.event.aggregation_key": "deploy",
+				"dogstatsd.event.source_type":     "jenkins",
+				"host.name":                       "web-1",
+				"env":                             "prod",
+				"team":                            "web",
+				"container.id":                    "abc123",
+			},
+		},
+		{
+			name:     "event text containing the separator",
+			input:    "_e{5,3}:title|a|b|t:warning",
+			severity: plog.SeverityNumberWarn,
+			body:     "a|b",
+			attrs: map[string]any{
+				"dogstatsd.type":             "event",
+				"dogstatsd.event.title":      "title",
+				"dogstatsd.event.priority":   "normal",
+				"dogstatsd.event.alert_type": "warning",
+			},
+		},
+		{
+			name:  "missing lengths",
+			input: "_e{5}:title|text",
+			err:   errors.New("invalid event format: _e{5}:title|text"),
+		},
+		{
+			name:  "invalid title length",
+			input: "_e{a,4}:title|text",
+			err:   errors.New("invalid event title length: a"),
+		},
+		{
+			name:  "lengths do not match",
+			input: "_e{5,10}:title|text",
+			err:   errors.New("event title and text do not match their lengths: _e{5,10}:title|text"),
+		},
+		{
+			name:  "unrecognized field",
+			input: "_e{5,4}:title|text|x:y",
+			err:   errors.New("unrecognized message part: x:y"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			record, err := parseEvent(tt.input, now)
+			if tt.err != nil {
+				assert.Equal(t, tt.err, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.body, record.Body().Str())
+			assert.Equal(t, tt.severity, record.SeverityNumber())
+			assert.Equal(t, tt.attrs, record.Attributes().AsRaw())
+			assert.Equal(t, pcommon.NewTimestampFromTime(now), record.ObservedTimestamp())
+			if !tt.time.IsZero() {
+				assert.Equal(t, pcommon.NewTimestampFromTime(tt.time), record.Timestamp())
+			}
+		})
+	}
+}
+
+func Test_ParseServiceCheck(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	tests := []struct {
+		name         string
+		input        string
+		err          error
+		severity     plog.SeverityNumber
+		severityText string
+		body         string
+		attrs        map[string]any
+	}{
+		{
+			name:         "minimal service check",
+			input:        "_sc|redis.can_connect|0",
+			severity:     plog.SeverityNumberInfo,
+			severityText: "OK",
+			attrs: map[string]any{
+				"dogstatsd.type":                 "service_check",
+				"dogstatsd.service_check.name":   "redis.can_connect",
+				"dogstatsd.service_check.status": "ok",
+			},
+		},
+		{
+			name:         "service check with all fields",
+			input:        "_sc|redis.can_connect|2|d:1656581400|h:db-1|#env:prod|c:abc123|m:connection refused | retrying",
+			severity:     plog.SeverityNumberError,
+			severityText: "CRITICAL",
+			body:         "connection refused | retrying",
+			attrs: map[string]any{
+				"dogstatsd.type":                 "service_check",
+				"dogstatsd.service_check.name":   "redis.can_connect",
+				"dogstatsd.service_check.status": "critical",
+				"host.name":                      "db-1",
+				"env":                            "prod",
+				"container.id":                   "abc123",
+			},
+		},
+		{
+			name:  "missing status",
+			input: "_sc|redis.can_connect",
+			err:   errors.New("invalid service check format: _sc|redis.can_connect"),
+		},
+		{
+			name:  "invalid status",
+			input: "_sc|redis.can_connect|4",
+			err:   errors.New("invalid service check status: 4"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			record, err := parseServiceCheck(tt.input, now)
+			if tt.err != nil {
+				assert.Equal(t, tt.err, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.body, record.Body().AsString())
+			assert.Equal(t, tt.severity, record.SeverityNumber())
+			assert.Equal(t, tt.severityText, record.SeverityText())
+			assert.Equal(t, tt.attrs, record.Attributes().AsRaw())
+		})
+	}
+}
+
+func TestStatsDParser_AggregateLogs(t *testing.T) {
+	p := &StatsDParser{}
+	require.NoError(t, p.Initialize(false, false, 0, nil))
+	addr1, _ := net.ResolveUDPAddr("udp", "1.2.3.4:5678")
+	addr2, _ := net.ResolveUDPAddr("udp", "1.2.3.4:5679")
+
+	require.NoError(t, p.Aggregate("_e{5,4}:title|text", addr1))
+	require.NoError(t, p.Aggregate("_sc|redis.can_connect|0", addr1))
+	require.NoError(t, p.Aggregate("_sc|redis.can_connect|1", addr2))
+	require.NoError(t, p.Aggregate("test.metric:42|c", addr1))
+	assert.Error(t, p.Aggregate("_sc|redis.can_connect", addr1))
+
+	batches := p.GetLogs()
+	require.Len(t, batches, 2)
+	counts := map[string]int{}
+	for _, batch := range batches {
+		require.Equal(t, 1, batch.Logs.ResourceLogs().Len())
+		sl := batch.Logs.ResourceLogs().At(0).ScopeLogs()
+		require.Equal(t, 1, sl.Len())
+		assert.Equal(t, receiverName, sl.At(0).Scope().Name())
+		counts[batch.Info.Addr.String()] = batch.Logs.LogRecordCount()
+	}
+	assert.Equal(t, map[string]int{"1.2.3.4:5678": 2, "1.2.3.4:5679": 1}, counts)
+	assert.Empty(t, p.GetLogs())
+
+	metrics := p.GetMetrics()
+	require.Len(t, metrics, 1)
+	assert.Equal(t, 1, metrics[0].Metrics.MetricCount())
+}
+
+func TestStatsDParser_AggregateGaugeWithTimestamp(t *testing.T) {
+	p := &StatsDParser{}
+	require.NoError(t, p.Initialize(false, false, 0, nil))
+	addr, _ := net.ResolveUDPAddr("udp", "1.2.3.4:5678")
+
+	require.NoError(t, p.Aggregate("test.gauge:42|g|T1656581400", addr))
+
+	metrics := p.GetMetrics()
+	require.Len(t, metrics, 1)
+	dp := metrics[0].Metrics.ResourceMetrics().At(0).ScopeMetrics().At(0).Metrics().At(0).Gauge().DataPoints().At(0)
+	assert.Equal(t, pcommon.NewTimestampFromTime(time.Unix(1656581400, 0)), dp.Timestamp())
+}
@@ -92,9 +92,13 @@ func buildSummaryMetric(desc statsDMetricDescription, summary summaryMetric, sta
 	}
 }
 
-func buildHistogramMetric(desc statsDMetricDescription, histogram histogramMetric, startTime, timeNow time.Time, ilm pmetric.ScopeMetrics) {
+func buildHistogramMetric(desc statsDMetricDescription, histogram *histogramMetric, startTime, timeNow time.Time, ilm pmetric.ScopeMetrics) {
 	nm := ilm.Metrics().AppendEmpty()
 	nm.SetName(desc.name)
+	if histogram.explicit != nil {
+		buildExplicitHistogram(desc, histogram.explicit, startTime, timeNow, nm)
+		return
+	}
 	expo := nm.SetEmptyExponentialHistogram()
 	expo.SetAggregationTemporality(pmetric.AggregationTemporalityDelta)
 
@@ -137,6 +141,29 @@ func buildHistogramMetric(desc statsDMetricDescription, histogram histogramMetri
 	}
 }
 
+func buildExplicitHistogram(desc statsDMetricDescription, histogram *explicitHistogram, startTime, timeNow time.Time, nm pmetric.Metric) {
+	hist := nm.SetEmptyHistogram()
+	hist.SetAggregationTemporality(pmetric.AggregationTemporalityDelta)
+
+	dp := hist.DataPoints().AppendEmpty()
+	dp.SetCount(histogram.count)
+	dp.SetSum(histogram.sum)
+	if histogram.count != 0 {
+		dp.SetMin(histogram.min)
+		dp.SetMax(histogram.max)
+	}
+
+	dp.SetStartTimestamp(pcommon.NewTimestampFromTime(startTime))
+	dp.SetTimestamp(pcommon.NewTimestampFromTime(timeNow))
+
+	for i := desc.attrs.Iter(); i.Next(); {
+		dp.Attributes().PutStr(string(i.Attribute().Key), i.Attribute().Value.AsString())
+	}
+
+	dp.ExplicitBounds().FromRaw(histogram.bounds)
+	dp.BucketCounts().FromRaw(histogram.counts)
+}
+
 func (s statsDMetric) counterValue() int64 {
 	x := s.asFloat
 	// Note statds counters are always represented as integers.
@@ -155,6 +182,14 @@ func (s statsDMetric) gaugeValue() float64 {
 	return s.asFloat
 }
 
+// timestampOr returns the timestamp sent with the metric, if any, or the given time.
+func (s statsDMetric) timestampOr(t time.Time) time.Time {
+	if s.timestamp.IsZero() {
+		return t
+	}
+	return s.timestamp
+}
+
 func (s statsDMetric) sampleValue() sampleValue {
 	count := 1.0
 	if 0 < s.sampleRate && s.sampleRate < 1 {
@@ -7,6 +7,7 @@ import (
 	"errors"
 	"fmt"
 	"net"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
@@ -15,6 +16,7 @@ import (
 	"go.opentelemetry.io/collector/client"
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/plog"
 	"go.opentelemetry.io/collector/pdata/pmetric"
 	"go.opentelemetry.io/otel/attribute"
 )
@@ -54,6 +56,8 @@ const (
 	DefaultObserverType = DisableObserver
 
 	receiverName = "otelcol/statsdreceiver"
+
+	overflowMetricName = "otel.metric.overflow"
 )
 
 type TimerHistogramMapping struct {
@@ -63,12 +67,21 @@ type TimerHistogramMapping struct {
 }
 
 type HistogramConfig struct {
-	MaxSize int32 `mapstructure:"max_size"`
+	MaxSize  int32                    `mapstructure:"max_size"`
+	Explicit *ExplicitHistogramConfig `mapstructure:"explicit"`
+}
+
+// ExplicitHistogramConfig selects an explicit bucket histogram instead of
+// an exponential one.
+type ExplicitHistogramConfig struct {
+	// Buckets are the upper bounds of the buckets, in increasing order.
+	Buckets []float64 `mapstructure:"buckets"`
 }
 
 type ObserverCategory struct {
 	method          ObserverType
 	histogramConfig structure.Config
+	explicitBounds  []float64
 }
 
 var defaultObserverCategory = ObserverCategory{
@@ -83,6 +96,9 @@ type StatsDParser struct {
 	timerEvents          ObserverCategory
 	histogramEvents      ObserverCategory
 	lastIntervalTime     time.Time
+	seriesLimit          int
+	seriesCount          int
+	logsByAddress        map[netAddr]BatchLogs
 	BuildInfo            component.BuildInfo
 }
 
@@ -91,7 +107,7 @@ type instruments struct {
 	gauges                 map[statsDMetricDescription]pmetric.ScopeMetrics
 	counters               map[statsDMetricDescription]pmetric.ScopeMetrics
 	summaries              map[statsDMetricDescription]summaryMetric
-	histograms             map[statsDMetricDescription]histogramMetric
+	histograms             map[statsDMetricDescription]*histogramMetric
 	timersAndDistributions []pmetric.ScopeMetrics
 }
 
@@ -101,7 +117,7 @@ func newInstruments(addr net.Addr) *instruments {
 		gauges:     make(map[statsDMetricDescription]pmetric.ScopeMetrics),
 		counters:   make(map[statsDMetricDescription]pmetric.ScopeMetrics),
 		summaries:  make(map[statsDMetricDescription]summaryMetric),
-		histograms: make(map[statsDMetricDescription]histogramMetric),
+		histograms: make(map[statsDMetricDescription]*histogramMetric),
 	}
 }
 
@@ -118,7 +134,70 @@ type summaryMetric struct {
 type histogramStructure = structure.Histogram[float64]
 
 type histogramMetric struct {
-	agg *histogramStructure
+	agg      *histogramStructure
+	explicit *explicitHistogram
+	// remainder is the fractional part of the sample weights that
+	// has not been counted yet.
+	remainder float64
+}
+
+func newHistogramMetric(category ObserverCategory) *histogramMetric {
+	if len(category.explicitBounds) > 0 {
+		return &histogramMetric{
+			explicit: newExplicitHistogram(category.explicitBounds),
+		}
+	}
+	agg := new(histogramStructure)
+	agg.Init(category.histogramConfig)
+	return &histogramMetric{
+		agg: agg,
+	}
+}
+
+// record adds a sample weighted by its sample rate. Counts are integers,
+// so the fractional part of the weight is carried over to the next sample
+// of the series to keep the total count unbiased.
+func (h *histogramMetric) record(raw sampleValue) {
+	weight := raw.count + h.remainder
+	incr := uint64(weight)
+	h.remainder = weight - float64(incr)
+	if incr == 0 {
+		return
+	}
+	if h.explicit != nil {
+		h.explicit.update(raw.value, incr)
+		return
+	}
+	h.agg.UpdateByIncr(raw.value, incr)
+}
+
+type explicitHistogram struct {
+	bounds []float64
+	counts []uint64
+	count  uint64
+	sum    float64
+	min    float64
+	max    float64
+}
+
+func newExplicitHistogram(bounds []float64) *explicitHistogram {
+	return &explicitHistogram{
+		bounds: bounds,
+		counts: make([]uint64, len(bounds)+1),
+	}
+}
+
+func (h *explicitHistogram) update(value float64, incr uint64) {
+	// Buckets include their upper bound.
+	h.counts[sort.SearchFloat64s(h.bounds, value)] += incr
+	if h.count == 0 || value < h.min {
+		h.min = value
+	}
+	if h.count == 0 || value > h.max {
+		h.max = value
+	}
+	h.count += incr
+	h.sum += value * float64(incr)
 }
 
 type statsDMetric struct {
@@ -127,6 +206,7 @@ type statsDMetric struct {
 	addition    bool
 	unit        string
 	sampleRate  float64
+	timestamp   time.Time
 }
 
 type statsDMetricDescription struct {
@@ -154,30 +234,44 @@ func (t MetricType) FullName() TypeName {
 func (p *StatsDParser) resetState(when time.Time) {
 	p.lastIntervalTime = when
 	p.instrumentsByAddress = make(map[netAddr]*instruments)
+	p.seriesCount = 0
 }
 
-func (p *StatsDParser) Initialize(enableMetricType bool, isMonotonicCounter bool, sendTimerHistogram []TimerHistogramMapping) error {
+// Initialize prepares the parser, seriesLimit bounds the number of series
+// aggregated per interval when positive.
+func (p *StatsDParser) Initialize(enableMetricType bool, isMonotonicCounter bool, seriesLimit int, sendTimerHistogram []TimerHistogramMapping) error {
 	p.resetState(timeNowFunc())
+	p.logsByAddress = make(map[netAddr]BatchLogs)
 
 	p.histogramEvents = defaultObserverCategory
 	p.timerEvents = defaultObserverCategory
 	p.enableMetricType = enableMetricType
 	p.isMonotonicCounter = isMonotonicCounter
+	p.seriesLimit = seriesLimit
 	// Note: validation occurs in ("../".Config).validate()
 	for _, eachMap := range sendTimerHistogram {
 		switch eachMap.StatsdType {
 		case HistogramTypeName, DistributionTypeName:
-			p.histogramEvents.method = eachMap.ObserverType
-			p.histogramEvents.histogramConfig = expoHistogramConfig(eachMap.Histogram)
+			p.histogramEvents = observerCategory(eachMap)
 		case TimingTypeName, TimingAltTypeName:
-			p.timerEvents.method = eachMap.ObserverType
-			p.timerEvents.histogramConfig = expoHistogramConfig(eachMap.Histogram)
+			p.timerEvents = observerCategory(eachMap)
 		case CounterTypeName, GaugeTypeName:
 		}
 	}
 	return nil
 }
 
+func observerCategory(mapping TimerHistogramMapping) ObserverCategory {
+	category := ObserverCategory{
+		method:          mapping.ObserverType,
+		histogramConfig: expoHistogramConfig(mapping.Histogram),
+	}
+	if mapping.Histogram.Explicit != nil {
+		category.explicitBounds = mapping.Histogram.Explicit.Buckets
+	}
+	return category
+}
+
 func expoHistogramConfig(opts HistogramConfig) structure.Config {
 	var r []structure.Option
 	if opts.MaxSize >= structure.MinSize {
@@ -244,6 +338,32 @@ func (p *StatsDParser) GetMetrics() []BatchMetrics {
 	return batchMetrics
 }
 
+// GetLogs gets the events and service checks received since the last call.
+func (p *StatsDParser) GetLogs() []BatchLogs {
+	batchLogs := make([]BatchLogs, 0, len(p.logsByAddress))
+	for _, batch := range p.logsByAddress {
+		batchLogs = append(batchLogs, batch)
+	}
+	p.logsByAddress = make(map[netAddr]BatchLogs)
+	return batchLogs
+}
+
+func (p *StatsDParser) appendLogRecord(record plog.LogRecord, addr net.Addr) {
+	addrKey := newNetAddr(addr)
+	batch, ok := p.logsByAddress[addrKey]
+	if !ok {
+		batch = BatchLogs{
+			Info: client.Info{
+				Addr: addr,
+			},
+			Logs: plog.NewLogs(),
+		}
+		p.setVersionAndNameScope(batch.Logs.ResourceLogs().AppendEmpty().ScopeLogs().AppendEmpty().Scope())
+		p.logsByAddress[addrKey] = batch
+	}
+	record.MoveTo(batch.Logs.ResourceLogs().At(0).ScopeLogs().At(0).LogRecords().AppendEmpty())
+}
+
 func (p *StatsDParser) copyMetricAndScope(rm pmetric.ResourceMetrics, metric pmetric.ScopeMetrics) {
 	ilm := rm.ScopeMetrics().AppendEmpty()
 	metric.CopyTo(ilm)
@@ -268,8 +388,26 @@ func (p *StatsDParser) observerCategoryFor(t MetricType) ObserverCategory {
 	return defaultObserverCategory
 }
 
-// Aggregate for each metric line.
+// Aggregate for each metric line. DogStatsD events and service checks
+// are kept as log records instead.
 func (p *StatsDParser) Aggregate(line string, addr net.Addr) error {
+	switch {
+	case strings.HasPrefix(line, eventPrefix):
+		record, err := parseEvent(line, timeNowFunc())
+		if err != nil {
+			return err
+		}
+		p.appendLogRecord(record, addr)
+		return nil
+	case strings.HasPrefix(line, serviceCheckPrefix):
+		record, err := parseServiceCheck(line, timeNowFunc())
+		if err != nil {
+			return err
+		}
+		p.appendLogRecord(record, addr)
+		return nil
+	}
+
 	parsedMetric, err := parseMessageToMetric(line, p.enableMetricType)
 	if err != nil {
 		return err
@@ -282,17 +420,25 @@ func (p *StatsDParser) Aggregate(line string, addr net.Addr) error {
 		p.instrumentsByAddress[addrKey] = instrument
 	}
 
+	if p.seriesLimit > 0 && !p.hasSeries(instrument, parsedMetric.description) {
+		if p.seriesCount >= p.seriesLimit {
+			parsedMetric.description = overflowDescription(parsedMetric.description.metricType)
+		} else {
+			p.seriesCount++
+		}
+	}
+
 	switch parsedMetric.description.metricType {
 	case GaugeType:
 		_, ok := instrument.gauges[parsedMetric.description]
 		if !ok {
-			instrument.gauges[parsedMetric.description] = buildGaugeMetric(parsedMetric, timeNowFunc())
+			instrument.gauges[parsedMetric.description] = buildGaugeMetric(parsedMetric, parsedMetric.timestampOr(timeNowFunc()))
 		} else {
 			if parsedMetric.addition {
 				point := instrument.gauges[parsedMetric.description].Metrics().At(0).Gauge().DataPoints().At(0)
 				point.SetDoubleValue(point.DoubleValue() + parsedMetric.gaugeValue())
 			} else {
-				instrument.gauges[parsedMetric.description] = buildGaugeMetric(parsedMetric, timeNowFunc())
+				instrument.gauges[parsedMetric.description] = buildGaugeMetric(parsedMetric, parsedMetric.timestampOr(timeNowFunc()))
 			}
 		}
 
@@ -309,7 +455,7 @@ func (p *StatsDParser) Aggregate(line string, addr net.Addr) error {
 		category := p.observerCategoryFor(parsedMetric.description.metricType)
 		switch category.method {
 		case GaugeObserver:
-			instrument.timersAndDistributions = append(instrument.timersAndDistributions, buildGaugeMetric(parsedMetric, timeNowFunc()))
+			instrument.timersAndDistributions = append(instrument.timersAndDistributions, buildGaugeMetric(parsedMetric, parsedMetric.timestampOr(timeNowFunc())))
 		case SummaryObserver:
 			raw := parsedMetric.sampleValue()
 			if existing, ok := instrument.summaries[parsedMetric.description]; !ok {
@@ -324,22 +470,12 @@ func (p *StatsDParser) Aggregate(line string, addr net.Addr) error {
 				}
 			}
 		case HistogramObserver:
-			raw := parsedMetric.sampleValue()
-			var agg *histogramStructure
-			if existing, ok := instrument.histograms[parsedMetric.description]; ok {
-				agg = existing.agg
-			} else {
-				agg = new(histogramStructure)
-				agg.Init(category.histogramConfig)
-
-				instrument.histograms[parsedMetric.description] = histogramMetric{
-					agg: agg,
-				}
+			histogram, ok := instrument.histograms[parsedMetric.description]
+			if !ok {
+				histogram = newHistogramMetric(category)
+				instrument.histograms[parsedMetric.description] = histogram
 			}
-			agg.UpdateByIncr(
-				raw.value,
-				uint64(raw.count), // Note! Rounding float64 to uint64 here.
-			)
+			histogram.record(parsedMetric.sampleValue())
 
 		case DisableObserver:
 			// No action.
@@ -349,6 +485,39 @@ func (p *StatsDParser) Aggregate(line string, addr net.Addr) error {
 	return nil
 }
 
+// hasSeries reports whether the series of the description is already
+// aggregated in this interval. Timers and distributions observed as gauges
+// are not aggregated and are considered present so that they are not limited.
+func (p *StatsDParser) hasSeries(instrument *instruments, desc statsDMetricDescription) bool {
+	var ok bool
+	switch desc.metricType {
+	case GaugeType:
+		_, ok = instrument.gauges[desc]
+	case CounterType:
+		_, ok = instrument.counters[desc]
+	case TimingType, HistogramType, DistributionType:
+		switch p.observerCategoryFor(desc.metricType).method {
+		case SummaryObserver:
+			_, ok = instrument.summaries[desc]
+		case HistogramObserver:
+			_, ok = instrument.histograms[desc]
+		case GaugeObserver, DisableObserver:
+			ok = true
+		}
+	}
+	return ok
+}
+
+// overflowDescription returns the series aggregating the samples of the
+// series beyond the series limit.
+func overflowDescription(metricType MetricType) statsDMetricDescription {
+	return statsDMetricDescription{
+		name:       overflowMetricName,
+		metricType: metricType,
+		attrs:      attribute.NewSet(attribute.String(overflowMetricName, "true")),
+	}
+}
+
 func parseMessageToMetric(line string, enableMetricType bool) (statsDMetric, error) {
 	result := statsDMetric{}
 
@@ -398,25 +567,19 @@ func parseMessageToMetric(line string, enableMetricType bool) (statsDMetric, err
 
 			result.sampleRate = f
 		case strings.HasPrefix(part, "#"):
-			tagsStr := strings.TrimPrefix(part, "#")
-
-			// handle an empty tag set
-			// where the tags part was still sent (some clients do this)
-			if len(tagsStr) == 0 {
-				continue
+			tags, err := parseTags(strings.TrimPrefix(part, "#"))
+			if err != nil {
+				return result, err
 			}
-
-			tagSets := strings.Split(tagsStr, ",")
-
-			for _, tagSet := range tagSets {
-				tagParts := strings.SplitN(tagSet, ":", 2)
-				if len(tagParts) != 2 {
-					return result, fmt.Errorf("invalid tag format: %s", tagParts)
-				}
-				k := tagParts[0]
-				v := tagParts[1]
-				kvs = append(kvs, attribute.String(k, v))
+			kvs = append(kvs, tags...)
+		case strings.HasPrefix(part, containerIDPrefix):
+			kvs = append(kvs, parseOrigin(strings.TrimPrefix(part, containerIDPrefix)))
+		case strings.HasPrefix(part, timestampPrefix):
+			timestamp, err := parseUnixTimestamp(strings.TrimPrefix(part, timestampPrefix))
+			if err != nil {
+				return result, err
 			}
+			result.timestamp = timestamp
 		default:
 			return result, fmt.Errorf("unrecognized message part: %s", part)
 		}
@@ -441,6 +604,25 @@ func parseMessageToMetric(line string, enableMetricType bool) (statsDMetric, err
 	return result, nil
 }
 
+// parseTags parses the comma separated key:value pairs of a tags field.
+func parseTags(tagsStr string) ([]attribute.KeyValue, error) {
+	// handle an empty tag set
+	// where the tags part was still sent (some clients do this)
+	if len(tagsStr) == 0 {
+		return nil, nil
+	}
+
+	var kvs []attribute.KeyValue
+	for _, tagSet := range strings.Split(tagsStr, ",") {
+		tagParts := strings.SplitN(tagSet, ":", 2)
+		if len(tagParts) != 2 {
+			return nil, fmt.Errorf("invalid tag format: %s", tagParts)
+		}
+		kvs = append(kvs, attribute.String(tagParts[0], tagParts[1]))
+	}
+	return kvs, nil
+}
+
 type netAddr struct {
 	Network string
 	String  string
@@ -231,6 +231,60 @@ func Test_ParseMessageToMetric(t *testing.T) {
 				false,
 				"h", 0, nil, nil),
 		},
+		{
+			name:  "counter with container ID",
+			input: "test.metric:42|c|#key:value|c:abc123",
+			wantMetric: testStatsDMetric(
+				"test.metric",
+				42,
+				false,
+				"c",
+				0,
+				[]string{"container.id", "key"},
+				[]string{"abc123", "value"}),
+		},
+		{
+			name:  "counter with container ID origin detection",
+			input: "test.metric:42|c|c:ci-abc123",
+			wantMetric: testStatsDMetric(
+				"test.metric",
+				42,
+				false,
+				"c",
+				0,
+				[]string{"container.id"},
+				[]string{"abc123"}),
+		},
+		{
+			name:  "counter with cgroup inode origin detection",
+			input: "test.metric:42|c|c:in-4026531835",
+			wantMetric: testStatsDMetric(
+				"test.metric",
+				42,
+				false,
+				"c",
+				0,
+				[]string{"dogstatsd.origin.cgroup_inode"},
+				[]string{"4026531835"}),
+		},
+		{
+			name:  "gauge with timestamp",
+			input: "test.gauge:42|g|T1656581400",
+			wantMetric: func() statsDMetric {
+				m := testStatsDMetric(
+					"test.gauge",
+					42,
+					false,
+					"g", 0, nil, nil)
+				m.timestamp = time.Unix(1656581400, 0)
+				return m
+			}(),
+		},
+		{
+			name:  "invalid timestamp",
+			input: "test.gauge:42|g|T16565814a",
+			err:   errors.New("parse timestamp: 16565814a"),
+		},
 	}
 
 	for _, tt := range tests {
@@ -677,7 +731,7 @@ func TestStatsDParser_Aggregate(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			var err error
 			p := &StatsDParser{}
-			assert.NoError(t, p.Initialize(false, false, []TimerHistogramMapping{{StatsdType: "timer", ObserverType: "gauge"}, {StatsdType: "histogram", ObserverType: "gauge"}}))
+			assert.NoError(t, p.Initialize(false, false, 0, []TimerHistogramMapping{{StatsdType: "timer", ObserverType: "gauge"}, {StatsdType: "histogram", ObserverType: "gauge"}}))
 			p.lastIntervalTime = time.Unix(611, 0)
 			addr, _ := net.ResolveUDPAddr("udp", "1.2.3.4:5678")
 			addrKey := newNetAddr(addr)
@@ -746,7 +800,7 @@ func TestStatsDParser_AggregateByAddress(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			p := &StatsDParser{}
-			assert.NoError(t, p.Initialize(true, false, []TimerHistogramMapping{{StatsdType: "timer", ObserverType: "gauge"}, {StatsdType: "histogram", ObserverType: "gauge"}}))
+			assert.NoError(t, p.Initialize(true, false, 0, []TimerHistogramMapping{{StatsdType: "timer", ObserverType: "gauge"}, {StatsdType: "histogram", ObserverType: "gauge"}}))
 			p.lastIntervalTime = time.Unix(611, 0)
 			for i, addr := range tt.addresses {
 				for _, line := range tt.input[i] {
@@ -814,7 +868,7 @@ func TestStatsDParser_AggregateWithMetricType(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			var err error
 			p := &StatsDParser{}
-			assert.NoError(t, p.Initialize(true, false, []TimerHistogramMapping{{StatsdType: "timer", ObserverType: "gauge"}, {StatsdType: "histogram", ObserverType: "gauge"}}))
+			assert.NoError(t, p.Initialize(true, false, 0, []TimerHistogramMapping{{StatsdType: "timer", ObserverType: "gauge"}, {StatsdType: "histogram", ObserverType: "gauge"}}))
 			p.lastIntervalTime = time.Unix(611, 0)
 			addr, _ := net.ResolveUDPAddr("udp", "1.2.3.4:5678")
 			addrKey := newNetAddr(addr)
@@ -864,7 +918,7 @@ func TestStatsDParser_AggregateWithIsMonotonicCounter(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			var err error
 			p := &StatsDParser{}
-			assert.NoError(t, p.Initialize(false, true, []TimerHistogramMapping{{StatsdType: "timer", ObserverType: "gauge"}, {StatsdType: "histogram", ObserverType: "gauge"}}))
+			assert.NoError(t, p.Initialize(false, true, 0, []TimerHistogramMapping{{StatsdType: "timer", ObserverType: "gauge"}, {StatsdType: "histogram", ObserverType: "gauge"}}))
 			p.lastIntervalTime = time.Unix(611, 0)
 			addr, _ := net.ResolveUDPAddr("udp", "1.2.3.4:5678")
 			addrKey := newNetAddr(addr)
@@ -986,7 +1040,7 @@ func TestStatsDParser_AggregateTimerWithSummary(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			var err error
 			p := &StatsDParser{}
-			assert.NoError(t, p.Initialize(false, false, []TimerHistogramMapping{{StatsdType: "timer", ObserverType: "summary"}, {StatsdType: "histogram", ObserverType: "summary"}}))
+			assert.NoError(t, p.Initialize(false, false, 0, []TimerHistogramMapping{{StatsdType: "timer", ObserverType: "summary"}, {StatsdType: "histogram", ObserverType: "summary"}}))
 			addr, _ := net.ResolveUDPAddr("udp", "1.2.3.4:5678")
 			addrKey := newNetAddr(addr)
 			for _, line := range tt.input {
@@ -1003,7 +1057,7 @@ func TestStatsDParser_AggregateTimerWithSummary(t *testing.T) {
 
 func TestStatsDParser_Initialize(t *testing.T) {
 	p := &StatsDParser{}
-	assert.NoError(t, p.Initialize(true, false, []TimerHistogramMapping{{StatsdType: "timer", ObserverType: "gauge"}, {StatsdType: "histogram", ObserverType: "gauge"}}))
+	assert.NoError(t, p.Initialize(true, false, 0, []TimerHistogramMapping{{StatsdType: "timer", ObserverType: "gauge"}, {StatsdType: "histogram", ObserverType: "gauge"}}))
 	teststatsdDMetricdescription := statsDMetricDescription{
 		name:       "test",
 		metricType: "g",
@@ -1022,7 +1076,7 @@ func TestStatsDParser_Initialize(t *testing.T) {
 
 func TestStatsDParser_GetMetricsWithMetricType(t *testing.T) {
 	p := &StatsDParser{}
-	assert.NoError(t, p.Initialize(true, false, []TimerHistogramMapping{{StatsdType: "timer", ObserverType: "gauge"}, {StatsdType: "histogram", ObserverType: "gauge"}}))
+	assert.NoError(t, p.Initialize(true, false, 0, []TimerHistogramMapping{{StatsdType: "timer", ObserverType: "gauge"}, {StatsdType: "histogram", ObserverType: "gauge"}}))
 	instrument := newInstruments(nil)
 	instrument.gauges[testDescription("statsdTestMetric1", "g",
 		[]string{"mykey", "metric_type"}, []string{"myvalue", "gauge"})] = buildGaugeMetric(testStatsDMetric("testGauge1", 1, false, "g", 0, []string{"mykey", "metric_type"}, []string{"myvalue", "gauge"}), time.Unix(711, 0))
@@ -1095,7 +1149,7 @@ func TestStatsDParser_Mappings(t *testing.T) {
 		t.Run(tc.name, func(t *testing.T) {
 			p := &StatsDParser{}
 
-			assert.NoError(t, p.Initialize(false, false, tc.mapping))
+			assert.NoError(t, p.Initialize(false, false, 0, tc.mapping))
 
 			addr, _ := net.ResolveUDPAddr("udp", "1.2.3.4:5678")
 			assert.NoError(t, p.Aggregate("H:10|h", addr))
@@ -1129,7 +1183,7 @@ func TestStatsDParser_ScopeIsIncluded(t *testing.T) {
 	}
 	testAddress, _ := net.ResolveUDPAddr("udp", "1.2.3.4:5678")
 
-	err := p.Initialize(true, false,
+	err := p.Initialize(true, false, 0,
 		[]TimerHistogramMapping{
 			{StatsdType: "timer", ObserverType: "summary"},
 			{StatsdType: "histogram", ObserverType: "histogram"},
@@ -1367,6 +1421,28 @@ func TestStatsDParser_AggregateTimerWithHistogram(t *testing.T) {
 			}(),
 			mapping: normalMapping,
 		},
+		{
+			name: "sampled_fractional_weights",
+			input: []string{
+				"expohisto:1|h|@0.3|#mykey:myvalue",
+				"expohisto:1|h|@0.3|#mykey:myvalue",
+				"expohisto:1|h|@0.3|#mykey:myvalue",
+			},
+			expected: func() pmetric.Metrics {
+				data, dp := newPoint()
+				dp.SetCount(10) // 3 * (1 / 0.3), carrying over the fractional weights.
+				dp.SetSum(10)
+				dp.SetMin(1)
+				dp.SetMax(1)
+				dp.SetScale(logarithm.MaxScale)
+				dp.Positive().SetOffset(-1)
+				dp.Positive().BucketCounts().FromRaw([]uint64{
+					10,
+				})
+				return data
+			}(),
+			mapping: normalMapping,
+		},
 		{
 			name: "one_each_distribution",
 			input: []string{
@@ -1399,7 +1475,7 @@ func TestStatsDParser_AggregateTimerWithHistogram(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			var err error
 			p := &StatsDParser{}
-			assert.NoError(t, p.Initialize(false, false, tt.mapping))
+			assert.NoError(t, p.Initialize(false, false, 0, tt.mapping))
 			addr, _ := net.ResolveUDPAddr("udp", "1.2.3.4:5678")
 			for _, line := range tt.input {
 				err = p.Aggregate(line, addr)
@@ -1410,3 +1486,87 @@ func TestStatsDParser_AggregateTimerWithHistogram(t *testing.T) {
 		})
 	}
 }
+
+func TestStatsDParser_AggregateTimerWithExplicitHistogram(t *testing.T) {
+	timeNowFunc = func() time.Time {
+		return time.Unix(711, 0)
+	}
+	p := &StatsDParser{}
+	assert.NoError(t, p.Initialize(false, false, 0, []TimerHistogramMapping{
+		{
+			StatsdType:   "timer",
+			ObserverType: "histogram",
+			Histogram: HistogramConfig{
+				Explicit: &ExplicitHistogramConfig{
+					Buckets: []float64{10, 100},
+				},
+			},
+		},
+	}))
+	addr, _ := net.ResolveUDPAddr("udp", "1.2.3.4:5678")
+	for _, line := range []string{
+		"latency:5|ms|#mykey:myvalue",
+		"latency:10|ms|#mykey:myvalue",
+		"latency:50|ms|@0.5|#mykey:myvalue",
+		"latency:500|ms|@0.4|#mykey:myvalue",
+		"latency:500|ms|@0.4|#mykey:myvalue",
+	} {
+		assert.NoError(t, p.Aggregate(line, addr))
+	}
+
+	expected := pmetric.NewMetrics()
+	m := expected.ResourceMetrics().AppendEmpty().ScopeMetrics().AppendEmpty().Metrics().AppendEmpty()
+	m.SetName("latency")
+	h := m.SetEmptyHistogram()
+	h.SetAggregationTemporality(pmetric.AggregationTemporalityDelta)
+	dp := h.DataPoints().AppendEmpty()
+	dp.Attributes().PutStr("mykey", "myvalue")
+	dp.SetCount(9)
+	dp.SetSum(5 + 10 + 2*50 + 5*500)
+	dp.SetMin(5)
+	dp.SetMax(500)
+	dp.ExplicitBounds().FromRaw([]float64{10, 100})
+	dp.BucketCounts().FromRaw([]uint64{2, 2, 5})
+
+	var nodiffs []*metricstestutil.MetricDiff
+	assert.Equal(t, nodiffs, metricstestutil.DiffMetrics(nodiffs, expected, p.GetMetrics()[0].Metrics))
+}
+
+func TestStatsDParser_SeriesLimit(t *testing.T) {
+	p := &StatsDParser{}
+	assert.NoError(t, p.Initialize(false, false, 2, []TimerHistogramMapping{
+		{StatsdType: "timer", ObserverType: "gauge"},
+		{StatsdType: "histogram", ObserverType: "summary"},
+	}))
+	addr1, _ := net.ResolveUDPAddr("udp", "1.2.3.4:5678")
+	addr2, _ := net.ResolveUDPAddr("udp", "1.2.3.4:5679")
+
+	for _, line := range []string{
+		"counter:1|c|#key:a",
+		"counter:1|c|#key:a",
+		"counter:1|c|#key:b",
+		"counter:2|c|#key:c",
+		"other:3|c",
+		"histogram:3|h",
+		"timer:3|ms",
+	} {
+		assert.NoError(t, p.Aggregate(line, addr1))
+	}
+	assert.NoError(t, p.Aggregate("counter:1|c|#key:a", addr2))
+
+	overflow := overflowDescription(CounterType)
+	instrument := p.instrumentsByAddress[newNetAddr(addr1)]
+	assert.Len(t, instrument.counters, 3)
+	assert.Contains(t, instrument.counters, testDescription("counter", "c", []string{"key"}, []string{"a"}))
+	assert.Contains(t, instrument.counters, testDescription("counter", "c", []string{"key"}, []string{"b"}))
+	require.Contains(t, instrument.counters, overflow)
+	assert.Equal(t, int64(5), instrument.counters[overflow].Metrics().At(0).Sum().DataPoints().At(0).IntValue())
+	assert.Equal(t, "otel.metric.overflow", instrument.counters[overflow].Metrics().At(0).Name())
+	assert.Contains(t, instrument.summaries, overflowDescription(HistogramType))
+	assert.Len(t, instrument.timersAndDistributions, 1, "timers observed as gauges are not limited")
+	assert.Contains(t, p.instrumentsByAddress[newNetAddr(addr2)].counters, overflow, "the limit applies across addresses")
+
+	p.GetMetrics()
+	assert.NoError(t, p.Aggregate("other:3|c", addr1))
+	assert.Contains(t, p.instrumentsByAddress[newNetAddr(addr1)].counters, statsDMetricDescription{name: "other", metricType: "c"}, "the limit is reset every interval")
+}
@@ -8,8 +8,6 @@ import (
 	"errors"
 	"net"
 
-	"go.opentelemetry.io/collector/consumer"
-
 	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/statsdreceiver/internal/protocol"
 )
 
@@ -20,10 +18,9 @@ var errNilListenAndServeParameters = errors.New("no parameter of ListenAndServe
 type Server interface {
 	// ListenAndServe is a blocking call that starts to listen for client messages
 	// on the specific transport, and prepares the message to be processed by
-	// the Parser and passed to the next consumer.
+	// the Parser.
 	ListenAndServe(
 		p protocol.Parser,
-		mc consumer.Metrics,
 		r Reporter,
 		transferChan chan<- Metric,
 	) error
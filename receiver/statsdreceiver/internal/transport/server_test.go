@@ -5,6 +5,8 @@ package transport
 
 import (
 	"net"
+	"os"
+	"path/filepath"
 	"runtime"
 	"strconv"
 	"sync"
@@ -13,7 +15,6 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
-	"go.opentelemetry.io/collector/consumer/consumertest"
 
 	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/common/testutil"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/statsdreceiver/internal/protocol"
@@ -73,45 +74,111 @@ func Test_Server_ListenAndServe(t *testing.T) {
 			port, err := strconv.Atoi(portStr)
 			require.NoError(t, err)
 
-			mc := new(consumertest.MetricsSink)
-			p := &protocol.StatsDParser{}
-			require.NoError(t, err)
-			mr := NewMockReporter(1)
-			transferChan := make(chan Metric, 10)
+			gc := func() (*client.StatsD, error) {
+				return tt.buildClientFn(host, port)
+			}
+			testServerReceivesMetric(t, srv, gc)
+		})
+	}
+}
 
-			wgListenAndServe := sync.WaitGroup{}
-			wgListenAndServe.Add(1)
-			go func() {
-				defer wgListenAndServe.Done()
-				assert.Error(t, srv.ListenAndServe(p, mc, mr, transferChan))
-			}()
+func Test_Server_ListenAndServe_Unix(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("unix sockets are not supported on windows")
+	}
+	tests := []struct {
+		name          string
+		transport     string
+		buildServerFn func(path string) (Server, error)
+	}{
+		{
+			name:          "unixgram",
+			transport:     "unixgram",
+			buildServerFn: NewUnixgramServer,
+		},
+		{
+			name:          "unix",
+			transport:     "unix",
+			buildServerFn: NewUnixServer,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "statsd.sock")
 
-			runtime.Gosched()
+			// A socket left behind by a previous run must not prevent the server from starting.
+			stale, err := net.Listen("unix", path)
+			require.NoError(t, err)
+			stale.(*net.UnixListener).SetUnlinkOnClose(false)
+			require.NoError(t, stale.Close())
 
-			gc, err := tt.buildClientFn(host, port)
+			srv, err := tt.buildServerFn(path)
 			require.NoError(t, err)
-			require.NotNil(t, gc)
-			err = gc.SendMetric(client.Metric{
-				Name:  "test.metric",
-				Value: "42",
-				Type:  "c",
-			})
-			assert.NoError(t, err)
-			runtime.Gosched()
-			err = gc.Disconnect()
-			assert.NoError(t, err)
-
-			// Keep trying until we're timed out or got a result
-			assert.Eventually(t, func() bool {
-				return len(transferChan) > 0
-			}, 10*time.Second, 500*time.Millisecond)
-
-			// Close the server connection, this will cause ListenAndServer to error out and the deferred wgListenAndServe.Done will fire
-			err = srv.Close()
-			assert.NoError(t, err)
-
-			wgListenAndServe.Wait()
-			assert.Equal(t, 1, len(transferChan))
+			require.NotNil(t, srv)
+
+			gc := func() (*client.StatsD, error) {
+				conn, err := net.Dial(tt.transport, path)
+				if err != nil {
+					return nil, err
+				}
+				return &client.StatsD{Conn: conn}, nil
+			}
+			testServerReceivesMetric(t, srv, gc)
+			assert.NoFileExists(t, path)
 		})
 	}
 }
+
+func testServerReceivesMetric(t *testing.T, srv Server, buildClientFn func() (*client.StatsD, error)) {
+	p := &protocol.StatsDParser{}
+	mr := NewMockReporter(1)
+	transferChan := make(chan Metric, 10)
+
+	wgListenAndServe := sync.WaitGroup{}
+	wgListenAndServe.Add(1)
+	go func() {
+		defer wgListenAndServe.Done()
+		assert.Error(t, srv.ListenAndServe(p, mr, transferChan))
+	}()
+
+	runtime.Gosched()
+
+	gc, err := buildClientFn()
+	require.NoError(t, err)
+	require.NotNil(t, gc)
+	err = gc.SendMetric(client.Metric{
+		Name:  "test.metric",
+		Value: "42",
+		Type:  "c",
+	})
+	assert.NoError(t, err)
+	runtime.Gosched()
+	err = gc.Disconnect()
+	assert.NoError(t, err)
+
+	// Keep trying until we're timed out or got a result
+	assert.Eventually(t, func() bool {
+		return len(transferChan) > 0
+	}, 10*time.Second, 500*time.Millisecond)
+
+	// Close the server connection, this will cause ListenAndServer to error out and the deferred wgListenAndServe.Done will fire
+	err = srv.Close()
+	assert.NoError(t, err)
+
+	wgListenAndServe.Wait()
+	require.Equal(t, 1, len(transferChan))
+	metric := <-transferChan
+	assert.Equal(t, "test.metric:42|c", metric.Raw)
+	assert.NotNil(t, metric.Addr)
+}
+
+func TestNewUnixServer_notASocket(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "statsd.sock")
+	require.NoError(t, os.WriteFile(path, nil, 0600))
+
+	_, err := NewUnixgramServer(path)
+	assert.ErrorContains(t, err, "is not a socket")
+	_, err = NewUnixServer(path)
+	assert.ErrorContains(t, err, "is not a socket")
+	assert.FileExists(t, path)
+}
@@ -11,18 +11,17 @@ import (
 	"strings"
 	"sync"
 
-	"go.opentelemetry.io/collector/consumer"
-
 	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/statsdreceiver/internal/protocol"
 )
 
 var errTCPServerDone = errors.New("server stopped")
 
 type tcpServer struct {
-	listener net.Listener
-	reporter Reporter
-	wg       sync.WaitGroup
-	stopChan chan struct{}
+	listener  net.Listener
+	transport string
+	reporter  Reporter
+	wg        sync.WaitGroup
+	stopChan  chan struct{}
 }
 
 var _ Server = (*tcpServer)(nil)
@@ -35,14 +34,15 @@ func NewTCPServer(addr string) (Server, error) {
 	}
 
 	t := tcpServer{
-		listener: l,
-		stopChan: make(chan struct{}),
+		listener:  l,
+		transport: "TCP",
+		stopChan:  make(chan struct{}),
 	}
 	return &t, nil
 }
 
-func (t *tcpServer) ListenAndServe(parser protocol.Parser, nextConsumer consumer.Metrics, reporter Reporter, transferChan chan<- Metric) error {
-	if parser == nil || nextConsumer == nil || reporter == nil {
+func (t *tcpServer) ListenAndServe(parser protocol.Parser, reporter Reporter, transferChan chan<- Metric) error {
+	if parser == nil || reporter == nil {
 		return errNilListenAndServeParameters
 	}
 
@@ -53,7 +53,8 @@ LOOP:
 		go func() {
 			c, err := t.listener.Accept()
 			if err != nil {
-				t.reporter.OnDebugf("TCP Transport - Accept error: %v",
+				t.reporter.OnDebugf("%s Transport - Accept error: %v",
+					t.transport,
 					err)
 			} else {
 				connChan <- c
@@ -77,7 +78,7 @@ func (t *tcpServer) handleConn(c net.Conn, transferChan chan<- Metric) {
 	for {
 		n, err := c.Read(payload)
 		if err != nil {
-			t.reporter.OnDebugf("TCP transport (%s) Error reading payload: %v", c.LocalAddr(), err)
+			t.reporter.OnDebugf("%s transport (%s) Error reading payload: %v", t.transport, c.LocalAddr(), err)
 			t.wg.Done()
 			return
 		}
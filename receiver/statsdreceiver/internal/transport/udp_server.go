@@ -10,13 +10,12 @@ import (
 	"net"
 	"strings"
 
-	"go.opentelemetry.io/collector/consumer"
-
 	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/statsdreceiver/internal/protocol"
 )
 
 type udpServer struct {
 	packetConn net.PacketConn
+	transport  string
 	reporter   Reporter
 }
 
@@ -31,17 +30,17 @@ func NewUDPServer(addr string) (Server, error) {
 
 	u := udpServer{
 		packetConn: packetConn,
+		transport:  "UDP",
 	}
 	return &u, nil
 }
 
 func (u *udpServer) ListenAndServe(
 	parser protocol.Parser,
-	nextConsumer consumer.Metrics,
 	reporter Reporter,
 	transferChan chan<- Metric,
 ) error {
-	if parser == nil || nextConsumer == nil || reporter == nil {
+	if parser == nil || reporter == nil {
 		return errNilListenAndServeParameters
 	}
 
@@ -53,10 +52,15 @@ func (u *udpServer) ListenAndServe(
 		if n > 0 {
 			bufCopy := make([]byte, n)
 			copy(bufCopy, buf)
+			if addr == nil {
+				// Unix datagram clients are usually not bound to a path.
+				addr = u.packetConn.LocalAddr()
+			}
 			u.handlePacket(bufCopy, addr, transferChan)
 		}
 		if err != nil {
-			u.reporter.OnDebugf("UDP Transport (%s) - ReadFrom error: %v",
+			u.reporter.OnDebugf("%s Transport (%s) - ReadFrom error: %v",
+				u.transport,
 				u.packetConn.LocalAddr(),
 				err)
 			var netErr net.Error
@@ -0,0 +1,83 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package transport // import "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/statsdreceiver/internal/transport"
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"net"
+	"os"
+)
+
+type unixgramServer struct {
+	udpServer
+	path string
+}
+
+var _ Server = (*unixgramServer)(nil)
+
+// NewUnixgramServer creates a transport.Server using Unix datagram sockets as its transport.
+func NewUnixgramServer(path string) (Server, error) {
+	if err := removeStaleSocket(path); err != nil {
+		return nil, err
+	}
+	packetConn, err := net.ListenPacket("unixgram", path)
+	if err != nil {
+		return nil, err
+	}
+
+	u := unixgramServer{
+		udpServer: udpServer{
+			packetConn: packetConn,
+			transport:  "Unixgram",
+		},
+		path: path,
+	}
+	return &u, nil
+}
+
+// Close closes the socket and removes its file, which unlike for stream
+// sockets is not done by the standard library.
+func (u *unixgramServer) Close() error {
+	err := u.udpServer.Close()
+	if rmErr := os.Remove(u.path); rmErr != nil && !errors.Is(rmErr, fs.ErrNotExist) {
+		err = errors.Join(err, rmErr)
+	}
+	return err
+}
+
+// NewUnixServer creates a transport.Server using Unix stream sockets as its transport.
+func NewUnixServer(path string) (Server, error) {
+	if err := removeStaleSocket(path); err != nil {
+		return nil, err
+	}
+	l, err := net.Listen("unix", path)
+	if err != nil {
+		return nil, err
+	}
+
+	t := tcpServer{
+		listener:  l,
+		transport: "Unix",
+		stopChan:  make(chan struct{}),
+	}
+	return &t, nil
+}
+
+// removeStaleSocket removes a socket file left behind by a previous run,
+// refusing to remove anything that is not a socket.
+func removeStaleSocket(path string) error {
+	info, err := os.Stat(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if info.Mode()&fs.ModeSocket == 0 {
+		return fmt.Errorf("%q already exists and is not a socket", path)
+	}
+	return os.Remove(path)
+}
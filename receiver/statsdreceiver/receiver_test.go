@@ -7,6 +7,8 @@ import (
 	"context"
 	"errors"
 	"net"
+	"path/filepath"
+	"runtime"
 	"strconv"
 	"testing"
 	"time"
@@ -18,6 +20,7 @@ import (
 	"go.opentelemetry.io/collector/config/confignet"
 	"go.opentelemetry.io/collector/consumer"
 	"go.opentelemetry.io/collector/consumer/consumertest"
+	"go.opentelemetry.io/collector/pdata/plog"
 	"go.opentelemetry.io/collector/pdata/pmetric"
 	"go.opentelemetry.io/collector/receiver/receivertest"
 
@@ -197,3 +200,47 @@ func Test_statsdreceiver_EndToEnd(t *testing.T) {
 		})
 	}
 }
+
+func Test_statsdreceiver_Unixgram_EndToEnd(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("unix sockets are not supported on windows")
+	}
+	path := filepath.Join(t.TempDir(), "dsd.socket")
+	cfg := &Config{
+		NetAddr: confignet.NetAddr{
+			Endpoint:  path,
+			Transport: "unixgram",
+		},
+		AggregationInterval: 100 * time.Millisecond,
+	}
+	metricsSink := new(consumertest.MetricsSink)
+	logsSink := new(consumertest.LogsSink)
+	r, err := newStatsdReceiver(receivertest.NewNopCreateSettings(), *cfg)
+	require.NoError(t, err)
+	r.nextConsumer = metricsSink
+	r.nextLogsConsumer = logsSink
+
+	require.NoError(t, r.Start(context.Background(), componenttest.NewNopHost()))
+	defer func() {
+		assert.NoError(t, r.Shutdown(context.Background()))
+	}()
+
+	conn, err := net.Dial("unixgram", path)
+	require.NoError(t, err)
+	defer conn.Close()
+	_, err = conn.Write([]byte("test.metric:42|c|c:abc123\n_sc|redis.can_connect|2|m:connection refused\n"))
+	require.NoError(t, err)
+
+	assert.Eventually(t, func() bool {
+		return metricsSink.DataPointCount() == 1 && logsSink.LogRecordCount() == 1
+	}, 10*time.Second, 100*time.Millisecond)
+
+	dp := metricsSink.AllMetrics()[0].ResourceMetrics().At(0).ScopeMetrics().At(0).Metrics().At(0).Sum().DataPoints().At(0)
+	containerID, ok := dp.Attributes().Get("container.id")
+	require.True(t, ok)
+	assert.Equal(t, "abc123", containerID.Str())
+
+	record := logsSink.AllLogs()[0].ResourceLogs().At(0).ScopeLogs().At(0).LogRecords().At(0)
+	assert.Equal(t, "connection refused", record.Body().Str())
+	assert.Equal(t, plog.SeverityNumberError, record.SeverityNumber())
+}
@@ -0,0 +1,54 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package stdinreceiver // import "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/stdinreceiver"
+
+import (
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/receiver"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/adapter"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/operator"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/operator/input/stdin"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/stdinreceiver/internal/metadata"
+)
+
+// NewFactory creates a factory for stdin receiver
+func NewFactory() receiver.Factory {
+	return adapter.NewFactory(ReceiverType{}, metadata.LogsStability)
+}
+
+// ReceiverType implements adapter.LogReceiverType
+// to create a stdin receiver
+type ReceiverType struct{}
+
+// Type is the receiver type
+func (f ReceiverType) Type() component.Type {
+	return metadata.Type
+}
+
+// CreateDefaultConfig creates a config with type and version
+func (f ReceiverType) CreateDefaultConfig() component.Config {
+	return &StdinConfig{
+		BaseConfig: adapter.BaseConfig{
+			Operators: []operator.Config{},
+		},
+		InputConfig: *stdin.NewConfig(""),
+	}
+}
+
+// BaseConfig gets the base config from config, for now
+func (f ReceiverType) BaseConfig(cfg component.Config) adapter.BaseConfig {
+	return cfg.(*StdinConfig).BaseConfig
+}
+
+// StdinConfig defines configuration for the stdin receiver
+type StdinConfig struct {
+	InputConfig        stdin.Config `mapstructure:",squash"`
+	adapter.BaseConfig `mapstructure:",squash"`
+}
+
+// InputConfig unmarshals the input operator
+func (f ReceiverType) InputConfig(cfg component.Config) operator.Config {
+	return operator.NewConfig(&cfg.(*StdinConfig).InputConfig)
+}
@@ -0,0 +1,111 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package stdinreceiver
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/confmap/confmaptest"
+	"go.opentelemetry.io/collector/consumer/consumertest"
+	"go.opentelemetry.io/collector/receiver/receivertest"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/adapter"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/operator"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/operator/input/stdin"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/split"
+)
+
+func TestStdin(t *testing.T) {
+	r, w, err := os.Pipe()
+	require.NoError(t, err)
+
+	// The input reads the stdin of the process when it is built
+	stdinFile := os.Stdin
+	os.Stdin = r
+	defer func() {
+		os.Stdin = stdinFile
+	}()
+
+	f := NewFactory()
+	sink := new(consumertest.LogsSink)
+	rcvr, err := f.CreateLogsReceiver(context.Background(), receivertest.NewNopCreateSettings(), testdataConfigYaml(), sink)
+	require.NoError(t, err)
+	require.NoError(t, rcvr.Start(context.Background(), componenttest.NewNopHost()))
+
+	_, err = w.WriteString("\x1e{\"msg\": \"first\"}\n\x1e{\n  \"msg\": \"second\"\n}\n")
+	require.NoError(t, err)
+	require.NoError(t, w.Close())
+
+	require.Eventually(t, expectNLogs(sink, 2), 2*time.Second, time.Millisecond)
+	require.NoError(t, rcvr.Shutdown(context.Background()))
+
+	var bodies []string
+	for _, logs := range sink.AllLogs() {
+		records := logs.ResourceLogs().At(0).ScopeLogs().At(0).LogRecords()
+		for i := 0; i < records.Len(); i++ {
+			bodies = append(bodies, records.At(i).Body().Str())
+		}
+	}
+	assert.Equal(t, []string{`{"msg": "first"}`, "{\n  \"msg\": \"second\"\n}"}, bodies)
+}
+
+func TestLoadConfig(t *testing.T) {
+	cm, err := confmaptest.LoadConf(filepath.Join("testdata", "config.yaml"))
+	require.NoError(t, err)
+	factory := NewFactory()
+	cfg := factory.CreateDefaultConfig()
+
+	sub, err := cm.Sub("stdin")
+	require.NoError(t, err)
+	require.NoError(t, component.UnmarshalConfig(sub, cfg))
+
+	assert.NoError(t, component.ValidateConfig(cfg))
+	assert.Equal(t, testdataConfigYaml(), cfg)
+}
+
+func testdataConfigYaml() *StdinConfig {
+	return &StdinConfig{
+		BaseConfig: adapter.BaseConfig{
+			Operators: []operator.Config{},
+		},
+		InputConfig: func() stdin.Config {
+			c := stdin.NewConfig("")
+			c.Framing = split.FramingJSONSequence
+			c.MaxLogSize = 1024 * 1024
+			return *c
+		}(),
+	}
+}
+
+func TestDecodeInputConfigFailure(t *testing.T) {
+	sink := new(consumertest.LogsSink)
+	factory := NewFactory()
+	badCfg := &StdinConfig{
+		BaseConfig: adapter.BaseConfig{
+			Operators: []operator.Config{},
+		},
+		InputConfig: func() stdin.Config {
+			c := stdin.NewConfig("")
+			c.Framing = "fake"
+			return *c
+		}(),
+	}
+	receiver, err := factory.CreateLogsReceiver(context.Background(), receivertest.NewNopCreateSettings(), badCfg, sink)
+	require.Error(t, err, "receiver creation should fail if input config isn't valid")
+	require.Nil(t, receiver, "receiver creation should fail if input config isn't valid")
+}
+
+func expectNLogs(sink *consumertest.LogsSink, expected int) func() bool {
+	return func() bool {
+		return sink.LogRecordCount() == expected
+	}
+}
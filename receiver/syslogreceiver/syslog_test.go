@@ -23,6 +23,7 @@ import (
 	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/coreinternal/consumerretry"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/adapter"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/operator"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/operator/helper"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/operator/input/syslog"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/operator/input/tcp"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/operator/input/udp"
@@ -36,6 +37,36 @@ func TestSyslogWithUdp(t *testing.T) {
 	testSyslog(t, testdataUDPConfig())
 }
 
+func TestSyslogWithUdpOctetCountingAndRateLimit(t *testing.T) {
+	cfg := testdataUDPConfig()
+	cfg.InputConfig.EnableOctetCounting = true
+	cfg.InputConfig.UDP.RateLimit = &helper.RateLimitConfig{Rate: 0.001, Burst: 2}
+
+	f := NewFactory()
+	sink := new(consumertest.LogsSink)
+	rcvr, err := f.CreateLogsReceiver(context.Background(), receivertest.NewNopCreateSettings(), cfg, sink)
+	require.NoError(t, err)
+	require.NoError(t, rcvr.Start(context.Background(), componenttest.NewNopHost()))
+	defer func() {
+		require.NoError(t, rcvr.Shutdown(context.Background()))
+	}()
+
+	conn, err := net.Dial("udp", "127.0.0.1:29018")
+	require.NoError(t, err)
+	defer conn.Close()
+
+	// The messages over the rate limit of the sender are dropped
+	for i := 0; i < 3; i++ {
+		msg := fmt.Sprintf("<86>1 2021-02-28T00:0%d:02.003Z 192.168.1.1 SecureAuth0 23108 ID52020 - test msg %d", i, i)
+		_, err = conn.Write([]byte(fmt.Sprintf("%d %s", len(msg), msg)))
+		require.NoError(t, err)
+	}
+
+	require.Eventually(t, expectNLogs(sink, 2), 2*time.Second, time.Millisecond)
+	time.Sleep(100 * time.Millisecond)
+	require.Equal(t, 2, sink.LogRecordCount())
+}
+
 func testSyslog(t *testing.T, cfg *SysLogConfig) {
 	numLogs := 5
 